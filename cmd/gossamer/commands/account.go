@@ -17,6 +17,7 @@ func init() {
 	AccountCmd.Flags().String("keystore-file", "", "name of keystore file to import")
 	AccountCmd.Flags().String("password", "", "password used to encrypt the keystore. Used with --generate or --unlock")
 	AccountCmd.Flags().String("scheme", crypto.Sr25519Type, "keyring scheme (sr25519, ed25519, secp256k1)")
+	AccountCmd.Flags().String("output", "", "path of the polkadot-js JSON keystore file to export to")
 }
 
 // AccountCmd is the command to manage the gossamer keystore
@@ -34,6 +35,10 @@ func init() {
 	gossamer account import --keystore-path=path/to/location --keystore-file=keystore.json
 To import a raw key:
 	gossamer account import-raw --keystore-path=path/to/location --keystore-file=keystore.json
+To import a polkadot-js JSON keystore file:
+	gossamer account import-polkadotjs --keystore-path=path/to/location --keystore-file=account.json --password=password
+To export a key to a polkadot-js JSON keystore file:
+	gossamer account export-polkadotjs --keystore-file=path/to/location/keystore/[public key].key --password=password --output=account.json
 To list keys: gossamer account list --keystore-path=path/to/location`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
@@ -54,6 +59,14 @@ func init() {
 			if err := importRawKey(cmd); err != nil {
 				return err
 			}
+		case "import-polkadotjs":
+			if err := importPolkadotJSKey(cmd); err != nil {
+				return err
+			}
+		case "export-polkadotjs":
+			if err := exportPolkadotJSKey(cmd); err != nil {
+				return err
+			}
 		case "list":
 			if err := listKeys(cmd); err != nil {
 				return err
@@ -172,6 +185,74 @@ func importRawKey(cmd *cobra.Command) error {
 	return nil
 }
 
+// importPolkadotJSKey imports a keypair from a polkadot-js JSON keystore file into the keystore
+func importPolkadotJSKey(cmd *cobra.Command) error {
+	keystorePath, err := cmd.Flags().GetString("keystore-path")
+	if err != nil {
+		return fmt.Errorf("failed to get keystore-path: %s", err)
+	}
+	if keystorePath == "" {
+		return fmt.Errorf("keystore-path cannot be empty")
+	}
+
+	keystoreFile, err := cmd.Flags().GetString("keystore-file")
+	if err != nil {
+		return fmt.Errorf("failed to get keystore-file: %s", err)
+	}
+	if keystoreFile == "" {
+		return fmt.Errorf("keystore-file cannot be empty")
+	}
+
+	password, err := cmd.Flags().GetString("password")
+	if err != nil {
+		return fmt.Errorf("failed to get password: %s", err)
+	}
+
+	file, err := keystore.ImportPolkadotJSKeypair(keystoreFile, keystorePath, []byte(password))
+	if err != nil {
+		logger.Errorf("failed to import polkadot-js keypair: %s", err)
+		return err
+	}
+
+	logger.Info("imported polkadot-js keypair and saved it to " + file)
+
+	return nil
+}
+
+// exportPolkadotJSKey exports a keypair from a keystore file to a polkadot-js JSON keystore file
+func exportPolkadotJSKey(cmd *cobra.Command) error {
+	keystoreFile, err := cmd.Flags().GetString("keystore-file")
+	if err != nil {
+		return fmt.Errorf("failed to get keystore-file: %s", err)
+	}
+	if keystoreFile == "" {
+		return fmt.Errorf("keystore-file cannot be empty")
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return fmt.Errorf("failed to get output: %s", err)
+	}
+	if output == "" {
+		return fmt.Errorf("output cannot be empty")
+	}
+
+	password, err := cmd.Flags().GetString("password")
+	if err != nil {
+		return fmt.Errorf("failed to get password: %s", err)
+	}
+
+	err = keystore.ExportPolkadotJSKeypair(keystoreFile, output, []byte(password))
+	if err != nil {
+		logger.Errorf("failed to export polkadot-js keypair: %s", err)
+		return err
+	}
+
+	logger.Info("exported keypair to " + output)
+
+	return nil
+}
+
 // listKeys lists the keys in the keystore
 func listKeys(cmd *cobra.Command) error {
 	keystorePath, err := cmd.Flags().GetString("keystore-path")
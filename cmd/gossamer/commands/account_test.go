@@ -5,8 +5,12 @@
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/stretchr/testify/require"
 )
 
@@ -132,3 +136,47 @@ func TestAccountList(t *testing.T) {
 	err = rootCmd.Execute()
 	require.NoError(t, err)
 }
+
+// TestAccountImportExportPolkadotJS test "gossamer account import-polkadotjs" and
+// "gossamer account export-polkadotjs"
+func TestAccountImportExportPolkadotJS(t *testing.T) {
+	testDir := t.TempDir()
+	directory := fmt.Sprintf("--keystore-path=%s", testDir)
+	password := []byte("VerySecurePassword")
+
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	data, err := keystore.EncodePolkadotJSKeystore(kp.Private(), password)
+	require.NoError(t, err)
+	polkadotJSFile := filepath.Join(testDir, "account.json")
+	err = os.WriteFile(polkadotJSFile, data, 0600)
+	require.NoError(t, err)
+
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(AccountCmd)
+
+	rootCmd.SetArgs([]string{"account",
+		"import-polkadotjs",
+		directory,
+		"--keystore-file=" + polkadotJSFile,
+		"--password=" + string(password)})
+	err = rootCmd.Execute()
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(testDir, "keystore", kp.Public().Hex()[2:]+".key")
+	exportedFile := filepath.Join(testDir, "exported.json")
+	rootCmd.SetArgs([]string{"account",
+		"export-polkadotjs",
+		"--keystore-file=" + keyFile,
+		"--password=" + string(password),
+		"--output=" + exportedFile})
+	err = rootCmd.Execute()
+	require.NoError(t, err)
+
+	data, err = os.ReadFile(exportedFile)
+	require.NoError(t, err)
+	priv, err := keystore.DecodePolkadotJSKeystore(data, password)
+	require.NoError(t, err)
+	require.Equal(t, kp.Private().Encode(), priv.Encode())
+}
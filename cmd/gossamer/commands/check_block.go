@@ -0,0 +1,74 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/dot"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/spf13/cobra"
+)
+
+// CheckBlockCmd is the command to re-execute a stored block
+var CheckBlockCmd = &cobra.Command{
+	Use:   "check-block <hash|number>",
+	Short: "Re-execute a stored block on top of the state of its parent",
+	Long: `The check-block command loads the state of the parent of a block from the database,
+re-executes the block with the runtime of its parent state, and reports the execution error
+or the state root mismatch, if any, along with the execution time. The database is not modified.
+The block is given by its hash, or by its number on the canonical chain.
+Example: 
+	gossamer check-block --base-path ~/.gossamer/westend 1000`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return execCheckBlock(args[0])
+	},
+}
+
+// execCheckBlock executes the check-block command
+func execCheckBlock(block string) error {
+	var (
+		blockHash   *common.Hash
+		blockNumber uint64
+	)
+	if strings.HasPrefix(block, "0x") {
+		hash, err := common.HexToHash(block)
+		if err != nil {
+			return fmt.Errorf("invalid block hash: %w", err)
+		}
+		blockHash = &hash
+	} else {
+		var err error
+		blockNumber, err = strconv.ParseUint(block, 10, 0)
+		if err != nil {
+			return fmt.Errorf("invalid block number: %w", err)
+		}
+	}
+
+	if err := setupOfflineConfig(); err != nil {
+		return err
+	}
+
+	check, err := dot.CheckBlock(config, blockHash, uint(blockNumber))
+	if err != nil {
+		return fmt.Errorf("checking block: %w", err)
+	}
+
+	header := check.Header
+	switch {
+	case check.ExecutionError != nil:
+		return fmt.Errorf("block #%d (%s) failed to execute after %s: %w",
+			header.Number, header.Hash(), check.Duration, check.ExecutionError)
+	case !check.StateRootMatches():
+		return fmt.Errorf("block #%d (%s) executed in %s with state root %s, but its header has state root %s",
+			header.Number, header.Hash(), check.Duration, check.StateRoot, header.StateRoot)
+	}
+
+	logger.Infof("block #%d (%s) executed in %s with the state root %s of its header",
+		header.Number, header.Hash(), check.Duration, check.StateRoot)
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBlockInvalidArgument(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		errContains string
+	}{
+		"missing_block": {
+			args:        []string{CheckBlockCmd.Name()},
+			errContains: "accepts 1 arg(s), received 0",
+		},
+		"invalid_hash": {
+			args:        []string{CheckBlockCmd.Name(), "0xinvalid"},
+			errContains: "invalid block hash",
+		},
+		"invalid_number": {
+			args:        []string{CheckBlockCmd.Name(), "one"},
+			errContains: "invalid block number",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd, err := NewRootCommand()
+			require.NoError(t, err)
+			rootCmd.AddCommand(CheckBlockCmd)
+
+			rootCmd.SetArgs(testCase.args)
+			err = rootCmd.Execute()
+			assert.ErrorContains(t, err, testCase.errContains)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cfg "github.com/ChainSafe/gossamer/config"
+	"github.com/ChainSafe/gossamer/dot"
+	"github.com/ChainSafe/gossamer/lib/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	ExportBlocksCmd.Flags().Uint("from", 1, "Number of the first block to export")
+	ExportBlocksCmd.Flags().Uint("to", 0, "Number of the last block to export, defaults to the best block")
+	ExportBlocksCmd.Flags().String("format", string(dot.BlocksFormatBinary), "Format of the output file: binary or json")
+	ExportBlocksCmd.Flags().String("output", "", "Path to the file to write the blocks to")
+}
+
+// ExportBlocksCmd is the command to export a range of blocks to a file
+var ExportBlocksCmd = &cobra.Command{
+	Use:   "export-blocks",
+	Short: "Export a range of blocks of the canonical chain to a file",
+	Long: `The export-blocks command writes the blocks of the canonical chain, along with
+their justifications, to a file which can be imported with the import-blocks command.
+Example: 
+	gossamer export-blocks --base-path ~/.gossamer/westend --from 1 --to 1000 --format json --output blocks.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return execExportBlocks(cmd)
+	},
+}
+
+// execExportBlocks executes the export-blocks command
+func execExportBlocks(cmd *cobra.Command) error {
+	from, err := cmd.Flags().GetUint("from")
+	if err != nil {
+		return fmt.Errorf("failed to get from: %s", err)
+	}
+
+	to, err := cmd.Flags().GetUint("to")
+	if err != nil {
+		return fmt.Errorf("failed to get to: %s", err)
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return fmt.Errorf("failed to get output: %s", err)
+	}
+	if output == "" {
+		return fmt.Errorf("output must be specified")
+	}
+
+	format, err := parseBlocksFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := setupOfflineConfig(); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Clean(output))
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+
+	err = dot.ExportBlocks(config, from, to, format, file)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("exporting blocks: %w", err)
+	}
+
+	return file.Close()
+}
+
+func parseBlocksFormat(cmd *cobra.Command) (dot.BlocksFormat, error) {
+	formatString, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return "", fmt.Errorf("failed to get format: %s", err)
+	}
+
+	return dot.ParseBlocksFormat(formatString)
+}
+
+// setupOfflineConfig sets the base path and chain-spec of the config to the ones of the
+// initialised node, for the commands which open its database without starting it.
+func setupOfflineConfig() error {
+	if basePath == "" {
+		basePath = config.BasePath
+	}
+
+	if basePath == "" {
+		return fmt.Errorf("basepath must be specified")
+	}
+
+	config.BasePath = utils.ExpandDir(basePath)
+	config.ChainSpec = cfg.GetChainSpec(config.BasePath)
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportBlocksMissingOutput(t *testing.T) {
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(ExportBlocksCmd)
+
+	rootCmd.SetArgs([]string{ExportBlocksCmd.Name(), "--output", ""})
+	err = rootCmd.Execute()
+	assert.ErrorContains(t, err, "output must be specified")
+}
+
+func TestExportBlocksInvalidFormat(t *testing.T) {
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(ExportBlocksCmd)
+
+	rootCmd.SetArgs([]string{ExportBlocksCmd.Name(),
+		"--output", "blocks.bin",
+		"--format", "xml",
+	})
+	err = rootCmd.Execute()
+	assert.ErrorContains(t, err, "invalid blocks format: xml")
+}
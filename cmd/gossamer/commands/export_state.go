@@ -0,0 +1,89 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChainSafe/gossamer/dot"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	ExportStateCmd.Flags().String("block", "", "Hash of the block to export the state of, defaults to the best block")
+	ExportStateCmd.Flags().Uint8("state-version",
+		uint8(trie.DefaultStateVersion),
+		"State version of the exported state",
+	)
+	ExportStateCmd.Flags().String("output", "", "Path to the file to write the state snapshot to")
+}
+
+// ExportStateCmd is the command to export a state snapshot to a file
+var ExportStateCmd = &cobra.Command{
+	Use:   "export-state",
+	Short: "Export the state of a block to a compressed binary snapshot",
+	Long: `The export-state command writes the header and the state of a block,
+including its child tries, to a compressed binary snapshot.
+The snapshot can be imported with the import-state command and its snapshot-file flag.
+Example: 
+	gossamer export-state --base-path ~/.gossamer/westend --block 0x... --state-version 1 --output state.snapshot`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return execExportState(cmd)
+	},
+}
+
+// execExportState executes the export-state command
+func execExportState(cmd *cobra.Command) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return fmt.Errorf("failed to get output: %s", err)
+	}
+	if output == "" {
+		return fmt.Errorf("output must be specified")
+	}
+
+	blockHashString, err := cmd.Flags().GetString("block")
+	if err != nil {
+		return fmt.Errorf("failed to get block: %s", err)
+	}
+
+	var blockHash *common.Hash
+	if blockHashString != "" {
+		hash, err := common.HexToHash(blockHashString)
+		if err != nil {
+			return fmt.Errorf("invalid block hash: %w", err)
+		}
+		blockHash = &hash
+	}
+
+	stateVersion, err := cmd.Flags().GetUint8("state-version")
+	if err != nil {
+		return fmt.Errorf("failed to get state-version: %s", err)
+	}
+	stateTrieVersion, err := trie.ParseVersion(stateVersion)
+	if err != nil {
+		return fmt.Errorf("invalid state version")
+	}
+
+	if err := setupOfflineConfig(); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Clean(output))
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+
+	err = dot.ExportState(config, blockHash, stateTrieVersion, file)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("exporting state: %w", err)
+	}
+
+	return file.Close()
+}
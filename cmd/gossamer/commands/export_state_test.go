@@ -0,0 +1,34 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportStateMissingOutput(t *testing.T) {
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(ExportStateCmd)
+
+	rootCmd.SetArgs([]string{ExportStateCmd.Name(), "--output", ""})
+	err = rootCmd.Execute()
+	assert.ErrorContains(t, err, "output must be specified")
+}
+
+func TestExportStateInvalidBlockHash(t *testing.T) {
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(ExportStateCmd)
+
+	rootCmd.SetArgs([]string{ExportStateCmd.Name(),
+		"--output", "state.snapshot",
+		"--block", "0xinvalid",
+	})
+	err = rootCmd.Execute()
+	assert.ErrorContains(t, err, "invalid block hash")
+}
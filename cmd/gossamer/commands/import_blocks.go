@@ -0,0 +1,65 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChainSafe/gossamer/dot"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	ImportBlocksCmd.Flags().String("format", string(dot.BlocksFormatBinary), "Format of the input file: binary or json")
+	ImportBlocksCmd.Flags().String("input", "", "Path to the file to read the blocks from")
+}
+
+// ImportBlocksCmd is the command to import blocks from a file
+var ImportBlocksCmd = &cobra.Command{
+	Use:   "import-blocks",
+	Short: "Verify and import blocks from a file written by export-blocks",
+	Long: `The import-blocks command verifies, executes and imports the blocks of a file
+written by the export-blocks command, without connecting to the network.
+Blocks already in the database are skipped.
+Example: 
+	gossamer import-blocks --base-path ~/.gossamer/westend --format json --input blocks.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return execImportBlocks(cmd)
+	},
+}
+
+// execImportBlocks executes the import-blocks command
+func execImportBlocks(cmd *cobra.Command) error {
+	format, err := parseBlocksFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	input, err := cmd.Flags().GetString("input")
+	if err != nil {
+		return fmt.Errorf("failed to get input: %s", err)
+	}
+	if input == "" {
+		return fmt.Errorf("input must be specified")
+	}
+
+	if err := setupOfflineConfig(); err != nil {
+		return err
+	}
+
+	file, err := os.Open(filepath.Clean(input))
+	if err != nil {
+		return fmt.Errorf("opening input file: %w", err)
+	}
+	defer file.Close()
+
+	err = dot.ImportBlocks(config, file, format)
+	if err != nil {
+		return fmt.Errorf("importing blocks: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportBlocksMissingInput(t *testing.T) {
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(ImportBlocksCmd)
+
+	rootCmd.SetArgs([]string{ImportBlocksCmd.Name(), "--input", ""})
+	err = rootCmd.Execute()
+	assert.ErrorContains(t, err, "input must be specified")
+}
+
+func TestImportBlocksInputFileNotFound(t *testing.T) {
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(ImportBlocksCmd)
+
+	rootCmd.SetArgs([]string{ImportBlocksCmd.Name(),
+		"--base-path", t.TempDir(),
+		"--input", "not_found.bin",
+	})
+	err = rootCmd.Execute()
+	assert.ErrorContains(t, err, "no such file or directory")
+}
@@ -21,6 +21,8 @@ func init() {
 	)
 	ImportStateCmd.Flags().String("header-file", "", "Path to JSON file of block header corresponding to the given state")
 	ImportStateCmd.Flags().Uint64("first-slot", 0, "The first BABE slot of the network")
+	ImportStateCmd.Flags().String("snapshot-file", "",
+		"Path to a state snapshot written by export-state, used instead of the state and header files")
 }
 
 // ImportStateCmd is the command to import a state from a JSON file
@@ -30,9 +32,12 @@ func init() {
 	Long: `The import-state command allows a JSON file containing a given state
 in the form of key-value pairs to be imported.
 Input can be generated by using the RPC function state_getPairs.
+Alternatively, a state snapshot written by the export-state command can be imported,
+in which case the state root rebuilt from the snapshot is checked against its header.
 Example: 
 	gossamer import-state --state-file state.json --state-version 1 --header-file header.json 
-	--first-slot <first slot of network>`,
+	--first-slot <first slot of network>
+	gossamer import-state --snapshot-file state.snapshot --first-slot <first slot of network>`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return execImportState(cmd)
 	},
@@ -52,6 +57,14 @@ func execImportState(cmd *cobra.Command) error {
 		return fmt.Errorf("failed to get first-slot: %s", err)
 	}
 
+	snapshotFile, err := cmd.Flags().GetString("snapshot-file")
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot-file: %s", err)
+	}
+	if snapshotFile != "" {
+		return dot.ImportStateSnapshot(utils.ExpandDir(basePath), snapshotFile, nil, firstSlot)
+	}
+
 	stateFile, err := cmd.Flags().GetString("state-file")
 	if err != nil {
 		return fmt.Errorf("failed to get state-file: %s", err)
@@ -55,3 +55,15 @@ func TestImportStateErrorImportingState(t *testing.T) {
 	err = rootCmd.Execute()
 	assert.ErrorContains(t, err, "no such file or directory")
 }
+
+func TestImportStateSnapshotFileNotFound(t *testing.T) {
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(ImportStateCmd)
+
+	rootCmd.SetArgs([]string{ImportStateCmd.Name(),
+		"--snapshot-file", "not_found.snapshot",
+	})
+	err = rootCmd.Execute()
+	assert.ErrorContains(t, err, "no such file or directory")
+}
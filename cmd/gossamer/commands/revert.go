@@ -0,0 +1,57 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RevertCmd.Flags().Uint("blocks", 256, "Number of blocks to revert")
+	RevertCmd.Flags().Bool("unsafe-revert-finalised", false,
+		"Revert finalised blocks, which the rest of the network will not revert")
+}
+
+// RevertCmd is the command to revert the best chain by a number of blocks
+var RevertCmd = &cobra.Command{
+	Use:   "revert",
+	Short: "Revert the best chain by a number of blocks",
+	Long: `The revert command reverts the best chain of a stopped node by the given number of
+blocks, down to the genesis block at most. The reverted blocks are removed from the database
+along with the BABE epoch data and the GRANDPA authority set changes they enacted, so the
+node can import another fork once restarted.
+Since unfinalised blocks are not kept across restarts, the reverted blocks are the highest
+finalised blocks of the node, so the revert fails unless --unsafe-revert-finalised is set.
+The node may then no longer agree with the network on the finalised chain.
+Example: 
+	gossamer revert --base-path ~/.gossamer/westend --blocks 10 --unsafe-revert-finalised`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return execRevert(cmd)
+	},
+}
+
+// execRevert executes the revert command
+func execRevert(cmd *cobra.Command) error {
+	blocks, err := cmd.Flags().GetUint("blocks")
+	if err != nil {
+		return fmt.Errorf("failed to get blocks: %s", err)
+	}
+	if blocks == 0 {
+		return fmt.Errorf("blocks must be greater than 0")
+	}
+
+	revertFinalised, err := cmd.Flags().GetBool("unsafe-revert-finalised")
+	if err != nil {
+		return fmt.Errorf("failed to get unsafe-revert-finalised: %s", err)
+	}
+
+	if err := setupOfflineConfig(); err != nil {
+		return err
+	}
+
+	return dot.RevertBlocks(config, blocks, revertFinalised)
+}
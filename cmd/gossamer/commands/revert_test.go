@@ -0,0 +1,21 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevertZeroBlocks(t *testing.T) {
+	rootCmd, err := NewRootCommand()
+	require.NoError(t, err)
+	rootCmd.AddCommand(RevertCmd)
+
+	rootCmd.SetArgs([]string{RevertCmd.Name(), "--blocks", "0"})
+	err = rootCmd.Execute()
+	assert.ErrorContains(t, err, "blocks must be greater than 0")
+}
@@ -106,6 +106,10 @@ func NewRootCommand() (*cobra.Command, error) {
 				return fmt.Errorf("failed to parse telemetry-url: %s", err.Error())
 			}
 
+			if err := parseStatePruning(cmd); err != nil {
+				return fmt.Errorf("failed to parse state-pruning: %s", err)
+			}
+
 			parseRPC()
 
 			// If no chain-spec is provided, it should already exist in the base-path
@@ -174,7 +178,7 @@ func addRootFlags(cmd *cobra.Command) error {
 		`Set a logging filter.
 	Syntax is a list of 'module=logLevel' (comma separated)
 	e.g. --log sync=debug,core=trace
-	Modules are global, core, digest, sync, network, rpc, state, runtime, babe, grandpa, wasmer.
+	Modules are global, core, digest, sync, network, rpc, state, runtime, babe, aura, grandpa, wasmer.
 	Log levels (least to most verbose) are error, warn, info, debug, and trace.
 	By default, all modules log 'info'.
 	The global log level can be set with --log global=debug`)
@@ -241,7 +245,14 @@ func addBaseConfigFlags(cmd *cobra.Command) error {
 	cmd.Flags().StringVar(&pruning,
 		"state-pruning",
 		string(config.BaseConfig.Pruning),
-		"State trie online pruning")
+		"State trie online pruning: archive, or the number of finalised blocks to retain the state of")
+	if err := addStringFlagBindViper(cmd,
+		"state-backend",
+		config.BaseConfig.StateBackend,
+		"Storage state backend: inmemory, or triedb to read the state tries lazily from the database",
+		"state-backend"); err != nil {
+		return fmt.Errorf("failed to add --state-backend flag: %s", err)
+	}
 	if err := addBoolFlagBindViper(cmd,
 		"prometheus-external",
 		config.BaseConfig.PrometheusExternal,
@@ -403,6 +414,14 @@ func addNetworkFlags(cmd *cobra.Command) error {
 		return fmt.Errorf("failed to add --listen-addr flag: %s", err)
 	}
 
+	if err := addStringFlagBindViper(cmd,
+		"sync",
+		config.Network.Sync,
+		"Blockchain syncing mode, one of 'full' or 'warp'",
+		"network.sync"); err != nil {
+		return fmt.Errorf("failed to add --sync flag: %s", err)
+	}
+
 	return nil
 }
 
@@ -529,6 +548,14 @@ func addCoreFlags(cmd *cobra.Command) error {
 		return fmt.Errorf("failed to add --grandpa-interval flag: %s", err)
 	}
 
+	if err := addStringFlagBindViper(cmd,
+		"offchain-worker",
+		config.Core.OffchainWorker,
+		"When to run the offchain workers. One of 'always', 'when-validating' or 'never'.",
+		"core.offchain-worker"); err != nil {
+		return fmt.Errorf("failed to add --offchain-worker flag: %s", err)
+	}
+
 	return nil
 }
 
@@ -30,6 +30,7 @@
 	terminal "golang.org/x/term"
 
 	cfg "github.com/ChainSafe/gossamer/config"
+	"github.com/ChainSafe/gossamer/dot/state/pruner"
 
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/keystore"
@@ -440,6 +441,31 @@ func parseTelemetryURL() error {
 	return nil
 }
 
+// parseStatePruning parses the state-pruning flag, which is either archive
+// or the number of finalised blocks to retain the state of.
+func parseStatePruning(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("state-pruning") {
+		return nil
+	}
+
+	if pruning == string(pruner.Archive) {
+		config.Pruning = pruner.Archive
+		viper.Set("pruning", config.Pruning)
+		return nil
+	}
+
+	retainBlocks, err := strconv.ParseUint(pruning, 10, 32)
+	if err != nil {
+		return fmt.Errorf("expected %q or a number of blocks but got %q", pruner.Archive, pruning)
+	}
+
+	config.Pruning = pruner.Full
+	config.RetainBlocks = uint32(retainBlocks)
+	viper.Set("pruning", config.Pruning)
+	viper.Set("retain-blocks", config.RetainBlocks)
+	return nil
+}
+
 // setViperDefault sets the default values for the config
 // The method goes through the config struct and binds each field to viper
 // in the format <parent-name>.<field-name> = <field-value>
@@ -512,6 +538,7 @@ func parseLogLevel() error {
 		"state":   config.Log.State,
 		"runtime": config.Log.Runtime,
 		"babe":    config.Log.Babe,
+		"aura":    config.Log.Aura,
 		"grandpa": config.Log.Grandpa,
 		"wasmer":  config.Log.Wasmer,
 	}
@@ -25,6 +25,11 @@ func main() {
 		commands.BuildSpecCmd,
 		commands.PruneStateCmd,
 		commands.ImportStateCmd,
+		commands.ExportStateCmd,
+		commands.ExportBlocksCmd,
+		commands.ImportBlocksCmd,
+		commands.RevertCmd,
+		commands.CheckBlockCmd,
 		commands.VersionCmd,
 	)
 	configureCobraCmd("GSSMR")
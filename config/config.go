@@ -29,6 +29,8 @@
 	DefaultRetainBlocks = uint32(512)
 	// DefaultPruning is the default pruning strategy
 	DefaultPruning = pruner.Archive
+	// DefaultStateBackend is the default storage state backend
+	DefaultStateBackend = "inmemory"
 
 	// defaultAccount is the default account key
 	defaultAccount = "alice"
@@ -46,6 +48,21 @@
 	DefaultMinPeers = 0
 	// DefaultMaxPeers is the default maximum number of peers
 	DefaultMaxPeers = 50
+	// FullSync downloads and executes every block from our highest finalised block
+	FullSync = "full"
+	// WarpSync jumps to the latest finalised block using GRANDPA warp sync proofs
+	WarpSync = "warp"
+	// DefaultSync is the default sync mode
+	DefaultSync = FullSync
+
+	// OffchainWorkerAlways runs the offchain workers of each new best block
+	OffchainWorkerAlways = "always"
+	// OffchainWorkerWhenValidating runs the offchain workers only if the node is an authority
+	OffchainWorkerWhenValidating = "when-validating"
+	// OffchainWorkerNever never runs the offchain workers
+	OffchainWorkerNever = "never"
+	// DefaultOffchainWorker is the default offchain worker mode
+	DefaultOffchainWorker = OffchainWorkerWhenValidating
 
 	// DefaultRPCPort is the default RPC port
 	DefaultRPCPort = uint32(8545)
@@ -133,6 +150,7 @@ type BaseConfig struct {
 	PrometheusPort     uint32                      `mapstructure:"prometheus-port,omitempty"`
 	RetainBlocks       uint32                      `mapstructure:"retain-blocks,omitempty"`
 	Pruning            pruner.Mode                 `mapstructure:"pruning,omitempty"`
+	StateBackend       string                      `mapstructure:"state-backend,omitempty"`
 	PrometheusExternal bool                        `mapstructure:"prometheus-external,omitempty"`
 	NoTelemetry        bool                        `mapstructure:"no-telemetry"`
 	TelemetryURLs      []genesis.TelemetryEndpoint `mapstructure:"telemetry-urls,omitempty"`
@@ -154,6 +172,7 @@ type LogConfig struct {
 	State   string `mapstructure:"state,omitempty"`
 	Runtime string `mapstructure:"runtime,omitempty"`
 	Babe    string `mapstructure:"babe,omitempty"`
+	Aura    string `mapstructure:"aura,omitempty"`
 	Grandpa string `mapstructure:"grandpa,omitempty"`
 	Wasmer  string `mapstructure:"wasmer,omitempty"`
 }
@@ -179,6 +198,7 @@ type NetworkConfig struct {
 	PublicDNS         string        `mapstructure:"public-dns"`
 	NodeKey           string        `mapstructure:"node-key"`
 	ListenAddress     string        `mapstructure:"listen-addr"`
+	Sync              string        `mapstructure:"sync"`
 }
 
 // CoreConfig is to marshal/unmarshal toml core config vars
@@ -188,6 +208,7 @@ type CoreConfig struct {
 	GrandpaAuthority bool               `mapstructure:"grandpa-authority"`
 	WasmInterpreter  string             `mapstructure:"wasm-interpreter,omitempty"`
 	GrandpaInterval  time.Duration      `mapstructure:"grandpa-interval,omitempty"`
+	OffchainWorker   string             `mapstructure:"offchain-worker,omitempty"`
 }
 
 // StateConfig contains the configuration for the state.
@@ -233,6 +254,9 @@ func (b *BaseConfig) ValidateBasic() error {
 	if b.PrometheusPort == 0 {
 		return fmt.Errorf("prometheus port cannot be empty")
 	}
+	if b.Pruning != "" && !b.Pruning.IsValid() {
+		return fmt.Errorf("invalid pruning mode: %s", b.Pruning)
+	}
 	if uint32Max < b.RetainBlocks {
 		return fmt.Errorf(
 			"retain-blocks value overflows uint32 boundaries, must be less than or equal to: %d",
@@ -268,6 +292,9 @@ func (n *NetworkConfig) ValidateBasic() error {
 	if n.DiscoveryInterval == 0 {
 		return fmt.Errorf("discovery-interval cannot be empty")
 	}
+	if n.Sync != "" && n.Sync != FullSync && n.Sync != WarpSync {
+		return fmt.Errorf("sync must be one of %q or %q", FullSync, WarpSync)
+	}
 
 	return nil
 }
@@ -280,6 +307,11 @@ func (c *CoreConfig) ValidateBasic() error {
 	if c.WasmInterpreter != wazero.Name {
 		return fmt.Errorf("wasm-interpreter is invalid")
 	}
+	if c.OffchainWorker != "" && c.OffchainWorker != OffchainWorkerAlways &&
+		c.OffchainWorker != OffchainWorkerWhenValidating && c.OffchainWorker != OffchainWorkerNever {
+		return fmt.Errorf("offchain-worker must be one of %q, %q or %q",
+			OffchainWorkerAlways, OffchainWorkerWhenValidating, OffchainWorkerNever)
+	}
 
 	return nil
 }
@@ -337,6 +369,7 @@ func DefaultConfig() *Config {
 			PrometheusPort:     DefaultPrometheusPort,
 			RetainBlocks:       DefaultRetainBlocks,
 			Pruning:            DefaultPruning,
+			StateBackend:       DefaultStateBackend,
 			PrometheusExternal: false,
 			NoTelemetry:        false,
 			TelemetryURLs:      nil,
@@ -350,6 +383,7 @@ func DefaultConfig() *Config {
 			State:   DefaultLogLevel,
 			Runtime: DefaultLogLevel,
 			Babe:    DefaultLogLevel,
+			Aura:    DefaultLogLevel,
 			Grandpa: DefaultLogLevel,
 			Wasmer:  DefaultLogLevel,
 		},
@@ -363,6 +397,7 @@ func DefaultConfig() *Config {
 			GrandpaAuthority: true,
 			WasmInterpreter:  DefaultWasmInterpreter,
 			GrandpaInterval:  DefaultDiscoveryInterval,
+			OffchainWorker:   DefaultOffchainWorker,
 		},
 		Network: &NetworkConfig{
 			Port:              DefaultNetworkPort,
@@ -378,6 +413,7 @@ func DefaultConfig() *Config {
 			PublicDNS:         "",
 			NodeKey:           "",
 			ListenAddress:     "",
+			Sync:              DefaultSync,
 		},
 		State: &StateConfig{
 			Rewind: 0,
@@ -418,6 +454,7 @@ func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 			PrometheusPort:     uint32(9876),
 			RetainBlocks:       DefaultRetainBlocks,
 			Pruning:            DefaultPruning,
+			StateBackend:       DefaultStateBackend,
 			PrometheusExternal: false,
 			NoTelemetry:        false,
 			TelemetryURLs:      nil,
@@ -431,6 +468,7 @@ func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 			State:   DefaultLogLevel,
 			Runtime: DefaultLogLevel,
 			Babe:    DefaultLogLevel,
+			Aura:    DefaultLogLevel,
 			Grandpa: DefaultLogLevel,
 			Wasmer:  DefaultLogLevel,
 		},
@@ -444,6 +482,7 @@ func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 			GrandpaAuthority: true,
 			WasmInterpreter:  DefaultWasmInterpreter,
 			GrandpaInterval:  DefaultDiscoveryInterval,
+			OffchainWorker:   DefaultOffchainWorker,
 		},
 		Network: &NetworkConfig{
 			Port:              DefaultNetworkPort,
@@ -459,6 +498,7 @@ func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 			PublicDNS:         "",
 			NodeKey:           "",
 			ListenAddress:     "",
+			Sync:              DefaultSync,
 		},
 		State: &StateConfig{
 			Rewind: 0,
@@ -499,6 +539,7 @@ func Copy(c *Config) Config {
 			PrometheusPort:     c.PrometheusPort,
 			RetainBlocks:       c.RetainBlocks,
 			Pruning:            c.Pruning,
+			StateBackend:       c.StateBackend,
 			PrometheusExternal: c.PrometheusExternal,
 			NoTelemetry:        c.NoTelemetry,
 			TelemetryURLs:      c.TelemetryURLs,
@@ -512,6 +553,7 @@ func Copy(c *Config) Config {
 			State:   c.Log.State,
 			Runtime: c.Log.Runtime,
 			Babe:    c.Log.Babe,
+			Aura:    c.Log.Aura,
 			Grandpa: c.Log.Grandpa,
 			Wasmer:  c.Log.Wasmer,
 		},
@@ -525,6 +567,7 @@ func Copy(c *Config) Config {
 			GrandpaAuthority: c.Core.GrandpaAuthority,
 			WasmInterpreter:  c.Core.WasmInterpreter,
 			GrandpaInterval:  c.Core.GrandpaInterval,
+			OffchainWorker:   c.Core.OffchainWorker,
 		},
 		Network: &NetworkConfig{
 			Port:              c.Network.Port,
@@ -540,6 +583,7 @@ func Copy(c *Config) Config {
 			PublicDNS:         c.Network.PublicDNS,
 			NodeKey:           c.Network.NodeKey,
 			ListenAddress:     c.Network.ListenAddress,
+			Sync:              c.Network.Sync,
 		},
 		State: &StateConfig{
 			Rewind: c.State.Rewind,
@@ -91,9 +91,18 @@ func WriteConfigFile(basePath string, config *Config) error {
 retain-blocks = {{ .BaseConfig.RetainBlocks }}
 
 # State trie online pruning mode
+# One of: archive, full
+# In full mode, only the state of the last retain-blocks finalised blocks is kept
 # Defaults to "archive"
 pruning = "{{ .BaseConfig.Pruning }}"
 
+# Storage state backend
+# One of: inmemory, triedb
+# The triedb backend reads the state tries lazily from the database instead of
+# holding them fully in memory
+# Defaults to "inmemory"
+state-backend = "{{ .BaseConfig.StateBackend }}"
+
 # Disable connecting to the Substrate telemetry server
 # Defaults to false
 no-telemetry = {{ .BaseConfig.NoTelemetry }}
@@ -149,6 +158,9 @@ func WriteConfigFile(basePath string, config *Config) error {
 # BABE module log level
 babe = "{{ .Log.Babe }}"
 
+# Aura module log level
+aura = "{{ .Log.Aura }}"
+
 # GRANDPA module log level
 grandpa = "{{ .Log.Grandpa }}"
 
@@ -217,6 +229,10 @@ func WriteConfigFile(basePath string, config *Config) error {
 # Multiaddress to listen on
 listen-addr = "{{ .Network.ListenAddress }}"
 
+# Blockchain syncing mode, one of "full" or "warp"
+# Defaults to "full"
+sync = "{{ .Network.Sync }}"
+
 #######################################################
 ###             Core Configuration Options          ###
 #######################################################
@@ -242,6 +258,11 @@ func WriteConfigFile(basePath string, config *Config) error {
 # Grandpa interval
 grandpa-interval = "{{ .Core.GrandpaInterval }}"
 
+# When to run the runtime offchain workers on new best blocks
+# One of: "always", "when-validating" (only if the node is an authority) or "never"
+# Defaults to "when-validating"
+offchain-worker = "{{ .Core.OffchainWorker }}"
+
 #######################################################
 ###            State Configuration Options          ###
 #######################################################
@@ -0,0 +1,214 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	cfg "github.com/ChainSafe/gossamer/config"
+	"github.com/ChainSafe/gossamer/dot/sync"
+	"github.com/ChainSafe/gossamer/dot/telemetry"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/grandpa"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+)
+
+var errInvalidBlockRange = errors.New("invalid block range")
+
+// ExportBlocks writes the blocks of the canonical chain from number `from` to number `to`,
+// both included, to the given writer in the given format. If `to` is 0, the blocks are
+// exported up to the best block.
+func ExportBlocks(config *cfg.Config, from, to uint, format BlocksFormat, w io.Writer) (err error) {
+	stateSrvc, err := nodeBuilder{}.createStateService(config)
+	if err != nil {
+		return fmt.Errorf("creating state service: %w", err)
+	}
+
+	err = stateSrvc.Start()
+	if err != nil {
+		return fmt.Errorf("starting state service: %w", err)
+	}
+	defer func() {
+		stopErr := stateSrvc.Stop()
+		if err == nil && stopErr != nil {
+			err = fmt.Errorf("stopping state service: %w", stopErr)
+		}
+	}()
+
+	bestBlockNumber, err := stateSrvc.Block.BestBlockNumber()
+	if err != nil {
+		return fmt.Errorf("getting best block number: %w", err)
+	}
+
+	if to == 0 {
+		to = bestBlockNumber
+	}
+
+	if from > to || to > bestBlockNumber {
+		return fmt.Errorf("%w: from %d to %d with best block number %d",
+			errInvalidBlockRange, from, to, bestBlockNumber)
+	}
+
+	logger.Infof("exporting blocks from #%d to #%d...", from, to)
+
+	number := from
+	next := func() (*exportedBlock, error) {
+		if number > to {
+			return nil, nil
+		}
+
+		hash, err := stateSrvc.Block.GetHashByNumber(number)
+		if err != nil {
+			return nil, fmt.Errorf("getting hash of block #%d: %w", number, err)
+		}
+
+		block, err := stateSrvc.Block.GetBlockByHash(hash)
+		if err != nil {
+			return nil, fmt.Errorf("getting block #%d: %w", number, err)
+		}
+
+		var justification *[]byte
+		encodedJustification, err := stateSrvc.Block.GetJustification(hash)
+		if err == nil {
+			justification = &encodedJustification
+		} else if !errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("getting justification of block #%d: %w", number, err)
+		}
+
+		number++
+		return &exportedBlock{
+			Header:        block.Header,
+			Body:          block.Body,
+			Justification: justification,
+		}, nil
+	}
+
+	err = writeBlocks(w, format, uint64(to-from+1), next)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("exported %d blocks", to-from+1)
+	return nil
+}
+
+// ImportBlocks reads the blocks in the given format from the reader, and verifies and
+// imports them without networking, through the same path as the blocks downloaded by
+// the sync service. Blocks already in the database are skipped.
+func ImportBlocks(config *cfg.Config, r io.Reader, format BlocksFormat) (err error) {
+	builder := nodeBuilder{}
+	stateSrvc, err := builder.createStateService(config)
+	if err != nil {
+		return fmt.Errorf("creating state service: %w", err)
+	}
+
+	err = startStateService(*config.State, stateSrvc)
+	if err != nil {
+		return fmt.Errorf("starting state service: %w", err)
+	}
+	defer func() {
+		stopErr := stateSrvc.Stop()
+		if err == nil && stopErr != nil {
+			err = fmt.Errorf("stopping state service: %w", stopErr)
+		}
+	}()
+
+	ns, err := builder.createRuntimeStorage(stateSrvc)
+	if err != nil {
+		return fmt.Errorf("creating runtime storage: %w", err)
+	}
+
+	ks := keystore.NewGlobalKeystore()
+	err = builder.loadRuntime(config, ns, stateSrvc, ks, nil)
+	if err != nil {
+		return fmt.Errorf("loading runtime: %w", err)
+	}
+
+	digestHandler, err := builder.createDigestHandler(stateSrvc)
+	if err != nil {
+		return fmt.Errorf("creating digest handler: %w", err)
+	}
+
+	err = digestHandler.Start()
+	if err != nil {
+		return fmt.Errorf("starting digest handler: %w", err)
+	}
+	defer func() {
+		stopErr := digestHandler.Stop()
+		if err == nil && stopErr != nil {
+			err = fmt.Errorf("stopping digest handler: %w", stopErr)
+		}
+	}()
+
+	coreSrvc, err := builder.createCoreService(config, ks, stateSrvc, nil)
+	if err != nil {
+		return fmt.Errorf("creating core service: %w", err)
+	}
+
+	blockVerifier, err := builder.createBlockVerifier(stateSrvc)
+	if err != nil {
+		return fmt.Errorf("creating block verifier: %w", err)
+	}
+
+	importer := sync.NewOfflineImporter(&sync.OfflineImportConfig{
+		BlockState:         stateSrvc.Block,
+		StorageState:       stateSrvc.Storage,
+		TransactionState:   stateSrvc.Transaction,
+		BabeVerifier:       blockVerifier,
+		FinalityGadget:     grandpa.NewJustificationVerifier(stateSrvc.Grandpa),
+		BlockImportHandler: coreSrvc,
+		Telemetry:          telemetry.NewNoopMailer(),
+	})
+
+	var imported, skipped uint
+	err = readBlocks(r, format, func(block *exportedBlock) error {
+		isImported, err := importer.ImportBlock(block.toBlockData())
+		if err != nil {
+			return err
+		}
+
+		if isImported {
+			imported++
+		} else {
+			skipped++
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("importing blocks: %w", err)
+	}
+
+	logger.Infof("imported %d blocks, skipped %d already imported blocks", imported, skipped)
+	return nil
+}
+
+// RevertBlocks reverts the best chain of the node by the given number of blocks, or down to
+// the genesis block if it has fewer blocks. Finalised blocks are only reverted if
+// revertFinalised is true. The node must not be running.
+func RevertBlocks(config *cfg.Config, blocks uint, revertFinalised bool) (err error) {
+	stateSrvc, err := nodeBuilder{}.createStateService(config)
+	if err != nil {
+		return fmt.Errorf("creating state service: %w", err)
+	}
+
+	err = stateSrvc.Start()
+	if err != nil {
+		return fmt.Errorf("starting state service: %w", err)
+	}
+	defer func() {
+		stopErr := stateSrvc.Stop()
+		if err == nil && stopErr != nil {
+			err = fmt.Errorf("stopping state service: %w", stopErr)
+		}
+	}()
+
+	_, err = stateSrvc.Revert(blocks, revertFinalised)
+	if err != nil {
+		return fmt.Errorf("reverting blocks: %w", err)
+	}
+
+	return nil
+}
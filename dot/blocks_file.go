@@ -0,0 +1,250 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// BlocksFormat is the format of the files written by ExportBlocks and read by ImportBlocks
+type BlocksFormat string
+
+const (
+	// BlocksFormatBinary is the SCALE encoded number of blocks as a u64,
+	// followed by each SCALE encoded block.
+	BlocksFormatBinary BlocksFormat = "binary"
+	// BlocksFormatJSON is a stream of JSON objects, one per block,
+	// with the header fields in the same format as the RPC API.
+	BlocksFormatJSON BlocksFormat = "json"
+)
+
+var errInvalidBlocksFormat = errors.New("invalid blocks format")
+
+// ParseBlocksFormat returns the BlocksFormat for the given string
+func ParseBlocksFormat(s string) (BlocksFormat, error) {
+	switch format := BlocksFormat(s); format {
+	case BlocksFormatBinary, BlocksFormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w: %s", errInvalidBlocksFormat, s)
+	}
+}
+
+// exportedBlock is a block along with its justification, as stored in a blocks file
+type exportedBlock struct {
+	Header        types.Header
+	Body          types.Body
+	Justification *[]byte
+}
+
+func (b *exportedBlock) toBlockData() *types.BlockData {
+	return &types.BlockData{
+		Hash:          b.Header.Hash(),
+		Header:        &b.Header,
+		Body:          &b.Body,
+		Justification: b.Justification,
+	}
+}
+
+type jsonExportedBlock struct {
+	Header        jsonExportedHeader `json:"header"`
+	Extrinsics    []string           `json:"extrinsics"`
+	Justification *string            `json:"justification"`
+}
+
+type jsonExportedHeader struct {
+	ParentHash     common.Hash              `json:"parentHash"`
+	Number         string                   `json:"number"`
+	StateRoot      common.Hash              `json:"stateRoot"`
+	ExtrinsicsRoot common.Hash              `json:"extrinsicsRoot"`
+	Digest         jsonExportedHeaderDigest `json:"digest"`
+}
+
+type jsonExportedHeaderDigest struct {
+	Logs []string `json:"logs"`
+}
+
+func newJSONExportedBlock(block *exportedBlock) (*jsonExportedBlock, error) {
+	logs := make([]string, len(block.Header.Digest))
+	for i, item := range block.Header.Digest {
+		encoded, err := scale.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("encoding digest item: %w", err)
+		}
+		logs[i] = common.BytesToHex(encoded)
+	}
+
+	extrinsics := make([]string, len(block.Body))
+	for i, extrinsic := range block.Body {
+		extrinsics[i] = common.BytesToHex(extrinsic)
+	}
+
+	var justification *string
+	if block.Justification != nil {
+		encoded := common.BytesToHex(*block.Justification)
+		justification = &encoded
+	}
+
+	return &jsonExportedBlock{
+		Header: jsonExportedHeader{
+			ParentHash:     block.Header.ParentHash,
+			Number:         common.UintToHex(block.Header.Number),
+			StateRoot:      block.Header.StateRoot,
+			ExtrinsicsRoot: block.Header.ExtrinsicsRoot,
+			Digest:         jsonExportedHeaderDigest{Logs: logs},
+		},
+		Extrinsics:    extrinsics,
+		Justification: justification,
+	}, nil
+}
+
+func (b *jsonExportedBlock) toExportedBlock() (*exportedBlock, error) {
+	number, err := common.HexToUint(b.Header.Number)
+	if err != nil {
+		return nil, fmt.Errorf("decoding block number: %w", err)
+	}
+
+	digest := types.NewDigest()
+	for _, log := range b.Header.Digest.Logs {
+		encoded, err := common.HexToBytes(log)
+		if err != nil {
+			return nil, fmt.Errorf("decoding digest item: %w", err)
+		}
+
+		item := types.NewDigestItem()
+		err = scale.Unmarshal(encoded, &item)
+		if err != nil {
+			return nil, fmt.Errorf("decoding digest item: %w", err)
+		}
+		digest = append(digest, item)
+	}
+
+	body, err := types.NewBodyFromExtrinsicStrings(b.Extrinsics)
+	if err != nil {
+		return nil, fmt.Errorf("decoding extrinsics: %w", err)
+	}
+
+	var justification *[]byte
+	if b.Justification != nil {
+		decoded, err := common.HexToBytes(*b.Justification)
+		if err != nil {
+			return nil, fmt.Errorf("decoding justification: %w", err)
+		}
+		justification = &decoded
+	}
+
+	return &exportedBlock{
+		Header: types.Header{
+			ParentHash:     b.Header.ParentHash,
+			Number:         number,
+			StateRoot:      b.Header.StateRoot,
+			ExtrinsicsRoot: b.Header.ExtrinsicsRoot,
+			Digest:         digest,
+		},
+		Body:          *body,
+		Justification: justification,
+	}, nil
+}
+
+// writeBlocks writes the blocks returned by next to the writer in the given format,
+// until next returns a nil block.
+func writeBlocks(w io.Writer, format BlocksFormat, count uint64,
+	next func() (*exportedBlock, error)) (err error) {
+	var write func(block *exportedBlock) error
+	switch format {
+	case BlocksFormatBinary:
+		encoder := scale.NewEncoder(w)
+		err = encoder.Encode(count)
+		if err != nil {
+			return fmt.Errorf("encoding blocks count: %w", err)
+		}
+		write = func(block *exportedBlock) error {
+			return encoder.Encode(*block)
+		}
+	case BlocksFormatJSON:
+		encoder := json.NewEncoder(w)
+		write = func(block *exportedBlock) error {
+			jsonBlock, err := newJSONExportedBlock(block)
+			if err != nil {
+				return err
+			}
+			return encoder.Encode(jsonBlock)
+		}
+	default:
+		return fmt.Errorf("%w: %s", errInvalidBlocksFormat, format)
+	}
+
+	for {
+		block, err := next()
+		if err != nil {
+			return err
+		}
+
+		if block == nil {
+			return nil
+		}
+
+		err = write(block)
+		if err != nil {
+			return fmt.Errorf("writing block #%d: %w", block.Header.Number, err)
+		}
+	}
+}
+
+// readBlocks reads the blocks in the given format from the reader
+// and calls handle for each of them.
+func readBlocks(r io.Reader, format BlocksFormat, handle func(block *exportedBlock) error) error {
+	switch format {
+	case BlocksFormatBinary:
+		decoder := scale.NewDecoder(r)
+		var count uint64
+		err := decoder.Decode(&count)
+		if err != nil {
+			return fmt.Errorf("decoding blocks count: %w", err)
+		}
+
+		for i := uint64(0); i < count; i++ {
+			block := &exportedBlock{}
+			err = decoder.Decode(block)
+			if err != nil {
+				return fmt.Errorf("decoding block %d of %d: %w", i+1, count, err)
+			}
+
+			err = handle(block)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case BlocksFormatJSON:
+		decoder := json.NewDecoder(r)
+		for decoder.More() {
+			jsonBlock := &jsonExportedBlock{}
+			err := decoder.Decode(jsonBlock)
+			if err != nil {
+				return fmt.Errorf("decoding block: %w", err)
+			}
+
+			block, err := jsonBlock.toExportedBlock()
+			if err != nil {
+				return err
+			}
+
+			err = handle(block)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", errInvalidBlocksFormat, format)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeBlocks_readBlocks(t *testing.T) {
+	t.Parallel()
+
+	digest := types.NewDigest()
+	err := digest.Add(types.PreRuntimeDigest{
+		ConsensusEngineID: types.BabeEngineID,
+		Data:              []byte{1, 2, 3},
+	})
+	require.NoError(t, err)
+
+	justification := []byte{4, 5, 6}
+	blocks := []*exportedBlock{
+		{
+			Header: *types.NewHeader(common.Hash{1}, common.Hash{2}, common.Hash{3}, 1, digest),
+			Body:   types.Body{{7, 8}, {9}},
+		},
+		{
+			Header:        *types.NewHeader(common.Hash{4}, common.Hash{5}, common.Hash{6}, 2, types.NewDigest()),
+			Body:          types.Body{{10, 11, 12}},
+			Justification: &justification,
+		},
+	}
+
+	for _, format := range []BlocksFormat{BlocksFormatBinary, BlocksFormatJSON} {
+		format := format
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+			index := 0
+			err := writeBlocks(buffer, format, uint64(len(blocks)), func() (*exportedBlock, error) {
+				if index == len(blocks) {
+					return nil, nil
+				}
+				index++
+				return blocks[index-1], nil
+			})
+			require.NoError(t, err)
+
+			var readHashes []common.Hash
+			err = readBlocks(buffer, format, func(block *exportedBlock) error {
+				blockData := block.toBlockData()
+				readHashes = append(readHashes, blockData.Hash)
+				assert.Equal(t, blocks[len(readHashes)-1].Body, *blockData.Body)
+				assert.Equal(t, blocks[len(readHashes)-1].Justification, blockData.Justification)
+				return nil
+			})
+			require.NoError(t, err)
+
+			expectedHashes := []common.Hash{blocks[0].Header.Hash(), blocks[1].Header.Hash()}
+			assert.Equal(t, expectedHashes, readHashes)
+		})
+	}
+}
+
+func Test_readBlocks_handleError(t *testing.T) {
+	t.Parallel()
+
+	buffer := bytes.NewBuffer(nil)
+	block := &exportedBlock{Header: *types.NewEmptyHeader()}
+	err := writeBlocks(buffer, BlocksFormatBinary, 1, func() (*exportedBlock, error) {
+		next := block
+		block = nil
+		return next, nil
+	})
+	require.NoError(t, err)
+
+	errTest := errors.New("test error")
+	err = readBlocks(buffer, BlocksFormatBinary, func(*exportedBlock) error {
+		return errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+}
+
+func Test_ParseBlocksFormat(t *testing.T) {
+	t.Parallel()
+
+	format, err := ParseBlocksFormat("json")
+	require.NoError(t, err)
+	assert.Equal(t, BlocksFormatJSON, format)
+
+	_, err = ParseBlocksFormat("xml")
+	assert.ErrorIs(t, err, errInvalidBlocksFormat)
+	assert.EqualError(t, err, "invalid blocks format: xml")
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+//go:build integration
+
+package dot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportBlocks_ImportBlocks(t *testing.T) {
+	config := DefaultTestWestendDevConfig(t)
+	config.ChainSpec = NewTestGenesisRawFile(t, config)
+
+	err := InitNode(config)
+	require.NoError(t, err)
+
+	buffer := bytes.NewBuffer(nil)
+	err = ExportBlocks(config, 1, 0, BlocksFormatJSON, buffer)
+	assert.ErrorIs(t, err, errInvalidBlockRange)
+	assert.EqualError(t, err, "invalid block range: from 1 to 0 with best block number 0")
+
+	err = ExportBlocks(config, 0, 0, BlocksFormatJSON, buffer)
+	require.NoError(t, err)
+
+	var exported []*exportedBlock
+	err = readBlocks(bytes.NewReader(buffer.Bytes()), BlocksFormatJSON, func(block *exportedBlock) error {
+		exported = append(exported, block)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	assert.Equal(t, uint(0), exported[0].Header.Number)
+
+	// the genesis block is already in the database and is skipped
+	err = ImportBlocks(config, buffer, BlocksFormatJSON)
+	require.NoError(t, err)
+}
+
+func TestRevertBlocks(t *testing.T) {
+	config := DefaultTestWestendDevConfig(t)
+	config.ChainSpec = NewTestGenesisRawFile(t, config)
+
+	err := InitNode(config)
+	require.NoError(t, err)
+
+	// the genesis block cannot be reverted
+	err = RevertBlocks(config, 10, false)
+	require.NoError(t, err)
+
+	buffer := bytes.NewBuffer(nil)
+	err = ExportBlocks(config, 0, 0, BlocksFormatBinary, buffer)
+	require.NoError(t, err)
+}
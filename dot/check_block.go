@@ -0,0 +1,135 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cfg "github.com/ChainSafe/gossamer/config"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
+)
+
+var errCheckGenesisBlock = errors.New("cannot check the genesis block")
+
+// BlockCheck is the result of the re-execution of a block by CheckBlock
+type BlockCheck struct {
+	Header *types.Header
+	// StateRoot is the state root computed after executing the block,
+	// which is empty if the execution failed.
+	StateRoot common.Hash
+	// ExecutionError is the error returned by Core_execute_block, if any.
+	ExecutionError error
+	Duration       time.Duration
+}
+
+// StateRootMatches returns true if the block was executed and the computed
+// state root is the state root of its header.
+func (c *BlockCheck) StateRootMatches() bool {
+	return c.ExecutionError == nil && c.StateRoot == c.Header.StateRoot
+}
+
+// CheckBlock re-executes the stored block with the given hash, or with the given number on
+// the canonical chain if the hash is nil, on top of the state of its parent, using the
+// runtime code of the parent state. The state of the database is left unchanged.
+func CheckBlock(config *cfg.Config, blockHash *common.Hash, blockNumber uint) (check *BlockCheck, err error) {
+	builder := nodeBuilder{}
+	stateSrvc, err := builder.createStateService(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating state service: %w", err)
+	}
+
+	err = stateSrvc.Start()
+	if err != nil {
+		return nil, fmt.Errorf("starting state service: %w", err)
+	}
+	defer func() {
+		stopErr := stateSrvc.Stop()
+		if err == nil && stopErr != nil {
+			err = fmt.Errorf("stopping state service: %w", stopErr)
+		}
+	}()
+
+	var hash common.Hash
+	if blockHash != nil {
+		hash = *blockHash
+	} else {
+		hash, err = stateSrvc.Block.GetHashByNumber(blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("getting hash of block #%d: %w", blockNumber, err)
+		}
+	}
+
+	block, err := stateSrvc.Block.GetBlockByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting block: %w", err)
+	}
+
+	if block.Header.Number == 0 {
+		return nil, errCheckGenesisBlock
+	}
+
+	parent, err := stateSrvc.Block.GetHeader(block.Header.ParentHash)
+	if err != nil {
+		return nil, fmt.Errorf("getting parent header: %w", err)
+	}
+
+	trieState, err := stateSrvc.Storage.TrieState(&parent.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("getting parent trie state: %w", err)
+	}
+
+	codeHash, err := trieState.LoadCodeHash()
+	if err != nil {
+		return nil, fmt.Errorf("loading runtime code hash: %w", err)
+	}
+
+	ns, err := builder.createRuntimeStorage(stateSrvc)
+	if err != nil {
+		return nil, fmt.Errorf("creating runtime storage: %w", err)
+	}
+
+	wasmerLogLevel, err := log.ParseLevel(config.Log.Wasmer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse wasmer log level: %w", err)
+	}
+
+	instance, err := wazero_runtime.NewInstance(trieState.LoadCode(), wazero_runtime.Config{
+		Storage:             trieState,
+		Keystore:            keystore.NewGlobalKeystore(),
+		LogLvl:              wasmerLogLevel,
+		NodeStorage:         *ns,
+		Transaction:         stateSrvc.Transaction,
+		Role:                config.Core.Role,
+		CodeHash:            codeHash,
+		CompilationCacheDir: runtimeCacheDir(config.BasePath),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating runtime instance: %w", err)
+	}
+	defer instance.Stop()
+
+	logger.Infof("executing block #%d (%s) on top of the state of its parent with root %s...",
+		block.Header.Number, hash, parent.StateRoot)
+
+	check = &BlockCheck{Header: &block.Header}
+	start := time.Now()
+	_, check.ExecutionError = instance.ExecuteBlock(block)
+	check.Duration = time.Since(start)
+	if check.ExecutionError != nil {
+		return check, nil
+	}
+
+	check.StateRoot, err = trieState.Root()
+	if err != nil {
+		return nil, fmt.Errorf("computing state root: %w", err)
+	}
+
+	return check, nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+//go:build integration
+
+package dot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBlock(t *testing.T) {
+	config := DefaultTestWestendDevConfig(t)
+	config.ChainSpec = NewTestGenesisRawFile(t, config)
+
+	err := InitNode(config)
+	require.NoError(t, err)
+
+	_, err = CheckBlock(config, nil, 1)
+	assert.ErrorContains(t, err, "getting hash of block #1")
+
+	_, err = CheckBlock(config, nil, 0)
+	assert.ErrorIs(t, err, errCheckGenesisBlock)
+}
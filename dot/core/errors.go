@@ -15,5 +15,9 @@
 	// ErrEmptyRuntimeCode is returned when the storage :code is empty
 	ErrEmptyRuntimeCode = errors.New("new :code is empty")
 
+	// ErrTransactionBanned is returned when submitting an extrinsic removed with
+	// author_removeExtrinsic whose ban has not expired yet
+	ErrTransactionBanned = errors.New("transaction is temporarily banned")
+
 	errInvalidTransactionQueueVersion = errors.New("invalid transaction queue version")
 )
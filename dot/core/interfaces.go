@@ -71,8 +71,11 @@ type TransactionState interface {
 	AddToPool(vt *transaction.ValidTransaction) common.Hash
 	RemoveExtrinsic(ext types.Extrinsic)
 	RemoveExtrinsicFromPool(ext types.Extrinsic)
+	SetBlockNumber(number uint)
 	PendingInPool() []*transaction.ValidTransaction
 	Exists(ext types.Extrinsic) bool
+	NotifyBroadcast(ext types.Extrinsic)
+	IsBanned(hash common.Hash) bool
 }
 
 // Network is the interface for the network service
@@ -76,6 +76,11 @@ func (s *Service) HandleTransactionMessage(peerID peer.ID, msg *network.Transact
 
 	allTxnsAreValid := true
 	for _, tx := range txs {
+		if s.transactionState.IsBanned(tx.Hash()) {
+			logger.Debugf("ignoring banned transaction %s", tx.Hash())
+			continue
+		}
+
 		validity, err := s.validateTransaction(head, rt, tx)
 		if err != nil {
 			allTxnsAreValid = false
@@ -6,6 +6,7 @@
 import (
 	"bytes"
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/dot/network"
@@ -142,6 +143,7 @@ type args struct {
 		mockStorageState *mockStorageState
 		mockTxnState     *mockTxnState
 		mockRuntime      *mockRuntime
+		banned           []common.Hash
 	}{
 		{
 			name: "not_synced",
@@ -208,6 +210,34 @@ type args struct {
 				msg:    &network.TransactionMessage{Extrinsics: []types.Extrinsic{}},
 			},
 		},
+		{
+			name: "banned_transaction",
+			mockNetwork: &mockNetwork{
+				IsSynced: true,
+				ReportPeer: &mockReportPeer{
+					change: peerset.ReputationChange{
+						Value:  peerset.GoodTransactionValue,
+						Reason: peerset.GoodTransactionReason,
+					},
+					id: peer.ID("jimbo"),
+				},
+			},
+			mockBlockState: &mockBlockState{
+				bestHeader: &mockBestHeader{
+					header: testEmptyHeader,
+				},
+				getRuntime: &mockGetRuntime{
+					runtime: runtimeMock,
+				},
+			},
+			banned: []common.Hash{testExtrinsic[0].Hash()},
+			args: args{
+				peerID: peer.ID("jimbo"),
+				msg: &network.TransactionMessage{
+					Extrinsics: []types.Extrinsic{{1, 2, 3}},
+				},
+			},
+		},
 		{
 			name: "trie_state_error",
 			mockNetwork: &mockNetwork{
@@ -370,11 +400,14 @@ type args struct {
 					tt.mockStorageState.err)
 				s.storageState = storageState
 			}
+			txnState := NewMockTransactionState(ctrl)
+			txnState.EXPECT().IsBanned(gomock.Any()).DoAndReturn(func(hash common.Hash) bool {
+				return slices.Contains(tt.banned, hash)
+			}).AnyTimes()
 			if tt.mockTxnState != nil {
-				txnState := NewMockTransactionState(ctrl)
 				txnState.EXPECT().AddToPool(tt.mockTxnState.input).Return(tt.mockTxnState.hash)
-				s.transactionState = txnState
 			}
+			s.transactionState = txnState
 			if tt.mockRuntime != nil {
 				rt := tt.mockRuntime.runtime
 				rt.EXPECT().SetContextStorage(tt.mockRuntime.setContextStorage.trieState)
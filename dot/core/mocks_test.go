@@ -10,7 +10,7 @@
 package core
 
 import (
-	json "encoding/json"
+	"encoding/json"
 	reflect "reflect"
 
 	network "github.com/ChainSafe/gossamer/dot/network"
@@ -377,6 +377,32 @@ func (mr *MockTransactionStateMockRecorder) Exists(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockTransactionState)(nil).Exists), arg0)
 }
 
+// IsBanned mocks base method.
+func (m *MockTransactionState) IsBanned(arg0 common.Hash) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBanned", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsBanned indicates an expected call of IsBanned.
+func (mr *MockTransactionStateMockRecorder) IsBanned(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBanned", reflect.TypeOf((*MockTransactionState)(nil).IsBanned), arg0)
+}
+
+// NotifyBroadcast mocks base method.
+func (m *MockTransactionState) NotifyBroadcast(arg0 types.Extrinsic) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyBroadcast", arg0)
+}
+
+// NotifyBroadcast indicates an expected call of NotifyBroadcast.
+func (mr *MockTransactionStateMockRecorder) NotifyBroadcast(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyBroadcast", reflect.TypeOf((*MockTransactionState)(nil).NotifyBroadcast), arg0)
+}
+
 // PendingInPool mocks base method.
 func (m *MockTransactionState) PendingInPool() []*transaction.ValidTransaction {
 	m.ctrl.T.Helper()
@@ -430,6 +456,18 @@ func (mr *MockTransactionStateMockRecorder) RemoveExtrinsicFromPool(arg0 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveExtrinsicFromPool", reflect.TypeOf((*MockTransactionState)(nil).RemoveExtrinsicFromPool), arg0)
 }
 
+// SetBlockNumber mocks base method.
+func (m *MockTransactionState) SetBlockNumber(arg0 uint) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBlockNumber", arg0)
+}
+
+// SetBlockNumber indicates an expected call of SetBlockNumber.
+func (mr *MockTransactionStateMockRecorder) SetBlockNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBlockNumber", reflect.TypeOf((*MockTransactionState)(nil).SetBlockNumber), arg0)
+}
+
 // MockNetwork is a mock of Network interface.
 type MockNetwork struct {
 	ctrl     *gomock.Controller
@@ -20,6 +20,7 @@
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
 	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 
 	cscale "github.com/centrifuge/go-substrate-rpc-client/v4/scale"
@@ -153,7 +154,8 @@ func (s *Service) StorageRoot() (common.Hash, error) {
 // HandleBlockImport handles a block that was imported via the network
 func (s *Service) HandleBlockImport(block *types.Block, state *rtstorage.TrieState, announce bool) error {
 	parentHash := block.Header.ParentHash
-	if parentHash != s.blockState.GenesisHash() {
+	// blocks of other consensus engines, such as aura, do not have babe epochs
+	if parentHash != s.blockState.GenesisHash() && !block.Header.IsAuraBlock() {
 		parentHeader, err := s.blockState.GetHeader(parentHash)
 		if err != nil {
 			return fmt.Errorf("getting parent header: %w", err)
@@ -335,10 +337,11 @@ func (s *Service) handleCodeSubstitution(hash common.Hash,
 	// this needs to create a new runtime instance, otherwise it will update
 	// the blocks that reference the current runtime version to use the code substition
 	cfg := wazero_runtime.Config{
-		Storage:     state,
-		Keystore:    rt.Keystore(),
-		NodeStorage: rt.NodeStorage(),
-		Network:     rt.NetworkService(),
+		Storage:             state,
+		Keystore:            rt.Keystore(),
+		NodeStorage:         rt.NodeStorage(),
+		Network:             rt.NetworkService(),
+		CompilationCacheDir: rt.CompilationCacheDir(),
 	}
 
 	if rt.Validator() {
@@ -468,7 +471,7 @@ func (s *Service) handleChainReorg(best, curr common.Hash) error {
 
 // maintainTransactionPool removes any transactions that were included in
 // the new block, revalidates the transactions in the pool, and moves
-// them to the queue if valid.
+// them to the queue if valid and the tags they require are provided.
 // See https://github.com/paritytech/substrate/blob/74804b5649eccfb83c90aec87bdca58e5d5c8789/client/transaction-pool/src/lib.rs#L545
 func (s *Service) maintainTransactionPool(block *types.Block, bestBlockHash common.Hash) error {
 	// remove extrinsics included in a block
@@ -476,6 +479,9 @@ func (s *Service) maintainTransactionPool(block *types.Block, bestBlockHash comm
 		s.transactionState.RemoveExtrinsic(ext)
 	}
 
+	// remove transactions whose longevity expired
+	s.transactionState.SetBlockNumber(block.Header.Number)
+
 	stateRoot, err := s.storageState.GetStateRootFromBlock(&bestBlockHash)
 	if err != nil {
 		logger.Errorf("could not get state root from block %s: %w", bestBlockHash, err)
@@ -512,10 +518,13 @@ func (s *Service) maintainTransactionPool(block *types.Block, bestBlockHash comm
 
 		tx = transaction.NewValidTransaction(tx.Extrinsic, txnValidity)
 
-		// Err is only thrown if tx is already in pool, in which case it still gets removed
-		h, _ := s.transactionState.Push(tx)
-
+		// the transaction is pushed back to the pool if the tags it requires are not provided yet
 		s.transactionState.RemoveExtrinsicFromPool(tx.Extrinsic)
+		h, err := s.transactionState.Push(tx)
+		if err != nil {
+			logger.Debugf("failed to move transaction %s to queue: %s", h, err)
+			continue
+		}
 		logger.Tracef("moved transaction %s to queue", h)
 	}
 	return nil
@@ -553,6 +562,34 @@ func (s *Service) DecodeSessionKeys(encodedSessionKeys []byte) ([]byte, error) {
 	return rt.DecodeSessionKeys(encodedSessionKeys)
 }
 
+// GenerateSessionKeys executes the runtime GenerateSessionKeys of the best block, which generates
+// new session keys in the node keystore, and returns the concatenated public keys
+func (s *Service) GenerateSessionKeys() ([]byte, error) {
+	rt, trieState, err := s.blockRuntimeState(nil)
+	if err != nil {
+		return nil, fmt.Errorf("setting up runtime: %w", err)
+	}
+
+	// no seed is given, the keys are generated randomly
+	encodedSeed, err := scale.Marshal((*[]byte)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("encoding seed: %w", err)
+	}
+
+	ret, err := rt.ExecWithStorage(trieState, runtime.GenerateSessionKeys, encodedSeed)
+	if err != nil {
+		return nil, fmt.Errorf("generating session keys: %w", err)
+	}
+
+	var sessionKeys []byte
+	err = scale.Unmarshal(ret, &sessionKeys)
+	if err != nil {
+		return nil, fmt.Errorf("decoding session keys: %w", err)
+	}
+
+	return sessionKeys, nil
+}
+
 // GetRuntimeVersion gets the current RuntimeVersion
 func (s *Service) GetRuntimeVersion(bhash *common.Hash) (
 	version runtime.Version, err error) {
@@ -569,6 +606,10 @@ func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
 		return nil
 	}
 
+	if s.transactionState.IsBanned(ext.Hash()) {
+		return fmt.Errorf("%w: %s", ErrTransactionBanned, ext.Hash())
+	}
+
 	if s.transactionState.Exists(ext) {
 		return nil
 	}
@@ -610,6 +651,7 @@ func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
 	// broadcast transaction
 	msg := &network.TransactionMessage{Extrinsics: []types.Extrinsic{ext}}
 	s.net.GossipMessage(msg)
+	s.transactionState.NotifyBroadcast(ext)
 	return nil
 }
 
@@ -622,6 +664,21 @@ func (s *Service) GetMetadata(bhash *common.Hash) (metadata []byte, err error) {
 	return rt.Metadata()
 }
 
+// DryRun applies the extrinsic on a copy of the state of the given block, or of the best
+// block if the hash is nil, and returns the SCALE encoded ApplyExtrinsicResult.
+func (s *Service) DryRun(ext types.Extrinsic, bhash *common.Hash) (applyExtrinsicResult []byte, err error) {
+	rt, trieState, err := s.blockRuntimeState(bhash)
+	if err != nil {
+		return nil, fmt.Errorf("setting up runtime: %w", err)
+	}
+
+	applyExtrinsicResult, err = rt.ExecWithStorage(trieState, runtime.BlockBuilderApplyExtrinsic, ext)
+	if err != nil {
+		return nil, fmt.Errorf("applying extrinsic: %w", err)
+	}
+	return applyExtrinsicResult, nil
+}
+
 // GetReadProofAt will return an array with the proofs for the keys passed as params
 // based on the block hash passed as param as well, if block hash is nil then the current state will take place
 func (s *Service) GetReadProofAt(block common.Hash, keys [][]byte) (
@@ -666,6 +723,38 @@ func (s *Service) buildExternalTransaction(rt runtime.Instance, ext types.Extrin
 	return types.Extrinsic(bytes.Join(extrinsicParts, nil)), nil
 }
 
+// blockRuntimeState returns the runtime of the given block, or of the best block if the
+// hash is nil, and a copy of the block state. Calls are run with both using ExecWithStorage,
+// so the storage of the instance shared with block production and import is left untouched.
+func (s *Service) blockRuntimeState(bhash *common.Hash) (rt runtime.Instance,
+	trieState *rtstorage.TrieState, err error) {
+	var blockHash common.Hash
+	if bhash != nil {
+		blockHash = *bhash
+	} else {
+		blockHash = s.blockState.BestBlockHash()
+	}
+
+	stateRoot, err := s.storageState.GetStateRootFromBlock(&blockHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting state root from block hash: %w", err)
+	}
+
+	s.storageState.Lock()
+	trieState, err = s.storageState.TrieState(stateRoot)
+	s.storageState.Unlock()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting trie state: %w", err)
+	}
+
+	rt, err = s.blockState.GetRuntime(blockHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting runtime: %w", err)
+	}
+
+	return rt, trieState, nil
+}
+
 func prepareRuntime(blockHash *common.Hash, storageState StorageState,
 	blockState BlockState) (instance runtime.Instance, err error) {
 	var stateRootHash *common.Hash
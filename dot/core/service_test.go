@@ -261,6 +261,7 @@ func Test_Service_handleCodeSubstitution(t *testing.T) {
 				storedRuntime.EXPECT().Keystore().Return(nil)
 				storedRuntime.EXPECT().NodeStorage().Return(runtime.NodeStorage{})
 				storedRuntime.EXPECT().NetworkService().Return(nil)
+				storedRuntime.EXPECT().CompilationCacheDir().Return("")
 				storedRuntime.EXPECT().Validator().Return(false)
 
 				blockState := NewMockBlockState(ctrl)
@@ -287,6 +288,7 @@ func Test_Service_handleCodeSubstitution(t *testing.T) {
 				storedRuntime.EXPECT().Keystore().Return(nil)
 				storedRuntime.EXPECT().NodeStorage().Return(runtime.NodeStorage{})
 				storedRuntime.EXPECT().NetworkService().Return(nil)
+				storedRuntime.EXPECT().CompilationCacheDir().Return("")
 				storedRuntime.EXPECT().Validator().Return(true)
 
 				blockState := NewMockBlockState(ctrl)
@@ -316,6 +318,7 @@ func Test_Service_handleCodeSubstitution(t *testing.T) {
 				storedRuntime.EXPECT().Keystore().Return(nil)
 				storedRuntime.EXPECT().NodeStorage().Return(runtime.NodeStorage{})
 				storedRuntime.EXPECT().NetworkService().Return(nil)
+				storedRuntime.EXPECT().CompilationCacheDir().Return("")
 				storedRuntime.EXPECT().Validator().Return(true)
 
 				blockState := NewMockBlockState(ctrl)
@@ -641,6 +644,7 @@ func Test_Service_maintainTransactionPool(t *testing.T) {
 
 		mockTxnState := NewMockTransactionState(ctrl)
 		mockTxnState.EXPECT().RemoveExtrinsic(types.Extrinsic{21}).Times(2)
+		mockTxnState.EXPECT().SetBlockNumber(uint(21))
 		mockTxnState.EXPECT().PendingInPool().Return([]*transaction.ValidTransaction{vt})
 		mockBlockState := NewMockBlockState(ctrl)
 		runtimeBlockHashCall := mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
@@ -705,9 +709,10 @@ func Test_Service_maintainTransactionPool(t *testing.T) {
 		runtimeMock.EXPECT().SetContextStorage(&rtstorage.TrieState{})
 		mockTxnState := NewMockTransactionState(ctrl)
 		mockTxnState.EXPECT().RemoveExtrinsic(types.Extrinsic{21})
+		mockTxnState.EXPECT().SetBlockNumber(uint(21))
 		mockTxnState.EXPECT().PendingInPool().Return([]*transaction.ValidTransaction{vt})
-		mockTxnState.EXPECT().Push(tx).Return(common.Hash{}, nil)
-		mockTxnState.EXPECT().RemoveExtrinsicFromPool(types.Extrinsic{21})
+		removeCall := mockTxnState.EXPECT().RemoveExtrinsicFromPool(types.Extrinsic{21})
+		mockTxnState.EXPECT().Push(tx).Return(common.Hash{}, nil).After(removeCall)
 
 		mockBlockStateOk := NewMockBlockState(ctrl)
 		runtimeBlockHashCall := mockBlockStateOk.EXPECT().BestBlockHash().Return(common.Hash{1})
@@ -1131,6 +1136,86 @@ func TestService_DecodeSessionKeys(t *testing.T) {
 	})
 }
 
+func TestService_GenerateSessionKeys(t *testing.T) {
+	t.Parallel()
+	sessionKeys := []byte{1, 2, 3, 4}
+	encodedSeed := scale.MustMarshal((*[]byte)(nil))
+
+	t.Run("ok_case", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		trieState := &rtstorage.TrieState{}
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&common.Hash{2}, nil)
+		mockStorageState.EXPECT().Lock()
+		mockStorageState.EXPECT().TrieState(&common.Hash{2}).Return(trieState, nil)
+		mockStorageState.EXPECT().Unlock()
+		runtimeMock := NewMockInstance(ctrl)
+		// the shared instance storage is not set, the keys are generated in isolation
+		runtimeMock.EXPECT().ExecWithStorage(trieState, runtime.GenerateSessionKeys, encodedSeed).
+			Return(scale.MustMarshal(sessionKeys), nil)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+
+		res, err := service.GenerateSessionKeys()
+		require.NoError(t, err)
+		assert.Equal(t, sessionKeys, res)
+	})
+
+	t.Run("get_runtime_err", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&common.Hash{2}, nil)
+		mockStorageState.EXPECT().Lock()
+		mockStorageState.EXPECT().TrieState(&common.Hash{2}).Return(&rtstorage.TrieState{}, nil)
+		mockStorageState.EXPECT().Unlock()
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(nil, errDummyErr)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+
+		res, err := service.GenerateSessionKeys()
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.EqualError(t, err, "setting up runtime: getting runtime: dummy error for testing")
+		assert.Nil(t, res)
+	})
+
+	t.Run("exec_err", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		trieState := &rtstorage.TrieState{}
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&common.Hash{2}, nil)
+		mockStorageState.EXPECT().Lock()
+		mockStorageState.EXPECT().TrieState(&common.Hash{2}).Return(trieState, nil)
+		mockStorageState.EXPECT().Unlock()
+		runtimeMock := NewMockInstance(ctrl)
+		runtimeMock.EXPECT().ExecWithStorage(trieState, runtime.GenerateSessionKeys, encodedSeed).
+			Return(nil, errDummyErr)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+
+		res, err := service.GenerateSessionKeys()
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.EqualError(t, err, "generating session keys: dummy error for testing")
+		assert.Nil(t, res)
+	})
+}
+
 func TestServiceGetRuntimeVersion(t *testing.T) {
 	t.Parallel()
 	rv := runtime.Version{
@@ -1243,6 +1328,20 @@ func TestServiceHandleSubmittedExtrinsic(t *testing.T) {
 		execTest(t, service, nil, nil)
 	})
 
+	t.Run("banned_transaction", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().IsBanned(ext.Hash()).Return(true)
+		service := &Service{
+			transactionState: mockTxnState,
+			net:              NewMockNetwork(ctrl),
+		}
+		err := service.HandleSubmittedExtrinsic(ext)
+		assert.ErrorIs(t, err, ErrTransactionBanned)
+		assert.EqualError(t, err, "transaction is temporarily banned: "+ext.Hash().String())
+	})
+
 	t.Run("trie_state_err", func(t *testing.T) {
 		t.Parallel()
 		ctrl := gomock.NewController(t)
@@ -1253,6 +1352,7 @@ func TestServiceHandleSubmittedExtrinsic(t *testing.T) {
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{})
 		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().IsBanned(ext.Hash()).Return(false)
 		mockTxnState.EXPECT().Exists(nil)
 		service := &Service{
 			blockState:       mockBlockState,
@@ -1276,6 +1376,7 @@ func TestServiceHandleSubmittedExtrinsic(t *testing.T) {
 		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{}).Return(&common.Hash{}, nil)
 
 		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().IsBanned(ext.Hash()).Return(false)
 		mockTxnState.EXPECT().Exists(nil).MaxTimes(2)
 		service := &Service{
 			storageState:     mockStorageState,
@@ -1300,6 +1401,7 @@ func TestServiceHandleSubmittedExtrinsic(t *testing.T) {
 		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{}).Return(&common.Hash{}, nil)
 
 		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().IsBanned(ext.Hash()).Return(false)
 		mockTxnState.EXPECT().Exists(types.Extrinsic{})
 
 		runtimeMockErr.EXPECT().ValidateTransaction(externalExt).Return(nil, errDummyErr)
@@ -1357,10 +1459,12 @@ func TestServiceHandleSubmittedExtrinsic(t *testing.T) {
 		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{}).Return(&common.Hash{}, nil)
 
 		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().IsBanned(ext.Hash()).Return(false)
 		mockTxnState.EXPECT().Exists(types.Extrinsic{}).MaxTimes(2)
 		mockTxnState.EXPECT().AddToPool(transaction.NewValidTransaction(ext, &transaction.Validity{Propagate: true}))
 		mockNetState := NewMockNetwork(ctrl)
 		mockNetState.EXPECT().GossipMessage(&network.TransactionMessage{Extrinsics: []types.Extrinsic{ext}})
+		mockTxnState.EXPECT().NotifyBroadcast(ext)
 		service := &Service{
 			storageState:     mockStorageState,
 			transactionState: mockTxnState,
@@ -1443,6 +1547,84 @@ func TestServiceGetMetadata(t *testing.T) {
 	})
 }
 
+func TestService_DryRun(t *testing.T) {
+	t.Parallel()
+
+	ext := types.Extrinsic{1, 2, 3}
+	execTest := func(t *testing.T, s *Service, bhash *common.Hash, exp []byte,
+		expErr error, expectedErrMessage string) {
+		res, err := s.DryRun(ext, bhash)
+		assert.ErrorIs(t, err, expErr)
+		if expErr != nil {
+			assert.EqualError(t, err, expectedErrMessage)
+		}
+		assert.Equal(t, exp, res)
+	}
+
+	t.Run("trie_state_error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&common.Hash{2}, nil)
+		mockStorageState.EXPECT().Lock()
+		mockStorageState.EXPECT().TrieState(&common.Hash{2}).Return(nil, errDummyErr)
+		mockStorageState.EXPECT().Unlock()
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+		const expectedErrMessage = "setting up runtime: getting trie state: dummy error for testing"
+		execTest(t, service, nil, nil, errDummyErr, expectedErrMessage)
+	})
+
+	t.Run("apply_extrinsic_error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		trieState := &rtstorage.TrieState{}
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&common.Hash{2}, nil)
+		mockStorageState.EXPECT().Lock()
+		mockStorageState.EXPECT().TrieState(&common.Hash{2}).Return(trieState, nil)
+		mockStorageState.EXPECT().Unlock()
+		runtimeMock := NewMockInstance(ctrl)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
+		runtimeMock.EXPECT().ExecWithStorage(trieState, runtime.BlockBuilderApplyExtrinsic, []byte(ext)).
+			Return(nil, errDummyErr)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+		const expectedErrMessage = "applying extrinsic: dummy error for testing"
+		execTest(t, service, &common.Hash{1}, nil, errDummyErr, expectedErrMessage)
+	})
+
+	t.Run("happy_path", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		trieState := &rtstorage.TrieState{}
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&common.Hash{2}, nil)
+		mockStorageState.EXPECT().Lock()
+		mockStorageState.EXPECT().TrieState(&common.Hash{2}).Return(trieState, nil)
+		mockStorageState.EXPECT().Unlock()
+		runtimeMock := NewMockInstance(ctrl)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
+		// the shared instance storage is not set, the extrinsic is applied in isolation
+		runtimeMock.EXPECT().ExecWithStorage(trieState, runtime.BlockBuilderApplyExtrinsic, []byte(ext)).
+			Return([]byte{0, 0}, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+		execTest(t, service, nil, []byte{0, 0}, nil, "")
+	})
+}
+
 func TestService_GetReadProofAt(t *testing.T) {
 	t.Parallel()
 	execTest := func(t *testing.T, s *Service, block common.Hash, keys [][]byte,
@@ -58,6 +58,10 @@ func (h *BlockImportHandler) handleConsensusDigest(d *types.ConsensusDigest, hea
 			return fmt.Errorf("handling grandpa digest: %w", err)
 		}
 	case types.BabeEngineID:
+		if header.IsAuraBlock() {
+			return fmt.Errorf("%w: babe consensus digest in aura block", ErrUnexpectedConsensusDigest)
+		}
+
 		data := types.NewBabeConsensusDigest()
 		err := scale.Unmarshal(d.Data, &data)
 		if err != nil {
@@ -68,6 +72,21 @@ func (h *BlockImportHandler) handleConsensusDigest(d *types.ConsensusDigest, hea
 		if err != nil {
 			return fmt.Errorf("handling babe digest: %w", err)
 		}
+	case types.AuraEngineID:
+		// the aura authorities are read from the runtime state of the parent block
+		// when authoring or verifying a block, so the digest is only decoded and logged.
+		data := types.NewAuraConsensusDigest()
+		err := scale.Unmarshal(d.Data, &data)
+		if err != nil {
+			return fmt.Errorf("unmarshaling aura consensus digest: %w", err)
+		}
+
+		value, err := data.Value()
+		if err != nil {
+			return fmt.Errorf("getting aura consensus digest value: %w", err)
+		}
+
+		logger.Debugf("block #%d (%s) has aura consensus digest %s", header.Number, header.Hash(), value)
 	default:
 		return fmt.Errorf("%w: 0x%x", ErrUnknownConsensusEngineID, d.ConsensusEngineID.ToBytes())
 	}
@@ -91,7 +110,7 @@ func toConsensusDigests(scaleVaryingTypes types.Digest) []types.ConsensusDigest
 		}
 
 		switch digest.ConsensusEngineID {
-		case types.GrandpaEngineID, types.BabeEngineID:
+		case types.GrandpaEngineID, types.BabeEngineID, types.AuraEngineID:
 			consensusDigests = append(consensusDigests, digest)
 		}
 	}
@@ -264,6 +264,62 @@ func TestBlockImportHandle(t *testing.T) {
 					consensusDigests
 			},
 		},
+		"handle_aura_digests_successfully": {
+			setupGrandpaState: func(_ *testing.T, ctrl *gomock.Controller, _ *types.Header,
+				_ []types.ConsensusDigest) GrandpaState {
+				return NewMockGrandpaState(ctrl)
+			},
+			setupEpochState: func(_ *testing.T, ctrl *gomock.Controller, _ *types.Header,
+				_ []types.ConsensusDigest) EpochState {
+				return NewMockEpochState(ctrl)
+			},
+			createBlockHeader: func(t *testing.T) (*types.Header, []types.ConsensusDigest) {
+				_, _, genesisHeader := newWestendDevGenesisWithTrieAndHeader(t)
+				auraAuths := make([]types.AuraAuthorityRaw, len(keyPairs))
+				for i, keyPair := range keyPairs {
+					auraAuths[i] = keyPair.Public().(*sr25519.PublicKey).AsBytes()
+				}
+
+				authoritiesChange := createAuraConsensusDigest(t, types.AuraAuthoritiesChange{
+					Authorities: auraAuths,
+				})
+				onDisabled := createAuraConsensusDigest(t, types.AuraOnDisabled{ID: 1})
+
+				consensusDigests := []types.ConsensusDigest{
+					authoritiesChange, onDisabled,
+				}
+				return createBlockWithDigests(t, &genesisHeader, authoritiesChange, onDisabled),
+					consensusDigests
+			},
+		},
+		"babe_digest_in_aura_block": {
+			wantErr: ErrUnexpectedConsensusDigest,
+			errString: "consensus digests: " +
+				"unexpected consensus digest: babe consensus digest in aura block",
+			setupGrandpaState: func(_ *testing.T, ctrl *gomock.Controller, _ *types.Header,
+				_ []types.ConsensusDigest) GrandpaState {
+				return NewMockGrandpaState(ctrl)
+			},
+			setupEpochState: func(_ *testing.T, ctrl *gomock.Controller, _ *types.Header,
+				_ []types.ConsensusDigest) EpochState {
+				return NewMockEpochState(ctrl)
+			},
+			createBlockHeader: func(t *testing.T) (*types.Header, []types.ConsensusDigest) {
+				_, _, genesisHeader := newWestendDevGenesisWithTrieAndHeader(t)
+
+				consensusDigests := []types.ConsensusDigest{
+					genericNextEpochDigest, genericNextConfigDataDigest,
+				}
+				header := createBlockWithDigests(t, &genesisHeader, consensusDigests...)
+
+				auraPreDigest, err := types.NewAuraPreRuntimeDigest(1)
+				require.NoError(t, err)
+				err = header.Digest.Add(*auraPreDigest)
+				require.NoError(t, err)
+
+				return header, consensusDigests
+			},
+		},
 	}
 
 	for tname, tt := range cases {
@@ -318,6 +374,21 @@ func createGRANDPAConsensusDigest(t *testing.T, digestData any) types.ConsensusD
 	}
 }
 
+func createAuraConsensusDigest(t *testing.T, digestData any) types.ConsensusDigest {
+	t.Helper()
+
+	auraConsensusDigest := types.NewAuraConsensusDigest()
+	require.NoError(t, auraConsensusDigest.SetValue(digestData))
+
+	marshaledData, err := scale.Marshal(auraConsensusDigest)
+	require.NoError(t, err)
+
+	return types.ConsensusDigest{
+		ConsensusEngineID: types.AuraEngineID,
+		Data:              marshaledData,
+	}
+}
+
 func createBlockWithDigests(t *testing.T, genesisHeader *types.Header, digestsToApply ...types.ConsensusDigest) (
 	header *types.Header) {
 	t.Helper()
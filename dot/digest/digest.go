@@ -14,7 +14,8 @@
 var logger = log.NewFromGlobal(log.AddContext("pkg", "digest"))
 
 var (
-	ErrUnknownConsensusEngineID = errors.New("unknown consensus engine ID")
+	ErrUnknownConsensusEngineID  = errors.New("unknown consensus engine ID")
+	ErrUnexpectedConsensusDigest = errors.New("unexpected consensus digest")
 )
 
 // Handler is used to handle consensus messages and relevant authority updates to BABE and GRANDPA
@@ -71,17 +72,20 @@ func (h *Handler) handleBlockFinalisation(ctx context.Context) {
 				continue
 			}
 
-			err := h.epochState.FinalizeBABENextEpochData(&info.Header)
-			if err != nil {
-				logger.Errorf("failed to persist babe next epoch data: %s", err)
-			}
-
-			err = h.epochState.FinalizeBABENextConfigData(&info.Header)
-			if err != nil {
-				logger.Errorf("failed to persist babe next epoch config: %s", err)
+			// blocks of other consensus engines, such as aura, do not have babe epochs
+			if !info.Header.IsAuraBlock() {
+				err := h.epochState.FinalizeBABENextEpochData(&info.Header)
+				if err != nil {
+					logger.Errorf("failed to persist babe next epoch data: %s", err)
+				}
+
+				err = h.epochState.FinalizeBABENextConfigData(&info.Header)
+				if err != nil {
+					logger.Errorf("failed to persist babe next epoch config: %s", err)
+				}
 			}
 
-			err = h.grandpaState.ApplyScheduledChanges(&info.Header)
+			err := h.grandpaState.ApplyScheduledChanges(&info.Header)
 			if err != nil {
 				logger.Errorf("failed to apply scheduled change: %s", err)
 			}
@@ -0,0 +1,72 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package digest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_handleBlockFinalisation(t *testing.T) {
+	t.Parallel()
+
+	auraPreDigest, err := types.NewAuraPreRuntimeDigest(1)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		preRuntimeDigest  *types.PreRuntimeDigest
+		finaliseBABEEpoch bool
+	}{
+		"babe_block": {
+			preRuntimeDigest:  types.NewBABEPreRuntimeDigest([]byte{1}),
+			finaliseBABEEpoch: true,
+		},
+		"aura_block": {
+			preRuntimeDigest: auraPreDigest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			digest := types.NewDigest()
+			err := digest.Add(*testCase.preRuntimeDigest)
+			require.NoError(t, err)
+			header := types.NewHeader(common.Hash{1}, common.Hash{}, common.Hash{}, 1, digest)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			epochState := NewMockEpochState(ctrl)
+			if testCase.finaliseBABEEpoch {
+				epochState.EXPECT().FinalizeBABENextEpochData(header)
+				epochState.EXPECT().FinalizeBABENextConfigData(header)
+			}
+
+			grandpaState := NewMockGrandpaState(ctrl)
+			grandpaState.EXPECT().ApplyScheduledChanges(header).
+				DoAndReturn(func(*types.Header) error {
+					cancel()
+					return nil
+				})
+
+			handler := &Handler{
+				epochState:   epochState,
+				grandpaState: grandpaState,
+				finalised:    make(chan *types.FinalisationInfo, 1),
+			}
+			handler.finalised <- &types.FinalisationInfo{Header: *header}
+
+			handler.handleBlockFinalisation(ctx)
+		})
+	}
+}
@@ -16,11 +16,13 @@
 	core "github.com/ChainSafe/gossamer/dot/core"
 	digest "github.com/ChainSafe/gossamer/dot/digest"
 	network "github.com/ChainSafe/gossamer/dot/network"
+	offchain "github.com/ChainSafe/gossamer/dot/offchain"
 	rpc "github.com/ChainSafe/gossamer/dot/rpc"
 	state "github.com/ChainSafe/gossamer/dot/state"
 	sync "github.com/ChainSafe/gossamer/dot/sync"
 	system "github.com/ChainSafe/gossamer/dot/system"
 	types "github.com/ChainSafe/gossamer/dot/types"
+	aura "github.com/ChainSafe/gossamer/lib/aura"
 	babe "github.com/ChainSafe/gossamer/lib/babe"
 	grandpa "github.com/ChainSafe/gossamer/lib/grandpa"
 	keystore "github.com/ChainSafe/gossamer/lib/keystore"
@@ -51,6 +53,21 @@ func (m *MocknodeBuilderIface) EXPECT() *MocknodeBuilderIfaceMockRecorder {
 	return m.recorder
 }
 
+// createAuraService mocks base method.
+func (m *MocknodeBuilderIface) createAuraService(config *config.Config, st *state.Service, ks KeyStore, cs *core.Service) (*aura.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createAuraService", config, st, ks, cs)
+	ret0, _ := ret[0].(*aura.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createAuraService indicates an expected call of createAuraService.
+func (mr *MocknodeBuilderIfaceMockRecorder) createAuraService(config, st, ks, cs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createAuraService", reflect.TypeOf((*MocknodeBuilderIface)(nil).createAuraService), config, st, ks, cs)
+}
+
 // createBABEService mocks base method.
 func (m *MocknodeBuilderIface) createBABEService(config *config.Config, st *state.Service, ks KeyStore, cs *core.Service, telemetryMailer Telemetry) (*babe.Service, error) {
 	m.ctrl.T.Helper()
@@ -67,11 +84,12 @@ func (mr *MocknodeBuilderIfaceMockRecorder) createBABEService(config, st, ks, cs
 }
 
 // createBlockVerifier mocks base method.
-func (m *MocknodeBuilderIface) createBlockVerifier(st *state.Service) *babe.VerificationManager {
+func (m *MocknodeBuilderIface) createBlockVerifier(st *state.Service) (sync.BabeVerifier, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "createBlockVerifier", st)
-	ret0, _ := ret[0].(*babe.VerificationManager)
-	return ret0
+	ret0, _ := ret[0].(sync.BabeVerifier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // createBlockVerifier indicates an expected call of createBlockVerifier.
@@ -140,6 +158,21 @@ func (mr *MocknodeBuilderIfaceMockRecorder) createNetworkService(config, stateSr
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createNetworkService", reflect.TypeOf((*MocknodeBuilderIface)(nil).createNetworkService), config, stateSrvc, telemetryMailer)
 }
 
+// createOffchainWorkerManager mocks base method.
+func (m *MocknodeBuilderIface) createOffchainWorkerManager(config *config.Config, st *state.Service, ks *keystore.GlobalKeystore, ns *runtime.NodeStorage, net *network.Service, cs *core.Service, syncer *sync.Service) (*offchain.Manager, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createOffchainWorkerManager", config, st, ks, ns, net, cs, syncer)
+	ret0, _ := ret[0].(*offchain.Manager)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createOffchainWorkerManager indicates an expected call of createOffchainWorkerManager.
+func (mr *MocknodeBuilderIfaceMockRecorder) createOffchainWorkerManager(config, st, ks, ns, net, cs, syncer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createOffchainWorkerManager", reflect.TypeOf((*MocknodeBuilderIface)(nil).createOffchainWorkerManager), config, st, ks, ns, net, cs, syncer)
+}
+
 // createRPCService mocks base method.
 func (m *MocknodeBuilderIface) createRPCService(params rpcServiceSettings) (*rpc.HTTPServer, error) {
 	m.ctrl.T.Helper()
@@ -229,7 +262,7 @@ func (mr *MocknodeBuilderIfaceMockRecorder) loadRuntime(config, ns, stateSrvc, k
 }
 
 // newSyncService mocks base method.
-func (m *MocknodeBuilderIface) newSyncService(config *config.Config, st *state.Service, finalityGadget sync.FinalityGadget, verifier *babe.VerificationManager, cs *core.Service, net *network.Service, telemetryMailer Telemetry) (*sync.Service, error) {
+func (m *MocknodeBuilderIface) newSyncService(config *config.Config, st *state.Service, finalityGadget sync.FinalityGadget, verifier sync.BabeVerifier, cs *core.Service, net *network.Service, telemetryMailer Telemetry) (*sync.Service, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "newSyncService", config, st, finalityGadget, verifier, cs, net, telemetryMailer)
 	ret0, _ := ret[0].(*sync.Service)
@@ -22,4 +22,5 @@
 	ErrInvalidLEB128EncodedData  = errors.New("invalid LEB128 encoded data")
 	ErrGreaterThanMaxSize        = errors.New("greater than maximum size")
 	ErrStreamReset               = errors.New("stream reset")
+	errChangesTriesNotSupported  = errors.New("changes tries are not supported")
 )
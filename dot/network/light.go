@@ -47,30 +47,35 @@ func (s *Service) handleLightMsg(stream libp2pnetwork.Stream, msg messages.P2PMe
 		return nil
 	}
 
+	if s.lightProvider == nil {
+		logger.Debugf("ignoring light request from peer %s: light provider not set",
+			stream.Conn().RemotePeer())
+		return nil
+	}
+
+	// a decoded request holds every kind of request, the one to answer is the
+	// one which is filled in
 	resp := NewLightResponse()
 	switch {
-	case lr.RemoteCallRequest != nil:
-		resp.RemoteCallResponse, err = remoteCallResp(lr.RemoteCallRequest)
-	case lr.RemoteHeaderRequest != nil:
-		resp.RemoteHeaderResponse, err = remoteHeaderResp(lr.RemoteHeaderRequest)
-	case lr.RemoteChangesRequest != nil:
-		resp.RemoteChangesResponse, err = remoteChangeResp(lr.RemoteChangesRequest)
-	case lr.RemoteReadRequest != nil:
-		resp.RemoteReadResponse, err = remoteReadResp(lr.RemoteReadRequest)
-	case lr.RemoteReadChildRequest != nil:
-		resp.RemoteReadResponse, err = remoteReadChildResp(lr.RemoteReadChildRequest)
+	case lr.RemoteCallRequest != nil && lr.RemoteCallRequest.Method != "":
+		resp.RemoteCallResponse, err = s.lightProvider.CreateRemoteCallResponse(lr.RemoteCallRequest)
+	case lr.RemoteReadChildRequest != nil && len(lr.RemoteReadChildRequest.StorageKey) > 0:
+		resp.RemoteReadResponse, err = s.lightProvider.CreateRemoteReadChildResponse(lr.RemoteReadChildRequest)
+	case lr.RemoteReadRequest != nil && len(lr.RemoteReadRequest.Keys) > 0:
+		resp.RemoteReadResponse, err = s.lightProvider.CreateRemoteReadResponse(lr.RemoteReadRequest)
+	case lr.RemoteHeaderRequest != nil && len(lr.RemoteHeaderRequest.Block) > 0:
+		resp.RemoteHeaderResponse, err = s.lightProvider.CreateRemoteHeaderResponse(lr.RemoteHeaderRequest)
+	case lr.RemoteChangesRequest != nil && lr.RemoteChangesRequest.FirstBlock != nil:
+		err = errChangesTriesNotSupported
 	default:
 		logger.Warn("ignoring LightRequest without request data")
 		return nil
 	}
 
 	if err != nil {
-		return err
+		return fmt.Errorf("creating response for light request %s: %w", lr, err)
 	}
 
-	// TODO(arijit): Remove once we implement the internal APIs. Added to increase code coverage. (#1856)
-	logger.Debugf("LightResponse message: %s", resp)
-
 	err = s.host.writeToStream(stream, resp)
 	if err != nil {
 		logger.Warnf("failed to send LightResponse message to peer %s: %s", stream.Conn().RemotePeer(), err)
@@ -335,12 +340,14 @@ func newRemoteReadResponse() *RemoteReadResponse {
 // RemoteHeaderResponse ...
 type RemoteHeaderResponse struct {
 	Header []*types.Header
-	proof  []byte
+	// Proof is the SCALE encoded justification of the block, if it has one
+	Proof []byte
 }
 
 func newRemoteHeaderResponse() *RemoteHeaderResponse {
 	return &RemoteHeaderResponse{
 		Header: nil,
+		Proof:  []byte{},
 	}
 }
 
@@ -444,21 +451,5 @@ func (rr *RemoteReadResponse) String() string {
 
 // String formats a RemoteHeaderResponse as a string
 func (rh *RemoteHeaderResponse) String() string {
-	return fmt.Sprintf("Header =%+v Proof =%s", rh.Header, string(rh.proof))
-}
-
-func remoteCallResp(_ *RemoteCallRequest) (*RemoteCallResponse, error) {
-	return &RemoteCallResponse{}, nil
-}
-func remoteChangeResp(_ *RemoteChangesRequest) (*RemoteChangesResponse, error) {
-	return &RemoteChangesResponse{}, nil
-}
-func remoteHeaderResp(_ *RemoteHeaderRequest) (*RemoteHeaderResponse, error) {
-	return &RemoteHeaderResponse{}, nil
-}
-func remoteReadChildResp(_ *RemoteReadChildRequest) (*RemoteReadResponse, error) {
-	return &RemoteReadResponse{}, nil
-}
-func remoteReadResp(_ *RemoteReadRequest) (*RemoteReadResponse, error) {
-	return &RemoteReadResponse{}, nil
+	return fmt.Sprintf("Header =%+v Proof =%s", rh.Header, string(rh.Proof))
 }
@@ -6,6 +6,7 @@
 package network
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestEncodeLightRequest(t *testing.T) {
@@ -33,7 +35,7 @@ func TestEncodeLightRequest(t *testing.T) {
 
 func TestEncodeLightResponse(t *testing.T) {
 	t.Parallel()
-	exp := common.MustHexToBytes("0x00000000000000")
+	exp := common.MustHexToBytes("0x0000000000000000")
 
 	testLightResponse := NewLightResponse()
 	enc, err := testLightResponse.Encode()
@@ -115,48 +117,62 @@ func TestHandleLightMessage_Response(t *testing.T) {
 	}
 	require.NoError(t, err)
 
-	stream, err := s.host.p2pHost.NewStream(s.ctx, b.host.id(), s.host.protocolID+lightID)
-	require.NoError(t, err)
+	ctrl := gomock.NewController(t)
+	lightProvider := NewMockLightProvider(ctrl)
+	s.SetLightProvider(lightProvider)
+
+	blockHash := common.Hash{1}
 
 	// Testing empty request
-	msg := &LightRequest{}
-	err = s.handleLightMsg(stream, msg)
+	stream, err := s.host.p2pHost.NewStream(s.ctx, b.host.id(), s.host.protocolID+lightID)
+	require.NoError(t, err)
+	err = s.handleLightMsg(stream, NewLightRequest())
 	require.NoError(t, err)
 
-	expectedErr := "failed to find any peer in table"
-
-	// Testing remoteCallResp()
-	msg = &LightRequest{
-		RemoteCallRequest: &RemoteCallRequest{},
-	}
-	err = s.handleLightMsg(stream, msg)
-	require.Error(t, err, expectedErr, msg.String())
+	// Testing remote call request
+	callRequest := &RemoteCallRequest{Block: blockHash.ToBytes(), Method: "Core_version"}
+	lightProvider.EXPECT().CreateRemoteCallResponse(callRequest).
+		Return(&RemoteCallResponse{Proof: []byte{1}}, nil)
+	stream, err = s.host.p2pHost.NewStream(s.ctx, b.host.id(), s.host.protocolID+lightID)
+	require.NoError(t, err)
+	err = s.handleLightMsg(stream, &LightRequest{RemoteCallRequest: callRequest})
+	require.NoError(t, err)
 
-	// Testing remoteHeaderResp()
-	msg = &LightRequest{
-		RemoteHeaderRequest: &RemoteHeaderRequest{},
-	}
-	err = s.handleLightMsg(stream, msg)
-	require.Error(t, err, expectedErr, msg.String())
+	// Testing remote header request
+	headerRequest := &RemoteHeaderRequest{Block: []byte{1, 0, 0, 0}}
+	lightProvider.EXPECT().CreateRemoteHeaderResponse(headerRequest).
+		Return(&RemoteHeaderResponse{Header: []*types.Header{types.NewEmptyHeader()}}, nil)
+	stream, err = s.host.p2pHost.NewStream(s.ctx, b.host.id(), s.host.protocolID+lightID)
+	require.NoError(t, err)
+	err = s.handleLightMsg(stream, &LightRequest{RemoteHeaderRequest: headerRequest})
+	require.NoError(t, err)
 
-	// Testing remoteChangeResp()
-	msg = &LightRequest{
-		RemoteChangesRequest: &RemoteChangesRequest{},
-	}
-	err = s.handleLightMsg(stream, msg)
-	require.Error(t, err, expectedErr, msg.String())
+	// Testing remote read request
+	readRequest := &RemoteReadRequest{Block: blockHash.ToBytes(), Keys: [][]byte{{1}}}
+	lightProvider.EXPECT().CreateRemoteReadResponse(readRequest).
+		Return(&RemoteReadResponse{Proof: []byte{2}}, nil)
+	stream, err = s.host.p2pHost.NewStream(s.ctx, b.host.id(), s.host.protocolID+lightID)
+	require.NoError(t, err)
+	err = s.handleLightMsg(stream, &LightRequest{RemoteReadRequest: readRequest})
+	require.NoError(t, err)
 
-	// Testing remoteReadResp()
-	msg = &LightRequest{
-		RemoteReadRequest: &RemoteReadRequest{},
+	// Testing remote read child request
+	readChildRequest := &RemoteReadChildRequest{
+		Block:      blockHash.ToBytes(),
+		StorageKey: []byte("child"),
+		Keys:       [][]byte{{1}},
 	}
-	err = s.handleLightMsg(stream, msg)
-	require.Error(t, err, expectedErr, msg.String())
+	lightProvider.EXPECT().CreateRemoteReadChildResponse(readChildRequest).
+		Return(nil, errors.New("test error"))
+	stream, err = s.host.p2pHost.NewStream(s.ctx, b.host.id(), s.host.protocolID+lightID)
+	require.NoError(t, err)
+	err = s.handleLightMsg(stream, &LightRequest{RemoteReadChildRequest: readChildRequest})
+	require.ErrorContains(t, err, "test error")
 
-	// Testing remoteReadChildResp()
-	msg = &LightRequest{
-		RemoteReadChildRequest: &RemoteReadChildRequest{},
-	}
-	err = s.handleLightMsg(stream, msg)
-	require.Error(t, err, expectedErr, msg.String())
+	// Testing remote changes request
+	changesRequest := &RemoteChangesRequest{FirstBlock: &blockHash}
+	stream, err = s.host.p2pHost.NewStream(s.ctx, b.host.id(), s.host.protocolID+lightID)
+	require.NoError(t, err)
+	err = s.handleLightMsg(stream, &LightRequest{RemoteChangesRequest: changesRequest})
+	require.ErrorIs(t, err, errChangesTriesNotSupported)
 }
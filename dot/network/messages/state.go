@@ -5,6 +5,7 @@
 
 import (
 	"fmt"
+	"strings"
 
 	pb "github.com/ChainSafe/gossamer/dot/network/proto"
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -12,7 +13,13 @@
 	"google.golang.org/protobuf/proto"
 )
 
-var _ P2PMessage = (*StateRequest)(nil)
+// MaxStateResponseSize is the maximum size in bytes of an encoded state response
+const MaxStateResponseSize = 16 * 1024 * 1024
+
+var (
+	_ P2PMessage = (*StateRequest)(nil)
+	_ P2PMessage = (*StateResponse)(nil)
+)
 
 // StateRequest defines the parameters to request the state keys
 // and values from another peer
@@ -23,9 +30,14 @@ type StateRequest struct {
 }
 
 func (s *StateRequest) String() string {
-	return fmt.Sprintf("StateRequest Block=%s Start=[0x%x, 0x%x] NoProof=%v",
+	start := make([]string, len(s.Start))
+	for i, key := range s.Start {
+		start[i] = fmt.Sprintf("0x%x", key)
+	}
+
+	return fmt.Sprintf("StateRequest Block=%s Start=[%s] NoProof=%v",
 		s.Block.String(),
-		s.Start[0], s.Start[1],
+		strings.Join(start, ", "),
 		s.NoProof,
 	)
 }
@@ -54,17 +66,62 @@ func (s *StateRequest) Decode(in []byte) error {
 	return nil
 }
 
+// StateResponse is the response to a StateRequest
 type StateResponse struct {
 	Entries []KeyValueStateEntry
 	Proof   []byte
 }
 
+// KeyValueStateEntry is a range of key value pairs of the top level trie,
+// if StateRoot is empty, or of the child trie with the given StateRoot.
 type KeyValueStateEntry struct {
 	StateRoot    common.Hash
 	StateEntries trie.Entries
 	Complete     bool
 }
 
+// String returns the string representation of the response
+func (s *StateResponse) String() string {
+	entries := 0
+	for _, entry := range s.Entries {
+		entries += len(entry.StateEntries)
+	}
+
+	return fmt.Sprintf("StateResponse Tries=%d Entries=%d ProofLength=%d",
+		len(s.Entries), entries, len(s.Proof))
+}
+
+// Encode returns the protobuf encoding of the response
+func (s *StateResponse) Encode() ([]byte, error) {
+	message := &pb.StateResponse{
+		Entries: make([]*pb.KeyValueStateEntry, len(s.Entries)),
+		Proof:   s.Proof,
+	}
+
+	for idx, entry := range s.Entries {
+		var stateRoot []byte
+		if !entry.StateRoot.IsEmpty() {
+			stateRoot = entry.StateRoot.ToBytes()
+		}
+
+		stateEntries := make([]*pb.StateEntry, len(entry.StateEntries))
+		for stateEntryIdx, stateEntry := range entry.StateEntries {
+			stateEntries[stateEntryIdx] = &pb.StateEntry{
+				Key:   stateEntry.Key,
+				Value: stateEntry.Value,
+			}
+		}
+
+		message.Entries[idx] = &pb.KeyValueStateEntry{
+			StateRoot: stateRoot,
+			Entries:   stateEntries,
+			Complete:  entry.Complete,
+		}
+	}
+
+	return proto.Marshal(message)
+}
+
 func (s *StateResponse) Decode(in []byte) error {
 	decodedResponse := &pb.StateResponse{}
 	err := proto.Unmarshal(in, decodedResponse)
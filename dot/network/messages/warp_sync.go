@@ -0,0 +1,175 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package messages
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// MaxWarpSyncProofSize is the maximum size in bytes of an encoded warp sync proof
+const MaxWarpSyncProofSize = 8 * 1024 * 1024
+
+var (
+	_ P2PMessage = (*WarpProofRequest)(nil)
+	_ P2PMessage = (*WarpSyncProof)(nil)
+)
+
+// WarpProofRequest is sent to a peer to request a warp sync proof
+// starting at the given finalised block hash
+type WarpProofRequest struct {
+	Begin common.Hash
+}
+
+// String returns the string representation of the request
+func (w *WarpProofRequest) String() string {
+	return fmt.Sprintf("WarpProofRequest Begin=%s", w.Begin)
+}
+
+// Encode returns the SCALE encoding of the request
+func (w *WarpProofRequest) Encode() ([]byte, error) {
+	return scale.Marshal(*w)
+}
+
+// Decode decodes the SCALE encoded request into w
+func (w *WarpProofRequest) Decode(in []byte) error {
+	return scale.Unmarshal(in, w)
+}
+
+// WarpSyncFragment is a header together with the GRANDPA justification
+// finalising it. Every fragment but the last one of a finished proof
+// contains an authority set change in its digest.
+type WarpSyncFragment struct {
+	Header types.Header
+	// Justification is the SCALE encoded GRANDPA justification for Header
+	Justification []byte
+}
+
+// WarpSyncProof is the response to a WarpProofRequest
+type WarpSyncProof struct {
+	Proofs []WarpSyncFragment
+	// IsFinished is true if the last fragment is the latest finalised block of the responder
+	IsFinished bool
+}
+
+// String returns the string representation of the proof
+func (w *WarpSyncProof) String() string {
+	if len(w.Proofs) == 0 {
+		return fmt.Sprintf("WarpSyncProof Fragments=0 IsFinished=%t", w.IsFinished)
+	}
+
+	last := w.Proofs[len(w.Proofs)-1].Header
+	return fmt.Sprintf("WarpSyncProof Fragments=%d Last=#%d (%s) IsFinished=%t",
+		len(w.Proofs), last.Number, last.Hash(), w.IsFinished)
+}
+
+// Encode returns the SCALE encoding of the proof. The justifications are
+// inlined rather than length prefixed, as expected by other implementations.
+func (w *WarpSyncProof) Encode() ([]byte, error) {
+	encodable := warpSyncProof{
+		Proofs:     make([]warpSyncFragment, len(w.Proofs)),
+		IsFinished: w.IsFinished,
+	}
+
+	for i, fragment := range w.Proofs {
+		justification, err := decodeWarpSyncJustification(fragment.Justification)
+		if err != nil {
+			return nil, fmt.Errorf("decoding justification of fragment %d: %w", i, err)
+		}
+
+		encodable.Proofs[i] = warpSyncFragment{
+			Header:        fragment.Header,
+			Justification: *justification,
+		}
+	}
+
+	return scale.Marshal(encodable)
+}
+
+// Decode decodes the SCALE encoded proof into w
+func (w *WarpSyncProof) Decode(in []byte) error {
+	var decoded warpSyncProof
+	err := scale.Unmarshal(in, &decoded)
+	if err != nil {
+		return err
+	}
+
+	w.IsFinished = decoded.IsFinished
+	w.Proofs = make([]WarpSyncFragment, len(decoded.Proofs))
+	for i, fragment := range decoded.Proofs {
+		justification, err := scale.Marshal(fragment.Justification)
+		if err != nil {
+			return fmt.Errorf("encoding justification of fragment %d: %w", i, err)
+		}
+
+		w.Proofs[i] = WarpSyncFragment{
+			Header:        fragment.Header,
+			Justification: justification,
+		}
+	}
+
+	return nil
+}
+
+type warpSyncProof struct {
+	Proofs     []warpSyncFragment
+	IsFinished bool
+}
+
+type warpSyncFragment struct {
+	Header        types.Header
+	Justification warpSyncJustification
+}
+
+// warpSyncJustification mirrors the SCALE layout of a GRANDPA justification
+type warpSyncJustification struct {
+	Round          uint64
+	Commit         warpSyncCommit
+	VoteAncestries []types.Header
+}
+
+type warpSyncCommit struct {
+	TargetHash   common.Hash
+	TargetNumber uint32
+	Precommits   []warpSyncSignedPrecommit
+}
+
+type warpSyncSignedPrecommit struct {
+	TargetHash   common.Hash
+	TargetNumber uint32
+	Signature    [64]byte
+	AuthorityID  [32]byte
+}
+
+// decodeWarpSyncJustification decodes a GRANDPA justification. Justifications
+// created by our own GRANDPA service do not carry vote ancestries, in which case
+// an empty ancestry list is assumed.
+func decodeWarpSyncJustification(encoded []byte) (*warpSyncJustification, error) {
+	justification := new(warpSyncJustification)
+	err := scale.Unmarshal(encoded, justification)
+	if err == nil {
+		return justification, nil
+	}
+
+	withoutAncestries := struct {
+		Round  uint64
+		Commit warpSyncCommit
+	}{}
+	if scale.Unmarshal(encoded, &withoutAncestries) != nil {
+		return nil, err
+	}
+
+	reencoded, encodeErr := scale.Marshal(withoutAncestries)
+	if encodeErr != nil || len(reencoded) != len(encoded) {
+		return nil, err
+	}
+
+	justification.Round = withoutAncestries.Round
+	justification.Commit = withoutAncestries.Commit
+	justification.VoteAncestries = nil
+	return justification, nil
+}
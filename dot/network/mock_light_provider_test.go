@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ChainSafe/gossamer/dot/network (interfaces: LightProvider)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock_light_provider_test.go -package network . LightProvider
+//
+
+// Package network is a generated GoMock package.
+package network
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLightProvider is a mock of LightProvider interface.
+type MockLightProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockLightProviderMockRecorder
+}
+
+// MockLightProviderMockRecorder is the mock recorder for MockLightProvider.
+type MockLightProviderMockRecorder struct {
+	mock *MockLightProvider
+}
+
+// NewMockLightProvider creates a new mock instance.
+func NewMockLightProvider(ctrl *gomock.Controller) *MockLightProvider {
+	mock := &MockLightProvider{ctrl: ctrl}
+	mock.recorder = &MockLightProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLightProvider) EXPECT() *MockLightProviderMockRecorder {
+	return m.recorder
+}
+
+// CreateRemoteCallResponse mocks base method.
+func (m *MockLightProvider) CreateRemoteCallResponse(arg0 *RemoteCallRequest) (*RemoteCallResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRemoteCallResponse", arg0)
+	ret0, _ := ret[0].(*RemoteCallResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRemoteCallResponse indicates an expected call of CreateRemoteCallResponse.
+func (mr *MockLightProviderMockRecorder) CreateRemoteCallResponse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteCallResponse", reflect.TypeOf((*MockLightProvider)(nil).CreateRemoteCallResponse), arg0)
+}
+
+// CreateRemoteHeaderResponse mocks base method.
+func (m *MockLightProvider) CreateRemoteHeaderResponse(arg0 *RemoteHeaderRequest) (*RemoteHeaderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRemoteHeaderResponse", arg0)
+	ret0, _ := ret[0].(*RemoteHeaderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRemoteHeaderResponse indicates an expected call of CreateRemoteHeaderResponse.
+func (mr *MockLightProviderMockRecorder) CreateRemoteHeaderResponse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteHeaderResponse", reflect.TypeOf((*MockLightProvider)(nil).CreateRemoteHeaderResponse), arg0)
+}
+
+// CreateRemoteReadChildResponse mocks base method.
+func (m *MockLightProvider) CreateRemoteReadChildResponse(arg0 *RemoteReadChildRequest) (*RemoteReadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRemoteReadChildResponse", arg0)
+	ret0, _ := ret[0].(*RemoteReadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRemoteReadChildResponse indicates an expected call of CreateRemoteReadChildResponse.
+func (mr *MockLightProviderMockRecorder) CreateRemoteReadChildResponse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteReadChildResponse", reflect.TypeOf((*MockLightProvider)(nil).CreateRemoteReadChildResponse), arg0)
+}
+
+// CreateRemoteReadResponse mocks base method.
+func (m *MockLightProvider) CreateRemoteReadResponse(arg0 *RemoteReadRequest) (*RemoteReadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRemoteReadResponse", arg0)
+	ret0, _ := ret[0].(*RemoteReadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRemoteReadResponse indicates an expected call of CreateRemoteReadResponse.
+func (mr *MockLightProviderMockRecorder) CreateRemoteReadResponse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteReadResponse", reflect.TypeOf((*MockLightProvider)(nil).CreateRemoteReadResponse), arg0)
+}
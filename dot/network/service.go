@@ -33,6 +33,8 @@
 
 	// the following are sub-protocols used by the node
 	SyncID          = "/sync/2"
+	WarpSyncID      = "/sync/warp"
+	StateSyncID     = "/state/2"
 	lightID         = "/light/2"
 	blockAnnounceID = "/block-announces/1"
 	transactionsID  = "/transactions/1"
@@ -129,6 +131,9 @@ type Service struct {
 	blockState         BlockState
 	syncer             Syncer
 	transactionHandler TransactionHandler
+	warpSyncProvider   WarpSyncProvider
+	stateSyncProvider  StateSyncProvider
+	lightProvider      LightProvider
 
 	// Configuration options
 	noBootstrap bool
@@ -238,6 +243,21 @@ func (s *Service) SetTransactionHandler(handler TransactionHandler) {
 	s.transactionHandler = handler
 }
 
+// SetWarpSyncProvider sets the WarpSyncProvider used to answer warp proof requests
+func (s *Service) SetWarpSyncProvider(provider WarpSyncProvider) {
+	s.warpSyncProvider = provider
+}
+
+// SetStateSyncProvider sets the StateSyncProvider used to answer state requests
+func (s *Service) SetStateSyncProvider(provider StateSyncProvider) {
+	s.stateSyncProvider = provider
+}
+
+// SetLightProvider sets the LightProvider used to answer light client requests
+func (s *Service) SetLightProvider(provider LightProvider) {
+	s.lightProvider = provider
+}
+
 // Start starts the network service
 func (s *Service) Start() error {
 	if s.syncer == nil {
@@ -253,6 +273,8 @@ func (s *Service) Start() error {
 	}
 
 	s.host.registerStreamHandler(s.host.protocolID+SyncID, s.handleSyncStream)
+	s.host.registerStreamHandler(s.host.protocolID+WarpSyncID, s.handleWarpSyncStream)
+	s.host.registerStreamHandler(s.host.protocolID+StateSyncID, s.handleStateSyncStream)
 	s.host.registerStreamHandler(s.host.protocolID+lightID, s.handleLightStream)
 
 	// register block announce protocol
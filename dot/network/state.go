@@ -36,6 +36,31 @@ type Syncer interface {
 	CreateBlockResponse(peer.ID, *messages.BlockRequestMessage) (*messages.BlockResponseMessage, error)
 }
 
+// WarpSyncProvider is implemented by the service creating warp sync proofs
+type WarpSyncProvider interface {
+	// CreateWarpSyncProof is called upon receipt of a WarpProofRequest to create the response
+	CreateWarpSyncProof(begin common.Hash) (*messages.WarpSyncProof, error)
+}
+
+// StateSyncProvider is implemented by the service serving state requests
+type StateSyncProvider interface {
+	// CreateStateResponse is called upon receipt of a StateRequest to create the response
+	CreateStateResponse(*messages.StateRequest) (*messages.StateResponse, error)
+}
+
+// LightProvider is implemented by the service answering light client requests
+type LightProvider interface {
+	// CreateRemoteCallResponse executes the requested runtime call and returns the
+	// proof of the storage read during its execution
+	CreateRemoteCallResponse(*RemoteCallRequest) (*RemoteCallResponse, error)
+	// CreateRemoteReadResponse returns the proof of the requested storage keys
+	CreateRemoteReadResponse(*RemoteReadRequest) (*RemoteReadResponse, error)
+	// CreateRemoteReadChildResponse returns the proof of the requested child storage keys
+	CreateRemoteReadChildResponse(*RemoteReadChildRequest) (*RemoteReadResponse, error)
+	// CreateRemoteHeaderResponse returns the requested header along with its proof
+	CreateRemoteHeaderResponse(*RemoteHeaderRequest) (*RemoteHeaderResponse, error)
+}
+
 // TransactionHandler is the interface used by the transactions sub-protocol
 type TransactionHandler interface {
 	HandleTransactionMessage(peer.ID, *TransactionMessage) (bool, error)
@@ -0,0 +1,64 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// handleStateSyncStream handles streams with the <protocol-id>/state/2 protocol ID
+func (s *Service) handleStateSyncStream(stream libp2pnetwork.Stream) {
+	if stream == nil {
+		return
+	}
+
+	s.readStream(stream, decodeStateSyncMessage, s.handleStateSyncMessage, MaxBlockResponseSize)
+}
+
+func decodeStateSyncMessage(in []byte, _ peer.ID, _ bool) (messages.P2PMessage, error) {
+	msg := new(messages.StateRequest)
+	err := msg.Decode(in)
+	return msg, err
+}
+
+// handleStateSyncMessage handles inbound state sync streams, the only messages
+// we should receive over an inbound stream are StateRequests
+func (s *Service) handleStateSyncMessage(stream libp2pnetwork.Stream, msg messages.P2PMessage) error {
+	if msg == nil {
+		return nil
+	}
+
+	defer func() {
+		err := stream.Close()
+		if err != nil && err.Error() != ErrStreamReset.Error() {
+			logger.Warnf("failed to close stream: %s", err)
+		}
+	}()
+
+	req, ok := msg.(*messages.StateRequest)
+	if !ok {
+		return nil
+	}
+
+	if s.stateSyncProvider == nil {
+		logger.Debugf("ignoring state request from peer %s: state sync provider not set",
+			stream.Conn().RemotePeer())
+		return nil
+	}
+
+	resp, err := s.stateSyncProvider.CreateStateResponse(req)
+	if err != nil {
+		logger.Debugf("cannot create response for request %s: %s", req, err)
+		return nil
+	}
+
+	if err = s.host.writeToStream(stream, resp); err != nil {
+		logger.Debugf("failed to send StateResponse message to peer %s: %s", stream.Conn().RemotePeer(), err)
+		return err
+	}
+
+	return nil
+}
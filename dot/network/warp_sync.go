@@ -0,0 +1,64 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// handleWarpSyncStream handles streams with the <protocol-id>/sync/warp protocol ID
+func (s *Service) handleWarpSyncStream(stream libp2pnetwork.Stream) {
+	if stream == nil {
+		return
+	}
+
+	s.readStream(stream, decodeWarpSyncMessage, s.handleWarpSyncMessage, MaxBlockResponseSize)
+}
+
+func decodeWarpSyncMessage(in []byte, _ peer.ID, _ bool) (messages.P2PMessage, error) {
+	msg := new(messages.WarpProofRequest)
+	err := msg.Decode(in)
+	return msg, err
+}
+
+// handleWarpSyncMessage handles inbound warp sync streams, the only messages
+// we should receive over an inbound stream are WarpProofRequests
+func (s *Service) handleWarpSyncMessage(stream libp2pnetwork.Stream, msg messages.P2PMessage) error {
+	if msg == nil {
+		return nil
+	}
+
+	defer func() {
+		err := stream.Close()
+		if err != nil && err.Error() != ErrStreamReset.Error() {
+			logger.Warnf("failed to close stream: %s", err)
+		}
+	}()
+
+	req, ok := msg.(*messages.WarpProofRequest)
+	if !ok {
+		return nil
+	}
+
+	if s.warpSyncProvider == nil {
+		logger.Debugf("ignoring warp proof request from peer %s: warp sync provider not set",
+			stream.Conn().RemotePeer())
+		return nil
+	}
+
+	proof, err := s.warpSyncProvider.CreateWarpSyncProof(req.Begin)
+	if err != nil {
+		logger.Debugf("cannot create warp sync proof for request %s: %s", req, err)
+		return nil
+	}
+
+	if err = s.host.writeToStream(stream, proof); err != nil {
+		logger.Debugf("failed to send WarpSyncProof message to peer %s: %s", stream.Conn().RemotePeer(), err)
+		return err
+	}
+
+	return nil
+}
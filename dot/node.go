@@ -18,6 +18,7 @@
 	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/digest"
 	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/offchain"
 	"github.com/ChainSafe/gossamer/dot/rpc"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/state/pruner"
@@ -28,6 +29,7 @@
 	"github.com/ChainSafe/gossamer/internal/database"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/internal/metrics"
+	"github.com/ChainSafe/gossamer/lib/aura"
 	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
@@ -56,17 +58,22 @@ type nodeBuilderIface interface {
 	createRuntimeStorage(st *state.Service) (*runtime.NodeStorage, error)
 	loadRuntime(config *cfg.Config, ns *runtime.NodeStorage, stateSrvc *state.Service, ks *keystore.GlobalKeystore,
 		net *network.Service) error
-	createBlockVerifier(st *state.Service) *babe.VerificationManager
+	createBlockVerifier(st *state.Service) (dotsync.BabeVerifier, error)
 	createDigestHandler(st *state.Service) (*digest.Handler, error)
+	createOffchainWorkerManager(config *cfg.Config, st *state.Service, ks *keystore.GlobalKeystore,
+		ns *runtime.NodeStorage, net *network.Service, cs *core.Service, syncer *dotsync.Service,
+	) (*offchain.Manager, error)
 	createCoreService(config *cfg.Config, ks *keystore.GlobalKeystore, st *state.Service, net *network.Service,
 	) (*core.Service, error)
 	createGRANDPAService(config *cfg.Config, st *state.Service, ks KeyStore,
 		net *network.Service, telemetryMailer Telemetry) (*grandpa.Service, error)
 	newSyncService(config *cfg.Config, st *state.Service, finalityGadget dotsync.FinalityGadget,
-		verifier *babe.VerificationManager, cs *core.Service, net *network.Service,
+		verifier dotsync.BabeVerifier, cs *core.Service, net *network.Service,
 		telemetryMailer Telemetry) (*dotsync.Service, error)
 	createBABEService(config *cfg.Config, st *state.Service, ks KeyStore, cs *core.Service,
 		telemetryMailer Telemetry) (service *babe.Service, err error)
+	createAuraService(config *cfg.Config, st *state.Service, ks KeyStore, cs *core.Service,
+	) (*aura.Service, error)
 	createSystemService(cfg *types.SystemInfo, stateSrvc *state.Service) (*system.Service, error)
 	createRPCService(params rpcServiceSettings) (*rpc.HTTPServer, error)
 }
@@ -353,7 +360,10 @@ func newNode(config *cfg.Config,
 		return nil, err
 	}
 
-	ver := builder.createBlockVerifier(stateSrvc)
+	ver, err := builder.createBlockVerifier(stateSrvc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block verifier: %s", err)
+	}
 
 	dh, err := builder.createDigestHandler(stateSrvc)
 	if err != nil {
@@ -380,15 +390,38 @@ func newNode(config *cfg.Config,
 
 	if networkSrvc != nil {
 		networkSrvc.SetSyncer(syncer)
+		networkSrvc.SetWarpSyncProvider(syncer)
+		networkSrvc.SetStateSyncProvider(syncer)
+		networkSrvc.SetLightProvider(syncer)
 		networkSrvc.SetTransactionHandler(coreSrvc)
 	}
 	nodeSrvcs = append(nodeSrvcs, syncer)
 
-	bp, err := builder.createBABEService(config, stateSrvc, ks.Babe, coreSrvc, telemetryMailer)
+	offchainWorkerManager, err := builder.createOffchainWorkerManager(config, stateSrvc, ks, ns, networkSrvc,
+		coreSrvc, syncer)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create offchain worker manager: %s", err)
+	}
+	if offchainWorkerManager != nil {
+		nodeSrvcs = append(nodeSrvcs, offchainWorkerManager)
+	}
+
+	var bp BlockProducer
+	if gd.ConsensusEngine == genesis.AuraConsensusEngine {
+		auraSrvc, err := builder.createAuraService(config, stateSrvc, ks.Aura, coreSrvc)
+		if err != nil {
+			return nil, err
+		}
+		nodeSrvcs = append(nodeSrvcs, auraSrvc)
+		bp = auraSrvc
+	} else {
+		babeSrvc, err := builder.createBABEService(config, stateSrvc, ks.Babe, coreSrvc, telemetryMailer)
+		if err != nil {
+			return nil, err
+		}
+		nodeSrvcs = append(nodeSrvcs, babeSrvc)
+		bp = babeSrvc
 	}
-	nodeSrvcs = append(nodeSrvcs, bp)
 
 	// check if rpc service is enabled
 	if enabled := config.RPC.IsRPCEnabled() || config.RPC.IsWSEnabled(); enabled {
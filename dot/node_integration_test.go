@@ -126,7 +126,7 @@ func TestNewNode(t *testing.T) {
 	m.EXPECT().loadRuntime(initConfig, &runtime.NodeStorage{}, gomock.AssignableToTypeOf(&state.Service{}),
 		ks, gomock.AssignableToTypeOf(&network.Service{})).Return(nil)
 	m.EXPECT().createBlockVerifier(gomock.AssignableToTypeOf(&state.Service{})).
-		Return(&babe.VerificationManager{})
+		Return(&babe.VerificationManager{}, nil)
 	m.EXPECT().createDigestHandler(gomock.AssignableToTypeOf(&state.Service{})).
 		Return(&digest.Handler{}, nil)
 	m.EXPECT().createCoreService(initConfig, ks, gomock.AssignableToTypeOf(&state.Service{}),
@@ -140,6 +140,10 @@ func TestNewNode(t *testing.T) {
 		&babe.VerificationManager{}, &core.Service{}, gomock.AssignableToTypeOf(&network.Service{}),
 		gomock.AssignableToTypeOf(&telemetry.Mailer{})).
 		Return(&dotsync.Service{}, nil)
+	m.EXPECT().createOffchainWorkerManager(initConfig, gomock.AssignableToTypeOf(&state.Service{}), ks,
+		&runtime.NodeStorage{}, gomock.AssignableToTypeOf(&network.Service{}), &core.Service{},
+		&dotsync.Service{}).
+		Return(nil, nil)
 	m.EXPECT().createBABEService(initConfig, gomock.AssignableToTypeOf(&state.Service{}), ks.Babe,
 		&core.Service{}, gomock.AssignableToTypeOf(&telemetry.Mailer{})).
 		Return(&babe.Service{}, nil)
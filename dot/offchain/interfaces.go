@@ -0,0 +1,35 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package offchain
+
+import (
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+)
+
+// BlockState interface for block state methods
+type BlockState interface {
+	BestBlockHash() common.Hash
+	GetImportedBlockNotifierChannel() chan *types.Block
+	FreeImportedBlockNotifierChannel(ch chan *types.Block)
+}
+
+// StorageState interface for storage state methods
+type StorageState interface {
+	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
+}
+
+// Syncer is the interface to check if the node is synced with its peers
+type Syncer interface {
+	IsSynced() bool
+}
+
+// Instance is the runtime instance running the offchain workers
+type Instance interface {
+	SetContextStorage(s runtime.Storage)
+	OffchainWorker(header *types.Header) error
+	Stop()
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package offchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
+)
+
+var logger = log.NewFromGlobal(log.AddContext("pkg", "offchain"))
+
+// Config is the configuration of the offchain worker Manager
+type Config struct {
+	BlockState           BlockState
+	StorageState         StorageState
+	Syncer               Syncer
+	Keystore             *keystore.GlobalKeystore
+	NodeStorage          runtime.NodeStorage
+	Network              runtime.BasicNetwork
+	TransactionSubmitter runtime.TransactionSubmitter
+	Role                 common.NetworkRole
+	// LogLvl is the log level of the runtime instances running the offchain workers.
+	LogLvl log.Level
+	// Timeout is the maximum duration of the offchain worker of a block.
+	Timeout time.Duration
+	// CompilationCacheDir is the directory where the compiled runtime code is persisted.
+	CompilationCacheDir string
+}
+
+// Manager runs the runtime offchain worker of each new best block imported
+// once the node is synced. Offchain workers run one at a time in a runtime instance
+// of the manager, on top of the state of their block, and the storage changes they
+// make are discarded. The instance is reused until the runtime code changes or an
+// offchain worker fails.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	blockState   BlockState
+	storageState StorageState
+	syncer       Syncer
+	imported     chan *types.Block
+
+	// workerRunning is true while an offchain worker runs, and the offchain
+	// workers of the blocks imported meanwhile are skipped.
+	workerRunning atomic.Bool
+	// instance is the runtime instance running the offchain workers, created
+	// from the runtime code with the hash codeHash. It is only accessed by the
+	// running offchain worker.
+	instance Instance
+	codeHash common.Hash
+
+	// newInstance creates the runtime instance running the offchain workers
+	// from the runtime code of the given state, which has the given hash.
+	newInstance func(trieState *rtstorage.TrieState, codeHash common.Hash) (Instance, error)
+}
+
+// NewManager returns a new offchain worker Manager
+func NewManager(cfg Config) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:          ctx,
+		cancel:       cancel,
+		blockState:   cfg.BlockState,
+		storageState: cfg.StorageState,
+		syncer:       cfg.Syncer,
+		imported:     cfg.BlockState.GetImportedBlockNotifierChannel(),
+		newInstance: func(trieState *rtstorage.TrieState, codeHash common.Hash) (Instance, error) {
+			return wazero_runtime.NewInstance(trieState.LoadCode(), wazero_runtime.Config{
+				Storage:              trieState,
+				Keystore:             cfg.Keystore,
+				LogLvl:               cfg.LogLvl,
+				Role:                 cfg.Role,
+				NodeStorage:          cfg.NodeStorage,
+				Network:              cfg.Network,
+				TransactionSubmitter: cfg.TransactionSubmitter,
+				CodeHash:             codeHash,
+				Timeout:              cfg.Timeout,
+				CompilationCacheDir:  cfg.CompilationCacheDir,
+			})
+		},
+	}
+}
+
+// Start starts the Manager
+func (m *Manager) Start() error {
+	m.wg.Add(1)
+	go m.handleImportedBlocks()
+	return nil
+}
+
+// Stop stops the Manager and waits for the running offchain workers to return
+func (m *Manager) Stop() error {
+	m.cancel()
+	m.wg.Wait()
+	m.blockState.FreeImportedBlockNotifierChannel(m.imported)
+
+	if m.instance != nil {
+		m.instance.Stop()
+		m.instance = nil
+	}
+	return nil
+}
+
+func (m *Manager) handleImportedBlocks() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case block, ok := <-m.imported:
+			if !ok {
+				return
+			}
+
+			if block == nil {
+				continue
+			}
+
+			m.handleImportedBlock(&block.Header)
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// handleImportedBlock starts the offchain worker of the given block if it is the
+// best block and the node is synced, so workers are not run for each block of the
+// initial sync or for blocks of forks. The worker is skipped if the worker of a
+// previous block is still running.
+func (m *Manager) handleImportedBlock(header *types.Header) {
+	if !m.syncer.IsSynced() {
+		return
+	}
+
+	hash := header.Hash()
+	if hash != m.blockState.BestBlockHash() {
+		return
+	}
+
+	if !m.workerRunning.CompareAndSwap(false, true) {
+		logger.Debugf("skipping offchain worker for block #%d (%s): previous offchain worker still running",
+			header.Number, hash)
+		return
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.workerRunning.Store(false)
+
+		err := m.runOffchainWorker(header)
+		if err != nil {
+			logger.Errorf("running offchain worker for block #%d (%s): %s", header.Number, hash, err)
+		}
+	}()
+}
+
+func (m *Manager) runOffchainWorker(header *types.Header) error {
+	trieState, err := m.storageState.TrieState(&header.StateRoot)
+	if err != nil {
+		return fmt.Errorf("getting trie state: %w", err)
+	}
+
+	codeHash, err := trieState.LoadCodeHash()
+	if err != nil {
+		return fmt.Errorf("loading runtime code hash: %w", err)
+	}
+
+	if m.instance == nil || codeHash != m.codeHash {
+		if m.instance != nil {
+			m.instance.Stop()
+			m.instance = nil
+		}
+
+		instance, err := m.newInstance(trieState, codeHash)
+		if err != nil {
+			return fmt.Errorf("creating runtime instance: %w", err)
+		}
+		m.instance, m.codeHash = instance, codeHash
+	}
+
+	m.instance.SetContextStorage(trieState)
+
+	start := time.Now()
+	err = m.instance.OffchainWorker(header)
+	if errors.Is(err, wazero_runtime.ErrExportFunctionNotFound) {
+		logger.Debugf("runtime of block #%d does not export the offchain worker API", header.Number)
+		return nil
+	} else if err != nil {
+		// the instance may be unusable, for example a timeout closes its module,
+		// so it is dropped and the offchain worker of the next block creates a new one.
+		m.instance.Stop()
+		m.instance = nil
+		return err
+	}
+
+	logger.Debugf("offchain worker for block #%d ran in %s", header.Number, time.Since(start))
+	return nil
+}
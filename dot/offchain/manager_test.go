@@ -0,0 +1,426 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package offchain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Manager_handleImportedBlock(t *testing.T) {
+	t.Parallel()
+
+	header := &types.Header{
+		Number:    1,
+		StateRoot: common.Hash{1},
+	}
+
+	testCases := map[string]struct {
+		synced        bool
+		bestBlock     common.Hash
+		workerRunning bool
+		runWorker     bool
+	}{
+		"not_synced": {},
+		"not_best_block": {
+			synced:    true,
+			bestBlock: common.Hash{2},
+		},
+		"previous_worker_running": {
+			synced:        true,
+			bestBlock:     header.Hash(),
+			workerRunning: true,
+		},
+		"best_block": {
+			synced:    true,
+			bestBlock: header.Hash(),
+			runWorker: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			syncer := NewMockSyncer(ctrl)
+			syncer.EXPECT().IsSynced().Return(testCase.synced)
+			blockState := NewMockBlockState(ctrl)
+			if testCase.synced {
+				blockState.EXPECT().BestBlockHash().Return(testCase.bestBlock)
+			}
+
+			storageState := NewMockStorageState(ctrl)
+			instance := NewMockInstance(ctrl)
+			if testCase.runWorker {
+				trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+				storageState.EXPECT().TrieState(&header.StateRoot).Return(trieState, nil)
+				instance.EXPECT().SetContextStorage(trieState)
+				instance.EXPECT().OffchainWorker(header).Return(nil)
+			}
+
+			manager := &Manager{
+				blockState:   blockState,
+				storageState: storageState,
+				syncer:       syncer,
+				newInstance: func(*rtstorage.TrieState, common.Hash) (Instance, error) {
+					return instance, nil
+				},
+			}
+			manager.workerRunning.Store(testCase.workerRunning)
+
+			manager.handleImportedBlock(header)
+			manager.wg.Wait()
+			assert.Equal(t, testCase.workerRunning, manager.workerRunning.Load())
+		})
+	}
+}
+
+func Test_Manager_runOffchainWorker(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	header := &types.Header{
+		Number:    1,
+		StateRoot: common.Hash{1},
+	}
+	emptyCodeHash, err := common.Blake2bHash(nil)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		trieStateErr   error
+		newInstanceErr error
+		workerErr      error
+		instanceKept   bool
+		errWrapped     error
+		errMessage     string
+	}{
+		"trie_state_error": {
+			trieStateErr: errTest,
+			errWrapped:   errTest,
+			errMessage:   "getting trie state: test error",
+		},
+		"new_instance_error": {
+			newInstanceErr: errTest,
+			errWrapped:     errTest,
+			errMessage:     "creating runtime instance: test error",
+		},
+		"offchain_worker_error": {
+			workerErr:  errTest,
+			errWrapped: errTest,
+			errMessage: "test error",
+		},
+		"offchain_worker_api_not_exported": {
+			workerErr:    wazero_runtime.ErrExportFunctionNotFound,
+			instanceKept: true,
+		},
+		"success": {
+			instanceKept: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+			storageState := NewMockStorageState(ctrl)
+			storageState.EXPECT().TrieState(&header.StateRoot).Return(trieState, testCase.trieStateErr)
+
+			instance := NewMockInstance(ctrl)
+			if testCase.trieStateErr == nil && testCase.newInstanceErr == nil {
+				instance.EXPECT().SetContextStorage(trieState)
+				instance.EXPECT().OffchainWorker(header).Return(testCase.workerErr)
+				if !testCase.instanceKept {
+					instance.EXPECT().Stop()
+				}
+			}
+
+			manager := &Manager{
+				storageState: storageState,
+				newInstance: func(ts *rtstorage.TrieState, codeHash common.Hash) (Instance, error) {
+					assert.Same(t, trieState, ts)
+					assert.Equal(t, emptyCodeHash, codeHash)
+					if testCase.newInstanceErr != nil {
+						return nil, testCase.newInstanceErr
+					}
+					return instance, nil
+				},
+			}
+
+			err := manager.runOffchainWorker(header)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			if testCase.instanceKept {
+				assert.Same(t, instance, manager.instance)
+			} else {
+				assert.Nil(t, manager.instance)
+			}
+		})
+	}
+}
+
+func Test_Manager_StartStop(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	header := &types.Header{
+		Number:    1,
+		StateRoot: common.Hash{1},
+	}
+
+	imported := make(chan *types.Block)
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetImportedBlockNotifierChannel().Return(imported)
+	blockState.EXPECT().BestBlockHash().Return(header.Hash())
+	blockState.EXPECT().FreeImportedBlockNotifierChannel(imported)
+
+	syncer := NewMockSyncer(ctrl)
+	syncer.EXPECT().IsSynced().Return(true)
+
+	trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+	storageState := NewMockStorageState(ctrl)
+	storageState.EXPECT().TrieState(&header.StateRoot).Return(trieState, nil)
+
+	workerDone := make(chan struct{})
+	instance := NewMockInstance(ctrl)
+	instance.EXPECT().SetContextStorage(trieState)
+	instance.EXPECT().OffchainWorker(header).DoAndReturn(func(*types.Header) error {
+		close(workerDone)
+		return nil
+	})
+	instance.EXPECT().Stop()
+
+	manager := NewManager(Config{
+		BlockState:   blockState,
+		StorageState: storageState,
+		Syncer:       syncer,
+	})
+	manager.newInstance = func(*rtstorage.TrieState, common.Hash) (Instance, error) {
+		return instance, nil
+	}
+
+	err := manager.Start()
+	require.NoError(t, err)
+
+	imported <- &types.Block{Header: *header}
+	<-workerDone
+
+	err = manager.Stop()
+	require.NoError(t, err)
+}
+
+func Test_Manager_runOffchainWorker_instanceReuse(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	header := &types.Header{
+		Number:    1,
+		StateRoot: common.Hash{1},
+	}
+	upgradedHeader := &types.Header{
+		Number:    2,
+		StateRoot: common.Hash{2},
+	}
+
+	trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+	upgradedTrie := inmemory.NewEmptyTrie()
+	require.NoError(t, upgradedTrie.Put(common.CodeKey, []byte{1}))
+	upgradedTrieState := rtstorage.NewTrieState(upgradedTrie)
+
+	storageState := NewMockStorageState(ctrl)
+	storageState.EXPECT().TrieState(&header.StateRoot).Return(trieState, nil).Times(2)
+	storageState.EXPECT().TrieState(&upgradedHeader.StateRoot).Return(upgradedTrieState, nil)
+
+	instance := NewMockInstance(ctrl)
+	instance.EXPECT().SetContextStorage(trieState).Times(2)
+	instance.EXPECT().OffchainWorker(header).Return(nil).Times(2)
+	instance.EXPECT().Stop()
+
+	upgradedInstance := NewMockInstance(ctrl)
+	upgradedInstance.EXPECT().SetContextStorage(upgradedTrieState)
+	upgradedInstance.EXPECT().OffchainWorker(upgradedHeader).Return(nil)
+
+	instances := []Instance{instance, upgradedInstance}
+	manager := &Manager{
+		storageState: storageState,
+		newInstance: func(*rtstorage.TrieState, common.Hash) (Instance, error) {
+			instance := instances[0]
+			instances = instances[1:]
+			return instance, nil
+		},
+	}
+
+	// the instance is reused until the runtime code changes
+	err := manager.runOffchainWorker(header)
+	require.NoError(t, err)
+	err = manager.runOffchainWorker(header)
+	require.NoError(t, err)
+
+	err = manager.runOffchainWorker(upgradedHeader)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+	assert.Same(t, upgradedInstance, manager.instance)
+}
+
+func Test_Manager_runOffchainWorker_instanceRecreatedAfterError(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	errTest := errors.New("test error")
+	header := &types.Header{
+		Number:    1,
+		StateRoot: common.Hash{1},
+	}
+
+	trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+	storageState := NewMockStorageState(ctrl)
+	storageState.EXPECT().TrieState(&header.StateRoot).Return(trieState, nil).Times(2)
+
+	failedInstance := NewMockInstance(ctrl)
+	failedInstance.EXPECT().SetContextStorage(trieState)
+	failedInstance.EXPECT().OffchainWorker(header).Return(errTest)
+	failedInstance.EXPECT().Stop()
+
+	newInstance := NewMockInstance(ctrl)
+	newInstance.EXPECT().SetContextStorage(trieState)
+	newInstance.EXPECT().OffchainWorker(header).Return(nil)
+
+	instances := []Instance{failedInstance, newInstance}
+	manager := &Manager{
+		storageState: storageState,
+		newInstance: func(*rtstorage.TrieState, common.Hash) (Instance, error) {
+			instance := instances[0]
+			instances = instances[1:]
+			return instance, nil
+		},
+	}
+
+	// the instance may be unusable after an error, so it is not reused
+	err := manager.runOffchainWorker(header)
+	require.ErrorIs(t, err, errTest)
+	assert.Nil(t, manager.instance)
+
+	err = manager.runOffchainWorker(header)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+	assert.Same(t, newInstance, manager.instance)
+}
+
+func Test_Manager_hangingHTTPResponse(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(unblock)
+		server.Close()
+	})
+
+	header := &types.Header{
+		Number:    1,
+		StateRoot: common.Hash{1},
+	}
+	nextHeader := &types.Header{
+		Number:    2,
+		StateRoot: common.Hash{2},
+	}
+
+	imported := make(chan *types.Block)
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetImportedBlockNotifierChannel().Return(imported)
+	blockState.EXPECT().BestBlockHash().Return(header.Hash())
+	blockState.EXPECT().BestBlockHash().Return(nextHeader.Hash())
+	blockState.EXPECT().FreeImportedBlockNotifierChannel(imported)
+
+	syncer := NewMockSyncer(ctrl)
+	syncer.EXPECT().IsSynced().Return(true).Times(2)
+
+	trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+	storageState := NewMockStorageState(ctrl)
+	storageState.EXPECT().TrieState(&header.StateRoot).Return(trieState, nil)
+	storageState.EXPECT().TrieState(&nextHeader.StateRoot).Return(trieState, nil)
+
+	// the offchain worker waits for a response which never comes, without deadline,
+	// and the wait is only bounded by the timeout of the runtime call.
+	const timeout = 100 * time.Millisecond
+	hangingInstance := NewMockInstance(ctrl)
+	hangingInstance.EXPECT().SetContextStorage(trieState)
+	workerStarted := make(chan struct{})
+	hangingInstance.EXPECT().OffchainWorker(header).DoAndReturn(func(*types.Header) error {
+		close(workerStarted)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		httpSet := offchain.NewHTTPSet()
+		id, err := httpSet.StartRequest(http.MethodGet, server.URL)
+		require.NoError(t, err)
+		statuses := httpSet.Wait(ctx, []int16{id}, nil)
+		assert.Equal(t, []offchain.HTTPRequestStatus{{Err: offchain.ErrDeadlineReached}}, statuses)
+		return ctx.Err()
+	})
+	hangingInstance.EXPECT().Stop()
+
+	nextWorkerDone := make(chan struct{})
+	nextInstance := NewMockInstance(ctrl)
+	nextInstance.EXPECT().SetContextStorage(trieState)
+	nextInstance.EXPECT().OffchainWorker(nextHeader).DoAndReturn(func(*types.Header) error {
+		close(nextWorkerDone)
+		return nil
+	})
+	nextInstance.EXPECT().Stop()
+
+	manager := NewManager(Config{
+		BlockState:   blockState,
+		StorageState: storageState,
+		Syncer:       syncer,
+	})
+	instances := []Instance{hangingInstance, nextInstance}
+	manager.newInstance = func(*rtstorage.TrieState, common.Hash) (Instance, error) {
+		instance := instances[0]
+		instances = instances[1:]
+		return instance, nil
+	}
+
+	err := manager.Start()
+	require.NoError(t, err)
+
+	imported <- &types.Block{Header: *header}
+	<-workerStarted
+	assert.Eventually(t, func() bool {
+		return !manager.workerRunning.Load()
+	}, 10*time.Second, timeout/10)
+
+	imported <- &types.Block{Header: *nextHeader}
+	<-nextWorkerDone
+
+	err = manager.Stop()
+	require.NoError(t, err)
+}
@@ -0,0 +1,219 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ChainSafe/gossamer/dot/offchain (interfaces: BlockState,StorageState,Syncer,Instance)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks_test.go -package offchain . BlockState,StorageState,Syncer,Instance
+//
+
+// Package offchain is a generated GoMock package.
+package offchain
+
+import (
+	reflect "reflect"
+
+	types "github.com/ChainSafe/gossamer/dot/types"
+	common "github.com/ChainSafe/gossamer/lib/common"
+	runtime "github.com/ChainSafe/gossamer/lib/runtime"
+	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockState is a mock of BlockState interface.
+type MockBlockState struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockStateMockRecorder
+}
+
+// MockBlockStateMockRecorder is the mock recorder for MockBlockState.
+type MockBlockStateMockRecorder struct {
+	mock *MockBlockState
+}
+
+// NewMockBlockState creates a new mock instance.
+func NewMockBlockState(ctrl *gomock.Controller) *MockBlockState {
+	mock := &MockBlockState{ctrl: ctrl}
+	mock.recorder = &MockBlockStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockState) EXPECT() *MockBlockStateMockRecorder {
+	return m.recorder
+}
+
+// BestBlockHash mocks base method.
+func (m *MockBlockState) BestBlockHash() common.Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BestBlockHash")
+	ret0, _ := ret[0].(common.Hash)
+	return ret0
+}
+
+// BestBlockHash indicates an expected call of BestBlockHash.
+func (mr *MockBlockStateMockRecorder) BestBlockHash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BestBlockHash", reflect.TypeOf((*MockBlockState)(nil).BestBlockHash))
+}
+
+// FreeImportedBlockNotifierChannel mocks base method.
+func (m *MockBlockState) FreeImportedBlockNotifierChannel(arg0 chan *types.Block) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FreeImportedBlockNotifierChannel", arg0)
+}
+
+// FreeImportedBlockNotifierChannel indicates an expected call of FreeImportedBlockNotifierChannel.
+func (mr *MockBlockStateMockRecorder) FreeImportedBlockNotifierChannel(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeImportedBlockNotifierChannel", reflect.TypeOf((*MockBlockState)(nil).FreeImportedBlockNotifierChannel), arg0)
+}
+
+// GetImportedBlockNotifierChannel mocks base method.
+func (m *MockBlockState) GetImportedBlockNotifierChannel() chan *types.Block {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportedBlockNotifierChannel")
+	ret0, _ := ret[0].(chan *types.Block)
+	return ret0
+}
+
+// GetImportedBlockNotifierChannel indicates an expected call of GetImportedBlockNotifierChannel.
+func (mr *MockBlockStateMockRecorder) GetImportedBlockNotifierChannel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportedBlockNotifierChannel", reflect.TypeOf((*MockBlockState)(nil).GetImportedBlockNotifierChannel))
+}
+
+// MockStorageState is a mock of StorageState interface.
+type MockStorageState struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageStateMockRecorder
+}
+
+// MockStorageStateMockRecorder is the mock recorder for MockStorageState.
+type MockStorageStateMockRecorder struct {
+	mock *MockStorageState
+}
+
+// NewMockStorageState creates a new mock instance.
+func NewMockStorageState(ctrl *gomock.Controller) *MockStorageState {
+	mock := &MockStorageState{ctrl: ctrl}
+	mock.recorder = &MockStorageStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageState) EXPECT() *MockStorageStateMockRecorder {
+	return m.recorder
+}
+
+// TrieState mocks base method.
+func (m *MockStorageState) TrieState(arg0 *common.Hash) (*storage.TrieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrieState", arg0)
+	ret0, _ := ret[0].(*storage.TrieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrieState indicates an expected call of TrieState.
+func (mr *MockStorageStateMockRecorder) TrieState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrieState", reflect.TypeOf((*MockStorageState)(nil).TrieState), arg0)
+}
+
+// MockSyncer is a mock of Syncer interface.
+type MockSyncer struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncerMockRecorder
+}
+
+// MockSyncerMockRecorder is the mock recorder for MockSyncer.
+type MockSyncerMockRecorder struct {
+	mock *MockSyncer
+}
+
+// NewMockSyncer creates a new mock instance.
+func NewMockSyncer(ctrl *gomock.Controller) *MockSyncer {
+	mock := &MockSyncer{ctrl: ctrl}
+	mock.recorder = &MockSyncerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSyncer) EXPECT() *MockSyncerMockRecorder {
+	return m.recorder
+}
+
+// IsSynced mocks base method.
+func (m *MockSyncer) IsSynced() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSynced")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSynced indicates an expected call of IsSynced.
+func (mr *MockSyncerMockRecorder) IsSynced() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSynced", reflect.TypeOf((*MockSyncer)(nil).IsSynced))
+}
+
+// MockInstance is a mock of Instance interface.
+type MockInstance struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstanceMockRecorder
+}
+
+// MockInstanceMockRecorder is the mock recorder for MockInstance.
+type MockInstanceMockRecorder struct {
+	mock *MockInstance
+}
+
+// NewMockInstance creates a new mock instance.
+func NewMockInstance(ctrl *gomock.Controller) *MockInstance {
+	mock := &MockInstance{ctrl: ctrl}
+	mock.recorder = &MockInstanceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInstance) EXPECT() *MockInstanceMockRecorder {
+	return m.recorder
+}
+
+// OffchainWorker mocks base method.
+func (m *MockInstance) OffchainWorker(arg0 *types.Header) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OffchainWorker", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OffchainWorker indicates an expected call of OffchainWorker.
+func (mr *MockInstanceMockRecorder) OffchainWorker(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffchainWorker", reflect.TypeOf((*MockInstance)(nil).OffchainWorker), arg0)
+}
+
+// SetContextStorage mocks base method.
+func (m *MockInstance) SetContextStorage(arg0 runtime.Storage) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetContextStorage", arg0)
+}
+
+// SetContextStorage indicates an expected call of SetContextStorage.
+func (mr *MockInstanceMockRecorder) SetContextStorage(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContextStorage", reflect.TypeOf((*MockInstance)(nil).SetContextStorage), arg0)
+}
+
+// Stop mocks base method.
+func (m *MockInstance) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockInstanceMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockInstance)(nil).Stop))
+}
@@ -64,11 +64,13 @@ func (c *DotUpCodecRequest) Method() (string, error) {
 			m = concreteMethod
 		}
 
-		parts := strings.Split(m, "_")
-		if len(parts) < 2 {
+		// the method name follows the last underscore, so versioned services
+		// such as archive_v1 are kept whole
+		separator := strings.LastIndex(m, "_")
+		if separator == -1 {
 			return "", fmt.Errorf("rpc error method %s not found", m)
 		}
-		service, method := parts[0], parts[1]
+		service, method := m[:separator], m[separator+1:]
 		r, n := utf8.DecodeRuneInString(method) // get the first rune, and it's length
 		if unicode.IsLower(r) {
 			upMethod := service + "." + string(unicode.ToUpper(r)) + method[n:]
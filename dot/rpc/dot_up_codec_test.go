@@ -37,6 +37,13 @@
 		),
 		expected: "chain.GetBlockHash",
 	},
+	{
+		rpcDataBody: fmt.Sprintf(
+			`{"jsonrpc":"2.0","method":"%s","params":[],"id":1}`,
+			"archive_v1_finalizedHeight",
+		),
+		expected: "archive_v1.FinalizedHeight",
+	},
 }
 
 func TestAliasesMethodReplace(t *testing.T) {
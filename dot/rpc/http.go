@@ -52,6 +52,7 @@ type HTTPServerConfig struct {
 	WSUnsafeExternal    bool
 	WSPort              uint32
 	Modules             []string
+	ArchiveNode         bool
 }
 
 func (h *HTTPServerConfig) rpcUnsafeEnabled() bool {
@@ -118,6 +119,14 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 			srvc = modules.NewSyncStateModule(h.serverConfig.SyncStateAPI)
 		case "payment":
 			srvc = modules.NewPaymentModule(h.serverConfig.BlockAPI)
+		case "archive":
+			if !h.serverConfig.ArchiveNode {
+				h.logger.Warn("Not enabling rpc module archive: the node is not running in archive pruning mode")
+				continue
+			}
+			srvc = modules.NewArchiveModule(h.serverConfig.StorageAPI, h.serverConfig.BlockAPI)
+			// the archive methods are versioned, e.g. archive_v1_body
+			mod = "archive_v1"
 		default:
 			h.logger.Warn("Unrecognised module: " + mod)
 			continue
@@ -5,6 +5,7 @@
 
 import (
 	"encoding/json"
+	"sync"
 
 	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/state"
@@ -14,6 +15,7 @@
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 )
@@ -27,8 +29,11 @@ type StorageAPI interface {
 	Entries(root *common.Hash) (map[string][]byte, error)
 	GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error)
 	GetKeysWithPrefix(root *common.Hash, prefix []byte) ([][]byte, error)
+	GetClosestDescendantMerkleValue(root *common.Hash, key []byte) ([]byte, error)
+	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
 	RegisterStorageObserver(observer state.Observer)
 	UnregisterStorageObserver(observer state.Observer)
+	sync.Locker
 }
 
 // BlockAPI is the interface for the block state
@@ -37,6 +42,7 @@ type BlockAPI interface {
 	BestBlockHash() common.Hash
 	GetBlockByHash(hash common.Hash) (*types.Block, error)
 	GetHashByNumber(blockNumber uint) (common.Hash, error)
+	GetHashesByNumber(blockNumber uint) ([]common.Hash, error)
 	GetFinalisedHash(uint64, uint64) (common.Hash, error)
 	GetHighestFinalisedHash() (common.Hash, error)
 	HasJustification(hash common.Hash) (bool, error)
@@ -46,6 +52,7 @@ type BlockAPI interface {
 	GetFinalisedNotifierChannel() chan *types.FinalisationInfo
 	FreeFinalisedNotifierChannel(ch chan *types.FinalisationInfo)
 	RangeInMemory(start, end common.Hash) ([]common.Hash, error)
+	GetAllDescendants(hash common.Hash) ([]common.Hash, error)
 	RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error)
 	UnregisterRuntimeUpdatedChannel(id uint32) bool
 	GetRuntime(blockHash common.Hash) (runtime runtime.Instance, err error)
@@ -76,6 +83,7 @@ type BlockProducerAPI interface {
 type TransactionStateAPI interface {
 	AddToPool(*transaction.ValidTransaction) common.Hash
 	Pending() []*transaction.ValidTransaction
+	RemoveAndBan(hashes []common.Hash) []common.Hash
 	GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status
 	FreeStatusNotifierChannel(ch chan transaction.Status)
 }
@@ -88,7 +96,9 @@ type CoreAPI interface {
 	HandleSubmittedExtrinsic(types.Extrinsic) error
 	GetMetadata(bhash *common.Hash) ([]byte, error)
 	DecodeSessionKeys(enc []byte) ([]byte, error)
+	GenerateSessionKeys() ([]byte, error)
 	GetReadProofAt(block common.Hash, keys [][]byte) (common.Hash, [][]byte, error)
+	DryRun(ext types.Extrinsic, bhash *common.Hash) ([]byte, error)
 }
 
 // API is the interface for methods related to RPC service
@@ -143,3 +143,17 @@ func (mr *MockTransactionStateAPIMockRecorder) Pending() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockTransactionStateAPI)(nil).Pending))
 }
+
+// RemoveAndBan mocks base method.
+func (m *MockTransactionStateAPI) RemoveAndBan(arg0 []common.Hash) []common.Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAndBan", arg0)
+	ret0, _ := ret[0].([]common.Hash)
+	return ret0
+}
+
+// RemoveAndBan indicates an expected call of RemoveAndBan.
+func (mr *MockTransactionStateAPIMockRecorder) RemoveAndBan(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAndBan", reflect.TypeOf((*MockTransactionStateAPI)(nil).RemoveAndBan), arg0)
+}
@@ -0,0 +1,262 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// maxArchiveStorageItems is the maximum number of items returned by a single
+// archive_v1_storage call, the remaining queries are reported as discarded.
+const maxArchiveStorageItems = 1000
+
+// ArchiveHashRequest holds the hash of the block to query
+type ArchiveHashRequest struct {
+	Hash common.Hash
+}
+
+// ArchiveHashByHeightRequest holds the height of the blocks to query
+type ArchiveHashByHeightRequest struct {
+	Height uint
+}
+
+// ArchiveCallRequest holds the runtime function to call at a given block
+type ArchiveCallRequest struct {
+	Hash           common.Hash
+	Function       string
+	CallParameters string
+}
+
+// ArchiveStorageQuery is a storage item requested by archive_v1_storage
+type ArchiveStorageQuery struct {
+	Key                string  `json:"key"`
+	Type               string  `json:"type"`
+	PaginationStartKey *string `json:"paginationStartKey"`
+}
+
+// ArchiveStorageRequest holds the storage items to query at a given block,
+// optionally in the child trie with the given key
+type ArchiveStorageRequest struct {
+	Hash      common.Hash
+	Items     []ArchiveStorageQuery
+	ChildTrie *string
+}
+
+// ArchiveBodyResponse holds the hex encoded extrinsics of a block,
+// or nil if the block is unknown
+type ArchiveBodyResponse []string
+
+// ArchiveHeaderResponse holds the hex encoded SCALE header of a block,
+// or nil if the block is unknown
+type ArchiveHeaderResponse *string
+
+// ArchiveHashByHeightResponse holds the hashes of the blocks at a given height
+type ArchiveHashByHeightResponse []string
+
+// ArchiveCallResponse is the result of a runtime call
+type ArchiveCallResponse struct {
+	Success bool   `json:"success"`
+	Value   string `json:"value,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ArchiveStorageResponse holds the storage items found and the number of
+// queries which were not processed
+type ArchiveStorageResponse struct {
+	Items          []StorageItem `json:"items"`
+	DiscardedItems uint32        `json:"discardedItems"`
+}
+
+// ArchiveModule is an RPC module providing access to the historical blocks
+// and states of an archive node.
+type ArchiveModule struct {
+	storageAPI StorageAPI
+	blockAPI   BlockAPI
+}
+
+// NewArchiveModule creates a new Archive module.
+func NewArchiveModule(s StorageAPI, b BlockAPI) *ArchiveModule {
+	return &ArchiveModule{
+		storageAPI: s,
+		blockAPI:   b,
+	}
+}
+
+// Body returns the extrinsics of the block with the given hash,
+// or null if the block is unknown.
+func (am *ArchiveModule) Body(_ *http.Request, req *ArchiveHashRequest, res *ArchiveBodyResponse) error {
+	block, err := am.blockAPI.GetBlockByHash(req.Hash)
+	if errors.Is(err, database.ErrNotFound) {
+		*res = nil
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting block: %w", err)
+	}
+
+	extrinsics := make(ArchiveBodyResponse, len(block.Body))
+	for i, extrinsic := range block.Body {
+		extrinsics[i] = common.BytesToHex(extrinsic)
+	}
+
+	*res = extrinsics
+	return nil
+}
+
+// Header returns the SCALE encoded header of the block with the given hash,
+// or null if the block is unknown.
+func (am *ArchiveModule) Header(_ *http.Request, req *ArchiveHashRequest, res *ArchiveHeaderResponse) error {
+	header, err := am.blockAPI.GetHeader(req.Hash)
+	if errors.Is(err, database.ErrNotFound) {
+		*res = nil
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting header: %w", err)
+	}
+
+	encodedHeader, err := scale.Marshal(*header)
+	if err != nil {
+		return fmt.Errorf("encoding header: %w", err)
+	}
+
+	encodedHeaderHex := common.BytesToHex(encodedHeader)
+	*res = &encodedHeaderHex
+	return nil
+}
+
+// HashByHeight returns the hashes of the blocks at the given height,
+// including the blocks of non finalized forks.
+func (am *ArchiveModule) HashByHeight(_ *http.Request, req *ArchiveHashByHeightRequest,
+	res *ArchiveHashByHeightResponse) error {
+	hashes, err := am.blockAPI.GetHashesByNumber(req.Height)
+	if errors.Is(err, database.ErrNotFound) {
+		*res = ArchiveHashByHeightResponse{}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting hashes by number: %w", err)
+	}
+
+	hexHashes := make(ArchiveHashByHeightResponse, len(hashes))
+	for i, hash := range hashes {
+		hexHashes[i] = hash.String()
+	}
+
+	*res = hexHashes
+	return nil
+}
+
+// Call calls the given runtime function with the runtime and state of the given block,
+// on its own instance of the runtime.
+func (am *ArchiveModule) Call(_ *http.Request, req *ArchiveCallRequest, res *ArchiveCallResponse) error {
+	callParameters, err := common.HexToBytes(req.CallParameters)
+	if err != nil {
+		return fmt.Errorf("convert hex to bytes: %w", err)
+	}
+
+	rt, err := am.blockAPI.GetRuntime(req.Hash)
+	if err != nil {
+		return fmt.Errorf("get runtime: %w", err)
+	}
+
+	stateRoot, err := am.storageAPI.GetStateRootFromBlock(&req.Hash)
+	if err != nil {
+		return fmt.Errorf("getting state root: %w", err)
+	}
+
+	am.storageAPI.Lock()
+	trieState, err := am.storageAPI.TrieState(stateRoot)
+	am.storageAPI.Unlock()
+	if err != nil {
+		return fmt.Errorf("getting trie state: %w", err)
+	}
+
+	output, err := rt.ExecWithStorage(trieState, req.Function, callParameters)
+	if err != nil {
+		*res = ArchiveCallResponse{Error: err.Error()}
+		return nil
+	}
+
+	*res = ArchiveCallResponse{
+		Success: true,
+		Value:   common.BytesToHex(output),
+	}
+	return nil
+}
+
+// Storage returns the storage items of the given block. Descendants queries
+// only return the keys strictly after their pagination start key, and at most
+// maxArchiveStorageItems items are returned. The query cut short by this limit
+// and the queries left are counted as discarded.
+func (am *ArchiveModule) Storage(_ *http.Request, req *ArchiveStorageRequest, res *ArchiveStorageResponse) error {
+	queries := make([]StorageQuery, len(req.Items))
+	for i, item := range req.Items {
+		query, err := NewStorageQuery(item.Key, item.Type, item.PaginationStartKey)
+		if err != nil {
+			return err
+		}
+		queries[i] = query
+	}
+
+	root, err := am.storageAPI.GetStateRootFromBlock(&req.Hash)
+	if err != nil {
+		return fmt.Errorf("getting state root: %w", err)
+	}
+
+	var childTrieKey []byte
+	if req.ChildTrie != nil {
+		childTrieKey, err = common.HexToBytes(*req.ChildTrie)
+		if err != nil {
+			return fmt.Errorf("decoding child trie key: %w", err)
+		}
+	}
+
+	reader, err := NewStorageQueryReader(am.storageAPI, root, childTrieKey)
+	if err != nil {
+		return err
+	}
+
+	items := make([]StorageItem, 0)
+	processed := 0
+	for _, query := range queries {
+		var complete bool
+		items, complete, err = reader.AppendItems(items, query, maxArchiveStorageItems-len(items))
+		if err != nil {
+			return err
+		}
+
+		if !complete {
+			// the query cut short is discarded with the queries left, so the
+			// client repeats it from the last key received.
+			break
+		}
+		processed++
+	}
+
+	*res = ArchiveStorageResponse{
+		Items:          items,
+		DiscardedItems: uint32(len(queries) - processed),
+	}
+	return nil
+}
+
+// FinalizedHeight returns the number of the highest finalized block.
+func (am *ArchiveModule) FinalizedHeight(_ *http.Request, _ *EmptyRequest, res *uint) error {
+	hash, err := am.blockAPI.GetHighestFinalisedHash()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised hash: %w", err)
+	}
+
+	header, err := am.blockAPI.GetHeader(hash)
+	if err != nil {
+		return fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	*res = header.Number
+	return nil
+}
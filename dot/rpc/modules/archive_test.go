@@ -0,0 +1,434 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestArchiveModule_Body(t *testing.T) {
+	t.Parallel()
+
+	hash := common.Hash{1}
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		newBlockAPI func(ctrl *gomock.Controller) BlockAPI
+		expected    ArchiveBodyResponse
+		errWrapped  error
+		errMessage  string
+	}{
+		"unknown_block": {
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetBlockByHash(hash).Return(nil, database.ErrNotFound)
+				return blockAPI
+			},
+		},
+		"get_block_error": {
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetBlockByHash(hash).Return(nil, errTest)
+				return blockAPI
+			},
+			errWrapped: errTest,
+			errMessage: "getting block: test error",
+		},
+		"empty_body": {
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetBlockByHash(hash).Return(&types.Block{}, nil)
+				return blockAPI
+			},
+			expected: ArchiveBodyResponse{},
+		},
+		"body": {
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetBlockByHash(hash).
+					Return(&types.Block{Body: types.Body{{1, 2}, {3}}}, nil)
+				return blockAPI
+			},
+			expected: ArchiveBodyResponse{"0x0102", "0x03"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			module := NewArchiveModule(nil, testCase.newBlockAPI(ctrl))
+
+			var res ArchiveBodyResponse
+			err := module.Body(nil, &ArchiveHashRequest{Hash: hash}, &res)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestArchiveModule_Header(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	header := types.Header{Number: 2, ParentHash: common.Hash{1}}
+	encodedHeader, err := scale.Marshal(header)
+	require.NoError(t, err)
+
+	blockAPI := NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetHeader(common.Hash{1}).Return(&header, nil)
+	blockAPI.EXPECT().GetHeader(common.Hash{2}).Return(nil, database.ErrNotFound)
+	module := NewArchiveModule(nil, blockAPI)
+
+	var res ArchiveHeaderResponse
+	err = module.Header(nil, &ArchiveHashRequest{Hash: common.Hash{1}}, &res)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	assert.Equal(t, common.BytesToHex(encodedHeader), *res)
+
+	err = module.Header(nil, &ArchiveHashRequest{Hash: common.Hash{2}}, &res)
+	require.NoError(t, err)
+	assert.Nil(t, res)
+}
+
+func TestArchiveModule_HashByHeight(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	blockAPI := NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetHashesByNumber(uint(1)).Return([]common.Hash{{1}, {2}}, nil)
+	blockAPI.EXPECT().GetHashesByNumber(uint(2)).Return(nil, database.ErrNotFound)
+	module := NewArchiveModule(nil, blockAPI)
+
+	var res ArchiveHashByHeightResponse
+	err := module.HashByHeight(nil, &ArchiveHashByHeightRequest{Height: 1}, &res)
+	require.NoError(t, err)
+	assert.Equal(t, ArchiveHashByHeightResponse{common.Hash{1}.String(), common.Hash{2}.String()}, res)
+
+	err = module.HashByHeight(nil, &ArchiveHashByHeightRequest{Height: 2}, &res)
+	require.NoError(t, err)
+	assert.Equal(t, ArchiveHashByHeightResponse{}, res)
+}
+
+func TestArchiveModule_Call(t *testing.T) {
+	t.Parallel()
+
+	hash := common.Hash{1}
+	stateRoot := common.Hash{2}
+	trieState := storage.NewTrieState(inmemory.NewEmptyTrie())
+
+	newStorageAPI := func(ctrl *gomock.Controller) StorageAPI {
+		storageAPI := NewMockStorageAPI(ctrl)
+		storageAPI.EXPECT().GetStateRootFromBlock(&hash).Return(&stateRoot, nil)
+		storageAPI.EXPECT().Lock()
+		storageAPI.EXPECT().TrieState(&stateRoot).Return(trieState, nil)
+		storageAPI.EXPECT().Unlock()
+		return storageAPI
+	}
+
+	testCases := map[string]struct {
+		request       ArchiveCallRequest
+		newStorageAPI func(ctrl *gomock.Controller) StorageAPI
+		newBlockAPI   func(ctrl *gomock.Controller) BlockAPI
+		expected      ArchiveCallResponse
+		errMessage    string
+	}{
+		"invalid_parameters": {
+			request: ArchiveCallRequest{Hash: hash, Function: "Core_version", CallParameters: "0x0"},
+			newStorageAPI: func(ctrl *gomock.Controller) StorageAPI {
+				return NewMockStorageAPI(ctrl)
+			},
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				return NewMockBlockAPI(ctrl)
+			},
+			errMessage: "convert hex to bytes: encoding/hex: odd length hex string: 0x0",
+		},
+		"get_runtime_error": {
+			request: ArchiveCallRequest{Hash: hash, Function: "Core_version", CallParameters: "0x"},
+			newStorageAPI: func(ctrl *gomock.Controller) StorageAPI {
+				return NewMockStorageAPI(ctrl)
+			},
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetRuntime(hash).Return(nil, errors.New("test error"))
+				return blockAPI
+			},
+			errMessage: "get runtime: test error",
+		},
+		"trie_state_error": {
+			request: ArchiveCallRequest{Hash: hash, Function: "Core_version", CallParameters: "0x"},
+			newStorageAPI: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&hash).Return(&stateRoot, nil)
+				storageAPI.EXPECT().Lock()
+				storageAPI.EXPECT().TrieState(&stateRoot).Return(nil, errors.New("test error"))
+				storageAPI.EXPECT().Unlock()
+				return storageAPI
+			},
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetRuntime(hash).Return(mocksruntime.NewMockInstance(ctrl), nil)
+				return blockAPI
+			},
+			errMessage: "getting trie state: test error",
+		},
+		"call_failure": {
+			request:       ArchiveCallRequest{Hash: hash, Function: "Core_version", CallParameters: "0x01"},
+			newStorageAPI: newStorageAPI,
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				rt := mocksruntime.NewMockInstance(ctrl)
+				rt.EXPECT().ExecWithStorage(trieState, "Core_version", []byte{1}).
+					Return(nil, errors.New("test error"))
+				blockAPI := NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetRuntime(hash).Return(rt, nil)
+				return blockAPI
+			},
+			expected: ArchiveCallResponse{Error: "test error"},
+		},
+		"call_success": {
+			request:       ArchiveCallRequest{Hash: hash, Function: "Core_version", CallParameters: "0x01"},
+			newStorageAPI: newStorageAPI,
+			newBlockAPI: func(ctrl *gomock.Controller) BlockAPI {
+				rt := mocksruntime.NewMockInstance(ctrl)
+				rt.EXPECT().ExecWithStorage(trieState, "Core_version", []byte{1}).Return([]byte{2}, nil)
+				blockAPI := NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetRuntime(hash).Return(rt, nil)
+				return blockAPI
+			},
+			expected: ArchiveCallResponse{Success: true, Value: "0x02"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			module := NewArchiveModule(testCase.newStorageAPI(ctrl), testCase.newBlockAPI(ctrl))
+
+			var res ArchiveCallResponse
+			err := module.Call(nil, &testCase.request, &res)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestArchiveModule_Storage(t *testing.T) {
+	t.Parallel()
+
+	hash := common.Hash{1}
+	stateRoot := common.Hash{2}
+	hash3, err := common.Blake2bHash([]byte{3})
+	require.NoError(t, err)
+
+	childTrie := inmemory.NewEmptyTrie()
+	require.NoError(t, childTrie.Put([]byte{1}, []byte{3}))
+	require.NoError(t, childTrie.Put([]byte{1, 2}, []byte{4}))
+	childMerkleValue, err := childTrie.ClosestDescendantMerkleValue([]byte{1})
+	require.NoError(t, err)
+
+	paginationStartKey := "0x0101"
+	childTrieKey := "0x0a"
+
+	testCases := map[string]struct {
+		request       ArchiveStorageRequest
+		newStorageAPI func(ctrl *gomock.Controller) StorageAPI
+		expected      ArchiveStorageResponse
+		errWrapped    error
+		errMessage    string
+	}{
+		"unsupported_query_type": {
+			request: ArchiveStorageRequest{
+				Hash:  hash,
+				Items: []ArchiveStorageQuery{{Key: "0x01", Type: "unknown"}},
+			},
+			newStorageAPI: func(ctrl *gomock.Controller) StorageAPI {
+				return NewMockStorageAPI(ctrl)
+			},
+			errWrapped: ErrUnsupportedStorageQuery,
+			errMessage: "unsupported storage query type: unknown",
+		},
+		"values_and_hashes": {
+			request: ArchiveStorageRequest{
+				Hash: hash,
+				Items: []ArchiveStorageQuery{
+					{Key: "0x01", Type: StorageQueryValue},
+					{Key: "0x01", Type: StorageQueryHash},
+					{Key: "0x02", Type: StorageQueryValue},
+					{Key: "0x03", Type: StorageQueryClosestDescendantMerkleValue},
+				},
+			},
+			newStorageAPI: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&hash).Return(&stateRoot, nil)
+				storageAPI.EXPECT().GetStorage(&stateRoot, []byte{1}).Return([]byte{3}, nil).Times(2)
+				storageAPI.EXPECT().GetStorage(&stateRoot, []byte{2}).Return(nil, nil)
+				storageAPI.EXPECT().GetClosestDescendantMerkleValue(&stateRoot, []byte{3}).
+					Return([]byte{4}, nil)
+				return storageAPI
+			},
+			expected: ArchiveStorageResponse{
+				Items: []StorageItem{
+					{Key: "0x01", Value: "0x03"},
+					{Key: "0x01", Hash: hash3.String()},
+					{Key: "0x03", ClosestDescendantMerkleValue: "0x04"},
+				},
+			},
+		},
+		"descendants_after_pagination_start_key": {
+			request: ArchiveStorageRequest{
+				Hash: hash,
+				Items: []ArchiveStorageQuery{{
+					Key:                "0x01",
+					Type:               StorageQueryDescendantsValues,
+					PaginationStartKey: &paginationStartKey,
+				}},
+			},
+			newStorageAPI: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&hash).Return(&stateRoot, nil)
+				storageAPI.EXPECT().GetKeysWithPrefix(&stateRoot, []byte{1}).
+					Return([][]byte{{1}, {1, 1}, {1, 2}}, nil)
+				storageAPI.EXPECT().GetStorage(&stateRoot, []byte{1, 2}).Return([]byte{3}, nil)
+				return storageAPI
+			},
+			expected: ArchiveStorageResponse{
+				Items: []StorageItem{{Key: "0x0102", Value: "0x03"}},
+			},
+		},
+		"child_trie": {
+			request: ArchiveStorageRequest{
+				Hash: hash,
+				Items: []ArchiveStorageQuery{
+					{Key: "0x01", Type: StorageQueryDescendantsHashes},
+					{Key: "0x01", Type: StorageQueryClosestDescendantMerkleValue},
+				},
+				ChildTrie: &childTrieKey,
+			},
+			newStorageAPI: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&hash).Return(&stateRoot, nil)
+				storageAPI.EXPECT().GetStorageChild(&stateRoot, []byte{0xa}).Return(childTrie, nil)
+				return storageAPI
+			},
+			expected: ArchiveStorageResponse{
+				Items: []StorageItem{
+					{Key: "0x01", Hash: hash3.String(), ChildTrieKey: childTrieKey},
+					{Key: "0x0102", Hash: common.MustBlake2bHash([]byte{4}).String(), ChildTrieKey: childTrieKey},
+					{
+						Key:                          "0x01",
+						ClosestDescendantMerkleValue: common.BytesToHex(childMerkleValue),
+						ChildTrieKey:                 childTrieKey,
+					},
+				},
+			},
+		},
+		"state_root_error": {
+			request: ArchiveStorageRequest{Hash: hash},
+			newStorageAPI: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&hash).Return(nil, database.ErrNotFound)
+				return storageAPI
+			},
+			errWrapped: database.ErrNotFound,
+			errMessage: "getting state root: pebble: not found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			module := NewArchiveModule(testCase.newStorageAPI(ctrl), nil)
+
+			var res ArchiveStorageResponse
+			err := module.Storage(nil, &testCase.request, &res)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestArchiveModule_Storage_discardedItems(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	hash := common.Hash{1}
+	stateRoot := common.Hash{2}
+	keys := make([][]byte, maxArchiveStorageItems+1)
+	for i := range keys {
+		keys[i] = []byte{1, byte(i >> 8), byte(i)}
+	}
+
+	storageAPI := NewMockStorageAPI(ctrl)
+	storageAPI.EXPECT().GetStateRootFromBlock(&hash).Return(&stateRoot, nil)
+	storageAPI.EXPECT().GetKeysWithPrefix(&stateRoot, []byte{1}).Return(keys, nil)
+	storageAPI.EXPECT().GetStorage(&stateRoot, gomock.Any()).Return([]byte{1}, nil).
+		Times(maxArchiveStorageItems + 1)
+	module := NewArchiveModule(storageAPI, nil)
+
+	request := &ArchiveStorageRequest{
+		Hash: hash,
+		Items: []ArchiveStorageQuery{
+			{Key: "0x01", Type: StorageQueryDescendantsValues},
+			{Key: "0x02", Type: StorageQueryValue},
+			{Key: "0x03", Type: StorageQueryValue},
+		},
+	}
+	var res ArchiveStorageResponse
+	err := module.Storage(nil, request, &res)
+	require.NoError(t, err)
+
+	// the truncated descendants query is discarded with the queries left
+	assert.Len(t, res.Items, maxArchiveStorageItems)
+	assert.Equal(t, uint32(3), res.DiscardedItems)
+}
+
+func TestArchiveModule_FinalizedHeight(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	finalisedHash := common.Hash{1}
+	blockAPI := NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetHighestFinalisedHash().Return(finalisedHash, nil)
+	blockAPI.EXPECT().GetHeader(finalisedHash).Return(&types.Header{Number: 5}, nil)
+	module := NewArchiveModule(nil, blockAPI)
+
+	var res uint
+	err := module.FinalizedHeight(nil, &EmptyRequest{}, &res)
+	require.NoError(t, err)
+	assert.Equal(t, uint(5), res)
+}
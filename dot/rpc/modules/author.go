@@ -4,7 +4,9 @@
 package modules
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -15,7 +17,10 @@
 	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
-var ErrProvidedKeyDoesNotMatch = errors.New("generated public key does not equal provided public key")
+var (
+	ErrProvidedKeyDoesNotMatch = errors.New("generated public key does not equal provided public key")
+	errExtrinsicOrHashEmpty    = errors.New("expected either a hash or an extrinsic")
+)
 
 // AuthorModule holds a pointer to the API
 type AuthorModule struct {
@@ -47,6 +52,32 @@ type ExtrinsicOrHash struct {
 	Extrinsic []byte
 }
 
+// UnmarshalJSON decodes either a hex encoded hash `{"hash": "0x..."}`
+// or a hex encoded extrinsic `{"extrinsic": "0x..."}`.
+func (e *ExtrinsicOrHash) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Hash      *common.Hash `json:"hash"`
+		Extrinsic *string      `json:"extrinsic"`
+	}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case fields.Hash != nil:
+		e.Hash = *fields.Hash
+	case fields.Extrinsic != nil:
+		e.Extrinsic, err = common.HexToBytes(*fields.Extrinsic)
+		if err != nil {
+			return fmt.Errorf("decoding extrinsic: %w", err)
+		}
+	default:
+		return errExtrinsicOrHashEmpty
+	}
+	return nil
+}
+
 // ExtrinsicOrHashRequest is a array of ExtrinsicOrHash
 type ExtrinsicOrHashRequest []ExtrinsicOrHash
 
@@ -59,9 +90,15 @@ type ExtrinsicOrHash struct {
 // RemoveExtrinsicsResponse is a array of hash used to Remove extrinsics
 type RemoveExtrinsicsResponse []common.Hash
 
-// KeyRotateResponse is a byte array used to rotate
+// KeyRotateResponse is the response to the RPC call author_rotateKeys,
+// the concatenated public session keys
 type KeyRotateResponse []byte
 
+// MarshalJSON encodes the session keys as a hex string
+func (k KeyRotateResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(common.BytesToHex(k))
+}
+
 // HasSessionKeyResponse is the response to the RPC call author_hasSessionKeys
 type HasSessionKeyResponse bool
 
@@ -192,12 +229,34 @@ func (am *AuthorModule) PendingExtrinsics(r *http.Request, req *EmptyRequest, re
 }
 
 // RemoveExtrinsic Remove given extrinsic from the pool and temporarily ban it to prevent reimporting
-func (am *AuthorModule) RemoveExtrinsic(r *http.Request, _ *ExtrinsicOrHashRequest, _ *RemoveExtrinsicsResponse) error {
+func (am *AuthorModule) RemoveExtrinsic(_ *http.Request, req *ExtrinsicOrHashRequest,
+	res *RemoveExtrinsicsResponse) error {
+	hashes := make([]common.Hash, len(*req))
+	for i, extrinsicOrHash := range *req {
+		if extrinsicOrHash.Extrinsic != nil {
+			hashes[i] = types.Extrinsic(extrinsicOrHash.Extrinsic).Hash()
+		} else {
+			hashes[i] = extrinsicOrHash.Hash
+		}
+	}
+
+	removed := am.txStateAPI.RemoveAndBan(hashes)
+	if removed == nil {
+		removed = []common.Hash{}
+	}
+
+	*res = RemoveExtrinsicsResponse(removed)
 	return nil
 }
 
 // RotateKeys Generate new session keys and returns the corresponding public keys
 func (am *AuthorModule) RotateKeys(r *http.Request, req *EmptyRequest, res *KeyRotateResponse) error {
+	sessionKeys, err := am.coreAPI.GenerateSessionKeys()
+	if err != nil {
+		return fmt.Errorf("generating session keys: %w", err)
+	}
+
+	*res = KeyRotateResponse(sessionKeys)
 	return nil
 }
 
@@ -571,6 +571,57 @@ func TestAuthorModule_HasSessionKeys_Integration(t *testing.T) {
 	}
 }
 
+func TestAuthorModule_RotateKeys_Integration(t *testing.T) {
+	t.Parallel()
+
+	// the runtime generates the session keys in its own keystore, which must be
+	// the node keystore for the keys to be usable by the node services
+	ks := keystore.NewGlobalKeystore()
+	useInstanceWithKeystore := func(t *testing.T, rtStorage *storage.TrieState) runtime.Instance {
+		t.Helper()
+
+		cfg := wazero_runtime.Config{
+			Storage:  rtStorage,
+			LogLvl:   log.Warn,
+			Keystore: ks,
+			NodeStorage: runtime.NodeStorage{
+				BaseDB: runtime.NewInMemoryDB(t),
+			},
+		}
+
+		runtimeInstance, err := wazero_runtime.NewRuntimeFromGenesis(cfg)
+		require.NoError(t, err)
+
+		return runtimeInstance
+	}
+
+	integrationTestController := setupStateAndRuntime(t, t.TempDir(), useInstanceWithKeystore)
+	integrationTestController.keystore = ks
+	auth := newAuthorModule(t, integrationTestController)
+
+	var res KeyRotateResponse
+	err := auth.RotateKeys(nil, nil, &res)
+	require.NoError(t, err)
+
+	// session keys are the concatenated public keys in the order defined by the runtime
+	sessionKeystores := []keystore.Keystore{ks.Gran, ks.Babe, ks.Imon, ks.Para, ks.Asgn, ks.Audi}
+	require.GreaterOrEqual(t, len(res), len(sessionKeystores)*32)
+
+	for i, sessionKeystore := range sessionKeystores {
+		publicKey := []byte(res[i*32 : (i+1)*32])
+		publicKeys := sessionKeystore.PublicKeys()
+		require.Lenf(t, publicKeys, 1, "keystore %s", sessionKeystore.Name())
+		require.Equalf(t, publicKey, publicKeys[0].Encode(), "keystore %s", sessionKeystore.Name())
+	}
+
+	var hasSessionKeys HasSessionKeyResponse
+	err = auth.HasSessionKeys(nil, &HasSessionKeyRequest{
+		PublicKeys: common.BytesToHex(res),
+	}, &hasSessionKeys)
+	require.NoError(t, err)
+	require.True(t, bool(hasSessionKeys))
+}
+
 func TestAuthorModule_SubmitExtrinsic_WithVersion_V0929(t *testing.T) {
 	t.Parallel()
 	integrationTestController := setupStateAndPopulateTrieState(t, t.TempDir(), useInstanceFromRuntimeV0929)
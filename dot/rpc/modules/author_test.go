@@ -4,6 +4,7 @@
 package modules
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -228,6 +229,56 @@ type args struct {
 	}
 }
 
+func TestAuthorModule_RotateKeys(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	sessionKeys := []byte{1, 2, 3, 4}
+
+	tests := map[string]struct {
+		sessionKeys []byte
+		err         error
+		errMessage  string
+		expRes      KeyRotateResponse
+	}{
+		"generate_session_keys_error": {
+			err:        errTest,
+			errMessage: "generating session keys: test error",
+		},
+		"happy_path": {
+			sessionKeys: sessionKeys,
+			expRes:      KeyRotateResponse(sessionKeys),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			coreAPI := mocks.NewMockCoreAPI(ctrl)
+			coreAPI.EXPECT().GenerateSessionKeys().Return(tt.sessionKeys, tt.err)
+			am := &AuthorModule{coreAPI: coreAPI}
+
+			var res KeyRotateResponse
+			err := am.RotateKeys(nil, nil, &res)
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.errMessage)
+			}
+			assert.Equal(t, tt.expRes, res)
+		})
+	}
+}
+
+func TestKeyRotateResponse_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := json.Marshal(KeyRotateResponse{0x01, 0xab})
+	require.NoError(t, err)
+	assert.Equal(t, `"0x01ab"`, string(encoded))
+}
+
 func TestAuthorModule_PendingExtrinsics(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -299,6 +350,58 @@ type args struct {
 	}
 }
 
+func TestAuthorModule_RemoveExtrinsic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	extrinsic := types.Extrinsic{1, 2, 3}
+	hash := common.Hash{1}
+
+	mockTransactionStateAPI := mocks.NewMockTransactionStateAPI(ctrl)
+	mockTransactionStateAPI.EXPECT().RemoveAndBan([]common.Hash{hash, extrinsic.Hash()}).
+		Return([]common.Hash{extrinsic.Hash()})
+	emptyMockTransactionStateAPI := mocks.NewMockTransactionStateAPI(ctrl)
+	emptyMockTransactionStateAPI.EXPECT().RemoveAndBan([]common.Hash{hash}).Return(nil)
+
+	tests := map[string]struct {
+		txStateAPI TransactionStateAPI
+		params     string
+		wantRes    RemoveExtrinsicsResponse
+	}{
+		"hash_and_extrinsic": {
+			txStateAPI: mockTransactionStateAPI,
+			params:     `[{"hash":"` + hash.String() + `"},{"extrinsic":"0x010203"}]`,
+			wantRes:    RemoveExtrinsicsResponse{extrinsic.Hash()},
+		},
+		"nothing_removed": {
+			txStateAPI: emptyMockTransactionStateAPI,
+			params:     `[{"hash":"` + hash.String() + `"}]`,
+			wantRes:    RemoveExtrinsicsResponse{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var req ExtrinsicOrHashRequest
+			err := json.Unmarshal([]byte(tt.params), &req)
+			require.NoError(t, err)
+
+			am := &AuthorModule{txStateAPI: tt.txStateAPI}
+			var res RemoveExtrinsicsResponse
+			err = am.RemoveExtrinsic(nil, &req, &res)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRes, res)
+		})
+	}
+}
+
+func TestExtrinsicOrHash_UnmarshalJSON(t *testing.T) {
+	var extrinsicOrHash ExtrinsicOrHash
+	err := json.Unmarshal([]byte(`{}`), &extrinsicOrHash)
+	assert.ErrorIs(t, err, errExtrinsicOrHashEmpty)
+
+	err = json.Unmarshal([]byte(`{"extrinsic":"0x0"}`), &extrinsicOrHash)
+	assert.EqualError(t, err, "decoding extrinsic: encoding/hex: odd length hex string: 0x0")
+}
+
 func TestAuthorModule_InsertKey(t *testing.T) {
 	kp1, err := sr25519.NewKeypairFromSeed(
 		common.MustHexToBytes("0x6246ddf254e0b4b4e7dffefc8adf69d212b98ac2b579c362b473fec8c40b4c0a"))
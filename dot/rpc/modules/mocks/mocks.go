@@ -19,6 +19,7 @@
 	ed25519 "github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	genesis "github.com/ChainSafe/gossamer/lib/genesis"
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
+	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	transaction "github.com/ChainSafe/gossamer/lib/transaction"
 	trie "github.com/ChainSafe/gossamer/pkg/trie"
 	gomock "go.uber.org/mock/gomock"
@@ -62,6 +63,21 @@ func (mr *MockStorageAPIMockRecorder) Entries(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Entries", reflect.TypeOf((*MockStorageAPI)(nil).Entries), arg0)
 }
 
+// GetClosestDescendantMerkleValue mocks base method.
+func (m *MockStorageAPI) GetClosestDescendantMerkleValue(arg0 *common.Hash, arg1 []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosestDescendantMerkleValue", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosestDescendantMerkleValue indicates an expected call of GetClosestDescendantMerkleValue.
+func (mr *MockStorageAPIMockRecorder) GetClosestDescendantMerkleValue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosestDescendantMerkleValue", reflect.TypeOf((*MockStorageAPI)(nil).GetClosestDescendantMerkleValue), arg0, arg1)
+}
+
 // GetKeysWithPrefix mocks base method.
 func (m *MockStorageAPI) GetKeysWithPrefix(arg0 *common.Hash, arg1 []byte) ([][]byte, error) {
 	m.ctrl.T.Helper()
@@ -152,6 +168,18 @@ func (mr *MockStorageAPIMockRecorder) GetStorageFromChild(arg0, arg1, arg2 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorageFromChild", reflect.TypeOf((*MockStorageAPI)(nil).GetStorageFromChild), arg0, arg1, arg2)
 }
 
+// Lock mocks base method.
+func (m *MockStorageAPI) Lock() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Lock")
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockStorageAPIMockRecorder) Lock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockStorageAPI)(nil).Lock))
+}
+
 // RegisterStorageObserver mocks base method.
 func (m *MockStorageAPI) RegisterStorageObserver(arg0 state.Observer) {
 	m.ctrl.T.Helper()
@@ -164,6 +192,33 @@ func (mr *MockStorageAPIMockRecorder) RegisterStorageObserver(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterStorageObserver", reflect.TypeOf((*MockStorageAPI)(nil).RegisterStorageObserver), arg0)
 }
 
+// TrieState mocks base method.
+func (m *MockStorageAPI) TrieState(arg0 *common.Hash) (*storage.TrieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrieState", arg0)
+	ret0, _ := ret[0].(*storage.TrieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrieState indicates an expected call of TrieState.
+func (mr *MockStorageAPIMockRecorder) TrieState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrieState", reflect.TypeOf((*MockStorageAPI)(nil).TrieState), arg0)
+}
+
+// Unlock mocks base method.
+func (m *MockStorageAPI) Unlock() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Unlock")
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockStorageAPIMockRecorder) Unlock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockStorageAPI)(nil).Unlock))
+}
+
 // UnregisterStorageObserver mocks base method.
 func (m *MockStorageAPI) UnregisterStorageObserver(arg0 state.Observer) {
 	m.ctrl.T.Helper()
@@ -237,6 +292,21 @@ func (mr *MockBlockAPIMockRecorder) FreeImportedBlockNotifierChannel(arg0 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeImportedBlockNotifierChannel", reflect.TypeOf((*MockBlockAPI)(nil).FreeImportedBlockNotifierChannel), arg0)
 }
 
+// GetAllDescendants mocks base method.
+func (m *MockBlockAPI) GetAllDescendants(arg0 common.Hash) ([]common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllDescendants", arg0)
+	ret0, _ := ret[0].([]common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllDescendants indicates an expected call of GetAllDescendants.
+func (mr *MockBlockAPIMockRecorder) GetAllDescendants(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllDescendants", reflect.TypeOf((*MockBlockAPI)(nil).GetAllDescendants), arg0)
+}
+
 // GetBlockByHash mocks base method.
 func (m *MockBlockAPI) GetBlockByHash(arg0 common.Hash) (*types.Block, error) {
 	m.ctrl.T.Helper()
@@ -296,6 +366,21 @@ func (mr *MockBlockAPIMockRecorder) GetHashByNumber(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHashByNumber", reflect.TypeOf((*MockBlockAPI)(nil).GetHashByNumber), arg0)
 }
 
+// GetHashesByNumber mocks base method.
+func (m *MockBlockAPI) GetHashesByNumber(arg0 uint) ([]common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHashesByNumber", arg0)
+	ret0, _ := ret[0].([]common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHashesByNumber indicates an expected call of GetHashesByNumber.
+func (mr *MockBlockAPIMockRecorder) GetHashesByNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHashesByNumber", reflect.TypeOf((*MockBlockAPI)(nil).GetHashesByNumber), arg0)
+}
+
 // GetHeader mocks base method.
 func (m *MockBlockAPI) GetHeader(arg0 common.Hash) (*types.Header, error) {
 	m.ctrl.T.Helper()
@@ -702,6 +787,20 @@ func (mr *MockTransactionStateAPIMockRecorder) Pending() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockTransactionStateAPI)(nil).Pending))
 }
 
+// RemoveAndBan mocks base method.
+func (m *MockTransactionStateAPI) RemoveAndBan(arg0 []common.Hash) []common.Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAndBan", arg0)
+	ret0, _ := ret[0].([]common.Hash)
+	return ret0
+}
+
+// RemoveAndBan indicates an expected call of RemoveAndBan.
+func (mr *MockTransactionStateAPIMockRecorder) RemoveAndBan(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAndBan", reflect.TypeOf((*MockTransactionStateAPI)(nil).RemoveAndBan), arg0)
+}
+
 // MockCoreAPI is a mock of CoreAPI interface.
 type MockCoreAPI struct {
 	ctrl     *gomock.Controller
@@ -740,6 +839,36 @@ func (mr *MockCoreAPIMockRecorder) DecodeSessionKeys(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeSessionKeys", reflect.TypeOf((*MockCoreAPI)(nil).DecodeSessionKeys), arg0)
 }
 
+// DryRun mocks base method.
+func (m *MockCoreAPI) DryRun(arg0 types.Extrinsic, arg1 *common.Hash) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRun", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRun indicates an expected call of DryRun.
+func (mr *MockCoreAPIMockRecorder) DryRun(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRun", reflect.TypeOf((*MockCoreAPI)(nil).DryRun), arg0, arg1)
+}
+
+// GenerateSessionKeys mocks base method.
+func (m *MockCoreAPI) GenerateSessionKeys() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateSessionKeys")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateSessionKeys indicates an expected call of GenerateSessionKeys.
+func (mr *MockCoreAPIMockRecorder) GenerateSessionKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSessionKeys", reflect.TypeOf((*MockCoreAPI)(nil).GenerateSessionKeys))
+}
+
 // GetMetadata mocks base method.
 func (m *MockCoreAPI) GetMetadata(arg0 *common.Hash) ([]byte, error) {
 	m.ctrl.T.Helper()
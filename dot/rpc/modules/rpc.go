@@ -20,6 +20,8 @@
 		"state_getKeysPaged",
 		"state_queryStorage",
 		"state_trie",
+		"state_traceBlock",
+		"system_dryRun",
 	}
 
 	// AliasesMethods is a map that links the original methods to their aliases
@@ -311,7 +311,21 @@ func (sm *StateModule) Call(_ *http.Request, req *StateCallRequest, res *StateCa
 		return fmt.Errorf("convert hex to bytes: %w", err)
 	}
 
-	response, err := rt.Exec(req.Method, request)
+	stateRoot, err := sm.storageAPI.GetStateRootFromBlock(&blockHash)
+	if err != nil {
+		return fmt.Errorf("getting state root: %w", err)
+	}
+
+	sm.storageAPI.Lock()
+	trieState, err := sm.storageAPI.TrieState(stateRoot)
+	sm.storageAPI.Unlock()
+	if err != nil {
+		return fmt.Errorf("getting trie state: %w", err)
+	}
+
+	// the call runs on a pooled instance of the runtime, on its own copy of
+	// the block state, so parallel calls do not wait for each other.
+	response, err := rt.ExecWithStorage(trieState, req.Method, request)
 	if err != nil {
 		return fmt.Errorf("runtime exec: %w", err)
 	}
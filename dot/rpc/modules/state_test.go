@@ -21,8 +21,10 @@
 	"slices"
 	"testing"
 
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
 	"github.com/ChainSafe/gossamer/pkg/trie"
+	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
 
 	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
 	testdata "github.com/ChainSafe/gossamer/dot/rpc/modules/test_data"
@@ -298,6 +300,12 @@ func TestCall(t *testing.T) {
 	mockBlockAPI := mocks.NewMockBlockAPI(ctrl)
 	mockBlockAPI.EXPECT().BestBlockHash().Return(testHash)
 	mockBlockAPI.EXPECT().GetRuntime(testHash).Return(rt, nil)
+	stateRoot := common.Hash{0x03}
+	mockStorageAPI.EXPECT().GetStateRootFromBlock(&testHash).Return(&stateRoot, nil)
+	mockStorageAPI.EXPECT().Lock()
+	mockStorageAPI.EXPECT().TrieState(&stateRoot).
+		Return(rtstorage.NewTrieState(inmemory_trie.NewEmptyTrie()), nil)
+	mockStorageAPI.EXPECT().Unlock()
 
 	sm := NewStateModule(mockNetworkAPI, mockStorageAPI, nil, mockBlockAPI)
 
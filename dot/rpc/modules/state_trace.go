@@ -0,0 +1,244 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+)
+
+const (
+	// defaultTraceTargets are the targets traced when none are requested, as in Substrate.
+	defaultTraceTargets = "pallet,frame,state"
+	// traceStorageTarget is the target of the storage access events.
+	traceStorageTarget = "state"
+	// traceExecuteBlockSpanID is the identifier of the span of the block execution,
+	// which is the parent of all the events.
+	traceExecuteBlockSpanID uint64 = 1
+)
+
+// StateTraceBlockRequest holds the block to trace and the comma separated
+// targets, storage key prefixes and storage methods to filter the events with
+type StateTraceBlockRequest struct {
+	Block       common.Hash `json:"block"`
+	Targets     *string     `json:"targets"`
+	StorageKeys *string     `json:"storageKeys"`
+	Methods     *string     `json:"methods"`
+}
+
+// StateTraceBlockResponse holds either the trace of the block or the error
+// which occurred while tracing it
+type StateTraceBlockResponse struct {
+	TraceError *TraceError `json:"traceError,omitempty"`
+	BlockTrace *BlockTrace `json:"blockTrace,omitempty"`
+}
+
+// TraceError is the error which occurred while tracing a block
+type TraceError struct {
+	Error string `json:"error"`
+}
+
+// BlockTrace holds the spans and events recorded while executing a block
+type BlockTrace struct {
+	BlockHash      string       `json:"blockHash"`
+	ParentHash     string       `json:"parentHash"`
+	TracingTargets string       `json:"tracingTargets"`
+	StorageKeys    string       `json:"storageKeys"`
+	Methods        string       `json:"methods"`
+	Spans          []TraceSpan  `json:"spans"`
+	Events         []TraceEvent `json:"events"`
+}
+
+// TraceSpan is a period of time during which the traced events happened
+type TraceSpan struct {
+	ID       uint64  `json:"id"`
+	ParentID *uint64 `json:"parentId"`
+	Name     string  `json:"name"`
+	Target   string  `json:"target"`
+	Wasm     bool    `json:"wasm"`
+}
+
+// TraceEvent is an event recorded while executing a block
+type TraceEvent struct {
+	Target   string         `json:"target"`
+	Data     TraceEventData `json:"data"`
+	ParentID *uint64        `json:"parentId"`
+}
+
+// TraceEventData holds the values of a trace event
+type TraceEventData struct {
+	StringValues map[string]string `json:"stringValues"`
+}
+
+// TraceBlock re-executes the given block on top of its parent state and returns the
+// storage accesses made by the runtime, filtered by targets, storage keys and methods.
+func (sm *StateModule) TraceBlock(_ *http.Request, req *StateTraceBlockRequest, res *StateTraceBlockResponse) error {
+	filter, err := newTraceFilter(req.Targets, req.StorageKeys, req.Methods)
+	if err != nil {
+		return err
+	}
+
+	block, err := sm.blockAPI.GetBlockByHash(req.Block)
+	if err != nil {
+		return fmt.Errorf("getting block: %w", err)
+	}
+
+	parent, err := sm.blockAPI.GetHeader(block.Header.ParentHash)
+	if err != nil {
+		return fmt.Errorf("getting parent header: %w", err)
+	}
+
+	rt, err := sm.blockAPI.GetRuntime(parent.Hash())
+	if err != nil {
+		return fmt.Errorf("get runtime: %w", err)
+	}
+
+	encodedBlock, err := runtime.EncodeBlockToExecute(block)
+	if err != nil {
+		return fmt.Errorf("encoding block: %w", err)
+	}
+
+	sm.storageAPI.Lock()
+	trieState, err := sm.storageAPI.TrieState(&parent.StateRoot)
+	sm.storageAPI.Unlock()
+	if err != nil {
+		return fmt.Errorf("getting parent trie state: %w", err)
+	}
+
+	parentSpanID := traceExecuteBlockSpanID
+	events := make([]TraceEvent, 0)
+	tracingTrieState := rtstorage.NewTracingTrieState(trieState, func(event rtstorage.TraceEvent) {
+		if !filter.accepts(traceStorageTarget, event) {
+			return
+		}
+
+		events = append(events, TraceEvent{
+			Target:   traceStorageTarget,
+			Data:     TraceEventData{StringValues: traceEventValues(event)},
+			ParentID: &parentSpanID,
+		})
+	})
+
+	// the block is executed on a pooled instance of the runtime, so the storage
+	// of the instance shared with block production and import is left untouched.
+	tracingTrieState.StartTransaction()
+	_, err = rt.ExecWithStorage(tracingTrieState, runtime.CoreExecuteBlock, encodedBlock)
+	if err != nil {
+		*res = StateTraceBlockResponse{
+			TraceError: &TraceError{Error: fmt.Sprintf("executing block: %s", err)},
+		}
+		return nil
+	}
+
+	*res = StateTraceBlockResponse{
+		BlockTrace: &BlockTrace{
+			BlockHash:      req.Block.String(),
+			ParentHash:     block.Header.ParentHash.String(),
+			TracingTargets: filter.targetsString,
+			StorageKeys:    filter.storageKeysString,
+			Methods:        filter.methodsString,
+			Spans: []TraceSpan{{
+				ID:     traceExecuteBlockSpanID,
+				Name:   "Core_execute_block",
+				Target: "runtime",
+				Wasm:   true,
+			}},
+			Events: events,
+		},
+	}
+	return nil
+}
+
+func traceEventValues(event rtstorage.TraceEvent) map[string]string {
+	values := map[string]string{
+		"method": event.Method,
+		"key":    common.BytesToHex(event.Key),
+	}
+	if event.KeyToChild != nil {
+		values["child_info"] = common.BytesToHex(event.KeyToChild)
+	}
+	if event.Value != nil {
+		values["value"] = common.BytesToHex(event.Value)
+	}
+	return values
+}
+
+// traceFilter selects the trace events to return
+type traceFilter struct {
+	targets     []string
+	storageKeys [][]byte
+	methods     []string
+
+	targetsString     string
+	storageKeysString string
+	methodsString     string
+}
+
+func newTraceFilter(targets, storageKeys, methods *string) (filter traceFilter, err error) {
+	filter.targetsString = defaultTraceTargets
+	if targets != nil {
+		filter.targetsString = *targets
+	}
+
+	for _, target := range splitTraceList(filter.targetsString) {
+		// targets can be suffixed with a log level, which is not relevant here
+		name, _, _ := strings.Cut(target, "=")
+		filter.targets = append(filter.targets, name)
+	}
+
+	if storageKeys != nil {
+		filter.storageKeysString = *storageKeys
+		for _, storageKey := range splitTraceList(*storageKeys) {
+			key, err := common.HexToBytes(storageKey)
+			if err != nil {
+				return filter, fmt.Errorf("decoding storage key %s: %w", storageKey, err)
+			}
+			filter.storageKeys = append(filter.storageKeys, key)
+		}
+	}
+
+	if methods != nil {
+		filter.methodsString = *methods
+		filter.methods = splitTraceList(*methods)
+	}
+
+	return filter, nil
+}
+
+func splitTraceList(list string) (items []string) {
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// accepts returns true if the event matches one of the targets, and one of the
+// storage key prefixes and methods if any are set.
+func (f traceFilter) accepts(target string, event rtstorage.TraceEvent) bool {
+	targetMatches := slices.ContainsFunc(f.targets, func(filterTarget string) bool {
+		return strings.HasPrefix(target, filterTarget)
+	})
+	if !targetMatches {
+		return false
+	}
+
+	keyMatches := len(f.storageKeys) == 0 || slices.ContainsFunc(f.storageKeys, func(prefix []byte) bool {
+		return bytes.HasPrefix(event.Key, prefix)
+	})
+	if !keyMatches {
+		return false
+	}
+
+	return len(f.methods) == 0 || slices.Contains(f.methods, event.Method)
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStateModule_TraceBlock(t *testing.T) {
+	t.Parallel()
+
+	parent := types.Header{Number: 1, StateRoot: common.Hash{2}}
+	block := types.Block{Header: types.Header{Number: 2, ParentHash: parent.Hash()}}
+	blockHash := block.Header.Hash()
+	parentSpanID := traceExecuteBlockSpanID
+	stringPointer := func(s string) *string { return &s }
+
+	encodedBlock, err := runtime.EncodeBlockToExecute(&block)
+	require.NoError(t, err)
+
+	// executeBlock reads the key 0x01 and writes the key 0x0203
+	executeBlock := func(storage runtime.Storage, _ string, _ []byte) ([]byte, error) {
+		storage.Get([]byte{1})
+		return nil, storage.Put([]byte{2, 3}, []byte{4})
+	}
+
+	testCases := map[string]struct {
+		request         StateTraceBlockRequest
+		executeBlockErr error
+		expected        StateTraceBlockResponse
+	}{
+		"default_targets": {
+			request: StateTraceBlockRequest{Block: blockHash},
+			expected: StateTraceBlockResponse{BlockTrace: &BlockTrace{
+				BlockHash:      blockHash.String(),
+				ParentHash:     parent.Hash().String(),
+				TracingTargets: defaultTraceTargets,
+				Spans: []TraceSpan{{
+					ID: traceExecuteBlockSpanID, Name: "Core_execute_block", Target: "runtime", Wasm: true,
+				}},
+				Events: []TraceEvent{
+					{
+						Target: "state",
+						Data: TraceEventData{StringValues: map[string]string{
+							"method": "Get", "key": "0x01", "value": "0x05",
+						}},
+						ParentID: &parentSpanID,
+					},
+					{
+						Target: "state",
+						Data: TraceEventData{StringValues: map[string]string{
+							"method": "Put", "key": "0x0203", "value": "0x04",
+						}},
+						ParentID: &parentSpanID,
+					},
+				},
+			}},
+		},
+		"filtered_by_storage_keys_and_methods": {
+			request: StateTraceBlockRequest{
+				Block:       blockHash,
+				Targets:     stringPointer("state=trace"),
+				StorageKeys: stringPointer("0x02,0x01"),
+				Methods:     stringPointer("Put"),
+			},
+			expected: StateTraceBlockResponse{BlockTrace: &BlockTrace{
+				BlockHash:      blockHash.String(),
+				ParentHash:     parent.Hash().String(),
+				TracingTargets: "state=trace",
+				StorageKeys:    "0x02,0x01",
+				Methods:        "Put",
+				Spans: []TraceSpan{{
+					ID: traceExecuteBlockSpanID, Name: "Core_execute_block", Target: "runtime", Wasm: true,
+				}},
+				Events: []TraceEvent{{
+					Target: "state",
+					Data: TraceEventData{StringValues: map[string]string{
+						"method": "Put", "key": "0x0203", "value": "0x04",
+					}},
+					ParentID: &parentSpanID,
+				}},
+			}},
+		},
+		"other_targets": {
+			request: StateTraceBlockRequest{Block: blockHash, Targets: stringPointer("pallet")},
+			expected: StateTraceBlockResponse{BlockTrace: &BlockTrace{
+				BlockHash:      blockHash.String(),
+				ParentHash:     parent.Hash().String(),
+				TracingTargets: "pallet",
+				Spans: []TraceSpan{{
+					ID: traceExecuteBlockSpanID, Name: "Core_execute_block", Target: "runtime", Wasm: true,
+				}},
+				Events: []TraceEvent{},
+			}},
+		},
+		"execute_block_error": {
+			request:         StateTraceBlockRequest{Block: blockHash},
+			executeBlockErr: errors.New("test error"),
+			expected: StateTraceBlockResponse{
+				TraceError: &TraceError{Error: "executing block: test error"},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			trie := inmemory.NewEmptyTrie()
+			require.NoError(t, trie.Put([]byte{1}, []byte{5}))
+
+			blockAPI := NewMockBlockAPI(ctrl)
+			blockAPI.EXPECT().GetBlockByHash(blockHash).Return(&block, nil)
+			blockAPI.EXPECT().GetHeader(parent.Hash()).Return(&parent, nil)
+
+			// the block is executed on its own instance of the runtime
+			rt := mocksruntime.NewMockInstance(ctrl)
+			execute := rt.EXPECT().ExecWithStorage(gomock.Any(), runtime.CoreExecuteBlock, encodedBlock)
+			if testCase.executeBlockErr != nil {
+				execute.Return(nil, testCase.executeBlockErr)
+			} else {
+				execute.DoAndReturn(executeBlock)
+			}
+			blockAPI.EXPECT().GetRuntime(parent.Hash()).Return(rt, nil)
+
+			storageAPI := NewMockStorageAPI(ctrl)
+			storageAPI.EXPECT().Lock()
+			storageAPI.EXPECT().Unlock()
+			storageAPI.EXPECT().TrieState(&parent.StateRoot).Return(rtstorage.NewTrieState(trie), nil)
+
+			module := NewStateModule(nil, storageAPI, nil, blockAPI)
+
+			var res StateTraceBlockResponse
+			err := module.TraceBlock(nil, &testCase.request, &res)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestStateModule_TraceBlock_invalidStorageKeys(t *testing.T) {
+	t.Parallel()
+
+	storageKeys := "0x0"
+	module := NewStateModule(nil, nil, nil, nil)
+
+	var res StateTraceBlockResponse
+	err := module.TraceBlock(nil, &StateTraceBlockRequest{StorageKeys: &storageKeys}, &res)
+	assert.EqualError(t, err, "decoding storage key 0x0: encoding/hex: odd length hex string: 0x0")
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+)
+
+// storage query types of the chainHead_v1_storage and archive_v1_storage methods
+const (
+	StorageQueryValue                        = "value"
+	StorageQueryHash                         = "hash"
+	StorageQueryClosestDescendantMerkleValue = "closestDescendantMerkleValue"
+	StorageQueryDescendantsValues            = "descendantsValues"
+	StorageQueryDescendantsHashes            = "descendantsHashes"
+)
+
+// ErrUnsupportedStorageQuery is returned when the type of a storage query is unknown.
+var ErrUnsupportedStorageQuery = errors.New("unsupported storage query type")
+
+// StorageQuery is a storage item requested by the chainHead_v1_storage and
+// archive_v1_storage methods
+type StorageQuery struct {
+	Key                []byte
+	Type               string
+	PaginationStartKey []byte
+}
+
+// NewStorageQuery returns the storage query of the given type for the hex
+// encoded key, and optional hex encoded pagination start key.
+func NewStorageQuery(key, queryType string, paginationStartKey *string) (query StorageQuery, err error) {
+	switch queryType {
+	case StorageQueryValue, StorageQueryHash, StorageQueryClosestDescendantMerkleValue,
+		StorageQueryDescendantsValues, StorageQueryDescendantsHashes:
+	default:
+		return query, fmt.Errorf("%w: %s", ErrUnsupportedStorageQuery, queryType)
+	}
+
+	query.Type = queryType
+	query.Key, err = common.HexToBytes(key)
+	if err != nil {
+		return query, fmt.Errorf("decoding key: %w", err)
+	}
+
+	if paginationStartKey != nil {
+		query.PaginationStartKey, err = common.HexToBytes(*paginationStartKey)
+		if err != nil {
+			return query, fmt.Errorf("decoding pagination start key: %w", err)
+		}
+	}
+
+	return query, nil
+}
+
+// StorageItem is a storage item answering a storage query
+type StorageItem struct {
+	Key                          string `json:"key"`
+	Value                        string `json:"value,omitempty"`
+	Hash                         string `json:"hash,omitempty"`
+	ClosestDescendantMerkleValue string `json:"closestDescendantMerkleValue,omitempty"`
+	ChildTrieKey                 string `json:"childTrieKey,omitempty"`
+}
+
+// StorageQueryState is the storage state read by a StorageQueryReader
+type StorageQueryState interface {
+	GetStorage(root *common.Hash, key []byte) ([]byte, error)
+	GetStorageChild(root *common.Hash, keyToChild []byte) (trie.Trie, error)
+	GetKeysWithPrefix(root *common.Hash, prefix []byte) ([][]byte, error)
+	GetClosestDescendantMerkleValue(root *common.Hash, key []byte) ([]byte, error)
+}
+
+// StorageQueryReader reads the storage items answering storage queries, in
+// either the main trie or a child trie of a state.
+type StorageQueryReader struct {
+	get                          func(key []byte) ([]byte, error)
+	keysWithPrefix               func(prefix []byte) ([][]byte, error)
+	closestDescendantMerkleValue func(key []byte) ([]byte, error)
+	childTrieKey                 string
+}
+
+// NewStorageQueryReader returns a reader of the state with the given root, reading
+// the child trie with the given key instead of the main trie if it is not nil.
+func NewStorageQueryReader(storageAPI StorageQueryState, root *common.Hash, childTrieKey []byte) (
+	*StorageQueryReader, error) {
+	if childTrieKey == nil {
+		return &StorageQueryReader{
+			get: func(key []byte) ([]byte, error) {
+				return storageAPI.GetStorage(root, key)
+			},
+			keysWithPrefix: func(prefix []byte) ([][]byte, error) {
+				return storageAPI.GetKeysWithPrefix(root, prefix)
+			},
+			closestDescendantMerkleValue: func(key []byte) ([]byte, error) {
+				return storageAPI.GetClosestDescendantMerkleValue(root, key)
+			},
+		}, nil
+	}
+
+	child, err := storageAPI.GetStorageChild(root, childTrieKey)
+	if err != nil {
+		return nil, fmt.Errorf("getting child trie: %w", err)
+	}
+
+	return &StorageQueryReader{
+		get: func(key []byte) ([]byte, error) {
+			return child.Get(key), nil
+		},
+		keysWithPrefix: func(prefix []byte) ([][]byte, error) {
+			return child.GetKeysWithPrefix(prefix), nil
+		},
+		closestDescendantMerkleValue: child.ClosestDescendantMerkleValue,
+		childTrieKey:                 common.BytesToHex(childTrieKey),
+	}, nil
+}
+
+// AppendItems appends at most limit items answering the query to items, and returns
+// false if items answering the query were left out because of the limit. Descendants
+// queries only return the keys strictly after their pagination start key.
+func (r *StorageQueryReader) AppendItems(items []StorageItem, query StorageQuery, limit int) (
+	_ []StorageItem, complete bool, err error) {
+	if query.Type == StorageQueryClosestDescendantMerkleValue {
+		merkleValue, err := r.closestDescendantMerkleValue(query.Key)
+		if err != nil {
+			return nil, false, fmt.Errorf("getting closest descendant merkle value of key 0x%x: %w", query.Key, err)
+		}
+
+		if merkleValue != nil {
+			if limit <= 0 {
+				return items, false, nil
+			}
+			items = append(items, StorageItem{
+				Key:                          common.BytesToHex(query.Key),
+				ClosestDescendantMerkleValue: common.BytesToHex(merkleValue),
+				ChildTrieKey:                 r.childTrieKey,
+			})
+		}
+		return items, true, nil
+	}
+
+	keys := [][]byte{query.Key}
+	if query.Type == StorageQueryDescendantsValues || query.Type == StorageQueryDescendantsHashes {
+		keys, err = r.keysWithPrefix(query.Key)
+		if err != nil {
+			return nil, false, fmt.Errorf("getting keys with prefix 0x%x: %w", query.Key, err)
+		}
+	}
+
+	added := 0
+	for _, key := range keys {
+		if query.PaginationStartKey != nil && bytes.Compare(key, query.PaginationStartKey) <= 0 {
+			continue
+		}
+
+		value, err := r.get(key)
+		if err != nil {
+			return nil, false, fmt.Errorf("getting value of key 0x%x: %w", key, err)
+		}
+
+		if value == nil {
+			continue
+		}
+
+		if added >= limit {
+			return items, false, nil
+		}
+
+		item := StorageItem{
+			Key:          common.BytesToHex(key),
+			ChildTrieKey: r.childTrieKey,
+		}
+		switch query.Type {
+		case StorageQueryValue, StorageQueryDescendantsValues:
+			item.Value = common.BytesToHex(value)
+		case StorageQueryHash, StorageQueryDescendantsHashes:
+			valueHash, err := common.Blake2bHash(value)
+			if err != nil {
+				return nil, false, fmt.Errorf("hashing value of key 0x%x: %w", key, err)
+			}
+			item.Hash = valueHash.String()
+		}
+		items = append(items, item)
+		added++
+	}
+
+	return items, true, nil
+}
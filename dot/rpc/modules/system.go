@@ -6,10 +6,12 @@
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"net/http"
 	"strings"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -60,6 +62,13 @@ type StringRequest struct {
 	String string
 }
 
+// SystemDryRunRequest holds the hex encoded extrinsic to apply on top of the
+// state of the given block, or of the best block if none is given
+type SystemDryRunRequest struct {
+	Extrinsic string
+	At        *common.Hash
+}
+
 // SyncStateResponse is the struct to return on the system_syncState rpc call
 type SyncStateResponse struct {
 	CurrentBlock  uint32 `json:"currentBlock"`
@@ -272,6 +281,23 @@ func (sm *SystemModule) LocalPeerId(r *http.Request, req *EmptyRequest, res *str
 	return nil
 }
 
+// DryRun applies the extrinsic on a copy of the state of the given block, without
+// submitting it, and returns the hex encoded SCALE ApplyExtrinsicResult.
+func (sm *SystemModule) DryRun(_ *http.Request, req *SystemDryRunRequest, res *string) error {
+	extrinsic, err := common.HexToBytes(req.Extrinsic)
+	if err != nil {
+		return fmt.Errorf("decoding extrinsic: %w", err)
+	}
+
+	applyExtrinsicResult, err := sm.coreAPI.DryRun(types.Extrinsic(extrinsic), req.At)
+	if err != nil {
+		return err
+	}
+
+	*res = common.BytesToHex(applyExtrinsicResult)
+	return nil
+}
+
 // AddReservedPeer adds a reserved peer. The string parameter should encode a p2p multiaddr.
 func (sm *SystemModule) AddReservedPeer(r *http.Request, req *StringRequest, res *[]byte) error {
 	if strings.TrimSpace(req.String) == "" {
@@ -525,6 +525,68 @@ type args struct {
 	}
 }
 
+func TestSystemModule_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	at := common.Hash{1}
+	mockCoreAPI := mocks.NewMockCoreAPI(ctrl)
+	mockCoreAPI.EXPECT().DryRun(types.Extrinsic{1, 2}, &at).Return([]byte{0, 0}, nil)
+
+	mockCoreAPIErr := mocks.NewMockCoreAPI(ctrl)
+	mockCoreAPIErr.EXPECT().DryRun(types.Extrinsic{1, 2}, (*common.Hash)(nil)).
+		Return(nil, errors.New("dryRun error"))
+
+	type args struct {
+		r   *http.Request
+		req *SystemDryRunRequest
+	}
+	tests := []struct {
+		name      string
+		sysModule *SystemModule
+		args      args
+		expErr    error
+		exp       string
+	}{
+		{
+			name:      "OK",
+			sysModule: NewSystemModule(nil, nil, mockCoreAPI, nil, nil, nil, nil),
+			args: args{
+				req: &SystemDryRunRequest{Extrinsic: "0x0102", At: &at},
+			},
+			exp: "0x0000",
+		},
+		{
+			name:      "DryRun Error",
+			sysModule: NewSystemModule(nil, nil, mockCoreAPIErr, nil, nil, nil, nil),
+			args: args{
+				req: &SystemDryRunRequest{Extrinsic: "0x0102"},
+			},
+			expErr: errors.New("dryRun error"),
+		},
+		{
+			name:      "Invalid Extrinsic Error",
+			sysModule: NewSystemModule(nil, nil, nil, nil, nil, nil, nil),
+			args: args{
+				req: &SystemDryRunRequest{Extrinsic: "0x0"},
+			},
+			expErr: errors.New("decoding extrinsic: encoding/hex: odd length hex string: 0x0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := tt.sysModule
+			var res string
+			err := sm.DryRun(tt.args.r, tt.args.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
+
 func TestSystemModule_AddReservedPeer(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -23,7 +23,7 @@ func TestNewService(t *testing.T) {
 }
 
 func TestService_Methods(t *testing.T) {
-	qtySystemMethods := 15
+	qtySystemMethods := 16
 	qtyRPCMethods := 1
 	qtyAuthorMethods := 8
 
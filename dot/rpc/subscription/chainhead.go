@@ -0,0 +1,1133 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// chainHead_v1 RPC methods, see https://paritytech.github.io/json-rpc-interface-spec/api/chainHead.html
+const (
+	chainHeadV1Follow        string = "chainHead_v1_follow"
+	chainHeadV1Unfollow      string = "chainHead_v1_unfollow"
+	chainHeadV1Header        string = "chainHead_v1_header"
+	chainHeadV1Body          string = "chainHead_v1_body"
+	chainHeadV1Call          string = "chainHead_v1_call"
+	chainHeadV1Storage       string = "chainHead_v1_storage"
+	chainHeadV1Unpin         string = "chainHead_v1_unpin"
+	chainHeadV1Continue      string = "chainHead_v1_continue"
+	chainHeadV1StopOperation string = "chainHead_v1_stopOperation"
+
+	chainHeadFollowEventMethod = "chainHead_v1_followEvent"
+)
+
+// chainHead_v1 error codes, derived from Substrate node output
+const (
+	invalidParamsCode                   = -32602
+	chainHeadInvalidBlockCode           = -32801
+	chainHeadInvalidContinueCode        = -32803
+	chainHeadInvalidDuplicateHashesCode = -32804
+)
+
+const (
+	// maxChainHeadPinnedBlocks is the maximum number of blocks pinned by a follow subscription,
+	// the subscription is stopped if the subscriber does not unpin blocks fast enough.
+	maxChainHeadPinnedBlocks = 512
+	// maxChainHeadOperations is the maximum number of operations running at the same time for
+	// a follow subscription.
+	maxChainHeadOperations = 16
+)
+
+var (
+	errTooManyPinnedBlocks      = errors.New("too many pinned blocks")
+	errBlockNotPinned           = errors.New("block hash not pinned")
+	errMissingFinalisedAncestor = errors.New("block is not a descendant of the finalised block")
+	errCoreAPINotSet            = errors.New("error CoreAPI not set")
+)
+
+// chainHeadRuntimeSpec is the specification of a runtime reported by chainHead_v1_followEvent
+type chainHeadRuntimeSpec struct {
+	SpecName           string            `json:"specName"`
+	ImplName           string            `json:"implName"`
+	SpecVersion        uint32            `json:"specVersion"`
+	ImplVersion        uint32            `json:"implVersion"`
+	TransactionVersion uint32            `json:"transactionVersion"`
+	APIs               map[string]uint32 `json:"apis"`
+}
+
+// chainHeadRuntime is either a valid runtime with its specification, or an invalid runtime
+type chainHeadRuntime struct {
+	Type  string                `json:"type"`
+	Spec  *chainHeadRuntimeSpec `json:"spec,omitempty"`
+	Error string                `json:"error,omitempty"`
+}
+
+func newChainHeadRuntime(version runtime.Version) *chainHeadRuntime {
+	apis := make(map[string]uint32, len(version.APIItems))
+	for _, apiItem := range version.APIItems {
+		apis[common.BytesToHex(apiItem.Name[:])] = apiItem.Ver
+	}
+
+	return &chainHeadRuntime{
+		Type: "valid",
+		Spec: &chainHeadRuntimeSpec{
+			SpecName:           string(version.SpecName),
+			ImplName:           string(version.ImplName),
+			SpecVersion:        version.SpecVersion,
+			ImplVersion:        version.ImplVersion,
+			TransactionVersion: version.TransactionVersion,
+			APIs:               apis,
+		},
+	}
+}
+
+// chainHead_v1_followEvent events, the runtime fields are only present if the subscription
+// was created with runtime updates. They hold a *chainHeadRuntime which is encoded as null
+// when there is no new runtime.
+type (
+	chainHeadInitializedEvent struct {
+		Event                 string      `json:"event"`
+		FinalizedBlockHashes  []string    `json:"finalizedBlockHashes"`
+		FinalizedBlockRuntime interface{} `json:"finalizedBlockRuntime,omitempty"`
+	}
+
+	chainHeadNewBlockEvent struct {
+		Event           string      `json:"event"`
+		BlockHash       string      `json:"blockHash"`
+		ParentBlockHash string      `json:"parentBlockHash"`
+		NewRuntime      interface{} `json:"newRuntime,omitempty"`
+	}
+
+	chainHeadBestBlockChangedEvent struct {
+		Event         string `json:"event"`
+		BestBlockHash string `json:"bestBlockHash"`
+	}
+
+	chainHeadFinalizedEvent struct {
+		Event                string   `json:"event"`
+		FinalizedBlockHashes []string `json:"finalizedBlockHashes"`
+		PrunedBlockHashes    []string `json:"prunedBlockHashes"`
+	}
+
+	chainHeadOperationBodyDoneEvent struct {
+		Event       string   `json:"event"`
+		OperationID string   `json:"operationId"`
+		Value       []string `json:"value"`
+	}
+
+	chainHeadOperationCallDoneEvent struct {
+		Event       string `json:"event"`
+		OperationID string `json:"operationId"`
+		Output      string `json:"output"`
+	}
+
+	chainHeadOperationStorageItemsEvent struct {
+		Event       string                 `json:"event"`
+		OperationID string                 `json:"operationId"`
+		Items       []chainHeadStorageItem `json:"items"`
+	}
+
+	chainHeadOperationEvent struct {
+		Event       string `json:"event"`
+		OperationID string `json:"operationId"`
+	}
+
+	chainHeadOperationErrorEvent struct {
+		Event       string `json:"event"`
+		OperationID string `json:"operationId"`
+		Error       string `json:"error"`
+	}
+
+	chainHeadStopEvent struct {
+		Event string `json:"event"`
+	}
+)
+
+// chainHeadStorageItem is a storage item reported by the chainHead_v1_storage operation
+type chainHeadStorageItem struct {
+	Key                          string `json:"key"`
+	Value                        string `json:"value,omitempty"`
+	Hash                         string `json:"hash,omitempty"`
+	ClosestDescendantMerkleValue string `json:"closestDescendantMerkleValue,omitempty"`
+}
+
+// chainHeadOperationStarted is the response of the chainHead_v1 methods starting an operation
+type chainHeadOperationStarted struct {
+	Result         string  `json:"result"`
+	OperationID    string  `json:"operationId,omitempty"`
+	DiscardedItems *uint32 `json:"discardedItems,omitempty"`
+}
+
+var chainHeadLimitReached = chainHeadOperationStarted{Result: "limitReached"}
+
+// chainHeadOperation runs an operation and returns the events to notify once it is done
+type chainHeadOperation func(operationID string) (events []interface{})
+
+// ChainHeadFollowListener keeps track of the blocks reported to a chainHead_v1_follow subscriber,
+// and of the blocks pinned by the subscriber.
+type ChainHeadFollowListener struct {
+	wsconn      *WSConn
+	subID       uint32
+	withRuntime bool
+
+	importedChan  chan *types.Block
+	finalisedChan chan *types.FinalisationInfo
+
+	mutex sync.Mutex
+	// blocks holds the headers of the reported blocks which are not finalised
+	blocks    map[common.Hash]*types.Header
+	finalised *types.Header
+	best      common.Hash
+	pinned    map[common.Hash]struct{}
+	// operations holds the identifiers of the running operations
+	operations      map[string]struct{}
+	nextOperationID uint64
+	stopped         bool
+
+	done          chan struct{}
+	cancel        chan struct{}
+	cancelTimeout time.Duration
+}
+
+func newChainHeadFollowListener(conn *WSConn, withRuntime bool) *ChainHeadFollowListener {
+	return &ChainHeadFollowListener{
+		wsconn:        conn,
+		withRuntime:   withRuntime,
+		blocks:        make(map[common.Hash]*types.Header),
+		pinned:        make(map[common.Hash]struct{}),
+		operations:    make(map[string]struct{}),
+		cancel:        make(chan struct{}, 1),
+		done:          make(chan struct{}, 1),
+		cancelTimeout: defaultCancelTimeout,
+	}
+}
+
+// Listen reports the finalised block and its descendants, then starts a goroutine
+// reporting the imported and finalised blocks
+func (l *ChainHeadFollowListener) Listen() {
+	go func() {
+		defer func() {
+			l.wsconn.BlockAPI.FreeImportedBlockNotifierChannel(l.importedChan)
+			l.wsconn.BlockAPI.FreeFinalisedNotifierChannel(l.finalisedChan)
+			close(l.done)
+		}()
+
+		err := l.initialise()
+		if err != nil {
+			logger.Warnf("failed to initialise chainHead follow subscription %d: %s", l.subID, err)
+			l.stop()
+			return
+		}
+
+		for {
+			select {
+			case <-l.cancel:
+				return
+			case block, ok := <-l.importedChan:
+				if !ok {
+					return
+				}
+
+				if block == nil {
+					continue
+				}
+
+				err = l.handleImportedBlock(block)
+			case info, ok := <-l.finalisedChan:
+				if !ok {
+					return
+				}
+
+				if info == nil {
+					continue
+				}
+
+				err = l.handleFinalisedBlock(info)
+			}
+
+			if err != nil {
+				logger.Warnf("stopping chainHead follow subscription %d: %s", l.subID, err)
+				l.stop()
+				return
+			}
+		}
+	}()
+}
+
+// Stop cancels the goroutine reporting the blocks
+func (l *ChainHeadFollowListener) Stop() error {
+	l.mutex.Lock()
+	l.stopped = true
+	l.mutex.Unlock()
+
+	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
+}
+
+// stop notifies the subscriber that the subscription is stopped and removes it
+// from the websocket connection subscriptions.
+func (l *ChainHeadFollowListener) stop() {
+	l.mutex.Lock()
+	l.stopped = true
+	l.mutex.Unlock()
+
+	l.wsconn.mu.Lock()
+	delete(l.wsconn.Subscriptions, l.subID)
+	l.wsconn.mu.Unlock()
+
+	l.send(chainHeadStopEvent{Event: "stop"})
+}
+
+func (l *ChainHeadFollowListener) send(event interface{}) {
+	l.wsconn.safeSend(newStringSubscriptionResponse(chainHeadFollowEventMethod,
+		strconv.FormatUint(uint64(l.subID), 10), event))
+}
+
+func (l *ChainHeadFollowListener) initialise() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	finalisedHash, err := l.wsconn.BlockAPI.GetHighestFinalisedHash()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised hash: %w", err)
+	}
+
+	l.finalised, err = l.wsconn.BlockAPI.GetHeader(finalisedHash)
+	if err != nil {
+		return fmt.Errorf("getting finalised header: %w", err)
+	}
+	l.pinned[finalisedHash] = struct{}{}
+	l.best = finalisedHash
+
+	initializedEvent := chainHeadInitializedEvent{
+		Event:                "initialized",
+		FinalizedBlockHashes: []string{finalisedHash.String()},
+	}
+	if l.withRuntime {
+		initializedEvent.FinalizedBlockRuntime = l.runtime(finalisedHash)
+	}
+	l.send(initializedEvent)
+
+	descendants, err := l.wsconn.BlockAPI.GetAllDescendants(finalisedHash)
+	if err != nil {
+		return fmt.Errorf("getting descendants of finalised block: %w", err)
+	}
+
+	headers := make([]*types.Header, 0, len(descendants))
+	for _, hash := range descendants {
+		if hash == finalisedHash {
+			continue
+		}
+
+		header, err := l.wsconn.BlockAPI.GetHeader(hash)
+		if err != nil {
+			return fmt.Errorf("getting header of block %s: %w", hash, err)
+		}
+		headers = append(headers, header)
+	}
+
+	// parents must be reported before their children
+	sort.Slice(headers, func(i, j int) bool {
+		return headers[i].Number < headers[j].Number
+	})
+
+	for _, header := range headers {
+		err = l.reportNewBlock(header)
+		if err != nil {
+			return err
+		}
+	}
+
+	l.reportBestBlock()
+	return nil
+}
+
+func (l *ChainHeadFollowListener) handleImportedBlock(block *types.Block) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	err := l.reportNewBlock(&block.Header)
+	if err != nil {
+		return err
+	}
+
+	l.reportBestBlock()
+	return nil
+}
+
+func (l *ChainHeadFollowListener) handleFinalisedBlock(info *types.FinalisationInfo) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if info.Header.Number <= l.finalised.Number {
+		return nil
+	}
+
+	// the finalised block may be notified before being imported
+	err := l.reportNewBlock(&info.Header)
+	if err != nil {
+		return err
+	}
+
+	finalisedHash := info.Header.Hash()
+	previousFinalisedHash := l.finalised.Hash()
+
+	// newly finalised blocks, in ascending order
+	var finalised []common.Hash
+	for current := finalisedHash; current != previousFinalisedHash; current = l.blocks[current].ParentHash {
+		finalised = append([]common.Hash{current}, finalised...)
+	}
+
+	var pruned []*types.Header
+	for hash, header := range l.blocks {
+		if header.Number <= info.Header.Number || !l.isDescendantOf(hash, finalisedHash) {
+			pruned = append(pruned, header)
+		}
+	}
+
+	sort.Slice(pruned, func(i, j int) bool {
+		return pruned[i].Number < pruned[j].Number
+	})
+
+	finalisedHashes := make([]string, len(finalised))
+	for i, hash := range finalised {
+		finalisedHashes[i] = hash.String()
+		delete(l.blocks, hash)
+	}
+
+	prunedHashes := make([]string, 0, len(pruned))
+	for _, header := range pruned {
+		hash := header.Hash()
+		if _, ok := l.blocks[hash]; !ok {
+			// finalised block
+			continue
+		}
+		prunedHashes = append(prunedHashes, hash.String())
+		delete(l.blocks, hash)
+	}
+
+	l.finalised = &info.Header
+
+	// the best block must be updated before reporting the finalisation if it is pruned
+	l.reportBestBlock()
+	_, isBestReported := l.blocks[l.best]
+	if !isBestReported && l.best != finalisedHash {
+		l.best = finalisedHash
+		l.send(chainHeadBestBlockChangedEvent{
+			Event:         "bestBlockChanged",
+			BestBlockHash: finalisedHash.String(),
+		})
+	}
+
+	l.send(chainHeadFinalizedEvent{
+		Event:                "finalized",
+		FinalizedBlockHashes: finalisedHashes,
+		PrunedBlockHashes:    prunedHashes,
+	})
+
+	return nil
+}
+
+// reportNewBlock reports the block to the subscriber if it was not reported yet, along with
+// its ancestors which were not reported yet. It must be called with the mutex held.
+func (l *ChainHeadFollowListener) reportNewBlock(header *types.Header) error {
+	hash := header.Hash()
+	if _, ok := l.blocks[hash]; ok || header.Number <= l.finalised.Number {
+		return nil
+	}
+
+	_, isParentReported := l.blocks[header.ParentHash]
+	if !isParentReported && header.ParentHash != l.finalised.Hash() {
+		if header.Number == l.finalised.Number+1 {
+			return fmt.Errorf("%w: block %s", errMissingFinalisedAncestor, hash)
+		}
+
+		parent, err := l.wsconn.BlockAPI.GetHeader(header.ParentHash)
+		if err != nil {
+			return fmt.Errorf("getting parent header of block %s: %w", hash, err)
+		}
+
+		err = l.reportNewBlock(parent)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(l.pinned) >= maxChainHeadPinnedBlocks {
+		return fmt.Errorf("%w: %d", errTooManyPinnedBlocks, len(l.pinned))
+	}
+
+	l.blocks[hash] = header
+	l.pinned[hash] = struct{}{}
+
+	newBlockEvent := chainHeadNewBlockEvent{
+		Event:           "newBlock",
+		BlockHash:       hash.String(),
+		ParentBlockHash: header.ParentHash.String(),
+	}
+	if l.withRuntime {
+		newBlockEvent.NewRuntime = l.newRuntime(header.ParentHash, hash)
+	}
+	l.send(newBlockEvent)
+
+	return nil
+}
+
+// reportBestBlock reports the best block if it changed and was reported.
+// It must be called with the mutex held.
+func (l *ChainHeadFollowListener) reportBestBlock() {
+	best := l.wsconn.BlockAPI.BestBlockHash()
+	if best == l.best {
+		return
+	}
+
+	if _, ok := l.blocks[best]; !ok && best != l.finalised.Hash() {
+		return
+	}
+
+	l.best = best
+	l.send(chainHeadBestBlockChangedEvent{
+		Event:         "bestBlockChanged",
+		BestBlockHash: best.String(),
+	})
+}
+
+// isDescendantOf returns true if the block is a descendant of the ancestor, using the
+// reported blocks. It must be called with the mutex held.
+func (l *ChainHeadFollowListener) isDescendantOf(hash, ancestor common.Hash) bool {
+	for {
+		header, ok := l.blocks[hash]
+		if !ok {
+			return false
+		}
+
+		if header.ParentHash == ancestor {
+			return true
+		}
+		hash = header.ParentHash
+	}
+}
+
+// runtime returns the runtime of the block
+func (l *ChainHeadFollowListener) runtime(hash common.Hash) *chainHeadRuntime {
+	if l.wsconn.CoreAPI == nil {
+		return &chainHeadRuntime{Type: "invalid", Error: errCoreAPINotSet.Error()}
+	}
+
+	version, err := l.wsconn.CoreAPI.GetRuntimeVersion(&hash)
+	if err != nil {
+		return &chainHeadRuntime{Type: "invalid", Error: err.Error()}
+	}
+
+	return newChainHeadRuntime(version)
+}
+
+// newRuntime returns the runtime of the block if it differs from the runtime of its parent,
+// and nil otherwise.
+func (l *ChainHeadFollowListener) newRuntime(parentHash, hash common.Hash) *chainHeadRuntime {
+	parentRuntime := l.runtime(parentHash)
+	blockRuntime := l.runtime(hash)
+
+	if parentRuntime.Type == blockRuntime.Type && parentRuntime.Error == blockRuntime.Error &&
+		(parentRuntime.Spec == nil) == (blockRuntime.Spec == nil) &&
+		(parentRuntime.Spec == nil || sameRuntimeSpec(parentRuntime.Spec, blockRuntime.Spec)) {
+		return nil
+	}
+
+	return blockRuntime
+}
+
+func sameRuntimeSpec(a, b *chainHeadRuntimeSpec) bool {
+	if a.SpecName != b.SpecName || a.ImplName != b.ImplName || a.SpecVersion != b.SpecVersion ||
+		a.ImplVersion != b.ImplVersion || a.TransactionVersion != b.TransactionVersion ||
+		len(a.APIs) != len(b.APIs) {
+		return false
+	}
+
+	for name, version := range a.APIs {
+		if b.APIs[name] != version {
+			return false
+		}
+	}
+
+	return true
+}
+
+// header returns the header of the pinned block
+func (l *ChainHeadFollowListener) header(hash common.Hash) (*types.Header, error) {
+	l.mutex.Lock()
+	_, ok := l.pinned[hash]
+	l.mutex.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errBlockNotPinned, hash)
+	}
+
+	return l.wsconn.BlockAPI.GetHeader(hash)
+}
+
+// unpin unpins the blocks, either all the blocks are unpinned or none of them.
+func (l *ChainHeadFollowListener) unpin(hashes []common.Hash) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, hash := range hashes {
+		if _, ok := l.pinned[hash]; !ok {
+			return fmt.Errorf("%w: %s", errBlockNotPinned, hash)
+		}
+	}
+
+	for _, hash := range hashes {
+		delete(l.pinned, hash)
+	}
+
+	return nil
+}
+
+// startOperation starts the operation on the pinned block in a goroutine and responds to the
+// request. The subscriber is notified of the events returned by the operation after the response,
+// unless the operation is stopped.
+func (l *ChainHeadFollowListener) startOperation(reqID float64, hash common.Hash,
+	operation chainHeadOperation, discardedItems *uint32) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.stopped || len(l.operations) >= maxChainHeadOperations {
+		l.wsconn.safeSend(newResultResponseJSON(chainHeadLimitReached, reqID))
+		return
+	}
+
+	if _, ok := l.pinned[hash]; !ok {
+		l.wsconn.safeSendError(reqID, big.NewInt(chainHeadInvalidBlockCode),
+			fmt.Errorf("%w: %s", errBlockNotPinned, hash).Error())
+		return
+	}
+
+	operationID := strconv.FormatUint(l.nextOperationID, 10)
+	l.nextOperationID++
+	l.operations[operationID] = struct{}{}
+
+	l.wsconn.safeSend(newResultResponseJSON(chainHeadOperationStarted{
+		Result:         "started",
+		OperationID:    operationID,
+		DiscardedItems: discardedItems,
+	}, reqID))
+
+	go func() {
+		events := operation(operationID)
+
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		if _, ok := l.operations[operationID]; !ok {
+			return
+		}
+		delete(l.operations, operationID)
+
+		for _, event := range events {
+			l.send(event)
+		}
+	}()
+}
+
+// stopOperation stops the operation, its events are not notified.
+func (l *ChainHeadFollowListener) stopOperation(operationID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.operations, operationID)
+}
+
+func (l *ChainHeadFollowListener) bodyOperation(hash common.Hash) chainHeadOperation {
+	return func(operationID string) []interface{} {
+		block, err := l.wsconn.BlockAPI.GetBlockByHash(hash)
+		if err != nil {
+			return []interface{}{newChainHeadOperationError(operationID, err)}
+		}
+
+		extrinsics := make([]string, len(block.Body))
+		for i, extrinsic := range block.Body {
+			extrinsics[i] = common.BytesToHex(extrinsic)
+		}
+
+		return []interface{}{chainHeadOperationBodyDoneEvent{
+			Event:       "operationBodyDone",
+			OperationID: operationID,
+			Value:       extrinsics,
+		}}
+	}
+}
+
+func (l *ChainHeadFollowListener) callOperation(hash common.Hash, function string,
+	parameters []byte) chainHeadOperation {
+	return func(operationID string) []interface{} {
+		output, err := l.call(hash, function, parameters)
+		if err != nil {
+			return []interface{}{newChainHeadOperationError(operationID, err)}
+		}
+
+		return []interface{}{chainHeadOperationCallDoneEvent{
+			Event:       "operationCallDone",
+			OperationID: operationID,
+			Output:      common.BytesToHex(output),
+		}}
+	}
+}
+
+// call executes the runtime function on the state of the given block, using a pooled
+// instance of its runtime so the block import instance and its storage are untouched.
+func (l *ChainHeadFollowListener) call(hash common.Hash, function string, parameters []byte) ([]byte, error) {
+	if l.wsconn.StorageAPI == nil {
+		return nil, errStorageNotSet
+	}
+
+	instance, err := l.wsconn.BlockAPI.GetRuntime(hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting runtime: %w", err)
+	}
+
+	root, err := l.wsconn.StorageAPI.GetStateRootFromBlock(&hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting state root: %w", err)
+	}
+
+	l.wsconn.StorageAPI.Lock()
+	trieState, err := l.wsconn.StorageAPI.TrieState(root)
+	l.wsconn.StorageAPI.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("getting trie state: %w", err)
+	}
+
+	return instance.ExecWithStorage(trieState, function, parameters)
+}
+
+func (l *ChainHeadFollowListener) storageOperation(hash common.Hash, queries []modules.StorageQuery,
+	childTrie []byte) chainHeadOperation {
+	return func(operationID string) []interface{} {
+		items, err := l.storageItems(hash, queries, childTrie)
+		if err != nil {
+			return []interface{}{newChainHeadOperationError(operationID, err)}
+		}
+
+		events := make([]interface{}, 0, 2)
+		if len(items) > 0 {
+			events = append(events, chainHeadOperationStorageItemsEvent{
+				Event:       "operationStorageItems",
+				OperationID: operationID,
+				Items:       items,
+			})
+		}
+
+		return append(events, chainHeadOperationEvent{
+			Event:       "operationStorageDone",
+			OperationID: operationID,
+		})
+	}
+}
+
+func (l *ChainHeadFollowListener) storageItems(hash common.Hash, queries []modules.StorageQuery,
+	childTrie []byte) (items []chainHeadStorageItem, err error) {
+	if l.wsconn.StorageAPI == nil {
+		return nil, errStorageNotSet
+	}
+
+	root, err := l.wsconn.StorageAPI.GetStateRootFromBlock(&hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting state root: %w", err)
+	}
+
+	reader, err := modules.NewStorageQueryReader(l.wsconn.StorageAPI, root, childTrie)
+	if err != nil {
+		return nil, err
+	}
+
+	var storageItems []modules.StorageItem
+	for _, query := range queries {
+		storageItems, _, err = reader.AppendItems(storageItems, query, math.MaxInt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items = make([]chainHeadStorageItem, len(storageItems))
+	for i, item := range storageItems {
+		items[i] = chainHeadStorageItem{
+			Key:                          item.Key,
+			Value:                        item.Value,
+			Hash:                         item.Hash,
+			ClosestDescendantMerkleValue: item.ClosestDescendantMerkleValue,
+		}
+	}
+
+	return items, nil
+}
+
+func newChainHeadOperationError(operationID string, err error) chainHeadOperationErrorEvent {
+	return chainHeadOperationErrorEvent{
+		Event:       "operationError",
+		OperationID: operationID,
+		Error:       err.Error(),
+	}
+}
+
+func (c *WSConn) initChainHeadFollow(reqID float64, params interface{}) (Listener, error) {
+	if c.BlockAPI == nil {
+		c.safeSendError(reqID, nil, errBlockAPINotSet.Error())
+		return nil, errBlockAPINotSet
+	}
+
+	var withRuntime bool
+	switch params := params.(type) {
+	case []interface{}:
+		if len(params) != 1 {
+			c.safeSendError(reqID, big.NewInt(invalidParamsCode), "expected 1 param")
+			return nil, fmt.Errorf("%w: expected 1 param, got: %d", errUnexpectedParamLen, len(params))
+		}
+
+		var ok bool
+		withRuntime, ok = params[0].(bool)
+		if !ok {
+			c.safeSendError(reqID, big.NewInt(invalidParamsCode), "withRuntime must be a boolean")
+			return nil, fmt.Errorf("%w: %T, expected type bool", errUnexpectedType, params[0])
+		}
+	default:
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), "expected 1 param")
+		return nil, fmt.Errorf("%w: %T, expected type []interface{}", errUnexpectedType, params)
+	}
+
+	listener := newChainHeadFollowListener(c, withRuntime)
+	listener.importedChan = c.BlockAPI.GetImportedBlockNotifierChannel()
+	listener.finalisedChan = c.BlockAPI.GetFinalisedNotifierChannel()
+
+	c.mu.Lock()
+	listener.subID = atomic.AddUint32(&c.qtyListeners, 1)
+	c.Subscriptions[listener.subID] = listener
+	c.mu.Unlock()
+
+	c.safeSend(newResultResponseJSON(strconv.FormatUint(uint64(listener.subID), 10), reqID))
+
+	return listener, nil
+}
+
+type chainHeadHandler func(reqID float64, params []interface{})
+
+func (c *WSConn) getChainHeadHandler(method string) (handler chainHeadHandler, paramsLen int) {
+	switch method {
+	case chainHeadV1Unfollow:
+		return c.chainHeadUnfollow, 1
+	case chainHeadV1Header:
+		return c.chainHeadHeader, 2
+	case chainHeadV1Body:
+		return c.chainHeadBody, 2
+	case chainHeadV1Call:
+		return c.chainHeadCall, 4
+	case chainHeadV1Storage:
+		return c.chainHeadStorage, 4
+	case chainHeadV1Unpin:
+		return c.chainHeadUnpin, 2
+	case chainHeadV1Continue:
+		return c.chainHeadContinue, 2
+	case chainHeadV1StopOperation:
+		return c.chainHeadStopOperation, 2
+	default:
+		return nil, 0
+	}
+}
+
+// handleChainHeadCall handles the chainHead_v1 method calls and returns true,
+// or returns false if the method is not a chainHead_v1 method.
+func (c *WSConn) handleChainHeadCall(method string, reqID float64, params interface{}) bool {
+	handler, paramsLen := c.getChainHeadHandler(method)
+	if handler == nil {
+		return false
+	}
+
+	paramsSlice, ok := params.([]interface{})
+	// the child trie of chainHead_v1_storage is optional
+	if !ok || len(paramsSlice) > paramsLen ||
+		(len(paramsSlice) < paramsLen && !(method == chainHeadV1Storage && len(paramsSlice) == paramsLen-1)) {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), fmt.Sprintf("expected %d params", paramsLen))
+		return true
+	}
+
+	handler(reqID, paramsSlice)
+	return true
+}
+
+// getChainHeadFollowListener returns the follow listener of the subscription identifier
+// given as parameter, or nil if the subscription does not exist.
+func (c *WSConn) getChainHeadFollowListener(param interface{}) *ChainHeadFollowListener {
+	subscriptionID, ok := param.(string)
+	if !ok {
+		return nil
+	}
+
+	subID, err := strconv.ParseUint(subscriptionID, 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	listener, _ := c.Subscriptions[uint32(subID)].(*ChainHeadFollowListener)
+	return listener
+}
+
+// chainHeadParamHash parses the block hash parameter and sends an error response if it is invalid
+func (c *WSConn) chainHeadParamHash(reqID float64, param interface{}) (hash common.Hash, ok bool) {
+	hashHex, ok := param.(string)
+	if !ok {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), "block hash must be a string")
+		return hash, false
+	}
+
+	hash, err := common.HexToHash(hashHex)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), fmt.Sprintf("invalid block hash: %s", err))
+		return hash, false
+	}
+
+	return hash, true
+}
+
+// chainHeadParamBytes parses the hex encoded bytes parameter and sends an error response if it is invalid
+func (c *WSConn) chainHeadParamBytes(reqID float64, name string, param interface{}) (b []byte, ok bool) {
+	hex, ok := param.(string)
+	if !ok {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), name+" must be a string")
+		return nil, false
+	}
+
+	b, err := common.HexToBytes(hex)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), fmt.Sprintf("invalid %s: %s", name, err))
+		return nil, false
+	}
+
+	return b, true
+}
+
+func (c *WSConn) chainHeadUnfollow(reqID float64, params []interface{}) {
+	listener := c.getChainHeadFollowListener(params[0])
+	if listener != nil {
+		c.mu.Lock()
+		delete(c.Subscriptions, listener.subID)
+		c.mu.Unlock()
+
+		err := listener.Stop()
+		if err != nil {
+			logger.Warnf("failed to stop chainHead follow subscription %d: %s", listener.subID, err)
+		}
+	}
+
+	c.safeSend(newResultResponseJSON(nil, reqID))
+}
+
+func (c *WSConn) chainHeadHeader(reqID float64, params []interface{}) {
+	hash, ok := c.chainHeadParamHash(reqID, params[1])
+	if !ok {
+		return
+	}
+
+	listener := c.getChainHeadFollowListener(params[0])
+	if listener == nil {
+		c.safeSend(newResultResponseJSON(nil, reqID))
+		return
+	}
+
+	header, err := listener.header(hash)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(chainHeadInvalidBlockCode), err.Error())
+		return
+	}
+
+	encodedHeader, err := scale.Marshal(*header)
+	if err != nil {
+		c.safeSendError(reqID, nil, fmt.Sprintf("encoding header: %s", err))
+		return
+	}
+
+	c.safeSend(newResultResponseJSON(common.BytesToHex(encodedHeader), reqID))
+}
+
+func (c *WSConn) chainHeadBody(reqID float64, params []interface{}) {
+	hash, ok := c.chainHeadParamHash(reqID, params[1])
+	if !ok {
+		return
+	}
+
+	listener := c.getChainHeadFollowListener(params[0])
+	if listener == nil {
+		c.safeSend(newResultResponseJSON(chainHeadLimitReached, reqID))
+		return
+	}
+
+	listener.startOperation(reqID, hash, listener.bodyOperation(hash), nil)
+}
+
+func (c *WSConn) chainHeadCall(reqID float64, params []interface{}) {
+	hash, ok := c.chainHeadParamHash(reqID, params[1])
+	if !ok {
+		return
+	}
+
+	function, ok := params[2].(string)
+	if !ok {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), "function must be a string")
+		return
+	}
+
+	callParameters, ok := c.chainHeadParamBytes(reqID, "call parameters", params[3])
+	if !ok {
+		return
+	}
+
+	listener := c.getChainHeadFollowListener(params[0])
+	if listener == nil {
+		c.safeSend(newResultResponseJSON(chainHeadLimitReached, reqID))
+		return
+	}
+
+	listener.startOperation(reqID, hash, listener.callOperation(hash, function, callParameters), nil)
+}
+
+func (c *WSConn) chainHeadStorage(reqID float64, params []interface{}) {
+	hash, ok := c.chainHeadParamHash(reqID, params[1])
+	if !ok {
+		return
+	}
+
+	queries, err := parseChainHeadStorageQueries(params[2])
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), err.Error())
+		return
+	}
+
+	var childTrie []byte
+	if len(params) == 4 && params[3] != nil {
+		childTrie, ok = c.chainHeadParamBytes(reqID, "child trie", params[3])
+		if !ok {
+			return
+		}
+	}
+
+	listener := c.getChainHeadFollowListener(params[0])
+	if listener == nil {
+		c.safeSend(newResultResponseJSON(chainHeadLimitReached, reqID))
+		return
+	}
+
+	// all the storage items are reported, none is discarded
+	discardedItems := uint32(0)
+	listener.startOperation(reqID, hash, listener.storageOperation(hash, queries, childTrie), &discardedItems)
+}
+
+func parseChainHeadStorageQueries(param interface{}) ([]modules.StorageQuery, error) {
+	items, ok := param.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %T, expected type []interface{}", errUnexpectedType, param)
+	}
+
+	queries := make([]modules.StorageQuery, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %T, expected type map[string]interface{}", errUnexpectedType, item)
+		}
+
+		key, ok := fields["key"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: key %T, expected type string", errUnexpectedType, fields["key"])
+		}
+
+		queryType, ok := fields["type"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: type %T, expected type string", errUnexpectedType, fields["type"])
+		}
+
+		query, err := modules.NewStorageQuery(key, queryType, nil)
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = query
+	}
+
+	return queries, nil
+}
+
+func (c *WSConn) chainHeadUnpin(reqID float64, params []interface{}) {
+	var hashParams []interface{}
+	switch param := params[1].(type) {
+	case []interface{}:
+		hashParams = param
+	default:
+		hashParams = []interface{}{param}
+	}
+
+	hashes := make([]common.Hash, len(hashParams))
+	seen := make(map[common.Hash]struct{}, len(hashParams))
+	for i, hashParam := range hashParams {
+		hash, ok := c.chainHeadParamHash(reqID, hashParam)
+		if !ok {
+			return
+		}
+
+		if _, ok := seen[hash]; ok {
+			c.safeSendError(reqID, big.NewInt(chainHeadInvalidDuplicateHashesCode),
+				fmt.Sprintf("duplicate block hash %s", hash))
+			return
+		}
+		seen[hash] = struct{}{}
+		hashes[i] = hash
+	}
+
+	listener := c.getChainHeadFollowListener(params[0])
+	if listener != nil {
+		err := listener.unpin(hashes)
+		if err != nil {
+			c.safeSendError(reqID, big.NewInt(chainHeadInvalidBlockCode), err.Error())
+			return
+		}
+	}
+
+	c.safeSend(newResultResponseJSON(nil, reqID))
+}
+
+// chainHeadContinue always fails since operations are never paused to wait for a continue
+func (c *WSConn) chainHeadContinue(reqID float64, params []interface{}) {
+	if c.getChainHeadFollowListener(params[0]) == nil {
+		c.safeSend(newResultResponseJSON(nil, reqID))
+		return
+	}
+
+	c.safeSendError(reqID, big.NewInt(chainHeadInvalidContinueCode), "operation is not waiting for continue")
+}
+
+func (c *WSConn) chainHeadStopOperation(reqID float64, params []interface{}) {
+	operationID, ok := params[1].(string)
+	if !ok {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), "operation id must be a string")
+		return
+	}
+
+	listener := c.getChainHeadFollowListener(params[0])
+	if listener != nil {
+		listener.stopOperation(operationID)
+	}
+
+	c.safeSend(newResultResponseJSON(nil, reqID))
+}
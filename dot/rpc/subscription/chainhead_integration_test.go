@@ -0,0 +1,270 @@
+//go:build integration
+
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func requireNextMessage(t *testing.T, ws *websocket.Conn, expected interface{}) {
+	t.Helper()
+
+	_, msg, err := ws.ReadMessage()
+	require.NoError(t, err)
+
+	expectedBytes, err := json.Marshal(expected)
+	require.NoError(t, err)
+
+	require.Equal(t, string(expectedBytes)+"\n", string(msg))
+}
+
+func chainHeadFollowEvent(event interface{}) StringSubscriptionResponseJSON {
+	return newStringSubscriptionResponse(chainHeadFollowEventMethod, "1", event)
+}
+
+func TestChainHeadFollowListener(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+	wsconn.Subscriptions = make(map[uint32]Listener)
+
+	finalised := types.Header{Number: 1, ParentHash: common.Hash{1}}
+	finalisedHash := finalised.Hash()
+	blockA := types.Header{Number: 2, ParentHash: finalisedHash}
+	blockAHash := blockA.Hash()
+	blockB := types.Block{Header: types.Header{Number: 3, ParentHash: blockAHash}}
+	blockBHash := blockB.Header.Hash()
+	fork := types.Header{Number: 2, ParentHash: finalisedHash, StateRoot: common.Hash{2}}
+	forkHash := fork.Hash()
+
+	importedChan := make(chan *types.Block)
+	finalisedChan := make(chan *types.FinalisationInfo)
+
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetImportedBlockNotifierChannel().Return(importedChan)
+	blockAPI.EXPECT().GetFinalisedNotifierChannel().Return(finalisedChan)
+	blockAPI.EXPECT().GetHighestFinalisedHash().Return(finalisedHash, nil)
+	blockAPI.EXPECT().GetHeader(finalisedHash).Return(&finalised, nil).AnyTimes()
+	blockAPI.EXPECT().GetAllDescendants(finalisedHash).
+		Return([]common.Hash{finalisedHash, forkHash, blockAHash}, nil)
+	blockAPI.EXPECT().GetHeader(blockAHash).Return(&blockA, nil).AnyTimes()
+	blockAPI.EXPECT().GetHeader(forkHash).Return(&fork, nil).AnyTimes()
+	blockAPI.EXPECT().BestBlockHash().Return(blockAHash)
+	blockAPI.EXPECT().FreeImportedBlockNotifierChannel(importedChan)
+	blockAPI.EXPECT().FreeFinalisedNotifierChannel(finalisedChan)
+	wsconn.BlockAPI = blockAPI
+
+	listener, err := wsconn.initChainHeadFollow(1, []interface{}{false})
+	require.NoError(t, err)
+	requireNextMessage(t, ws, newResultResponseJSON("1", 1))
+
+	listener.Listen()
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadInitializedEvent{
+		Event:                "initialized",
+		FinalizedBlockHashes: []string{finalisedHash.String()},
+	}))
+	// blocks are reported in ascending block number order
+	for _, header := range []types.Header{fork, blockA} {
+		requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadNewBlockEvent{
+			Event:           "newBlock",
+			BlockHash:       header.Hash().String(),
+			ParentBlockHash: finalisedHash.String(),
+		}))
+	}
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadBestBlockChangedEvent{
+		Event:         "bestBlockChanged",
+		BestBlockHash: blockAHash.String(),
+	}))
+
+	blockAPI.EXPECT().BestBlockHash().Return(blockBHash)
+	importedChan <- &blockB
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadNewBlockEvent{
+		Event:           "newBlock",
+		BlockHash:       blockBHash.String(),
+		ParentBlockHash: blockAHash.String(),
+	}))
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadBestBlockChangedEvent{
+		Event:         "bestBlockChanged",
+		BestBlockHash: blockBHash.String(),
+	}))
+
+	blockAPI.EXPECT().BestBlockHash().Return(blockBHash)
+	finalisedChan <- &types.FinalisationInfo{Header: blockA}
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadFinalizedEvent{
+		Event:                "finalized",
+		FinalizedBlockHashes: []string{blockAHash.String()},
+		PrunedBlockHashes:    []string{forkHash.String()},
+	}))
+
+	// header of a pinned block
+	blockAPI.EXPECT().GetHeader(blockBHash).Return(&blockB.Header, nil)
+	wsconn.chainHeadHeader(2, []interface{}{"1", blockBHash.String()})
+	encodedHeader, err := scale.Marshal(blockB.Header)
+	require.NoError(t, err)
+	requireNextMessage(t, ws, newResultResponseJSON(common.BytesToHex(encodedHeader), 2))
+
+	// unpinning a block twice in the same call fails
+	wsconn.chainHeadUnpin(3, []interface{}{"1", []interface{}{forkHash.String(), forkHash.String()}})
+	_, msg, err := ws.ReadMessage()
+	require.NoError(t, err)
+	require.Contains(t, string(msg), `"code":-32804`)
+
+	wsconn.chainHeadUnpin(4, []interface{}{"1", forkHash.String()})
+	requireNextMessage(t, ws, newResultResponseJSON(nil, 4))
+
+	// the block is no longer pinned
+	wsconn.chainHeadHeader(5, []interface{}{"1", forkHash.String()})
+	_, msg, err = ws.ReadMessage()
+	require.NoError(t, err)
+	require.Contains(t, string(msg), `"code":-32801`)
+
+	blockAPI.EXPECT().GetBlockByHash(blockBHash).
+		Return(&types.Block{Header: blockB.Header, Body: types.Body{{1, 2}}}, nil)
+	wsconn.chainHeadBody(6, []interface{}{"1", blockBHash.String()})
+	requireNextMessage(t, ws, newResultResponseJSON(chainHeadOperationStarted{
+		Result:      "started",
+		OperationID: "0",
+	}, 6))
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadOperationBodyDoneEvent{
+		Event:       "operationBodyDone",
+		OperationID: "0",
+		Value:       []string{"0x0102"},
+	}))
+
+	storageAPI := mocks.NewMockStorageAPI(ctrl)
+	stateRoot := common.Hash{3}
+	storageAPI.EXPECT().GetStateRootFromBlock(&blockBHash).Return(&stateRoot, nil)
+	storageAPI.EXPECT().GetStorage(&stateRoot, []byte{1}).Return([]byte{2}, nil)
+	storageAPI.EXPECT().GetKeysWithPrefix(&stateRoot, []byte{3}).Return([][]byte{{3, 1}, {3, 2}}, nil)
+	storageAPI.EXPECT().GetStorage(&stateRoot, []byte{3, 1}).Return([]byte{4}, nil)
+	storageAPI.EXPECT().GetStorage(&stateRoot, []byte{3, 2}).Return([]byte{5}, nil)
+	wsconn.StorageAPI = storageAPI
+
+	wsconn.chainHeadStorage(7, []interface{}{"1", blockBHash.String(), []interface{}{
+		map[string]interface{}{"key": "0x01", "type": "value"},
+		map[string]interface{}{"key": "0x03", "type": "descendantsHashes"},
+	}})
+	discardedItems := uint32(0)
+	requireNextMessage(t, ws, newResultResponseJSON(chainHeadOperationStarted{
+		Result:         "started",
+		OperationID:    "1",
+		DiscardedItems: &discardedItems,
+	}, 7))
+	hash4, err := common.Blake2bHash([]byte{4})
+	require.NoError(t, err)
+	hash5, err := common.Blake2bHash([]byte{5})
+	require.NoError(t, err)
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadOperationStorageItemsEvent{
+		Event:       "operationStorageItems",
+		OperationID: "1",
+		Items: []chainHeadStorageItem{
+			{Key: "0x01", Value: "0x02"},
+			{Key: "0x0301", Hash: hash4.String()},
+			{Key: "0x0302", Hash: hash5.String()},
+		},
+	}))
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadOperationEvent{
+		Event:       "operationStorageDone",
+		OperationID: "1",
+	}))
+
+	storageAPI.EXPECT().GetStateRootFromBlock(&blockBHash).Return(&stateRoot, nil)
+	storageAPI.EXPECT().GetClosestDescendantMerkleValue(&stateRoot, []byte{1}).Return([]byte{6}, nil)
+	wsconn.chainHeadStorage(8, []interface{}{"1", blockBHash.String(), []interface{}{
+		map[string]interface{}{"key": "0x01", "type": "closestDescendantMerkleValue"},
+	}})
+	requireNextMessage(t, ws, newResultResponseJSON(chainHeadOperationStarted{
+		Result:         "started",
+		OperationID:    "2",
+		DiscardedItems: &discardedItems,
+	}, 8))
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadOperationStorageItemsEvent{
+		Event:       "operationStorageItems",
+		OperationID: "2",
+		Items:       []chainHeadStorageItem{{Key: "0x01", ClosestDescendantMerkleValue: "0x06"}},
+	}))
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadOperationEvent{
+		Event:       "operationStorageDone",
+		OperationID: "2",
+	}))
+
+	wsconn.chainHeadStorage(8, []interface{}{"1", blockBHash.String(), []interface{}{
+		map[string]interface{}{"key": "0x01", "type": "unknown"},
+	}})
+	_, msg, err = ws.ReadMessage()
+	require.NoError(t, err)
+	require.Contains(t, string(msg), `"code":-32602`)
+
+	// the call runs on a pooled runtime instance with the state of the block
+	instance := mocksruntime.NewMockInstance(ctrl)
+	trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+	blockAPI.EXPECT().GetRuntime(blockBHash).Return(instance, nil)
+	storageAPI.EXPECT().GetStateRootFromBlock(&blockBHash).Return(&stateRoot, nil)
+	storageAPI.EXPECT().Lock()
+	storageAPI.EXPECT().TrieState(&stateRoot).Return(trieState, nil)
+	storageAPI.EXPECT().Unlock()
+	instance.EXPECT().ExecWithStorage(trieState, "Core_version", []byte{1}).Return([]byte{2}, nil)
+	wsconn.chainHeadCall(8, []interface{}{"1", blockBHash.String(), "Core_version", "0x01"})
+	requireNextMessage(t, ws, newResultResponseJSON(chainHeadOperationStarted{
+		Result:      "started",
+		OperationID: "3",
+	}, 8))
+	requireNextMessage(t, ws, chainHeadFollowEvent(chainHeadOperationCallDoneEvent{
+		Event:       "operationCallDone",
+		OperationID: "3",
+		Output:      "0x02",
+	}))
+
+	wsconn.chainHeadUnfollow(9, []interface{}{"1"})
+	requireNextMessage(t, ws, newResultResponseJSON(nil, 9))
+	require.Empty(t, wsconn.Subscriptions)
+
+	// operations on a stopped subscription are not started
+	wsconn.chainHeadBody(10, []interface{}{"1", blockBHash.String()})
+	requireNextMessage(t, ws, newResultResponseJSON(chainHeadLimitReached, 10))
+}
+
+func TestWSConn_handleChainHeadCall(t *testing.T) {
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+	wsconn.Subscriptions = make(map[uint32]Listener)
+
+	require.False(t, wsconn.handleChainHeadCall("chain_getHeader", 1, []interface{}{}))
+
+	require.True(t, wsconn.handleChainHeadCall(chainHeadV1Header, 1, []interface{}{"1"}))
+	_, msg, err := ws.ReadMessage()
+	require.NoError(t, err)
+	expected := &ErrorResponseJSON{
+		Jsonrpc: "2.0",
+		Error: &ErrorMessageJSON{
+			Code:    big.NewInt(invalidParamsCode),
+			Message: "expected 2 params",
+		},
+		ID: 1,
+	}
+	expectedBytes, err := json.Marshal(expected)
+	require.NoError(t, err)
+	require.Equal(t, string(expectedBytes)+"\n", string(msg))
+
+	// unknown subscriptions are ignored
+	require.True(t, wsconn.handleChainHeadCall(chainHeadV1Header, 2,
+		[]interface{}{"1", common.Hash{}.String()}))
+	requireNextMessage(t, ws, newResultResponseJSON(nil, 2))
+}
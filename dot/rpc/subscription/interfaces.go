@@ -4,27 +4,45 @@
 package subscription
 
 import (
+	"sync"
+
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/ChainSafe/gossamer/pkg/trie"
 )
 
 // StorageAPI is the interface for the storage state
 type StorageAPI interface {
+	GetStorage(root *common.Hash, key []byte) ([]byte, error)
+	GetStorageChild(root *common.Hash, keyToChild []byte) (trie.Trie, error)
+	GetStorageFromChild(root *common.Hash, keyToChild, key []byte) ([]byte, error)
+	GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error)
+	GetKeysWithPrefix(root *common.Hash, prefix []byte) ([][]byte, error)
+	GetClosestDescendantMerkleValue(root *common.Hash, key []byte) ([]byte, error)
+	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
 	RegisterStorageObserver(observer state.Observer)
 	UnregisterStorageObserver(observer state.Observer)
+	sync.Locker
 }
 
 // BlockAPI is the interface for the block state
 type BlockAPI interface {
+	GetHeader(hash common.Hash) (*types.Header, error)
+	GetBlockByHash(hash common.Hash) (*types.Block, error)
+	BestBlockHash() common.Hash
+	GetHighestFinalisedHash() (common.Hash, error)
+	GetAllDescendants(hash common.Hash) ([]common.Hash, error)
 	GetJustification(hash common.Hash) ([]byte, error)
 	GetImportedBlockNotifierChannel() chan *types.Block
 	FreeImportedBlockNotifierChannel(ch chan *types.Block)
 	GetFinalisedNotifierChannel() chan *types.FinalisationInfo
 	FreeFinalisedNotifierChannel(ch chan *types.FinalisationInfo)
 	RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error)
+	GetRuntime(blockHash common.Hash) (instance runtime.Instance, err error)
 }
 
 // TransactionStateAPI is the interface to get and free status notifier channels
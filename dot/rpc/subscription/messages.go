@@ -69,3 +69,42 @@ func newBooleanResponseJSON(value bool, reqID float64) BooleanResponse {
 		ID:      reqID,
 	}
 }
+
+// StringSubscriptionParams for json param response of subscriptions identified by a string
+type StringSubscriptionParams struct {
+	Result         interface{} `json:"result"`
+	SubscriptionID string      `json:"subscription"`
+}
+
+// StringSubscriptionResponseJSON for json notifications of subscriptions identified by a string
+type StringSubscriptionResponseJSON struct {
+	Jsonrpc string                   `json:"jsonrpc"`
+	Method  string                   `json:"method"`
+	Params  StringSubscriptionParams `json:"params"`
+}
+
+func newStringSubscriptionResponse(method, subID string, result interface{}) StringSubscriptionResponseJSON {
+	return StringSubscriptionResponseJSON{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params: StringSubscriptionParams{
+			Result:         result,
+			SubscriptionID: subID,
+		},
+	}
+}
+
+// ResultResponseJSON for responses with a result of any type, including null
+type ResultResponseJSON struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	Result  interface{} `json:"result"`
+	ID      float64     `json:"id"`
+}
+
+func newResultResponseJSON(result interface{}, reqID float64) ResultResponseJSON {
+	return ResultResponseJSON{
+		Jsonrpc: "2.0",
+		Result:  result,
+		ID:      reqID,
+	}
+}
@@ -46,6 +46,12 @@ func (c *WSConn) getSetupListener(method string) setupListener {
 		return c.initRuntimeVersionListener
 	case grandpaSubscribeJustifications:
 		return c.initGrandpaJustificationListener
+	case chainHeadV1Follow:
+		return c.initChainHeadFollow
+	case transactionWatchV1SubmitAndWatch:
+		return c.initTransactionWatch
+	case transactionV1Broadcast:
+		return c.initTransactionBroadcast
 	default:
 		return nil
 	}
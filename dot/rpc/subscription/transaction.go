@@ -0,0 +1,458 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+)
+
+// transactionWatch_v1 and transaction_v1 RPC methods, see
+// https://paritytech.github.io/json-rpc-interface-spec/api/transactionWatch.html and
+// https://paritytech.github.io/json-rpc-interface-spec/api/transaction.html
+const (
+	transactionWatchV1SubmitAndWatch string = "transactionWatch_v1_submitAndWatch"
+	transactionWatchV1Unwatch        string = "transactionWatch_v1_unwatch"
+	transactionV1Broadcast           string = "transaction_v1_broadcast"
+	transactionV1Stop                string = "transaction_v1_stop"
+
+	transactionWatchEventMethod = "transactionWatch_v1_watchEvent"
+)
+
+// transactionBroadcastRetryInterval is the interval at which the submission of a broadcast
+// transaction is retried until it is accepted by the transaction pool.
+const transactionBroadcastRetryInterval = 6 * time.Second
+
+// transactionBlock is the block including a transaction, with the index of the transaction
+// in the block body
+type transactionBlock struct {
+	Hash  string `json:"hash"`
+	Index uint   `json:"index"`
+}
+
+// transactionInclusion is the number of a block including a transaction, and the index of the
+// transaction in the block body
+type transactionInclusion struct {
+	number uint
+	index  uint
+}
+
+// transactionWatch_v1_watchEvent events
+type (
+	transactionEvent struct {
+		Event string `json:"event"`
+	}
+
+	transactionBlockIncludedEvent struct {
+		Event string            `json:"event"`
+		Block *transactionBlock `json:"block"`
+	}
+
+	transactionErrorEvent struct {
+		Event string `json:"event"`
+		Error string `json:"error"`
+	}
+)
+
+// TransactionWatchListener notifies the subscriber of the events of a transaction
+// submitted with transactionWatch_v1_submitAndWatch, until the transaction is finalised,
+// dropped or found invalid.
+type TransactionWatchListener struct {
+	wsconn        *WSConn
+	subID         uint32
+	extrinsic     types.Extrinsic
+	importedChan  chan *types.Block
+	finalisedChan chan *types.FinalisationInfo
+	txStatusChan  chan transaction.Status
+	// included holds the imported blocks including the transaction
+	included map[common.Hash]transactionInclusion
+	// bestIncluded is the best chain block including the transaction, if any
+	bestIncluded  *transactionBlock
+	done          chan struct{}
+	cancel        chan struct{}
+	cancelTimeout time.Duration
+}
+
+func newTransactionWatchListener(conn *WSConn, extrinsic types.Extrinsic) *TransactionWatchListener {
+	return &TransactionWatchListener{
+		wsconn:        conn,
+		extrinsic:     extrinsic,
+		included:      make(map[common.Hash]transactionInclusion),
+		cancel:        make(chan struct{}, 1),
+		done:          make(chan struct{}, 1),
+		cancelTimeout: defaultCancelTimeout,
+	}
+}
+
+// Listen notifies the subscriber that the transaction is validated, and starts a goroutine
+// notifying the subscriber of the transaction events
+func (l *TransactionWatchListener) Listen() {
+	l.send(transactionEvent{Event: "validated"})
+
+	go func() {
+		defer func() {
+			l.wsconn.BlockAPI.FreeImportedBlockNotifierChannel(l.importedChan)
+			l.wsconn.BlockAPI.FreeFinalisedNotifierChannel(l.finalisedChan)
+			l.wsconn.TxStateAPI.FreeStatusNotifierChannel(l.txStatusChan)
+			close(l.done)
+		}()
+
+		for {
+			var final bool
+			select {
+			case <-l.cancel:
+				return
+			case block, ok := <-l.importedChan:
+				if !ok {
+					return
+				}
+
+				if block == nil {
+					continue
+				}
+
+				l.handleImportedBlock(block)
+			case info, ok := <-l.finalisedChan:
+				if !ok {
+					return
+				}
+
+				if info == nil {
+					continue
+				}
+
+				final = l.handleFinalisedBlock(&info.Header)
+			case txStatus, ok := <-l.txStatusChan:
+				if !ok {
+					return
+				}
+
+				final = l.handleStatus(txStatus)
+			}
+
+			if final {
+				l.wsconn.mu.Lock()
+				delete(l.wsconn.Subscriptions, l.subID)
+				l.wsconn.mu.Unlock()
+				return
+			}
+		}
+	}()
+}
+
+// Stop cancels the goroutine notifying the transaction events
+func (l *TransactionWatchListener) Stop() error {
+	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
+}
+
+func (l *TransactionWatchListener) send(event interface{}) {
+	l.wsconn.safeSend(newStringSubscriptionResponse(transactionWatchEventMethod,
+		strconv.FormatUint(uint64(l.subID), 10), event))
+}
+
+func (l *TransactionWatchListener) handleImportedBlock(block *types.Block) {
+	hash := block.Header.Hash()
+	for i, extrinsic := range block.Body {
+		if bytes.Equal(extrinsic, l.extrinsic) {
+			l.included[hash] = transactionInclusion{number: block.Header.Number, index: uint(i)}
+			break
+		}
+	}
+
+	bestHash := l.wsconn.BlockAPI.BestBlockHash()
+	bestIncluded, err := l.includingAncestor(bestHash)
+	if err != nil {
+		logger.Debugf("failed to find transaction in best chain block %s: %s", bestHash, err)
+		return
+	}
+
+	if bestIncluded == nil && l.bestIncluded == nil ||
+		bestIncluded != nil && l.bestIncluded != nil && *bestIncluded == *l.bestIncluded {
+		return
+	}
+
+	l.bestIncluded = bestIncluded
+	l.send(transactionBlockIncludedEvent{
+		Event: "bestChainBlockIncluded",
+		Block: bestIncluded,
+	})
+}
+
+// handleFinalisedBlock notifies the subscriber that the transaction is finalised if it
+// is included in the finalised chain, and returns true in this case.
+func (l *TransactionWatchListener) handleFinalisedBlock(header *types.Header) (final bool) {
+	finalisedIncluded, err := l.includingAncestor(header.Hash())
+	if err != nil {
+		logger.Debugf("failed to find transaction in finalised block %s: %s", header.Hash(), err)
+		return false
+	}
+
+	if finalisedIncluded == nil {
+		return false
+	}
+
+	l.send(transactionBlockIncludedEvent{
+		Event: "finalized",
+		Block: finalisedIncluded,
+	})
+	return true
+}
+
+// handleStatus notifies the subscriber of the transaction pool status changes, and returns
+// true if the transaction left the pool without being included in a block.
+func (l *TransactionWatchListener) handleStatus(status transaction.Status) (final bool) {
+	switch status {
+	case transaction.Broadcast:
+		l.send(transactionEvent{Event: "broadcasted"})
+	case transaction.Invalid:
+		l.send(transactionErrorEvent{Event: "invalid", Error: "transaction is no longer valid"})
+		return true
+	case transaction.Dropped:
+		l.send(transactionErrorEvent{Event: "dropped", Error: "transaction was dropped from the pool"})
+		return true
+	case transaction.Usurped:
+		l.send(transactionErrorEvent{Event: "dropped", Error: "transaction was replaced by another transaction"})
+		return true
+	}
+
+	return false
+}
+
+// includingAncestor returns the block including the transaction in the chain ending with the
+// given block, or nil if the transaction is not included in this chain.
+func (l *TransactionWatchListener) includingAncestor(hash common.Hash) (*transactionBlock, error) {
+	if len(l.included) == 0 {
+		return nil, nil
+	}
+
+	lowestIncluded := ^uint(0)
+	for _, inclusion := range l.included {
+		lowestIncluded = min(lowestIncluded, inclusion.number)
+	}
+
+	for {
+		if inclusion, ok := l.included[hash]; ok {
+			return &transactionBlock{Hash: hash.String(), Index: inclusion.index}, nil
+		}
+
+		header, err := l.wsconn.BlockAPI.GetHeader(hash)
+		if err != nil {
+			return nil, fmt.Errorf("getting header of block %s: %w", hash, err)
+		}
+
+		if header.Number <= lowestIncluded {
+			return nil, nil
+		}
+		hash = header.ParentHash
+	}
+}
+
+// TransactionBroadcastListener submits a transaction broadcast with transaction_v1_broadcast,
+// and retries until the transaction is accepted by the transaction pool or the operation is stopped.
+type TransactionBroadcastListener struct {
+	wsconn        *WSConn
+	operationID   uint32
+	extrinsic     types.Extrinsic
+	retryInterval time.Duration
+	done          chan struct{}
+	cancel        chan struct{}
+	cancelTimeout time.Duration
+}
+
+// Listen starts a goroutine submitting the transaction, which removes the operation
+// from the connection subscriptions once it returns
+func (l *TransactionBroadcastListener) Listen() {
+	go func() {
+		defer close(l.done)
+		defer func() {
+			l.wsconn.mu.Lock()
+			delete(l.wsconn.Subscriptions, l.operationID)
+			l.wsconn.mu.Unlock()
+		}()
+
+		ticker := time.NewTicker(l.retryInterval)
+		defer ticker.Stop()
+
+		for {
+			err := l.wsconn.CoreAPI.HandleSubmittedExtrinsic(l.extrinsic)
+			if err == nil {
+				return
+			}
+			logger.Debugf("failed to submit broadcast transaction %s: %s", l.extrinsic, err)
+
+			select {
+			case <-l.cancel:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the goroutine submitting the transaction
+func (l *TransactionBroadcastListener) Stop() error {
+	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
+}
+
+// parseExtrinsicParam parses the single hex encoded SCALE extrinsic parameter
+func parseExtrinsicParam(params interface{}) (types.Extrinsic, error) {
+	paramsSlice, ok := params.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %T, expected type []interface{}", errUnexpectedType, params)
+	}
+
+	if len(paramsSlice) != 1 {
+		return nil, fmt.Errorf("%w: expected 1 param, got: %d", errUnexpectedParamLen, len(paramsSlice))
+	}
+
+	encodedExtrinsic, ok := paramsSlice[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T, expected type string", errUnexpectedType, paramsSlice[0])
+	}
+
+	return common.HexToBytes(encodedExtrinsic)
+}
+
+func (c *WSConn) initTransactionWatch(reqID float64, params interface{}) (Listener, error) {
+	extrinsic, err := parseExtrinsicParam(params)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), err.Error())
+		return nil, err
+	}
+
+	if c.BlockAPI == nil {
+		c.safeSendError(reqID, nil, errBlockAPINotSet.Error())
+		return nil, errBlockAPINotSet
+	}
+
+	if c.CoreAPI == nil {
+		c.safeSendError(reqID, nil, errCoreAPINotSet.Error())
+		return nil, errCoreAPINotSet
+	}
+
+	listener := newTransactionWatchListener(c, extrinsic)
+	listener.txStatusChan = c.TxStateAPI.GetStatusNotifierChannel(extrinsic)
+	listener.importedChan = c.BlockAPI.GetImportedBlockNotifierChannel()
+	listener.finalisedChan = c.BlockAPI.GetFinalisedNotifierChannel()
+
+	c.mu.Lock()
+	listener.subID = atomic.AddUint32(&c.qtyListeners, 1)
+	c.mu.Unlock()
+	subscriptionID := strconv.FormatUint(uint64(listener.subID), 10)
+
+	err = c.CoreAPI.HandleSubmittedExtrinsic(extrinsic)
+	if err != nil {
+		c.TxStateAPI.FreeStatusNotifierChannel(listener.txStatusChan)
+		c.BlockAPI.FreeImportedBlockNotifierChannel(listener.importedChan)
+		c.BlockAPI.FreeFinalisedNotifierChannel(listener.finalisedChan)
+
+		// the subscription is created, and its first and last event reports the error
+		var event transactionErrorEvent
+		switch err.(type) {
+		case runtime.InvalidTransaction, runtime.UnknownTransaction:
+			event = transactionErrorEvent{Event: "invalid", Error: err.Error()}
+		default:
+			event = transactionErrorEvent{Event: "error", Error: err.Error()}
+		}
+		c.safeSend(newResultResponseJSON(subscriptionID, reqID))
+		c.safeSend(newStringSubscriptionResponse(transactionWatchEventMethod, subscriptionID, event))
+		return nil, fmt.Errorf("handling submitted extrinsic: %w", err)
+	}
+
+	c.mu.Lock()
+	c.Subscriptions[listener.subID] = listener
+	c.mu.Unlock()
+
+	c.safeSend(newResultResponseJSON(subscriptionID, reqID))
+	return listener, nil
+}
+
+func (c *WSConn) initTransactionBroadcast(reqID float64, params interface{}) (Listener, error) {
+	extrinsic, err := parseExtrinsicParam(params)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), err.Error())
+		return nil, err
+	}
+
+	if c.CoreAPI == nil {
+		c.safeSendError(reqID, nil, errCoreAPINotSet.Error())
+		return nil, errCoreAPINotSet
+	}
+
+	listener := &TransactionBroadcastListener{
+		wsconn:        c,
+		extrinsic:     extrinsic,
+		retryInterval: transactionBroadcastRetryInterval,
+		cancel:        make(chan struct{}, 1),
+		done:          make(chan struct{}, 1),
+		cancelTimeout: defaultCancelTimeout,
+	}
+
+	c.mu.Lock()
+	listener.operationID = atomic.AddUint32(&c.qtyListeners, 1)
+	c.Subscriptions[listener.operationID] = listener
+	c.mu.Unlock()
+
+	c.safeSend(newResultResponseJSON(strconv.FormatUint(uint64(listener.operationID), 10), reqID))
+	return listener, nil
+}
+
+// handleTransactionCall handles the transactionWatch_v1 and transaction_v1 method calls which
+// do not create a listener and returns true, or returns false for any other method.
+func (c *WSConn) handleTransactionCall(method string, reqID float64, params interface{}) bool {
+	switch method {
+	case transactionWatchV1Unwatch:
+		c.stopTransactionListener(reqID, params, func(listener Listener) bool {
+			_, ok := listener.(*TransactionWatchListener)
+			return ok
+		})
+	case transactionV1Stop:
+		c.stopTransactionListener(reqID, params, func(listener Listener) bool {
+			_, ok := listener.(*TransactionBroadcastListener)
+			return ok
+		})
+	default:
+		return false
+	}
+
+	return true
+}
+
+// stopTransactionListener stops and removes the listener whose identifier is given as parameter,
+// and responds with null. An invalid parameter error is sent if there is no such listener.
+func (c *WSConn) stopTransactionListener(reqID float64, params interface{}, isExpectedListener func(Listener) bool) {
+	var listener Listener
+	id, err := parseSubscribeID(params)
+	if err == nil {
+		c.mu.Lock()
+		listener = c.Subscriptions[id]
+		if listener != nil && isExpectedListener(listener) {
+			delete(c.Subscriptions, id)
+		} else {
+			listener = nil
+		}
+		c.mu.Unlock()
+	}
+
+	if listener == nil {
+		c.safeSendError(reqID, big.NewInt(invalidParamsCode), "invalid identifier")
+		return
+	}
+
+	err = listener.Stop()
+	if err != nil {
+		logger.Warnf("failed to stop transaction listener %d: %s", id, err)
+	}
+
+	c.safeSend(newResultResponseJSON(nil, reqID))
+}
@@ -0,0 +1,236 @@
+//go:build integration
+
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func transactionWatchEvent(event interface{}) StringSubscriptionResponseJSON {
+	return newStringSubscriptionResponse(transactionWatchEventMethod, "1", event)
+}
+
+func TestTransactionWatchListener(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+	wsconn.Subscriptions = make(map[uint32]Listener)
+
+	extrinsic := types.Extrinsic{1, 2, 3}
+	parent := types.Header{Number: 1}
+	block := types.Block{
+		Header: types.Header{Number: 2, ParentHash: parent.Hash()},
+		Body:   types.Body{{4}, extrinsic},
+	}
+	blockHash := block.Header.Hash()
+	fork := types.Block{
+		Header: types.Header{Number: 2, ParentHash: parent.Hash(), StateRoot: common.Hash{1}},
+	}
+	forkHash := fork.Header.Hash()
+
+	importedChan := make(chan *types.Block)
+	finalisedChan := make(chan *types.FinalisationInfo)
+	txStatusChan := make(chan transaction.Status)
+
+	txStateAPI := NewMockTransactionStateAPI(ctrl)
+	txStateAPI.EXPECT().GetStatusNotifierChannel(extrinsic).Return(txStatusChan)
+	txStateAPI.EXPECT().FreeStatusNotifierChannel(txStatusChan)
+	wsconn.TxStateAPI = txStateAPI
+
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetImportedBlockNotifierChannel().Return(importedChan)
+	blockAPI.EXPECT().GetFinalisedNotifierChannel().Return(finalisedChan)
+	blockAPI.EXPECT().FreeImportedBlockNotifierChannel(importedChan)
+	blockAPI.EXPECT().FreeFinalisedNotifierChannel(finalisedChan)
+	blockAPI.EXPECT().GetHeader(forkHash).Return(&fork.Header, nil).AnyTimes()
+	wsconn.BlockAPI = blockAPI
+
+	coreAPI := mocks.NewMockCoreAPI(ctrl)
+	coreAPI.EXPECT().HandleSubmittedExtrinsic(extrinsic).Return(nil)
+	wsconn.CoreAPI = coreAPI
+
+	listener, err := wsconn.initTransactionWatch(1, []interface{}{"0x010203"})
+	require.NoError(t, err)
+	requireNextMessage(t, ws, newResultResponseJSON("1", 1))
+
+	listener.Listen()
+	requireNextMessage(t, ws, transactionWatchEvent(transactionEvent{Event: "validated"}))
+
+	txStatusChan <- transaction.Ready
+	txStatusChan <- transaction.Broadcast
+	requireNextMessage(t, ws, transactionWatchEvent(transactionEvent{Event: "broadcasted"}))
+
+	blockAPI.EXPECT().BestBlockHash().Return(blockHash)
+	importedChan <- &block
+	requireNextMessage(t, ws, transactionWatchEvent(transactionBlockIncludedEvent{
+		Event: "bestChainBlockIncluded",
+		Block: &transactionBlock{Hash: blockHash.String(), Index: 1},
+	}))
+
+	// the fork not including the transaction becomes the best chain
+	blockAPI.EXPECT().BestBlockHash().Return(forkHash)
+	importedChan <- &fork
+	requireNextMessage(t, ws, transactionWatchEvent(transactionBlockIncludedEvent{
+		Event: "bestChainBlockIncluded",
+	}))
+
+	finalisedChan <- &types.FinalisationInfo{Header: block.Header}
+	requireNextMessage(t, ws, transactionWatchEvent(transactionBlockIncludedEvent{
+		Event: "finalized",
+		Block: &transactionBlock{Hash: blockHash.String(), Index: 1},
+	}))
+
+	// the subscription ends with the finalized event
+	select {
+	case <-listener.(*TransactionWatchListener).done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the listener to stop")
+	}
+	require.Empty(t, wsconn.Subscriptions)
+}
+
+func TestTransactionWatchListener_handleStatus_dropped(t *testing.T) {
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+
+	listener := newTransactionWatchListener(wsconn, types.Extrinsic{1})
+	listener.subID = 1
+
+	final := listener.handleStatus(transaction.Dropped)
+	require.True(t, final)
+	requireNextMessage(t, ws, transactionWatchEvent(transactionErrorEvent{
+		Event: "dropped",
+		Error: "transaction was dropped from the pool",
+	}))
+}
+
+func TestWSConn_initTransactionWatch_invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+	wsconn.Subscriptions = make(map[uint32]Listener)
+
+	extrinsic := types.Extrinsic{1}
+	txStatusChan := make(chan transaction.Status)
+	txStateAPI := NewMockTransactionStateAPI(ctrl)
+	txStateAPI.EXPECT().GetStatusNotifierChannel(extrinsic).Return(txStatusChan)
+	txStateAPI.EXPECT().FreeStatusNotifierChannel(txStatusChan)
+	wsconn.TxStateAPI = txStateAPI
+
+	importedChan := make(chan *types.Block)
+	finalisedChan := make(chan *types.FinalisationInfo)
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetImportedBlockNotifierChannel().Return(importedChan)
+	blockAPI.EXPECT().GetFinalisedNotifierChannel().Return(finalisedChan)
+	blockAPI.EXPECT().FreeImportedBlockNotifierChannel(importedChan)
+	blockAPI.EXPECT().FreeFinalisedNotifierChannel(finalisedChan)
+	wsconn.BlockAPI = blockAPI
+
+	invalidTransaction := runtime.NewInvalidTransaction()
+	err := invalidTransaction.SetValue(runtime.Stale{})
+	require.NoError(t, err)
+
+	coreAPI := mocks.NewMockCoreAPI(ctrl)
+	coreAPI.EXPECT().HandleSubmittedExtrinsic(extrinsic).Return(invalidTransaction)
+	wsconn.CoreAPI = coreAPI
+
+	listener, err := wsconn.initTransactionWatch(1, []interface{}{"0x01"})
+	require.ErrorIs(t, err, invalidTransaction)
+	require.Nil(t, listener)
+
+	requireNextMessage(t, ws, newResultResponseJSON("1", 1))
+	requireNextMessage(t, ws, transactionWatchEvent(transactionErrorEvent{
+		Event: "invalid",
+		Error: invalidTransaction.Error(),
+	}))
+	require.Empty(t, wsconn.Subscriptions)
+}
+
+func TestTransactionBroadcastListener(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+	wsconn.Subscriptions = make(map[uint32]Listener)
+
+	extrinsic := types.Extrinsic{1}
+	coreAPI := mocks.NewMockCoreAPI(ctrl)
+	submitted := make(chan struct{})
+	gomock.InOrder(
+		coreAPI.EXPECT().HandleSubmittedExtrinsic(extrinsic).Return(errors.New("test error")),
+		coreAPI.EXPECT().HandleSubmittedExtrinsic(extrinsic).DoAndReturn(func(types.Extrinsic) error {
+			close(submitted)
+			return nil
+		}),
+	)
+	wsconn.CoreAPI = coreAPI
+
+	listener, err := wsconn.initTransactionBroadcast(1, []interface{}{"0x01"})
+	require.NoError(t, err)
+	requireNextMessage(t, ws, newResultResponseJSON("1", 1))
+
+	// the submission is retried until the transaction is accepted
+	listener.(*TransactionBroadcastListener).retryInterval = time.Millisecond
+	listener.Listen()
+	select {
+	case <-submitted:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the transaction to be submitted")
+	}
+
+	// the operation is removed once the transaction is accepted
+	select {
+	case <-listener.(*TransactionBroadcastListener).done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the broadcast to end")
+	}
+	wsconn.mu.Lock()
+	require.Empty(t, wsconn.Subscriptions)
+	wsconn.mu.Unlock()
+
+	require.True(t, wsconn.handleTransactionCall(transactionV1Stop, 2, []interface{}{"1"}))
+	_, msg, err := ws.ReadMessage()
+	require.NoError(t, err)
+	require.Contains(t, string(msg), `"code":-32602`)
+}
+
+func TestTransactionBroadcastListener_Stop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+	wsconn.Subscriptions = make(map[uint32]Listener)
+
+	coreAPI := mocks.NewMockCoreAPI(ctrl)
+	coreAPI.EXPECT().HandleSubmittedExtrinsic(types.Extrinsic{1}).
+		Return(errors.New("test error")).MinTimes(1)
+	wsconn.CoreAPI = coreAPI
+
+	listener, err := wsconn.initTransactionBroadcast(1, []interface{}{"0x01"})
+	require.NoError(t, err)
+	requireNextMessage(t, ws, newResultResponseJSON("1", 1))
+
+	listener.(*TransactionBroadcastListener).retryInterval = time.Millisecond
+	listener.Listen()
+
+	require.True(t, wsconn.handleTransactionCall(transactionV1Stop, 2, []interface{}{"1"}))
+	requireNextMessage(t, ws, newResultResponseJSON(nil, 2))
+	wsconn.mu.Lock()
+	require.Empty(t, wsconn.Subscriptions)
+	wsconn.mu.Unlock()
+}
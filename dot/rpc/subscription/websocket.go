@@ -94,6 +94,11 @@ func (c *WSConn) HandleConn() {
 		logger.Tracef("websocket message received: %s", string(rawBytes))
 		logger.Debugf("ws method %s called with params %v", wsMessage.Method, wsMessage.Params)
 
+		if c.handleChainHeadCall(wsMessage.Method, wsMessage.ID, wsMessage.Params) ||
+			c.handleTransactionCall(wsMessage.Method, wsMessage.ID, wsMessage.Params) {
+			continue
+		}
+
 		if !strings.Contains(wsMessage.Method, "_unsubscribe") && !strings.Contains(wsMessage.Method, "_unwatch") {
 			setupListener := c.getSetupListener(wsMessage.Method)
 
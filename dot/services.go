@@ -6,6 +6,7 @@
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,9 +15,12 @@
 	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/digest"
 	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	"github.com/ChainSafe/gossamer/dot/offchain"
 	"github.com/ChainSafe/gossamer/dot/rpc"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/sync"
 	"github.com/ChainSafe/gossamer/dot/system"
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -24,6 +28,7 @@
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/internal/metrics"
 	"github.com/ChainSafe/gossamer/internal/pprof"
+	"github.com/ChainSafe/gossamer/lib/aura"
 	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
@@ -74,26 +79,13 @@ func (nodeBuilder) createStateService(config *cfg.Config) (*state.Service, error
 		return nil, fmt.Errorf("genesis should be raw")
 	}
 
-	genTrie, err := runtime.NewTrieFromGenesis(*gen)
-	if err != nil {
-		return nil, fmt.Errorf("creating trie from genesis: %w", err)
-	}
-
-	// create genesis runtime
-	rtCfg := wazero_runtime.Config{
-		LogLvl:  log.Critical,
-		Storage: rtstorage.NewTrieState(genTrie),
-	}
-
-	genesisRuntime, err := wazero_runtime.NewRuntimeFromGenesis(rtCfg)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating genesis runtime: %w", err)
-	}
-	defer genesisRuntime.Stop()
-
-	babeCfg, err := genesisRuntime.BabeConfiguration()
-	if err != nil {
-		return nil, fmt.Errorf("getting babe configuration: %w", err)
+	// aura chains do not have babe epochs
+	var babeCfg *types.BabeConfiguration
+	if gen.ConsensusEngine != genesis.AuraConsensusEngine {
+		babeCfg, err = genesisBabeConfiguration(gen)
+		if err != nil {
+			return nil, fmt.Errorf("getting babe configuration: %w", err)
+		}
 	}
 
 	stateLogLevel, err := log.ParseLevel(config.Log.State)
@@ -101,9 +93,19 @@ func (nodeBuilder) createStateService(config *cfg.Config) (*state.Service, error
 		return nil, err
 	}
 
+	storageBackend := state.StorageBackend(config.StateBackend)
+	if storageBackend != "" && !storageBackend.IsValid() {
+		return nil, fmt.Errorf("invalid state backend: %s", storageBackend)
+	}
+
 	stateConfig := state.Config{
-		Path:              config.BasePath,
-		LogLevel:          stateLogLevel,
+		Path:     config.BasePath,
+		LogLevel: stateLogLevel,
+		PrunerCfg: pruner.Config{
+			Mode:           config.Pruning,
+			RetainedBlocks: config.RetainBlocks,
+		},
+		StorageBackend:    storageBackend,
 		Metrics:           metrics.NewIntervalConfig(config.PrometheusExternal),
 		GenesisBABEConfig: babeCfg,
 	}
@@ -117,6 +119,28 @@ func (nodeBuilder) createStateService(config *cfg.Config) (*state.Service, error
 	return stateSrvc, nil
 }
 
+// genesisBabeConfiguration returns the babe configuration of the genesis runtime
+func genesisBabeConfiguration(gen *genesis.Genesis) (*types.BabeConfiguration, error) {
+	genTrie, err := runtime.NewTrieFromGenesis(*gen)
+	if err != nil {
+		return nil, fmt.Errorf("creating trie from genesis: %w", err)
+	}
+
+	// create genesis runtime
+	rtCfg := wazero_runtime.Config{
+		LogLvl:  log.Critical,
+		Storage: rtstorage.NewTrieState(genTrie),
+	}
+
+	genesisRuntime, err := wazero_runtime.NewRuntimeFromGenesis(rtCfg)
+	if err != nil {
+		return nil, fmt.Errorf("instantiating genesis runtime: %w", err)
+	}
+	defer genesisRuntime.Stop()
+
+	return genesisRuntime.BabeConfiguration()
+}
+
 func startStateService(config cfg.StateConfig, stateSrvc *state.Service) error {
 	logger.Debug("starting state service...")
 
@@ -185,14 +209,15 @@ func createRuntime(config *cfg.Config, ns runtime.NodeStorage, st *state.Service
 	switch config.Core.WasmInterpreter {
 	case wazero_runtime.Name:
 		rtCfg := wazero_runtime.Config{
-			Storage:     ts,
-			Keystore:    ks,
-			LogLvl:      wasmerLogLevel,
-			NodeStorage: ns,
-			Network:     net,
-			Transaction: st.Transaction,
-			Role:        config.Core.Role,
-			CodeHash:    codeHash,
+			Storage:             ts,
+			Keystore:            ks,
+			LogLvl:              wasmerLogLevel,
+			NodeStorage:         ns,
+			Network:             net,
+			Transaction:         st.Transaction,
+			Role:                config.Core.Role,
+			CodeHash:            codeHash,
+			CompilationCacheDir: runtimeCacheDir(config.BasePath),
 		}
 
 		// create runtime executor
@@ -208,6 +233,12 @@ func createRuntime(config *cfg.Config, ns runtime.NodeStorage, st *state.Service
 	return rt, nil
 }
 
+// runtimeCacheDir returns the directory in the base path where the
+// compiled runtime code is persisted across restarts.
+func runtimeCacheDir(basePath string) string {
+	return filepath.Join(basePath, "runtime-cache")
+}
+
 func asAuthority(authority bool) string {
 	if authority {
 		return " as authority"
@@ -278,6 +309,48 @@ func (nodeBuilder) createBABEServiceWithBuilder(config *cfg.Config, st *state.Se
 	return bs, nil
 }
 
+func (nodeBuilder) createAuraService(config *cfg.Config, st *state.Service, ks KeyStore,
+	cs *core.Service) (*aura.Service, error) {
+	isAuthority := config.Core.Role == common.AuthorityRole
+	logger.Info("creating Aura service" + asAuthority(isAuthority) + "...")
+
+	if ks.Name() != keystore.AuraName || ks.Type() != crypto.Sr25519Type {
+		return nil, ErrInvalidKeystoreType
+	}
+
+	kps := ks.Keypairs()
+	logger.Infof("keystore with keys %v", kps)
+	if len(kps) == 0 && isAuthority {
+		return nil, ErrNoKeysProvided
+	}
+
+	auraLogLevel, err := log.ParseLevel(config.Log.Aura)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse aura log level: %w", err)
+	}
+
+	slotDuration, err := st.SlotDuration()
+	if err != nil {
+		return nil, fmt.Errorf("getting slot duration: %w", err)
+	}
+
+	auraCfg := &aura.ServiceConfig{
+		LogLvl:             auraLogLevel,
+		BlockState:         st.Block,
+		StorageState:       st.Storage,
+		TransactionState:   st.Transaction,
+		BlockImportHandler: cs,
+		SlotDuration:       slotDuration,
+		Authority:          isAuthority,
+	}
+
+	if isAuthority {
+		auraCfg.Keypair = kps[0].(*sr25519.Keypair)
+	}
+
+	return aura.NewService(auraCfg)
+}
+
 // Core Service
 
 // createCoreService creates the core service from the provided core configuration
@@ -337,7 +410,7 @@ func (nodeBuilder) createNetworkService(config *cfg.Config, stateSrvc *state.Ser
 		config.Core.Role, config.Network.Port, strings.Join(config.Network.Bootnodes, ","), config.Network.ProtocolID,
 		config.Network.NoBootstrap, config.Network.NoMDNS)
 
-	slotDuration, err := stateSrvc.Epoch.GetSlotDuration()
+	slotDuration, err := stateSrvc.SlotDuration()
 	if err != nil {
 		return nil, fmt.Errorf("cannot get slot duration: %w", err)
 	}
@@ -431,6 +504,7 @@ func (nodeBuilder) createRPCService(params rpcServiceSettings) (*rpc.HTTPServer,
 		WSUnsafeExternal:    params.config.RPC.UnsafeWSExternal,
 		WSPort:              params.config.RPC.WSPort,
 		Modules:             params.config.RPC.Modules,
+		ArchiveNode:         params.config.Pruning != pruner.Full,
 	}
 
 	return rpc.NewHTTPServer(rpcConfig), nil
@@ -493,14 +567,29 @@ func (nodeBuilder) createGRANDPAService(config *cfg.Config, st *state.Service, k
 	return grandpa.NewService(gsCfg)
 }
 
-func (nodeBuilder) createBlockVerifier(st *state.Service) *babe.VerificationManager {
-	return babe.NewVerificationManager(st.Block, st.Slot, st.Epoch)
+// createBlockVerifier returns the block verifier of the consensus engine of the chain
+func (nodeBuilder) createBlockVerifier(st *state.Service) (sync.BabeVerifier, error) {
+	genesisData, err := st.Base.LoadGenesisData()
+	if err != nil {
+		return nil, fmt.Errorf("loading genesis data: %w", err)
+	}
+
+	if genesisData.ConsensusEngine != genesis.AuraConsensusEngine {
+		return babe.NewVerificationManager(st.Block, st.Slot, st.Epoch), nil
+	}
+
+	slotDuration, err := st.SlotDuration()
+	if err != nil {
+		return nil, fmt.Errorf("getting slot duration: %w", err)
+	}
+
+	return aura.NewVerifier(st.Block, st.Storage, slotDuration), nil
 }
 
 func (nodeBuilder) newSyncService(config *cfg.Config, st *state.Service, fg sync.FinalityGadget,
-	verifier *babe.VerificationManager, cs *core.Service, net *network.Service, telemetryMailer Telemetry) (
+	verifier sync.BabeVerifier, cs *core.Service, net *network.Service, telemetryMailer Telemetry) (
 	*sync.Service, error) {
-	slotDuration, err := st.Epoch.GetSlotDuration()
+	slotDuration, err := st.SlotDuration()
 	if err != nil {
 		return nil, err
 	}
@@ -521,6 +610,18 @@ func (nodeBuilder) newSyncService(config *cfg.Config, st *state.Service, fg sync
 		blockRequestTimeout,
 		network.MaxBlockResponseSize)
 
+	const warpSyncRequestTimeout = time.Second * 60
+	warpSyncRequestMaker := net.GetRequestResponseProtocol(
+		network.WarpSyncID,
+		warpSyncRequestTimeout,
+		messages.MaxWarpSyncProofSize)
+
+	const stateRequestTimeout = time.Second * 60
+	stateRequestMaker := net.GetRequestResponseProtocol(
+		network.StateSyncID,
+		stateRequestTimeout,
+		messages.MaxStateResponseSize)
+
 	syncCfg := &sync.Config{
 		LogLvl:             syncLogLevel,
 		Network:            net,
@@ -536,6 +637,11 @@ func (nodeBuilder) newSyncService(config *cfg.Config, st *state.Service, fg sync
 		Telemetry:          telemetryMailer,
 		BadBlocks:          genesisData.BadBlocks,
 		RequestMaker:       requestMaker,
+
+		WarpSync:             config.Network.Sync == cfg.WarpSync,
+		GrandpaState:         st.Grandpa,
+		WarpSyncRequestMaker: warpSyncRequestMaker,
+		StateRequestMaker:    stateRequestMaker,
 	}
 
 	return sync.NewService(syncCfg)
@@ -545,6 +651,50 @@ func (nodeBuilder) createDigestHandler(st *state.Service) (*digest.Handler, erro
 	return digest.NewHandler(st.Block, st.Epoch, st.Grandpa)
 }
 
+// offchainWorkerTimeout is the maximum duration of the offchain worker of a block
+const offchainWorkerTimeout = time.Minute
+
+// createOffchainWorkerManager returns the offchain worker manager, or nil if the
+// offchain workers should not run given the offchain worker mode and the node role.
+func (nodeBuilder) createOffchainWorkerManager(config *cfg.Config, st *state.Service,
+	ks *keystore.GlobalKeystore, ns *runtime.NodeStorage, net *network.Service, cs *core.Service,
+	syncer *sync.Service) (*offchain.Manager, error) {
+	switch config.Core.OffchainWorker {
+	case cfg.OffchainWorkerNever:
+		return nil, nil
+	case cfg.OffchainWorkerAlways:
+	default: // cfg.OffchainWorkerWhenValidating
+		if config.Core.Role != common.AuthorityRole {
+			return nil, nil
+		}
+	}
+
+	logger.Debug("creating offchain worker manager...")
+
+	wasmerLogLevel, err := log.ParseLevel(config.Log.Wasmer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse wasmer log level: %w", err)
+	}
+
+	managerConfig := offchain.Config{
+		BlockState:           st.Block,
+		StorageState:         st.Storage,
+		Syncer:               syncer,
+		Keystore:             ks,
+		NodeStorage:          *ns,
+		TransactionSubmitter: cs,
+		Role:                 config.Core.Role,
+		LogLvl:               wasmerLogLevel,
+		Timeout:              offchainWorkerTimeout,
+		CompilationCacheDir:  runtimeCacheDir(config.BasePath),
+	}
+	if net != nil {
+		managerConfig.Network = net
+	}
+
+	return offchain.NewManager(managerConfig), nil
+}
+
 func createPprofService(config cfg.PprofConfig) (service *pprof.Service) {
 	pprofLogger := log.NewFromGlobal(log.AddContext("pkg", "pprof"))
 	return pprof.NewService(config, pprofLogger)
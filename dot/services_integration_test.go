@@ -123,6 +123,68 @@ func(cfg *babe.ServiceConfig) (*babe.Service, error) {
 	}
 }
 
+func Test_nodeBuilder_createAuraService(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	authorityConfig := DefaultTestWestendDevConfig(t)
+	authorityConfig.Core.Role = common.AuthorityRole
+	fullNodeConfig := DefaultTestWestendDevConfig(t)
+	fullNodeConfig.Core.Role = common.FullNodeRole
+
+	ks := keystore.NewGlobalKeystore()
+	ks2 := keystore.NewGlobalKeystore()
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	ks2.Aura.Insert(kr.Alice())
+
+	tests := map[string]struct {
+		cfg *cfg.Config
+		ks  KeyStore
+		err error
+	}{
+		"invalid_keystore": {
+			cfg: authorityConfig,
+			ks:  ks.Babe,
+			err: ErrInvalidKeystoreType,
+		},
+		"empty_keystore": {
+			cfg: authorityConfig,
+			ks:  ks.Aura,
+			err: ErrNoKeysProvided,
+		},
+		"full_node_empty_keystore": {
+			cfg: fullNodeConfig,
+			ks:  ks.Aura,
+		},
+		"authority": {
+			cfg: authorityConfig,
+			ks:  ks2.Aura,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			stateSrvc := newStateService(t, ctrl)
+
+			builder := nodeBuilder{}
+			got, err := builder.createAuraService(tt.cfg, stateSrvc, tt.ks, &core.Service{})
+
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err != nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NotNil(t, got)
+			assert.Equal(t, config.BABEConfigurationTestDefault.SlotDuration, got.SlotDuration())
+		})
+	}
+}
+
 func Test_nodeBuilder_createCoreService(t *testing.T) {
 	t.Parallel()
 
@@ -522,7 +584,8 @@ func TestCreateBlockVerifier(t *testing.T) {
 	require.NoError(t, err)
 	stateSrvc.Epoch = &state.EpochState{}
 
-	_ = builder.createBlockVerifier(stateSrvc)
+	_, err = builder.createBlockVerifier(stateSrvc)
+	require.NoError(t, err)
 	err = stateSrvc.DB().Close()
 	require.NoError(t, err)
 }
@@ -543,7 +606,8 @@ func TestCreateSyncService(t *testing.T) {
 	ks := keystore.NewGlobalKeystore()
 	require.NotNil(t, ks)
 
-	ver := builder.createBlockVerifier(stateSrvc)
+	ver, err := builder.createBlockVerifier(stateSrvc)
+	require.NoError(t, err)
 
 	networkService, err := network.NewService(&network.Config{
 		BlockState: stateSrvc.Block,
@@ -841,3 +905,69 @@ func Test_createDigestHandler(t *testing.T) {
 	_, err = builder.createDigestHandler(stateSrvc)
 	require.NoError(t, err)
 }
+
+func Test_nodeBuilder_createOffchainWorkerManager(t *testing.T) {
+	config := DefaultTestWestendDevConfig(t)
+
+	genFile := NewTestGenesisRawFile(t, config)
+
+	config.ChainSpec = genFile
+
+	err := InitNode(config)
+	require.NoError(t, err)
+
+	builder := nodeBuilder{}
+	stateSrvc, err := builder.createStateService(config)
+	require.NoError(t, err)
+
+	err = startStateService(*config.State, stateSrvc)
+	require.NoError(t, err)
+
+	ns, err := builder.createRuntimeStorage(stateSrvc)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		offchainWorker string
+		role           common.NetworkRole
+		enabled        bool
+	}{
+		{
+			name:           "never",
+			offchainWorker: cfg.OffchainWorkerNever,
+			role:           common.AuthorityRole,
+		},
+		{
+			name:           "always",
+			offchainWorker: cfg.OffchainWorkerAlways,
+			role:           common.FullNodeRole,
+			enabled:        true,
+		},
+		{
+			name:           "when_validating_full_node",
+			offchainWorker: cfg.OffchainWorkerWhenValidating,
+			role:           common.FullNodeRole,
+		},
+		{
+			name:           "when_validating_authority",
+			offchainWorker: cfg.OffchainWorkerWhenValidating,
+			role:           common.AuthorityRole,
+			enabled:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Core.OffchainWorker = tt.offchainWorker
+			config.Core.Role = tt.role
+
+			manager, err := builder.createOffchainWorkerManager(config, stateSrvc, keystore.NewGlobalKeystore(),
+				ns, nil, &core.Service{}, &sync.Service{})
+			require.NoError(t, err)
+			if tt.enabled {
+				assert.NotNil(t, manager)
+			} else {
+				assert.Nil(t, manager)
+			}
+		})
+	}
+}
@@ -7,11 +7,14 @@
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 )
 
+var auraSlotDurationKey = []byte("aura_slot_duration")
+
 // BaseState is a wrapper for a database, without any prefixes
 type BaseState struct {
 	db GetPutDeleter
@@ -109,3 +112,18 @@ func (s *BaseState) loadSkipToEpoch() (uint64, error) {
 
 	return binary.LittleEndian.Uint64(data), nil
 }
+
+func (s *BaseState) storeAuraSlotDuration(slotDuration uint64) error {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, slotDuration)
+	return s.db.Put(auraSlotDurationKey, buf)
+}
+
+func (s *BaseState) loadAuraSlotDuration() (time.Duration, error) {
+	data, err := s.db.Get(auraSlotDurationKey)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(binary.LittleEndian.Uint64(data)) * time.Millisecond, nil
+}
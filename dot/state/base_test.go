@@ -5,7 +5,9 @@
 
 import (
 	"testing"
+	"time"
 
+	"github.com/ChainSafe/gossamer/internal/database"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
@@ -63,3 +65,18 @@ func TestStoreAndLoadGenesisData(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, gen)
 }
+
+func TestStoreAndLoadAuraSlotDuration(t *testing.T) {
+	db := NewInMemoryDB(t)
+	base := NewBaseState(db)
+
+	_, err := base.loadAuraSlotDuration()
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	err = base.storeAuraSlotDuration(6000)
+	require.NoError(t, err)
+
+	slotDuration, err := base.loadAuraSlotDuration()
+	require.NoError(t, err)
+	require.Equal(t, 6*time.Second, slotDuration)
+}
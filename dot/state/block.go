@@ -899,11 +899,12 @@ func (bs *BlockState) HandleRuntimeChanges(newState *rtstorage.TrieState,
 	}
 
 	rtCfg := wazero_runtime.Config{
-		Storage:     newState,
-		Keystore:    parentRuntimeInstance.Keystore(),
-		NodeStorage: parentRuntimeInstance.NodeStorage(),
-		Network:     parentRuntimeInstance.NetworkService(),
-		CodeHash:    currCodeHash,
+		Storage:             newState,
+		Keystore:            parentRuntimeInstance.Keystore(),
+		NodeStorage:         parentRuntimeInstance.NodeStorage(),
+		Network:             parentRuntimeInstance.NetworkService(),
+		CodeHash:            currCodeHash,
+		CompilationCacheDir: parentRuntimeInstance.CompilationCacheDir(),
 	}
 
 	if parentRuntimeInstance.Validator() {
@@ -7,13 +7,17 @@
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/telemetry"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/blocktree"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
 var errSetIDLowerThanHighest = errors.New("set id lower than highest")
+var errWarpSyncTargetTooLow = errors.New("warp sync target too low")
 var highestRoundAndSetIDKey = []byte("hrs")
 
 // finalisedHashKey = FinalizedBlockHashKey + round + setID (LE encoded)
@@ -282,3 +286,80 @@ func (bs *BlockState) handleFinalisedBlock(currentFinalizedHash common.Hash) err
 
 	return batch.Flush()
 }
+
+// SetWarpSyncTarget stores the header and justification of a block reached through warp sync
+// and makes it the highest finalised block and the new root of the block tree. The block is
+// stored without its body, and any unfinalised block we had is discarded.
+func (bs *BlockState) SetWarpSyncTarget(header *types.Header, justification []byte, round, setID uint64) error {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	if bs.IsPaused() {
+		return errors.New("blockstate service is paused")
+	}
+
+	lastFinalisedHeader, err := bs.GetHeader(bs.lastFinalised)
+	if err != nil {
+		return fmt.Errorf("getting last finalised header: %w", err)
+	}
+
+	if header.Number <= lastFinalisedHeader.Number {
+		return fmt.Errorf("%w: target #%d is not above finalised block #%d",
+			errWarpSyncTargetTooLow, header.Number, lastFinalisedHeader.Number)
+	}
+
+	_, highestSetID, err := bs.GetHighestRoundAndSetID()
+	if err != nil {
+		return err
+	}
+
+	if setID < highestSetID {
+		return fmt.Errorf("%w: %d should be greater or equal %d", errSetIDLowerThanHighest, setID, highestSetID)
+	}
+
+	hash := header.Hash()
+	encodedHeader, err := scale.Marshal(*header)
+	if err != nil {
+		return fmt.Errorf("encoding header: %w", err)
+	}
+
+	arrivalTime := make([]byte, 8)
+	binary.LittleEndian.PutUint64(arrivalTime, uint64(time.Now().UnixNano()))
+
+	batch := bs.db.NewBatch()
+	puts := []struct{ key, value []byte }{
+		{headerKey(hash), encodedHeader},
+		{headerHashKey(uint64(header.Number)), hash.ToBytes()},
+		{prefixKey(hash, justificationPrefix), justification},
+		{arrivalTimeKey(hash), arrivalTime},
+		{finalisedHashKey(round, setID), hash.ToBytes()},
+		{highestRoundAndSetIDKey, roundAndSetIDToBytes(round, setID)},
+	}
+	for _, put := range puts {
+		if err = batch.Put(put.key, put.value); err != nil {
+			return err
+		}
+	}
+
+	if err = batch.Flush(); err != nil {
+		return fmt.Errorf("writing warp sync target: %w", err)
+	}
+
+	for _, unfinalisedHash := range bs.bt.GetAllBlocks() {
+		blockHeader := bs.unfinalisedBlocks.delete(unfinalisedHash)
+		if blockHeader != nil {
+			bs.tries.delete(blockHeader.StateRoot)
+		}
+	}
+	bs.tries.delete(lastFinalisedHeader.StateRoot)
+
+	bs.bt = blocktree.NewBlockTreeFromRoot(header)
+	bs.lastFinalised = hash
+	bs.lastRound = round
+	bs.lastSetID = setID
+	bs.notifyFinalized(hash, round, setID)
+
+	logger.Infof("⏩ finalised warp sync target block #%d (%s), round %d, set id %d",
+		header.Number, hash, round, setID)
+	return nil
+}
@@ -164,3 +164,39 @@ func TestSetFinalisedHash_retrieveBlockNumber1SlotNumber(t *testing.T) {
 
 	require.Equal(t, firstSlot, veryFirstSlot)
 }
+
+func TestBlockState_SetWarpSyncTarget(t *testing.T) {
+	bs := newTestBlockState(t, newTriesEmpty())
+
+	target := &types.Header{
+		ParentHash: common.Hash{0xaa},
+		Number:     100,
+		StateRoot:  common.Hash{1, 1},
+		Digest:     types.NewDigest(),
+	}
+	justification := []byte{1, 2, 3}
+
+	err := bs.SetWarpSyncTarget(target, justification, 5, 2)
+	require.NoError(t, err)
+
+	finalised, err := bs.GetHighestFinalisedHeader()
+	require.NoError(t, err)
+	require.Equal(t, target.Hash(), finalised.Hash())
+
+	hash, err := bs.GetHashByNumber(100)
+	require.NoError(t, err)
+	require.Equal(t, target.Hash(), hash)
+
+	storedJustification, err := bs.GetJustification(target.Hash())
+	require.NoError(t, err)
+	require.Equal(t, justification, storedJustification)
+
+	round, setID, err := bs.GetHighestRoundAndSetID()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), round)
+	require.Equal(t, uint64(2), setID)
+	require.Equal(t, target.Hash(), bs.BestBlockHash())
+
+	err = bs.SetWarpSyncTarget(target, justification, 6, 2)
+	require.ErrorIs(t, err, errWarpSyncTargetTooLow)
+}
@@ -661,6 +661,21 @@ func (nem nextEpochMap[T]) Retrieve(blockState *BlockState, epoch uint64, header
 	return value, err
 }
 
+// pruneAnnouncedBy removes the next epoch definitions announced by the given blocks
+func (nem nextEpochMap[T]) pruneAnnouncedBy(hashes map[common.Hash]struct{}) {
+	for epoch, hashesAtEpoch := range nem {
+		for hash := range hashesAtEpoch {
+			if _, has := hashes[hash]; has {
+				delete(hashesAtEpoch, hash)
+			}
+		}
+
+		if len(hashesAtEpoch) == 0 {
+			delete(nem, epoch)
+		}
+	}
+}
+
 func findAncestor[T types.NextEpochData | types.NextConfigDataV1](blockState *BlockState,
 	hashesAtEpoch map[common.Hash]T, header *types.Header) (common.Hash, *T, error) {
 
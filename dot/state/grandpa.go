@@ -21,6 +21,7 @@
 	errDuplicateHashes         = errors.New("duplicated hashes")
 	errAlreadyHasForcedChange  = errors.New("already has a forced change")
 	errUnfinalizedAncestor     = errors.New("unfinalized ancestor")
+	errUnexpectedSetID         = errors.New("unexpected set id")
 
 	ErrNoNextAuthorityChange = errors.New("no next authority change")
 )
@@ -419,6 +420,32 @@ func (s *GrandpaState) SetNextChange(authorities []types.GrandpaVoter, number ui
 	return nil
 }
 
+// AddAuthoritySetChange stores the authorities of the given set ID, records the given block
+// number as the last block of the previous set and makes setID the current set ID. It is
+// used when authority set changes are learnt from a warp sync proof rather than from digests.
+func (s *GrandpaState) AddAuthoritySetChange(setID uint64, authorities []types.GrandpaVoter, number uint) error {
+	currSetID, err := s.GetCurrentSetID()
+	if err != nil {
+		return fmt.Errorf("cannot get current set ID: %w", err)
+	}
+
+	if setID != currSetID+1 {
+		return fmt.Errorf("%w: expected set id %d but got %d", errUnexpectedSetID, currSetID+1, setID)
+	}
+
+	err = s.setAuthorities(setID, authorities)
+	if err != nil {
+		return fmt.Errorf("cannot set authorities: %w", err)
+	}
+
+	err = s.setChangeSetIDAtBlock(setID, number)
+	if err != nil {
+		return fmt.Errorf("cannot set the change set id at block: %w", err)
+	}
+
+	return s.setCurrentSetID(setID)
+}
+
 // IncrementSetID increments the set ID
 func (s *GrandpaState) IncrementSetID() (newSetID uint64, err error) {
 	currSetID, err := s.GetCurrentSetID()
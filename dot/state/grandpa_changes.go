@@ -136,6 +136,18 @@ func (oc *orderedPendingChanges) pruneAll() {
 	*oc = make([]pendingChange, 0, oc.Len())
 }
 
+// pruneAnnouncedBy removes the changes announced by the given blocks
+func (oc *orderedPendingChanges) pruneAnnouncedBy(hashes map[common.Hash]struct{}) {
+	remainingChanges := make([]pendingChange, 0, oc.Len())
+	for _, change := range *oc {
+		if _, has := hashes[change.announcingHeader.Hash()]; !has {
+			remainingChanges = append(remainingChanges, change)
+		}
+	}
+
+	*oc = remainingChanges
+}
+
 type pendingChangeNode struct {
 	change *pendingChange
 	nodes  []*pendingChangeNode
@@ -322,3 +334,23 @@ func (ct *changeTree) pruneChanges(hash common.Hash, isDescendantOf isDescendant
 func (ct *changeTree) pruneAll() {
 	*ct = []*pendingChangeNode{}
 }
+
+// pruneAnnouncedBy removes the changes announced by the given blocks along with their
+// descendant changes, which are announced by descendants of the given blocks.
+func (ct *changeTree) pruneAnnouncedBy(hashes map[common.Hash]struct{}) {
+	*ct = pruneNodesAnnouncedBy(*ct, hashes)
+}
+
+func pruneNodesAnnouncedBy(nodes []*pendingChangeNode, hashes map[common.Hash]struct{}) []*pendingChangeNode {
+	remainingNodes := make([]*pendingChangeNode, 0, len(nodes))
+	for _, node := range nodes {
+		if _, has := hashes[node.change.announcingHeader.Hash()]; has {
+			continue
+		}
+
+		node.nodes = pruneNodesAnnouncedBy(node.nodes, hashes)
+		remainingNodes = append(remainingNodes, node)
+	}
+
+	return remainingNodes
+}
@@ -74,6 +74,34 @@ func TestGrandpaState_IncrementSetID(t *testing.T) {
 	require.Equal(t, genesisSetID+1, setID)
 }
 
+func TestGrandpaState_AddAuthoritySetChange(t *testing.T) {
+	db := NewInMemoryDB(t)
+	gs, err := NewGrandpaStateFromGenesis(db, nil, testAuths, nil)
+	require.NoError(t, err)
+
+	err = gs.AddAuthoritySetChange(genesisSetID+2, testAuths, 10)
+	require.ErrorIs(t, err, errUnexpectedSetID)
+
+	err = gs.AddAuthoritySetChange(genesisSetID+1, testAuths, 10)
+	require.NoError(t, err)
+
+	setID, err := gs.GetCurrentSetID()
+	require.NoError(t, err)
+	require.Equal(t, genesisSetID+1, setID)
+
+	auths, err := gs.GetAuthorities(setID)
+	require.NoError(t, err)
+	require.Equal(t, testAuths, auths)
+
+	setID, err = gs.GetSetIDByBlockNumber(11)
+	require.NoError(t, err)
+	require.Equal(t, genesisSetID+1, setID)
+
+	setID, err = gs.GetSetIDByBlockNumber(10)
+	require.NoError(t, err)
+	require.Equal(t, genesisSetID, setID)
+}
+
 func TestGrandpaState_GetSetIDByBlockNumber(t *testing.T) {
 	db := NewInMemoryDB(t)
 	gs, err := NewGrandpaStateFromGenesis(db, nil, testAuths, nil)
@@ -56,9 +56,22 @@ func (s *Service) Initialise(gen *genesis.Genesis, header *types.Header, t trie.
 	}
 	defer rt.Stop()
 
-	babeCfg, err := s.loadBabeConfigurationFromRuntime(rt)
-	if err != nil {
-		return err
+	// aura chains do not have babe epochs, only their slot duration is stored
+	var babeCfg *types.BabeConfiguration
+	if gen.ConsensusEngine == genesis.AuraConsensusEngine {
+		slotDuration, err := rt.AuraSlotDuration()
+		if err != nil {
+			return fmt.Errorf("failed to fetch genesis aura slot duration: %w", err)
+		}
+
+		if err = s.Base.storeAuraSlotDuration(slotDuration); err != nil {
+			return fmt.Errorf("failed to store aura slot duration: %w", err)
+		}
+	} else {
+		babeCfg, err = s.loadBabeConfigurationFromRuntime(rt)
+		if err != nil {
+			return err
+		}
 	}
 
 	// write initial genesis values to database
@@ -81,9 +94,12 @@ func (s *Service) Initialise(gen *genesis.Genesis, header *types.Header, t trie.
 		return fmt.Errorf("failed to create storage state from trie: %s", err)
 	}
 
-	epochState, err := NewEpochStateFromGenesis(db, blockState, babeCfg)
-	if err != nil {
-		return fmt.Errorf("failed to create epoch state: %s", err)
+	var epochState *EpochState
+	if babeCfg != nil {
+		epochState, err = NewEpochStateFromGenesis(db, blockState, babeCfg)
+		if err != nil {
+			return fmt.Errorf("failed to create epoch state: %s", err)
+		}
 	}
 
 	grandpaAuths, err := loadGrandpaAuthorities(t)
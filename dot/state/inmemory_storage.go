@@ -38,9 +38,8 @@ type InmemoryStorageState struct {
 	sync.RWMutex
 
 	// change notifiers
-	observerListMutex sync.RWMutex
-	observerList      []Observer
-	pruner            pruner.Pruner
+	storageObservers
+	pruner pruner.Pruner
 }
 
 // NewStorageState creates a new StorageState backed by the given block state
@@ -49,13 +48,22 @@ func NewStorageState(db database.Database, blockState *BlockState,
 	tries *Tries) (*InmemoryStorageState, error) {
 	storageTable := database.NewTable(db, storagePrefix)
 
-	return &InmemoryStorageState{
+	s := &InmemoryStorageState{
+		blockState: blockState,
+		tries:      tries,
+		db:         storageTable,
+		pruner:     &pruner.ArchiveNode{},
+	}
+	s.storageObservers = storageObservers{
 		blockState:   blockState,
-		tries:        tries,
-		db:           storageTable,
+		trieState:    s.TrieState,
 		observerList: []Observer{},
-		pruner:       &pruner.ArchiveNode{},
-	}, nil
+	}
+	return s, nil
+}
+
+func (s *InmemoryStorageState) setPruner(p pruner.Pruner) {
+	s.pruner = p
 }
 
 // StoreTrie stores the given trie in the StorageState and writes it to the database
@@ -63,26 +71,25 @@ func (s *InmemoryStorageState) StoreTrie(ts *storage.TrieState, header *types.He
 	root := ts.Trie().MustHash()
 	s.tries.softSet(root, ts.Trie())
 
-	if header != nil {
-		insertedNodeHashes, deletedNodeHashes, err := ts.GetChangedNodeHashes()
-		if err != nil {
-			return fmt.Errorf("getting trie changed node hashes for block hash %s: %w", header.Hash(), err)
-		}
-
-		err = s.pruner.StoreJournalRecord(deletedNodeHashes, insertedNodeHashes, header.Hash(), int64(header.Number))
-		if err != nil {
-			return fmt.Errorf("storing journal record: %w", err)
-		}
-	}
-
 	logger.Tracef("cached trie in storage state: %s", root)
 
 	// TODO: all trie related db operations should be done in pkg/trie
-	if inmemoryTrie, ok := ts.Trie().(*inmemory_trie.InMemoryTrie); ok {
-		if err := inmemoryTrie.WriteDirty(s.db); err != nil {
-			logger.Warnf("failed to write trie with root %s to database: %s", root, err)
-			return err
+	writeNodes := func() error {
+		if inmemoryTrie, ok := ts.Trie().(*inmemory_trie.InMemoryTrie); ok {
+			return inmemoryTrie.WriteDirty(s.db)
 		}
+		return nil
+	}
+
+	var err error
+	if header != nil {
+		err = s.pruner.StoreJournalRecord(writeNodes, root, header.Hash(), int64(header.Number))
+	} else {
+		err = writeNodes()
+	}
+	if err != nil {
+		logger.Warnf("failed to write trie with root %s to database: %s", root, err)
+		return err
 	}
 
 	go s.notifyAll(root)
@@ -248,6 +255,17 @@ func (s *InmemoryStorageState) GetKeysWithPrefix(root *common.Hash, prefix []byt
 	return tr.GetKeysWithPrefix(prefix), nil
 }
 
+// GetClosestDescendantMerkleValue returns the Merkle value of the closest node whose key starts
+// with the given key for the given hash (or best block state root if hash is nil)
+func (s *InmemoryStorageState) GetClosestDescendantMerkleValue(root *common.Hash, key []byte) ([]byte, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.ClosestDescendantMerkleValue(key)
+}
+
 // GetStorageChild returns a child trie, if it exists
 func (s *InmemoryStorageState) GetStorageChild(root *common.Hash, keyToChild []byte) (trie.Trie, error) {
 	tr, err := s.loadTrie(root)
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ChainSafe/gossamer/dot/state/pruner (interfaces: BlockState)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks_test.go -package=pruner . BlockState
+//
+
+// Package pruner is a generated GoMock package.
+package pruner
+
+import (
+	reflect "reflect"
+
+	types "github.com/ChainSafe/gossamer/dot/types"
+	common "github.com/ChainSafe/gossamer/lib/common"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockState is a mock of BlockState interface.
+type MockBlockState struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockStateMockRecorder
+}
+
+// MockBlockStateMockRecorder is the mock recorder for MockBlockState.
+type MockBlockStateMockRecorder struct {
+	mock *MockBlockState
+}
+
+// NewMockBlockState creates a new mock instance.
+func NewMockBlockState(ctrl *gomock.Controller) *MockBlockState {
+	mock := &MockBlockState{ctrl: ctrl}
+	mock.recorder = &MockBlockStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockState) EXPECT() *MockBlockStateMockRecorder {
+	return m.recorder
+}
+
+// FreeFinalisedNotifierChannel mocks base method.
+func (m *MockBlockState) FreeFinalisedNotifierChannel(arg0 chan *types.FinalisationInfo) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FreeFinalisedNotifierChannel", arg0)
+}
+
+// FreeFinalisedNotifierChannel indicates an expected call of FreeFinalisedNotifierChannel.
+func (mr *MockBlockStateMockRecorder) FreeFinalisedNotifierChannel(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeFinalisedNotifierChannel", reflect.TypeOf((*MockBlockState)(nil).FreeFinalisedNotifierChannel), arg0)
+}
+
+// GetAllDescendants mocks base method.
+func (m *MockBlockState) GetAllDescendants(arg0 common.Hash) ([]common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllDescendants", arg0)
+	ret0, _ := ret[0].([]common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllDescendants indicates an expected call of GetAllDescendants.
+func (mr *MockBlockStateMockRecorder) GetAllDescendants(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllDescendants", reflect.TypeOf((*MockBlockState)(nil).GetAllDescendants), arg0)
+}
+
+// GetFinalisedNotifierChannel mocks base method.
+func (m *MockBlockState) GetFinalisedNotifierChannel() chan *types.FinalisationInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFinalisedNotifierChannel")
+	ret0, _ := ret[0].(chan *types.FinalisationInfo)
+	return ret0
+}
+
+// GetFinalisedNotifierChannel indicates an expected call of GetFinalisedNotifierChannel.
+func (mr *MockBlockStateMockRecorder) GetFinalisedNotifierChannel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinalisedNotifierChannel", reflect.TypeOf((*MockBlockState)(nil).GetFinalisedNotifierChannel))
+}
+
+// GetHeader mocks base method.
+func (m *MockBlockState) GetHeader(arg0 common.Hash) (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeader", arg0)
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeader indicates an expected call of GetHeader.
+func (mr *MockBlockStateMockRecorder) GetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeader", reflect.TypeOf((*MockBlockState)(nil).GetHeader), arg0)
+}
+
+// GetHighestFinalisedHeader mocks base method.
+func (m *MockBlockState) GetHighestFinalisedHeader() (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHighestFinalisedHeader")
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHighestFinalisedHeader indicates an expected call of GetHighestFinalisedHeader.
+func (mr *MockBlockStateMockRecorder) GetHighestFinalisedHeader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestFinalisedHeader", reflect.TypeOf((*MockBlockState)(nil).GetHighestFinalisedHeader))
+}
@@ -4,21 +4,53 @@
 package pruner
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/codec"
+	"github.com/ChainSafe/gossamer/pkg/trie/node"
 )
 
 const (
 	// Archive pruner mode.
 	Archive = Mode("archive")
+	// Full pruner mode.
+	Full = Mode("full")
+)
+
+var logger = log.NewFromGlobal(log.AddContext("pkg", "pruner"))
+
+// ErrBlockStatePruned is returned when reverting to a block whose state is pruned.
+var ErrBlockStatePruned = errors.New("block state is pruned")
+
+var (
+	journalPrefix = []byte("journal")
+	lastPrunedKey = []byte("pruner_last_pruned")
+	refsPrefix    = []byte("pruner_refs")
+
+	childStorageKeyPrefix = codec.KeyLEToNibbles(trie.ChildStorageKeyPrefix)
 )
 
+// maxPendingRefs is the number of node reference counts buffered in memory
+// before being written to the database while counting the references of a state.
+const maxPendingRefs = 1 << 16
+
 // Mode online pruning mode of historical state tries
 type Mode string
 
 // IsValid checks whether the pruning mode is valid
 func (p Mode) IsValid() bool {
 	switch p {
-	case Archive:
+	case Archive, Full:
 		return true
 	default:
 		return false
@@ -33,15 +65,580 @@ type Config struct {
 
 // Pruner is implemented by FullNode and ArchiveNode.
 type Pruner interface {
-	StoreJournalRecord(deletedNodeHashes, insertedNodeHashes map[common.Hash]struct{},
-		blockHash common.Hash, blockNum int64) error
+	StoreJournalRecord(writeNodes func() error, stateRoot, blockHash common.Hash, blockNum int64) error
 }
 
 // ArchiveNode is a no-op since we don't prune nodes in archive mode.
 type ArchiveNode struct{}
 
-// StoreJournalRecord for archive node doesn't do anything.
-func (*ArchiveNode) StoreJournalRecord(_, _ map[common.Hash]struct{},
-	_ common.Hash, _ int64) error {
+// StoreJournalRecord for archive node only writes the trie nodes of the block.
+func (*ArchiveNode) StoreJournalRecord(writeNodes func() error, _, _ common.Hash, _ int64) error {
+	return writeNodes()
+}
+
+// BlockState is the block state used by the full node pruner.
+type BlockState interface {
+	GetHighestFinalisedHeader() (*types.Header, error)
+	GetAllDescendants(hash common.Hash) ([]common.Hash, error)
+	GetHeader(hash common.Hash) (*types.Header, error)
+	GetFinalisedNotifierChannel() chan *types.FinalisationInfo
+	FreeFinalisedNotifierChannel(ch chan *types.FinalisationInfo)
+}
+
+// FullNode counts in the database the references to each trie node, from its
+// parent nodes and from the state roots of the journaled blocks, and deletes the
+// trie nodes which are no longer referenced once the states of the blocks older
+// than the last RetainedBlocks finalised blocks are released, forks included.
+type FullNode struct {
+	db           database.Database
+	nodePrefix   []byte
+	blockState   BlockState
+	retainBlocks uint32
+
+	mutex sync.Mutex
+	// lastPruned is the last pruned block number, the states of the blocks
+	// numbered below it are released.
+	lastPruned uint
+
+	finalised chan *types.FinalisationInfo
+	done      chan struct{}
+}
+
+// journalRecord is the SCALE encoded value of journal database entries
+type journalRecord struct {
+	StateRoot common.Hash
+}
+
+// NewFullNode creates a full node pruner retaining the states of the last
+// retainBlocks finalised blocks. Trie nodes are stored in the database under
+// the given node prefix. When the database is pruned for the first time, the
+// references to the trie nodes of the finalised block state and of its
+// descendants states are counted.
+func NewFullNode(db database.Database, nodePrefix string, blockState BlockState,
+	retainBlocks uint32) (*FullNode, error) {
+	fullNode := &FullNode{
+		db:           db,
+		nodePrefix:   []byte(nodePrefix),
+		blockState:   blockState,
+		retainBlocks: retainBlocks,
+	}
+
+	lastPruned, err := db.Get(lastPrunedKey)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		err = fullNode.initialise()
+		if err != nil {
+			return nil, fmt.Errorf("initialising: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("getting last pruned block number: %w", err)
+	default:
+		fullNode.lastPruned = uint(binary.BigEndian.Uint64(lastPruned))
+	}
+
+	return fullNode, nil
+}
+
+// initialise journals the finalised block and its descendants, since their
+// states were stored before pruning was enabled.
+func (p *FullNode) initialise() error {
+	finalised, err := p.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	blockHashes, err := p.blockState.GetAllDescendants(finalised.Hash())
+	if err != nil {
+		return fmt.Errorf("getting descendants of finalised block: %w", err)
+	}
+
+	for _, blockHash := range blockHashes {
+		header, err := p.blockState.GetHeader(blockHash)
+		if err != nil {
+			return fmt.Errorf("getting header of block %s: %w", blockHash, err)
+		}
+
+		err = p.journal(header.StateRoot, blockHash, header.Number)
+		if err != nil {
+			return fmt.Errorf("journaling block %s: %w", blockHash, err)
+		}
+	}
+
+	err = p.db.Put(lastPrunedKey, encodeNumber(finalised.Number))
+	if err != nil {
+		return fmt.Errorf("storing last pruned block number: %w", err)
+	}
+
+	p.lastPruned = finalised.Number
+	logger.Infof("counted trie node references of %d block states from finalised block #%d",
+		len(blockHashes), finalised.Number)
+	return nil
+}
+
+// Start starts pruning the database each time a block is finalised.
+func (p *FullNode) Start() {
+	p.finalised = p.blockState.GetFinalisedNotifierChannel()
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+		for info := range p.finalised {
+			err := p.Prune(info.Header.Number)
+			if err != nil {
+				logger.Errorf("pruning state below finalised block #%d: %s", info.Header.Number, err)
+			}
+		}
+	}()
+}
+
+// Stop stops pruning the database.
+func (p *FullNode) Stop() {
+	if p.finalised == nil {
+		return
+	}
+
+	p.blockState.FreeFinalisedNotifierChannel(p.finalised)
+	<-p.done
+	p.finalised = nil
+}
+
+// StoreJournalRecord writes the trie nodes of the block given using writeNodes,
+// counts the references to the nodes of its state and stores its journal record.
+// Pruning is paused meanwhile, so the nodes written are not deleted before
+// being referenced.
+func (p *FullNode) StoreJournalRecord(writeNodes func() error, stateRoot, blockHash common.Hash,
+	blockNum int64) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	err := writeNodes()
+	if err != nil {
+		return err
+	}
+
+	number := uint(blockNum)
+	if number >= p.lastPruned {
+		return p.journal(stateRoot, blockHash, number)
+	}
+
+	// the state of the block is already below the pruned blocks so its nodes
+	// which are not referenced by another state are deleted right away.
+	refs := newNodeRefs(p.db)
+	batch := p.db.NewBatch()
+	err = p.reference(refs, batch, stateRoot)
+	if err != nil {
+		return fmt.Errorf("referencing state root: %w", err)
+	}
+
+	pruned, err := p.release(refs, batch, stateRoot)
+	if err != nil {
+		return fmt.Errorf("releasing state root: %w", err)
+	}
+
+	err = refs.writeTo(batch)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("pruned %d trie nodes of block %s below pruned block #%d", pruned, blockHash, p.lastPruned)
+	return batch.Flush()
+}
+
+// journal references the state root given and stores the journal record of the block.
+func (p *FullNode) journal(stateRoot, blockHash common.Hash, number uint) error {
+	refs := newNodeRefs(p.db)
+	batch := p.db.NewBatch()
+	err := p.reference(refs, batch, stateRoot)
+	if err != nil {
+		return fmt.Errorf("referencing state root: %w", err)
+	}
+
+	err = refs.writeTo(batch)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := scale.Marshal(journalRecord{StateRoot: stateRoot})
+	if err != nil {
+		return fmt.Errorf("encoding journal record: %w", err)
+	}
+
+	err = batch.Put(journalKey(number, blockHash), encoded)
+	if err != nil {
+		return err
+	}
+
+	return batch.Flush()
+}
+
+// Prune releases the states of the blocks numbered below finalised minus the
+// retained blocks, and deletes their journal records together with the trie
+// nodes no longer referenced.
+func (p *FullNode) Prune(finalised uint) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if finalised <= uint(p.retainBlocks) {
+		return nil
+	}
+
+	target := finalised - uint(p.retainBlocks)
+	for number := p.lastPruned + 1; number <= target; number++ {
+		err := p.pruneBlockNumber(number)
+		if err != nil {
+			return fmt.Errorf("pruning block #%d: %w", number, err)
+		}
+	}
+
+	return nil
+}
+
+// pruneBlockNumber releases the states of all the blocks numbered below the
+// given number, whether they are canonical or not.
+func (p *FullNode) pruneBlockNumber(number uint) error {
+	type keyedRecord struct {
+		key    []byte
+		record journalRecord
+	}
+
+	var records []keyedRecord
+	err := p.iterateJournal(journalNumberPrefix(number-1),
+		func(key []byte, record journalRecord) error {
+			records = append(records, keyedRecord{key: key, record: record})
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("loading journal records: %w", err)
+	}
+
+	refs := newNodeRefs(p.db)
+	batch := p.db.NewBatch()
+	pruned := 0
+	for _, keyed := range records {
+		released, err := p.release(refs, batch, keyed.record.StateRoot)
+		if err != nil {
+			return fmt.Errorf("releasing state root %s: %w", keyed.record.StateRoot, err)
+		}
+		pruned += released
+
+		err = batch.Del(keyed.key)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = refs.writeTo(batch)
+	if err != nil {
+		return err
+	}
+
+	err = batch.Put(lastPrunedKey, encodeNumber(number))
+	if err != nil {
+		return err
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return err
+	}
+
+	p.lastPruned = number
+	logger.Debugf("pruned %d trie nodes and %d journal records of block #%d", pruned, len(records), number-1)
 	return nil
 }
+
+// Revert releases the states of the blocks numbered above the given number,
+// whether they are canonical or not, and deletes their journal records together
+// with the trie nodes no longer referenced. It fails if the state of the blocks
+// with the given number is already released.
+func (p *FullNode) Revert(number uint) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if number < p.lastPruned {
+		return fmt.Errorf("%w: reverting to block #%d below the last pruned block #%d",
+			ErrBlockStatePruned, number, p.lastPruned)
+	}
+
+	refs := newNodeRefs(p.db)
+	batch := p.db.NewBatch()
+	pruned, records := 0, 0
+	err := p.iterateJournal(journalPrefix, func(key []byte, record journalRecord) error {
+		recordNumber := binary.BigEndian.Uint64(key[len(journalPrefix):])
+		if recordNumber <= uint64(number) {
+			return nil
+		}
+
+		released, err := p.release(refs, batch, record.StateRoot)
+		if err != nil {
+			return fmt.Errorf("releasing state root %s: %w", record.StateRoot, err)
+		}
+		pruned += released
+		records++
+
+		return batch.Del(key)
+	})
+	if err != nil {
+		return fmt.Errorf("reverting journal records: %w", err)
+	}
+
+	err = refs.writeTo(batch)
+	if err != nil {
+		return err
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("pruned %d trie nodes and %d journal records above block #%d", pruned, records, number)
+	return nil
+}
+
+// reference increments the reference count of the given trie node and, if the
+// node was not referenced yet, the reference counts of the trie nodes and
+// hashed storage values it references.
+// Reference counts are written to the batch when too many of them are buffered.
+func (p *FullNode) reference(refs *nodeRefs, batch database.Batch, nodeHash common.Hash) error {
+	stack := []trieRef{{key: nodeHash.ToBytes()}}
+	for len(stack) > 0 {
+		ref := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		count, err := refs.get(ref.key)
+		if err != nil {
+			return err
+		}
+
+		refs.set(ref.key, count+1)
+		if count > 0 {
+			continue
+		}
+
+		children, err := p.children(ref)
+		if err != nil {
+			return err
+		}
+		stack = append(stack, children...)
+
+		if len(refs.counts) < maxPendingRefs {
+			continue
+		}
+
+		err = refs.writeTo(batch)
+		if err != nil {
+			return err
+		}
+
+		err = batch.Flush()
+		if err != nil {
+			return fmt.Errorf("writing node reference counts: %w", err)
+		}
+		batch.Reset()
+	}
+
+	return nil
+}
+
+// release decrements the reference count of the given trie node and deletes
+// it if it is no longer referenced, releasing the trie nodes and hashed storage
+// values it references in turn.
+// It returns the number of trie nodes and hashed storage values deleted.
+func (p *FullNode) release(refs *nodeRefs, batch database.Batch, nodeHash common.Hash) (pruned int, err error) {
+	stack := []trieRef{{key: nodeHash.ToBytes()}}
+	for len(stack) > 0 {
+		ref := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		count, err := refs.get(ref.key)
+		if err != nil {
+			return pruned, err
+		}
+
+		if count == 0 {
+			// the node is not referenced by any journaled state
+			continue
+		}
+
+		refs.set(ref.key, count-1)
+		if count > 1 {
+			continue
+		}
+
+		children, err := p.children(ref)
+		if err != nil {
+			return pruned, err
+		}
+		stack = append(stack, children...)
+
+		err = batch.Del(p.nodeKey(ref.key))
+		if err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+// trieRef is a trie node or a hashed storage value referenced from a state.
+type trieRef struct {
+	// key is the database key of the node or value, without the node prefix.
+	key []byte
+	// prefix is the key in nibbles of the node position in its trie.
+	prefix  []byte
+	isValue bool
+}
+
+// children returns the trie nodes and hashed storage values referenced from the
+// trie node given: its child nodes referenced by hash, its hashed storage value
+// and, for the child storage entries of a main trie, the root of the child trie.
+// Storage values and nodes missing from the database reference nothing.
+func (p *FullNode) children(ref trieRef) ([]trieRef, error) {
+	if ref.isValue {
+		return nil, nil
+	}
+
+	encoding, err := p.db.Get(p.nodeKey(ref.key))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting node 0x%x: %w", ref.key, err)
+	}
+
+	decoded, err := node.Decode(bytes.NewReader(encoding))
+	if err != nil {
+		return nil, fmt.Errorf("decoding node 0x%x: %w", ref.key, err)
+	}
+
+	fullKey := bytes.Join([][]byte{ref.prefix, decoded.PartialKey}, nil)
+
+	var children []trieRef
+	switch {
+	case decoded.IsHashedValue:
+		// hashed storage values are stored under the node partial key followed by the value hash
+		children = append(children, trieRef{
+			key:     bytes.Join([][]byte{decoded.PartialKey, decoded.StorageValue}, nil),
+			isValue: true,
+		})
+	case len(decoded.StorageValue) == common.HashLength && len(fullKey) > len(childStorageKeyPrefix) &&
+		bytes.HasPrefix(fullKey, childStorageKeyPrefix):
+		// child trie nodes are stored alongside the main trie nodes
+		children = append(children, trieRef{key: decoded.StorageValue})
+	}
+
+	for i, child := range decoded.Children {
+		if child == nil || len(child.MerkleValue) != common.HashLength {
+			// inlined children cannot reference other nodes by hash
+			continue
+		}
+		children = append(children, trieRef{
+			key:    child.MerkleValue,
+			prefix: append(bytes.Clone(fullKey), byte(i)),
+		})
+	}
+
+	return children, nil
+}
+
+func (p *FullNode) nodeKey(key []byte) []byte {
+	return bytes.Join([][]byte{p.nodePrefix, key}, nil)
+}
+
+func (p *FullNode) iterateJournal(prefix []byte, handle func(key []byte, record journalRecord) error) error {
+	iter, err := p.db.NewPrefixIterator(prefix)
+	if err != nil {
+		return fmt.Errorf("creating prefix iterator: %w", err)
+	}
+	defer iter.Release()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		key := make([]byte, len(iter.Key()))
+		copy(key, iter.Key())
+
+		var record journalRecord
+		err = scale.Unmarshal(iter.Value(), &record)
+		if err != nil {
+			return fmt.Errorf("decoding journal record at key 0x%x: %w", key, err)
+		}
+
+		err = handle(key, record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nodeRefs buffers the updated reference counts of trie nodes on top of the database.
+type nodeRefs struct {
+	db     database.Reader
+	counts map[string]uint32
+}
+
+func newNodeRefs(db database.Reader) *nodeRefs {
+	return &nodeRefs{
+		db:     db,
+		counts: make(map[string]uint32),
+	}
+}
+
+func (r *nodeRefs) get(key []byte) (count uint32, err error) {
+	count, ok := r.counts[string(key)]
+	if ok {
+		return count, nil
+	}
+
+	encoded, err := r.db.Get(refsKey(key))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("getting reference count of node 0x%x: %w", key, err)
+	}
+
+	return binary.BigEndian.Uint32(encoded), nil
+}
+
+func (r *nodeRefs) set(key []byte, count uint32) {
+	r.counts[string(key)] = count
+}
+
+// writeTo writes the buffered reference counts to the batch and clears them.
+func (r *nodeRefs) writeTo(batch database.Batch) (err error) {
+	for key, count := range r.counts {
+		if count == 0 {
+			err = batch.Del(refsKey([]byte(key)))
+		} else {
+			err = batch.Put(refsKey([]byte(key)), binary.BigEndian.AppendUint32(nil, count))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	clear(r.counts)
+	return nil
+}
+
+func refsKey(key []byte) []byte {
+	return bytes.Join([][]byte{refsPrefix, key}, nil)
+}
+
+func encodeNumber(number uint) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(number))
+}
+
+// journalKey returns the journal key of the given block, made of the journal
+// prefix, the big endian encoded block number and the block hash.
+func journalKey(number uint, blockHash common.Hash) []byte {
+	return append(journalNumberPrefix(number), blockHash.ToBytes()...)
+}
+
+// journalNumberPrefix returns the prefix of the journal keys of the blocks with the given number.
+func journalNumberPrefix(number uint) []byte {
+	prefix := make([]byte, 0, len(journalPrefix)+8+common.HashLength)
+	prefix = append(prefix, journalPrefix...)
+	return binary.BigEndian.AppendUint64(prefix, uint64(number))
+}
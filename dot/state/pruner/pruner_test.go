@@ -0,0 +1,291 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package pruner
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testNodePrefix = "storage"
+
+// leafValue is long enough for leaves to be referenced by hash by their parent.
+var leafValue = []byte("a storage value long enough to be hashed")
+
+func newTestTrie(t *testing.T, entries map[string][]byte) *inmemory.InMemoryTrie {
+	t.Helper()
+
+	trie := inmemory.NewEmptyTrie()
+	for key, value := range entries {
+		err := trie.Put([]byte(key), value)
+		require.NoError(t, err)
+	}
+	return trie
+}
+
+// storeState writes the trie nodes of the given trie using the pruner.
+func storeState(t *testing.T, db database.Database, fullNode *FullNode,
+	trie *inmemory.InMemoryTrie, blockHash common.Hash, number int64) common.Hash {
+	t.Helper()
+
+	root := trie.MustHash()
+	writeNodes := func() error {
+		return trie.WriteDirty(database.NewTable(db, testNodePrefix))
+	}
+
+	err := fullNode.StoreJournalRecord(writeNodes, root, blockHash, number)
+	require.NoError(t, err)
+	return root
+}
+
+func stateExists(t *testing.T, db database.Database, root common.Hash) bool {
+	t.Helper()
+
+	trie := inmemory.NewEmptyTrie()
+	err := trie.Load(database.NewTable(db, testNodePrefix), root)
+	return err == nil
+}
+
+// newGenesis writes the trie of the given entries as the state of the finalised
+// genesis block, before pruning is enabled.
+func newGenesis(t *testing.T, db database.Database, blockState *MockBlockState,
+	entries map[string][]byte) common.Hash {
+	t.Helper()
+
+	return newGenesisTrie(t, db, blockState, newTestTrie(t, entries))
+}
+
+// newGenesisTrie writes the given trie as the state of the finalised genesis
+// block, before pruning is enabled.
+func newGenesisTrie(t *testing.T, db database.Database, blockState *MockBlockState,
+	trie *inmemory.InMemoryTrie) common.Hash {
+	t.Helper()
+
+	err := trie.WriteDirty(database.NewTable(db, testNodePrefix))
+	require.NoError(t, err)
+
+	header := types.NewEmptyHeader()
+	header.StateRoot = trie.MustHash()
+	blockState.EXPECT().GetHighestFinalisedHeader().Return(header, nil)
+	blockState.EXPECT().GetAllDescendants(header.Hash()).Return([]common.Hash{header.Hash()}, nil)
+	blockState.EXPECT().GetHeader(header.Hash()).Return(header, nil)
+
+	return header.StateRoot
+}
+
+func Test_Mode_IsValid(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Archive.IsValid())
+	assert.True(t, Full.IsValid())
+	assert.False(t, Mode("").IsValid())
+	assert.False(t, Mode("light").IsValid())
+}
+
+func Test_FullNode_Prune(t *testing.T) {
+	t.Parallel()
+
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+
+	// the leaves of keys a1 and b1 are the same node referenced twice
+	genesisEntries := map[string][]byte{"a1": leafValue, "b1": leafValue, "c": {1}}
+	genesisRoot := newGenesis(t, db, blockState, genesisEntries)
+
+	fullNode, err := NewFullNode(db, testNodePrefix, blockState, 0)
+	require.NoError(t, err)
+
+	blockOneEntries := map[string][]byte{"a1": {2}, "b1": leafValue, "c": {1}}
+	blockOneRoot := storeState(t, db, fullNode, newTestTrie(t, blockOneEntries), common.Hash{1}, 1)
+	forkOneEntries := map[string][]byte{"a1": leafValue, "b1": leafValue, "c": {3}, "d": leafValue}
+	forkOneRoot := storeState(t, db, fullNode, newTestTrie(t, forkOneEntries), common.Hash{0x11}, 1)
+	blockTwoEntries := map[string][]byte{"a1": {2}, "b1": leafValue, "c": {1}, "e": leafValue}
+	blockTwoRoot := storeState(t, db, fullNode, newTestTrie(t, blockTwoEntries), common.Hash{2}, 2)
+
+	err = fullNode.Prune(1)
+	require.NoError(t, err)
+
+	assert.False(t, stateExists(t, db, genesisRoot))
+	// the leaf of b1 is still referenced by the other states
+	assert.True(t, stateExists(t, db, blockOneRoot))
+	assert.True(t, stateExists(t, db, forkOneRoot))
+	assert.True(t, stateExists(t, db, blockTwoRoot))
+
+	err = fullNode.Prune(2)
+	require.NoError(t, err)
+
+	// the states of canonical and non canonical blocks are both pruned
+	assert.False(t, stateExists(t, db, blockOneRoot))
+	assert.False(t, stateExists(t, db, forkOneRoot))
+	assert.True(t, stateExists(t, db, blockTwoRoot))
+
+	var journaled []common.Hash
+	err = fullNode.iterateJournal(journalPrefix, func(_ []byte, record journalRecord) error {
+		journaled = append(journaled, record.StateRoot)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{blockTwoRoot}, journaled)
+
+	// the last pruned block number is persisted
+	reloaded, err := NewFullNode(db, testNodePrefix, blockState, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), reloaded.lastPruned)
+
+	// the nodes of blocks below the pruned blocks are deleted right away
+	lateForkEntries := map[string][]byte{"a1": {2}, "b1": leafValue, "c": {1}, "f": leafValue}
+	lateForkRoot := storeState(t, db, reloaded, newTestTrie(t, lateForkEntries), common.Hash{0x12}, 1)
+	assert.False(t, stateExists(t, db, lateForkRoot))
+	assert.True(t, stateExists(t, db, blockTwoRoot))
+
+	err = reloaded.Prune(3)
+	require.NoError(t, err)
+	assert.False(t, stateExists(t, db, blockTwoRoot))
+
+	// all the nodes are pruned so no reference count is left
+	iter, err := db.NewPrefixIterator(refsPrefix)
+	require.NoError(t, err)
+	assert.False(t, iter.First())
+	iter.Release()
+}
+
+func Test_FullNode_Prune_childTrieAndHashedValues(t *testing.T) {
+	t.Parallel()
+
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+
+	// leafValue is longer than 32 bytes so it is stored apart from its node
+	genesisTrie := newTestTrie(t, nil)
+	genesisTrie.SetVersion(trie.V1)
+	err = genesisTrie.Put([]byte("a"), leafValue)
+	require.NoError(t, err)
+	err = genesisTrie.Put([]byte("b"), []byte{1})
+	require.NoError(t, err)
+	childTrie := newTestTrie(t, map[string][]byte{"c": leafValue, "d": leafValue})
+	err = genesisTrie.SetChild([]byte("child"), childTrie)
+	require.NoError(t, err)
+	childRoot := childTrie.MustHash()
+	genesisRoot := newGenesisTrie(t, db, blockState, genesisTrie)
+
+	fullNode, err := NewFullNode(db, testNodePrefix, blockState, 0)
+	require.NoError(t, err)
+
+	refs := newNodeRefs(db)
+	count, err := refs.get(childRoot.ToBytes())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), count)
+
+	blockOneRoot := storeState(t, db, fullNode, newTestTrie(t, map[string][]byte{"b": {1}}), common.Hash{1}, 1)
+
+	err = fullNode.Prune(1)
+	require.NoError(t, err)
+	assert.False(t, stateExists(t, db, genesisRoot))
+
+	// only the root node of the block one state is left, the child trie
+	// nodes and the hashed storage value of the genesis state are deleted.
+	var storedKeys [][]byte
+	iter, err := db.NewPrefixIterator([]byte(testNodePrefix))
+	require.NoError(t, err)
+	for iter.First(); iter.Valid(); iter.Next() {
+		storedKeys = append(storedKeys, bytes.Clone(iter.Key()))
+	}
+	iter.Release()
+	expectedKeys := [][]byte{append([]byte(testNodePrefix), blockOneRoot.ToBytes()...)}
+	assert.Equal(t, expectedKeys, storedKeys)
+}
+
+func Test_FullNode_Prune_retainBlocks(t *testing.T) {
+	t.Parallel()
+
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+
+	genesisRoot := newGenesis(t, db, blockState, map[string][]byte{"a": leafValue})
+
+	fullNode, err := NewFullNode(db, testNodePrefix, blockState, 10)
+	require.NoError(t, err)
+
+	blockOneRoot := storeState(t, db, fullNode,
+		newTestTrie(t, map[string][]byte{"b": leafValue}), common.Hash{1}, 1)
+
+	err = fullNode.Prune(10)
+	require.NoError(t, err)
+	assert.True(t, stateExists(t, db, genesisRoot))
+	assert.Equal(t, uint(0), fullNode.lastPruned)
+
+	err = fullNode.Prune(11)
+	require.NoError(t, err)
+	assert.False(t, stateExists(t, db, genesisRoot))
+	assert.True(t, stateExists(t, db, blockOneRoot))
+	assert.Equal(t, uint(1), fullNode.lastPruned)
+}
+
+func Test_FullNode_Revert(t *testing.T) {
+	t.Parallel()
+
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+
+	genesisRoot := newGenesis(t, db, blockState, map[string][]byte{"a": leafValue})
+
+	fullNode, err := NewFullNode(db, testNodePrefix, blockState, 0)
+	require.NoError(t, err)
+
+	blockOneEntries := map[string][]byte{"a": leafValue, "b": leafValue}
+	blockOneRoot := storeState(t, db, fullNode, newTestTrie(t, blockOneEntries), common.Hash{1}, 1)
+	blockTwoEntries := map[string][]byte{"a": leafValue, "b": leafValue, "c": leafValue}
+	blockTwoRoot := storeState(t, db, fullNode, newTestTrie(t, blockTwoEntries), common.Hash{2}, 2)
+	forkTwoRoot := storeState(t, db, fullNode,
+		newTestTrie(t, map[string][]byte{"a": leafValue, "d": leafValue}), common.Hash{0x12}, 2)
+
+	err = fullNode.Prune(1)
+	require.NoError(t, err)
+
+	// the states of the blocks above the target block are released, forks included
+	err = fullNode.Revert(1)
+	require.NoError(t, err)
+	assert.True(t, stateExists(t, db, blockOneRoot))
+	assert.False(t, stateExists(t, db, blockTwoRoot))
+	assert.False(t, stateExists(t, db, forkTwoRoot))
+
+	var journaled []common.Hash
+	err = fullNode.iterateJournal(journalPrefix, func(_ []byte, record journalRecord) error {
+		journaled = append(journaled, record.StateRoot)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{blockOneRoot}, journaled)
+
+	// the state of the genesis block is already released
+	err = fullNode.Revert(0)
+	assert.ErrorIs(t, err, ErrBlockStatePruned)
+	assert.EqualError(t, err, "block state is pruned: reverting to block #0 below the last pruned block #1")
+	assert.False(t, stateExists(t, db, genesisRoot))
+}
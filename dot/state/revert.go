@@ -0,0 +1,416 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/blocktree"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// ErrRevertFinalised is returned when reverting finalised blocks is not allowed.
+var ErrRevertFinalised = errors.New("cannot revert finalised blocks")
+
+// Revert reverts the best chain by the given number of blocks, or down to the genesis block
+// if it has fewer blocks, and returns the number of reverted blocks. The reverted blocks and
+// their descendants are removed from the blocktree, along with the BABE epoch definitions and
+// the GRANDPA authority changes they announced.
+// Reverting finalised blocks fails with ErrRevertFinalised, unless revertFinalised is true.
+// Since unfinalised blocks are only kept in memory, reverting a node which is not running
+// reverts its highest finalised blocks: they are removed from the database, along with the
+// pruner journal of their states, and the new best block becomes the highest finalised block.
+// This must not be done on a running node, and the node may no longer agree with the network
+// on the finalised chain.
+func (s *Service) Revert(blocks uint, revertFinalised bool) (reverted uint, err error) {
+	bestHeader, err := s.Block.BestBlockHeader()
+	if err != nil {
+		return 0, fmt.Errorf("getting best block header: %w", err)
+	}
+
+	blocks = min(blocks, bestHeader.Number)
+	if blocks == 0 {
+		return 0, nil
+	}
+
+	target := bestHeader
+	var firstReverted *types.Header
+	for target.Number > bestHeader.Number-blocks {
+		firstReverted = target
+		target, err = s.Block.GetHeader(target.ParentHash)
+		if err != nil {
+			return 0, fmt.Errorf("getting header of block #%d: %w", firstReverted.Number-1, err)
+		}
+	}
+
+	finalisedHeader, err := s.Block.GetHighestFinalisedHeader()
+	if err != nil {
+		return 0, fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	switch {
+	case target.Number >= finalisedHeader.Number:
+		err = s.revertUnfinalised(target, firstReverted.Hash())
+	case !revertFinalised:
+		return 0, fmt.Errorf("%w: reverting %d blocks reaches block #%d below the highest finalised block #%d",
+			ErrRevertFinalised, blocks, target.Number, finalisedHeader.Number)
+	default:
+		err = s.revertFinalised(target, bestHeader, finalisedHeader)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Infof("reverted %d blocks, best block is now #%d (%s)", blocks, target.Number, target.Hash())
+	return blocks, nil
+}
+
+// revertUnfinalised removes the unfinalised block with the given hash and its descendants.
+func (s *Service) revertUnfinalised(target *types.Header, hash common.Hash) error {
+	removed, err := s.Block.removeUnfinalisedBlocks(hash)
+	if err != nil {
+		return fmt.Errorf("removing unfinalised blocks: %w", err)
+	}
+
+	removedHashes := make(map[common.Hash]struct{}, len(removed))
+	for _, hash := range removed {
+		removedHashes[hash] = struct{}{}
+	}
+
+	if s.Epoch != nil {
+		s.Epoch.pruneNextEpochDefinitions(removedHashes)
+	}
+
+	// forced changes are enacted when their block is imported
+	err = s.Grandpa.revertSetIDChanges(target.Number)
+	if err != nil {
+		return fmt.Errorf("reverting grandpa set id changes: %w", err)
+	}
+
+	s.Grandpa.prunePendingChanges(removedHashes)
+	return nil
+}
+
+// revertFinalised reverts the canonical chain down to the target block, which becomes the
+// highest finalised block. All the unfinalised blocks descend from the reverted blocks,
+// so they are discarded.
+func (s *Service) revertFinalised(target, bestHeader, finalisedHeader *types.Header) error {
+	// check the state of the target block is available before writing to the database
+	_, err := s.Storage.LoadFromDB(target.StateRoot)
+	if err != nil {
+		return fmt.Errorf("loading state of block #%d: %w", target.Number, err)
+	}
+
+	if s.onlinePruner != nil {
+		// release the states of the reverted blocks before the blocks are deleted,
+		// which fails if the state of the target block is pruned already.
+		err = s.onlinePruner.Revert(target.Number)
+		if err != nil {
+			return fmt.Errorf("reverting pruner journal: %w", err)
+		}
+	}
+
+	// the epochs are computed before the first slot number can be deleted,
+	// aura chains do not have babe epochs
+	var targetEpoch, bestEpoch uint64
+	if s.Epoch != nil {
+		if target.Number > 0 {
+			targetEpoch, err = s.Epoch.GetEpochForBlock(target)
+			if err != nil {
+				return fmt.Errorf("getting epoch of block #%d: %w", target.Number, err)
+			}
+		}
+
+		bestEpoch, err = s.Epoch.GetEpochForBlock(bestHeader)
+		if err != nil {
+			return fmt.Errorf("getting epoch of best block: %w", err)
+		}
+	}
+
+	revertedHashes := make(map[common.Hash]struct{}, finalisedHeader.Number-target.Number)
+	for number := target.Number + 1; number <= finalisedHeader.Number; number++ {
+		hash, err := s.Block.GetHashByNumber(number)
+		if err != nil {
+			return fmt.Errorf("getting hash of block #%d: %w", number, err)
+		}
+		revertedHashes[hash] = struct{}{}
+	}
+
+	round, setID, err := s.revertFinalisedHashes(revertedHashes)
+	if err != nil {
+		return fmt.Errorf("reverting finalised hashes: %w", err)
+	}
+
+	err = s.Block.revertFinalisedBlocks(target, revertedHashes, round, setID)
+	if err != nil {
+		return fmt.Errorf("reverting finalised blocks: %w", err)
+	}
+
+	if s.Epoch != nil {
+		err = s.Epoch.revertEpochDefinitions(targetEpoch, bestEpoch)
+		if err != nil {
+			return fmt.Errorf("reverting epoch definitions: %w", err)
+		}
+	}
+
+	err = s.Grandpa.revertSetIDChanges(target.Number)
+	if err != nil {
+		return fmt.Errorf("reverting grandpa set id changes: %w", err)
+	}
+
+	err = s.Grandpa.SetLatestRound(round)
+	if err != nil {
+		return fmt.Errorf("setting latest round: %w", err)
+	}
+
+	s.Grandpa.forcedChanges.pruneAll()
+	s.Grandpa.scheduledChangeRoots.pruneAll()
+	return nil
+}
+
+// revertFinalisedHashes deletes the finalised hashes of the reverted blocks, and returns the
+// highest round and set ID remaining, which the new highest finalised block is stored at.
+func (s *Service) revertFinalisedHashes(revertedHashes map[common.Hash]struct{}) (
+	round, setID uint64, err error) {
+	prefix := append([]byte(blockPrefix), common.FinalizedBlockHashKey...)
+	iter, err := s.db.NewPrefixIterator(prefix)
+	if err != nil {
+		return 0, 0, fmt.Errorf("creating prefix iterator: %w", err)
+	}
+
+	var revertedKeys [][]byte
+	for iter.First(); iter.Valid(); iter.Next() {
+		key := iter.Key()
+		if len(key) != len(prefix)+16 {
+			continue
+		}
+
+		entryRound := binary.LittleEndian.Uint64(key[len(prefix) : len(prefix)+8])
+		entrySetID := binary.LittleEndian.Uint64(key[len(prefix)+8:])
+
+		if _, has := revertedHashes[common.NewHash(iter.Value())]; has {
+			revertedKeys = append(revertedKeys, finalisedHashKey(entryRound, entrySetID))
+			continue
+		}
+
+		if entrySetID > setID || (entrySetID == setID && entryRound > round) {
+			round, setID = entryRound, entrySetID
+		}
+	}
+	iter.Release()
+
+	for _, key := range revertedKeys {
+		err = s.Block.db.Del(key)
+		if err != nil {
+			return 0, 0, fmt.Errorf("deleting finalised hash: %w", err)
+		}
+	}
+
+	return round, setID, nil
+}
+
+// removeUnfinalisedBlocks removes the unfinalised block with the given hash and its
+// descendants from the blocktree and from memory, and returns their hashes.
+func (bs *BlockState) removeUnfinalisedBlocks(hash common.Hash) (removed []common.Hash, err error) {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	removed, err = bs.bt.Remove(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hash := range removed {
+		header := bs.unfinalisedBlocks.delete(hash)
+		if header != nil {
+			bs.tries.delete(header.StateRoot)
+		}
+	}
+
+	return removed, nil
+}
+
+// revertFinalisedBlocks deletes the reverted blocks from the database and makes the target
+// block the highest finalised block, stored at the given round and set ID. The blocktree is
+// reset to the target block.
+func (bs *BlockState) revertFinalisedBlocks(target *types.Header, revertedHashes map[common.Hash]struct{},
+	round, setID uint64) error {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	batch := bs.db.NewBatch()
+	for hash := range revertedHashes {
+		keys := [][]byte{
+			headerKey(hash),
+			blockBodyKey(hash),
+			arrivalTimeKey(hash),
+			prefixKey(hash, receiptPrefix),
+			prefixKey(hash, messageQueuePrefix),
+			prefixKey(hash, justificationPrefix),
+		}
+		for _, key := range keys {
+			err := batch.Del(key)
+			if err != nil {
+				return fmt.Errorf("deleting block %s: %w", hash, err)
+			}
+		}
+	}
+
+	for number := target.Number + 1; number <= target.Number+uint(len(revertedHashes)); number++ {
+		err := batch.Del(headerHashKey(uint64(number)))
+		if err != nil {
+			return fmt.Errorf("deleting hash of block #%d: %w", number, err)
+		}
+	}
+
+	if target.Number == 0 {
+		err := batch.Del(firstSlotNumberKey)
+		if err != nil {
+			return fmt.Errorf("deleting first slot number: %w", err)
+		}
+	}
+
+	targetHash := target.Hash()
+	err := batch.Put(finalisedHashKey(round, setID), targetHash[:])
+	if err != nil {
+		return fmt.Errorf("setting finalised hash: %w", err)
+	}
+
+	err = batch.Put(highestRoundAndSetIDKey, roundAndSetIDToBytes(round, setID))
+	if err != nil {
+		return fmt.Errorf("setting highest round and set ID: %w", err)
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing batch: %w", err)
+	}
+
+	for _, hash := range bs.bt.GetAllBlocks() {
+		header := bs.unfinalisedBlocks.delete(hash)
+		if header != nil {
+			bs.tries.delete(header.StateRoot)
+		}
+	}
+
+	bs.bt = blocktree.NewBlockTreeFromRoot(target)
+	bs.lastFinalised = targetHash
+	bs.lastRound = round
+	bs.lastSetID = setID
+	return nil
+}
+
+// pruneNextEpochDefinitions removes the next epoch data and config data announced by the
+// given blocks from memory.
+func (s *EpochState) pruneNextEpochDefinitions(hashes map[common.Hash]struct{}) {
+	s.nextEpochDataLock.Lock()
+	s.nextEpochData.pruneAnnouncedBy(hashes)
+	s.nextEpochDataLock.Unlock()
+
+	s.nextConfigDataLock.Lock()
+	s.nextConfigData.pruneAnnouncedBy(hashes)
+	s.nextConfigDataLock.Unlock()
+}
+
+// revertEpochDefinitions deletes the epoch data and config data of the epochs after the next
+// epoch of the target block up to the next epoch of the best block, makes the target epoch
+// the current epoch and clears the next epoch definitions of the unfinalised blocks.
+func (s *EpochState) revertEpochDefinitions(targetEpoch, bestEpoch uint64) error {
+	// the definitions of the epoch following the target epoch are announced by
+	// the first block of the target epoch, so they are kept
+	batch := s.db.NewBatch()
+	for epoch := targetEpoch + 2; epoch <= bestEpoch+1; epoch++ {
+		err := batch.Del(epochDataKey(epoch))
+		if err != nil {
+			return fmt.Errorf("deleting data of epoch %d: %w", epoch, err)
+		}
+
+		err = batch.Del(configDataKey(epoch))
+		if err != nil {
+			return fmt.Errorf("deleting config data of epoch %d: %w", epoch, err)
+		}
+	}
+
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing batch: %w", err)
+	}
+
+	err = s.StoreCurrentEpoch(targetEpoch)
+	if err != nil {
+		return fmt.Errorf("storing current epoch: %w", err)
+	}
+
+	s.nextEpochDataLock.Lock()
+	s.nextEpochData = make(nextEpochMap[types.NextEpochData])
+	s.nextEpochDataLock.Unlock()
+
+	s.nextConfigDataLock.Lock()
+	s.nextConfigData = make(nextEpochMap[types.NextConfigDataV1])
+	s.nextConfigDataLock.Unlock()
+	return nil
+}
+
+// revertSetIDChanges deletes the authority sets enacted after the given block number,
+// including a next change stored ahead, and makes the last remaining set the current one.
+func (s *GrandpaState) revertSetIDChanges(number uint) error {
+	currentSetID, err := s.GetCurrentSetID()
+	if err != nil {
+		return fmt.Errorf("getting current set ID: %w", err)
+	}
+
+	nextChange, err := s.GetSetIDChange(currentSetID + 1)
+	switch {
+	case err == nil && nextChange > number:
+		err = s.deleteSetID(currentSetID + 1)
+		if err != nil {
+			return err
+		}
+	case err != nil && !errors.Is(err, database.ErrNotFound):
+		return fmt.Errorf("getting set ID change of set %d: %w", currentSetID+1, err)
+	}
+
+	for currentSetID > genesisSetID {
+		change, err := s.GetSetIDChange(currentSetID)
+		if err != nil {
+			return fmt.Errorf("getting set ID change of set %d: %w", currentSetID, err)
+		}
+
+		if change <= number {
+			break
+		}
+
+		err = s.deleteSetID(currentSetID)
+		if err != nil {
+			return err
+		}
+		currentSetID--
+	}
+
+	return s.setCurrentSetID(currentSetID)
+}
+
+func (s *GrandpaState) deleteSetID(setID uint64) error {
+	err := s.db.Del(authoritiesKey(setID))
+	if err != nil {
+		return fmt.Errorf("deleting authorities of set %d: %w", setID, err)
+	}
+
+	err = s.db.Del(setIDChangeKey(setID))
+	if err != nil {
+		return fmt.Errorf("deleting set ID change of set %d: %w", setID, err)
+	}
+
+	return nil
+}
+
+// prunePendingChanges removes the forced and scheduled changes announced by the given blocks
+func (s *GrandpaState) prunePendingChanges(hashes map[common.Hash]struct{}) {
+	s.forcedChanges.pruneAnnouncedBy(hashes)
+	s.scheduledChangeRoots.pruneAnnouncedBy(hashes)
+}
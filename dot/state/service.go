@@ -4,8 +4,10 @@
 package state
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -13,6 +15,7 @@
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/internal/metrics"
 	"github.com/ChainSafe/gossamer/lib/blocktree"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
 )
@@ -21,6 +24,8 @@
 	log.AddContext("pkg", "state"),
 )
 
+var errArchiveOnPrunedDatabase = errors.New("cannot run an archive node on a pruned database")
+
 // Service is the struct that holds storage, block and network states
 type Service struct {
 	dbPath            string
@@ -28,7 +33,7 @@ type Service struct {
 	db                database.Database
 	isMemDB           bool // set to true if using an in-memory database; only used for testing.
 	Base              *BaseState
-	Storage           *InmemoryStorageState
+	Storage           StorageState
 	Block             *BlockState
 	Transaction       *TransactionState
 	Epoch             *EpochState
@@ -36,6 +41,8 @@ type Service struct {
 	Slot              *SlotState
 	closeCh           chan interface{}
 	genesisBABEConfig *types.BabeConfiguration
+	onlinePruner      *pruner.FullNode
+	storageBackend    StorageBackend
 
 	PrunerCfg pruner.Config
 	Telemetry Telemetry
@@ -50,20 +57,46 @@ func (s *Service) Pause() error {
 	return s.Block.Pause()
 }
 
+// StorageBackend is the implementation holding the storage state tries
+type StorageBackend string
+
+const (
+	// InMemoryBackend keeps the storage state tries fully loaded in memory.
+	InMemoryBackend = StorageBackend("inmemory")
+	// TrieDBBackend reads the storage state tries lazily from the database.
+	TrieDBBackend = StorageBackend("triedb")
+)
+
+// IsValid checks whether the storage backend is valid
+func (b StorageBackend) IsValid() bool {
+	switch b {
+	case InMemoryBackend, TrieDBBackend:
+		return true
+	default:
+		return false
+	}
+}
+
 // Config is the default configuration used by state service.
 type Config struct {
 	Path              string
 	LogLevel          log.Level
 	PrunerCfg         pruner.Config
+	StorageBackend    StorageBackend
 	Telemetry         Telemetry
 	Metrics           metrics.IntervalConfig
-	GenesisBABEConfig *types.BabeConfiguration
+	GenesisBABEConfig *types.BabeConfiguration // nil for chains without babe epochs, such as aura chains
 }
 
 // NewService create a new instance of Service
 func NewService(config Config) *Service {
 	logger.Patch(log.SetLevel(config.LogLevel))
 
+	storageBackend := config.StorageBackend
+	if storageBackend == "" {
+		storageBackend = InMemoryBackend
+	}
+
 	return &Service{
 		dbPath:            config.Path,
 		logLvl:            config.LogLevel,
@@ -75,6 +108,7 @@ func NewService(config Config) *Service {
 		PrunerCfg:         config.PrunerCfg,
 		Telemetry:         config.Telemetry,
 		genesisBABEConfig: config.GenesisBABEConfig,
+		storageBackend:    storageBackend,
 	}
 }
 
@@ -90,6 +124,21 @@ func (s *Service) DB() database.Database {
 	return s.db
 }
 
+// SlotDuration returns the slot duration of the chain, which is stored in the
+// babe epoch configuration, or at genesis for aura chains.
+func (s *Service) SlotDuration() (time.Duration, error) {
+	if s.Epoch != nil {
+		return s.Epoch.GetSlotDuration()
+	}
+
+	slotDuration, err := s.Base.loadAuraSlotDuration()
+	if err != nil {
+		return 0, fmt.Errorf("loading aura slot duration: %w", err)
+	}
+
+	return slotDuration, nil
+}
+
 // SetupBase intitializes state.Base property with
 // the instance of a chain.NewBadger database
 func (s *Service) SetupBase() error {
@@ -139,30 +188,45 @@ func (s *Service) Start() (err error) {
 	logger.Debugf("start with latest state root: %s", stateRoot)
 
 	// create storage state
-	s.Storage, err = NewStorageState(s.db, s.Block, tries)
-	if err != nil {
-		return fmt.Errorf("failed to create storage state: %w", err)
+	switch s.storageBackend {
+	case TrieDBBackend:
+		s.Storage = NewTrieDBStorageState(s.db, s.Block)
+	default:
+		s.Storage, err = NewStorageState(s.db, s.Block, tries)
+		if err != nil {
+			return fmt.Errorf("failed to create storage state: %w", err)
+		}
 	}
+	logger.Debugf("using %s storage backend", s.storageBackend)
 
-	// load current storage state trie into memory
+	// load current storage state trie
 	_, err = s.Storage.LoadFromDB(stateRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load storage trie from database: %w", err)
 	}
 
+	err = s.setupPruner()
+	if err != nil {
+		return fmt.Errorf("failed to setup state pruner: %w", err)
+	}
+
+	num, _ := s.Block.BestBlockNumber()
+
 	// create transaction queue
 	s.Transaction = NewTransactionState(s.Telemetry)
+	s.Transaction.SetBlockNumber(num)
 
 	// create epoch and slot state
 	s.Slot = NewSlotState(s.db)
 
-	s.Epoch, err = NewEpochState(s.db, s.Block, s.genesisBABEConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create epoch state: %w", err)
+	if s.genesisBABEConfig != nil {
+		s.Epoch, err = NewEpochState(s.db, s.Block, s.genesisBABEConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create epoch state: %w", err)
+		}
 	}
 
 	s.Grandpa = NewGrandpaState(s.db, s.Block, s.Telemetry)
-	num, _ := s.Block.BestBlockNumber()
 	logger.Infof(
 		"created state service with head %s, highest number %d and genesis hash %s",
 		s.Block.BestBlockHash(), num, s.Block.genesisHash.String())
@@ -170,6 +234,41 @@ func (s *Service) Start() (err error) {
 	return nil
 }
 
+// setupPruner sets up the online pruner of the storage state according to the
+// pruner configuration. Once pruned, the database can no longer be used by an archive node.
+func (s *Service) setupPruner() error {
+	storedMode, err := s.db.Get(common.PruningKey)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("getting stored pruning mode: %w", err)
+	}
+
+	if pruner.Mode(storedMode) == pruner.Full && s.PrunerCfg.Mode != pruner.Full {
+		return fmt.Errorf("%w: database was pruned by a full node", errArchiveOnPrunedDatabase)
+	}
+
+	if s.PrunerCfg.Mode != pruner.Full {
+		return nil
+	}
+
+	err = s.db.Put(common.PruningKey, []byte(pruner.Full))
+	if err != nil {
+		return fmt.Errorf("storing pruning mode: %w", err)
+	}
+
+	fullNode, err := pruner.NewFullNode(s.db, storagePrefix, s.Block, s.PrunerCfg.RetainedBlocks)
+	if err != nil {
+		return fmt.Errorf("creating full node pruner: %w", err)
+	}
+
+	s.Storage.setPruner(fullNode)
+	s.onlinePruner = fullNode
+	fullNode.Start()
+
+	logger.Infof("online state pruning enabled, retaining the state of the last %d finalised blocks",
+		s.PrunerCfg.RetainedBlocks)
+	return nil
+}
+
 // Rewind rewinds the chain to the given block number.
 // If the given number of blocks is greater than the chain height, it will rewind to genesis.
 func (s *Service) Rewind(toBlock uint) error {
@@ -199,14 +298,16 @@ func (s *Service) Rewind(toBlock uint) error {
 		"rewinding state for new height %s and best block hash %s...",
 		header.Number, header.Hash())
 
-	epoch, err := s.Epoch.GetEpochForBlock(header)
-	if err != nil {
-		return err
-	}
+	if s.Epoch != nil {
+		epoch, err := s.Epoch.GetEpochForBlock(header)
+		if err != nil {
+			return err
+		}
 
-	err = s.Epoch.StoreCurrentEpoch(epoch)
-	if err != nil {
-		return err
+		err = s.Epoch.StoreCurrentEpoch(epoch)
+		if err != nil {
+			return err
+		}
 	}
 
 	s.Block.lastFinalised = header.Hash()
@@ -250,6 +351,10 @@ func (s *Service) Rewind(toBlock uint) error {
 func (s *Service) Stop() error {
 	close(s.closeCh)
 
+	if s.onlinePruner != nil {
+		s.onlinePruner.Stop()
+	}
+
 	hash, err := s.Block.GetHighestFinalisedHash()
 	if err != nil {
 		return err
@@ -268,6 +373,12 @@ func (s *Service) Stop() error {
 // to it. Additionally, it uses the first slot to correctly set the epoch number of the block.
 func (s *Service) Import(header *types.Header, t trie.Trie,
 	stateTrieVersion trie.TrieLayout, firstSlot uint64) error {
+	// check the state root before writing anything to the database
+	root := stateTrieVersion.MustHash(t)
+	if root != header.StateRoot {
+		return fmt.Errorf("trie state root does not equal header state root")
+	}
+
 	var err error
 	// initialise database using data directory
 	if !s.isMemDB {
@@ -310,11 +421,6 @@ func (s *Service) Import(header *types.Header, t trie.Trie,
 		return err
 	}
 
-	root := stateTrieVersion.MustHash(t)
-	if root != header.StateRoot {
-		return fmt.Errorf("trie state root does not equal header state root")
-	}
-
 	logger.Info("importing storage trie from base path " +
 		s.dbPath + " with root " + root.String() + "...")
 
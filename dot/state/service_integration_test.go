@@ -22,6 +22,7 @@
 	"github.com/ChainSafe/gossamer/tests/utils/config"
 	"go.uber.org/mock/gomock"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -75,6 +76,49 @@ func TestService_Start(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_StartWithTrieDBBackend(t *testing.T) {
+	state := newTestMemDBService(t)
+	state.storageBackend = TrieDBBackend
+
+	genData, genTrie, genesisHeader := newWestendDevGenesisWithTrieAndHeader(t)
+	err := state.Initialise(&genData, &genesisHeader, genTrie)
+	require.NoError(t, err)
+
+	err = state.Start()
+	require.NoError(t, err)
+	require.IsType(t, &TrieDBStorageState{}, state.Storage)
+
+	code, err := state.Storage.LoadCode(&genesisHeader.StateRoot)
+	require.NoError(t, err)
+	require.Equal(t, genTrie.Get(common.CodeKey), code)
+}
+
+func TestService_SlotDuration(t *testing.T) {
+	state := newTestMemDBService(t)
+
+	genData, genTrie, genesisHeader := newWestendDevGenesisWithTrieAndHeader(t)
+	err := state.Initialise(&genData, &genesisHeader, genTrie)
+	require.NoError(t, err)
+
+	// the babe configuration is read from the genesis runtime
+	slotDuration, err := state.SlotDuration()
+	require.NoError(t, err)
+	require.Equal(t, 6*time.Second, slotDuration)
+
+	// aura chains do not have an epoch state
+	state.Epoch = nil
+
+	_, err = state.SlotDuration()
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	err = state.Base.storeAuraSlotDuration(12000)
+	require.NoError(t, err)
+
+	slotDuration, err = state.SlotDuration()
+	require.NoError(t, err)
+	require.Equal(t, 12*time.Second, slotDuration)
+}
+
 func TestService_Initialise(t *testing.T) {
 	state := newTestService(t)
 
@@ -208,7 +252,7 @@ func TestService_StorageTriePruning(t *testing.T) {
 	for i := uint(1); i < totalBlock; i++ {
 		block, trieState := generateBlockWithRandomTrie(t, serv, &parentHash, i)
 
-		err = serv.Storage.blockState.AddBlock(block)
+		err = serv.Block.AddBlock(block)
 		require.NoError(t, err)
 
 		err = serv.Storage.StoreTrie(trieState, &block.Header)
@@ -266,7 +310,7 @@ type prunedBlock struct {
 		require.NoError(t, err)
 		block.Header.Digest = digest
 
-		err = serv.Storage.blockState.AddBlock(block)
+		err = serv.Block.AddBlock(block)
 		require.NoError(t, err)
 
 		err = serv.Storage.StoreTrie(trieState, nil)
@@ -284,7 +328,7 @@ type prunedBlock struct {
 	for i := uint(0); i < 3; i++ {
 		block, trieState := generateBlockWithRandomTrie(t, serv, &parentHash, i+1)
 
-		err = serv.Storage.blockState.AddBlock(block)
+		err = serv.Block.AddBlock(block)
 		require.NoError(t, err)
 
 		err = serv.Storage.StoreTrie(trieState, nil)
@@ -307,7 +351,7 @@ type prunedBlock struct {
 	time.Sleep(1 * time.Second)
 
 	for _, v := range prunedArr {
-		tr := serv.Storage.blockState.tries.get(v.hash)
+		tr := serv.Block.tries.get(v.hash)
 		require.Nil(t, tr)
 	}
 }
@@ -371,6 +415,157 @@ func TestService_Rewind(t *testing.T) {
 	require.Equal(t, database.ErrNotFound, err)
 }
 
+func newTestRevertService(t *testing.T) *Service {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	babeConfig := *config.BABEConfigurationTestDefault
+	babeConfig.EpochLength = 2
+
+	serv := NewService(Config{
+		Path:              t.TempDir(),
+		LogLevel:          log.Info,
+		Telemetry:         telemetryMock,
+		GenesisBABEConfig: &babeConfig,
+	})
+	serv.UseMemDB()
+
+	genData, genTrie, genesisHeader := newWestendDevGenesisWithTrieAndHeader(t)
+	err := serv.Initialise(&genData, &genesisHeader, genTrie)
+	require.NoError(t, err)
+
+	err = serv.Start()
+	require.NoError(t, err)
+	return serv
+}
+
+func TestService_Revert_FinalisedBlocks(t *testing.T) {
+	serv := newTestRevertService(t)
+
+	// blocks #1 to #12 are finalised, #13 and #14 are not
+	chain, _ := AddBlocksToState(t, serv.Block, 12, false)
+	err := serv.Block.SetFinalisedHash(chain[5].Hash(), 1, 0)
+	require.NoError(t, err)
+
+	err = serv.Grandpa.SetNextChange([]types.GrandpaVoter{}, 8)
+	require.NoError(t, err)
+	_, err = serv.Grandpa.IncrementSetID()
+	require.NoError(t, err)
+
+	err = serv.Block.SetFinalisedHash(chain[11].Hash(), 2, 1)
+	require.NoError(t, err)
+
+	unfinalised, _ := AddBlocksToState(t, serv.Block, 2, false)
+
+	// block #14 is in epoch 6
+	for epoch := uint64(1); epoch <= 7; epoch++ {
+		err = serv.Epoch.SetEpochDataRaw(epoch, &types.EpochDataRaw{})
+		require.NoError(t, err)
+	}
+
+	// finalised blocks are only reverted when explicitly allowed
+	reverted, err := serv.Revert(8, false)
+	assert.ErrorIs(t, err, ErrRevertFinalised)
+	assert.EqualError(t, err, "cannot revert finalised blocks: "+
+		"reverting 8 blocks reaches block #6 below the highest finalised block #12")
+	assert.Zero(t, reverted)
+
+	reverted, err = serv.Revert(8, true)
+	require.NoError(t, err)
+	assert.Equal(t, uint(8), reverted)
+
+	bestHeader, err := serv.Block.BestBlockHeader()
+	require.NoError(t, err)
+	assert.Equal(t, chain[5], bestHeader)
+
+	finalisedHeader, err := serv.Block.GetHighestFinalisedHeader()
+	require.NoError(t, err)
+	assert.Equal(t, chain[5], finalisedHeader)
+
+	round, setID, err := serv.Block.GetHighestRoundAndSetID()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), round)
+	assert.Equal(t, uint64(0), setID)
+
+	has, err := serv.Block.HasFinalisedBlock(2, 1)
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	for _, header := range append(chain[6:], unfinalised...) {
+		has, err := serv.Block.HasHeader(header.Hash())
+		require.NoError(t, err)
+		assert.False(t, has)
+	}
+
+	has, err = serv.Block.db.Has(headerHashKey(7))
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	setID, err = serv.Grandpa.GetCurrentSetID()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), setID)
+
+	_, err = serv.Grandpa.GetSetIDChange(1)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	// block #6 is in epoch 2
+	currentEpoch, err := serv.Epoch.GetCurrentEpoch()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), currentEpoch)
+
+	_, err = serv.Epoch.db.Get(epochDataKey(3))
+	assert.NoError(t, err)
+
+	for epoch := uint64(4); epoch <= 7; epoch++ {
+		_, err = serv.Epoch.db.Get(epochDataKey(epoch))
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	}
+}
+
+func TestService_Revert_UnfinalisedBlocks(t *testing.T) {
+	serv := newTestRevertService(t)
+
+	chain, _ := AddBlocksToState(t, serv.Block, 6, false)
+
+	serv.Epoch.storeBABENextEpochData(1, chain[1].Hash(), types.NextEpochData{})
+	serv.Epoch.storeBABENextEpochData(2, chain[4].Hash(), types.NextEpochData{})
+
+	err := serv.Grandpa.scheduledChangeRoots.importChange(&pendingChange{announcingHeader: chain[1]},
+		serv.Block.IsDescendantOf)
+	require.NoError(t, err)
+	err = serv.Grandpa.scheduledChangeRoots.importChange(&pendingChange{announcingHeader: chain[4]},
+		serv.Block.IsDescendantOf)
+	require.NoError(t, err)
+
+	reverted, err := serv.Revert(3, false)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), reverted)
+
+	bestHeader, err := serv.Block.BestBlockHeader()
+	require.NoError(t, err)
+	assert.Equal(t, chain[2], bestHeader)
+	assert.Equal(t, []common.Hash{chain[2].Hash()}, serv.Block.Leaves())
+
+	for _, header := range chain[3:] {
+		has, err := serv.Block.HasHeader(header.Hash())
+		require.NoError(t, err)
+		assert.False(t, has)
+	}
+
+	expectedNextEpochData := nextEpochMap[types.NextEpochData]{
+		1: {chain[1].Hash(): types.NextEpochData{}},
+	}
+	assert.Equal(t, expectedNextEpochData, serv.Epoch.nextEpochData)
+
+	require.Len(t, *serv.Grandpa.scheduledChangeRoots, 1)
+	root := (*serv.Grandpa.scheduledChangeRoots)[0]
+	assert.Equal(t, chain[1], root.change.announcingHeader)
+	assert.Empty(t, root.nodes)
+}
+
 func TestService_Import(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	telemetryMock := NewMockTelemetry(ctrl)
@@ -7,8 +7,10 @@
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 )
 
 // KeyValue struct to hold key value pairs
@@ -44,8 +46,19 @@ type Observer interface {
 	GetFilter() map[string][]byte
 }
 
+// storageObservers holds the observers notified of the storage changes of a
+// storage state.
+type storageObservers struct {
+	blockState *BlockState
+	// trieState returns the trie state for the given state root
+	trieState func(root *common.Hash) (*storage.TrieState, error)
+
+	observerListMutex sync.RWMutex
+	observerList      []Observer
+}
+
 // RegisterStorageObserver to add abserver to notification list
-func (s *InmemoryStorageState) RegisterStorageObserver(o Observer) {
+func (s *storageObservers) RegisterStorageObserver(o Observer) {
 	s.observerListMutex.Lock()
 	defer s.observerListMutex.Unlock()
 	s.observerList = append(s.observerList, o)
@@ -65,13 +78,13 @@ func (s *InmemoryStorageState) RegisterStorageObserver(o Observer) {
 }
 
 // UnregisterStorageObserver removes observer from notification list
-func (s *InmemoryStorageState) UnregisterStorageObserver(o Observer) {
+func (s *storageObservers) UnregisterStorageObserver(o Observer) {
 	s.observerListMutex.Lock()
 	defer s.observerListMutex.Unlock()
 	s.observerList = s.removeFromSlice(s.observerList, o)
 }
 
-func (s *InmemoryStorageState) notifyAll(root common.Hash) {
+func (s *storageObservers) notifyAll(root common.Hash) {
 	s.observerListMutex.RLock()
 	defer s.observerListMutex.RUnlock()
 	for _, observer := range s.observerList {
@@ -82,8 +95,8 @@ func (s *InmemoryStorageState) notifyAll(root common.Hash) {
 	}
 }
 
-func (s *InmemoryStorageState) notifyObserver(root common.Hash, o Observer) error {
-	t, err := s.TrieState(&root)
+func (s *storageObservers) notifyObserver(root common.Hash, o Observer) error {
+	t, err := s.trieState(&root)
 	if err != nil {
 		return err
 	}
@@ -137,7 +150,7 @@ func (s *InmemoryStorageState) notifyObserver(root common.Hash, o Observer) erro
 	return nil
 }
 
-func (s *InmemoryStorageState) removeFromSlice(observerList []Observer, observerToRemove Observer) []Observer {
+func (s *storageObservers) removeFromSlice(observerList []Observer, observerToRemove Observer) []Observer {
 	observerListLength := len(observerList)
 	for i, observer := range observerList {
 		if observerToRemove.GetID() == observer.GetID() {
@@ -0,0 +1,100 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb/proof"
+)
+
+// GenerateReadProof returns the storage proof of the values of the given keys
+// in the state trie with the given root.
+func (s *InmemoryStorageState) GenerateReadProof(stateRoot common.Hash, keys [][]byte) ([][]byte, error) {
+	return generateReadProof(s.db, stateRoot, keys)
+}
+
+// GenerateChildReadProof returns the storage proof of the values of the given
+// keys in the child trie stored at :child_storage:default:[keyToChild] of the
+// state trie with the given root.
+func (s *InmemoryStorageState) GenerateChildReadProof(stateRoot common.Hash, keyToChild []byte,
+	keys [][]byte) ([][]byte, error) {
+	return generateChildReadProof(s.db, stateRoot, keyToChild, keys)
+}
+
+// GenerateExecutionProof runs execute on the trie state with the given root and
+// returns the storage proof of the storage it read.
+func (s *InmemoryStorageState) GenerateExecutionProof(stateRoot common.Hash,
+	execute func(*storage.TrieState) error) ([][]byte, error) {
+	return generateExecutionProof(s.db, stateRoot, execute)
+}
+
+// GenerateReadProof returns the storage proof of the values of the given keys
+// in the state trie with the given root.
+func (s *TrieDBStorageState) GenerateReadProof(stateRoot common.Hash, keys [][]byte) ([][]byte, error) {
+	return generateReadProof(s.db, stateRoot, keys)
+}
+
+// GenerateChildReadProof returns the storage proof of the values of the given
+// keys in the child trie stored at :child_storage:default:[keyToChild] of the
+// state trie with the given root.
+func (s *TrieDBStorageState) GenerateChildReadProof(stateRoot common.Hash, keyToChild []byte,
+	keys [][]byte) ([][]byte, error) {
+	return generateChildReadProof(s.db, stateRoot, keyToChild, keys)
+}
+
+// GenerateExecutionProof runs execute on the trie state with the given root and
+// returns the storage proof of the storage it read.
+func (s *TrieDBStorageState) GenerateExecutionProof(stateRoot common.Hash,
+	execute func(*storage.TrieState) error) ([][]byte, error) {
+	return generateExecutionProof(s.db, stateRoot, execute)
+}
+
+func generateReadProof(db Getter, stateRoot common.Hash, keys [][]byte) ([][]byte, error) {
+	err := checkStateRoot(db, stateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return proof.NewReadProof(newBufferedDB(db), stateRoot, keys), nil
+}
+
+func generateChildReadProof(db Getter, stateRoot common.Hash, keyToChild []byte,
+	keys [][]byte) ([][]byte, error) {
+	err := checkStateRoot(db, stateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return proof.NewChildReadProof(newBufferedDB(db), stateRoot, keyToChild, keys)
+}
+
+// generateExecutionProof runs execute on a trie state recording the storage
+// accesses. The changes made to the trie state are discarded.
+func generateExecutionProof(db Getter, stateRoot common.Hash,
+	execute func(*storage.TrieState) error) ([][]byte, error) {
+	err := checkStateRoot(db, stateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := triedb.NewRecorder()
+	trie := triedb.NewTrieDB(stateRoot, newBufferedDB(db), triedb.WithRecorder(recorder))
+	err = execute(storage.NewTrieState(trie))
+	if err != nil {
+		return nil, err
+	}
+
+	return proof.NewStorageProof(recorder.Drain()), nil
+}
+
+// checkStateRoot checks the root node of the state trie is in the database.
+func checkStateRoot(db Getter, stateRoot common.Hash) error {
+	_, err := db.Get(stateRoot[:])
+	if err != nil {
+		return errTrieDoesNotExist(stateRoot)
+	}
+	return nil
+}
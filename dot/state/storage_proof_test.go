@@ -0,0 +1,61 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_GenerateProofs(t *testing.T) {
+	t.Parallel()
+
+	storageState := newTestTrieDBStorageState(t)
+	ts, err := storageState.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+	for _, key := range []string{"key1", "key2", "other"} {
+		require.NoError(t, ts.Put([]byte(key), []byte("value of "+key)))
+	}
+	require.NoError(t, ts.SetChildStorage([]byte("child"), []byte("key"), []byte("child value")))
+
+	root, err := ts.Trie().Hash()
+	require.NoError(t, err)
+	require.NoError(t, storageState.StoreTrie(ts, nil))
+
+	keys := [][]byte{[]byte("key1"), []byte("missing")}
+	readProof, err := storageState.GenerateReadProof(root, keys)
+	require.NoError(t, err)
+	require.NotEmpty(t, readProof)
+
+	// reading the same keys while executing gives the same proof
+	executionProof, err := storageState.GenerateExecutionProof(root, func(ts *storage.TrieState) error {
+		for _, key := range keys {
+			ts.Get(key)
+		}
+		return ts.Put([]byte("key2"), []byte("discarded"))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, readProof, executionProof)
+
+	value, err := storageState.GetStorage(&root, []byte("key2"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value of key2"), value)
+
+	childProof, err := storageState.GenerateChildReadProof(root, []byte("child"), [][]byte{[]byte("key")})
+	require.NoError(t, err)
+	assert.NotEmpty(t, childProof)
+
+	errTest := errors.New("test error")
+	_, err = storageState.GenerateExecutionProof(root, func(*storage.TrieState) error { return errTest })
+	assert.ErrorIs(t, err, errTest)
+
+	_, err = storageState.GenerateReadProof(common.Hash{9}, keys)
+	assert.ErrorIs(t, err, ErrTrieDoesNotExist)
+}
@@ -16,31 +16,120 @@
 
 // TransactionState represents the queue of transactions
 type TransactionState struct {
+	// queue holds the ready transactions and pool the future transactions.
 	queue *transaction.PriorityQueue
 	pool  *transaction.Pool
+	// mutex makes the moves of transactions between the queue and pool atomic.
+	mutex sync.Mutex
 
 	// notifierChannels are used to notify transaction status. It maps a channel to
 	// hex string of the extrinsic it is supposed to notify about.
 	notifierChannels map[chan transaction.Status]string
 	notifierLock     sync.RWMutex
 
+	// banned maps the hashes of the removed extrinsics to the time their ban expires,
+	// until then they are not re-imported from the network.
+	banned      map[common.Hash]time.Time
+	banDuration time.Duration
+
 	telemetry Telemetry
 }
 
+// defaultBanDuration is the time during which a removed extrinsic is banned
+const defaultBanDuration = 30 * time.Minute
+
 // NewTransactionState returns a new TransactionState
 func NewTransactionState(telemetry Telemetry) *TransactionState {
 	return &TransactionState{
 		queue:            transaction.NewPriorityQueue(),
 		pool:             transaction.NewPool(),
 		notifierChannels: make(map[chan transaction.Status]string),
+		banned:           make(map[common.Hash]time.Time),
+		banDuration:      defaultBanDuration,
 		telemetry:        telemetry,
 	}
 }
 
-// Push pushes a transaction to the queue, ordered by priority
+// Push pushes a transaction to the queue, ordered by priority, if all the tags it requires
+// are provided by transactions of the queue. Otherwise it is added to the pool, until these
+// tags are provided. The transactions providing the same tags are replaced if their priority
+// is lower, otherwise transaction.ErrTooLowPriority is returned.
 func (s *TransactionState) Push(vt *transaction.ValidTransaction) (common.Hash, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.replace(vt)
+	if err != nil {
+		return vt.Extrinsic.Hash(), err
+	}
+
+	if !s.requirementsMet(vt) {
+		return s.addToPool(vt), nil
+	}
+
+	s.pool.Remove(vt.Extrinsic.Hash())
+	return s.push(vt)
+}
+
+// push pushes a transaction to the queue, and moves the transactions of the pool
+// requiring the tags it provides to the queue once all their requirements are met.
+func (s *TransactionState) push(vt *transaction.ValidTransaction) (common.Hash, error) {
+	hash, err := s.queue.Push(vt)
+	if err != nil {
+		return hash, err
+	}
 	s.notifyStatus(vt.Extrinsic, transaction.Ready)
-	return s.queue.Push(vt)
+
+	for _, future := range s.pool.Requiring(vt.Validity.Provides) {
+		if !s.requirementsMet(future) {
+			continue
+		}
+
+		s.pool.Remove(future.Extrinsic.Hash())
+		futureHash, err := s.push(future)
+		if err != nil {
+			logger.Debugf("failed to move transaction %s from pool to queue: %s", futureHash, err)
+		}
+	}
+
+	return hash, nil
+}
+
+// requirementsMet returns true if all the tags required by the transaction
+// are provided by transactions of the queue.
+func (s *TransactionState) requirementsMet(vt *transaction.ValidTransaction) bool {
+	for _, tag := range vt.Validity.Requires {
+		if !s.queue.IsProvided(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// replace removes the transactions of the queue and pool providing the same tags as
+// the given transaction, if their priority is lower.
+func (s *TransactionState) replace(vt *transaction.ValidTransaction) error {
+	hash := vt.Extrinsic.Hash()
+	providers := append(s.queue.Providers(vt.Validity.Provides), s.pool.Providers(vt.Validity.Provides)...)
+
+	usurped := make([]*transaction.ValidTransaction, 0, len(providers))
+	for _, provider := range providers {
+		if provider.Extrinsic.Hash() == hash {
+			continue
+		}
+
+		if provider.Validity.Priority >= vt.Validity.Priority {
+			return transaction.ErrTooLowPriority
+		}
+		usurped = append(usurped, provider)
+	}
+
+	for _, provider := range usurped {
+		s.pool.Remove(provider.Extrinsic.Hash())
+		s.queue.RemoveExtrinsic(provider.Extrinsic)
+		s.notifyStatus(provider.Extrinsic, transaction.Usurped)
+	}
+	return nil
 }
 
 // Pop removes and returns the head of the queue
@@ -77,17 +166,122 @@ func (s *TransactionState) Exists(ext types.Extrinsic) bool {
 
 // RemoveExtrinsic removes an extrinsic from the queue and pool
 func (s *TransactionState) RemoveExtrinsic(ext types.Extrinsic) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.pool.Remove(ext.Hash())
 	s.queue.RemoveExtrinsic(ext)
 }
 
 // RemoveExtrinsicFromPool removes an extrinsic from the pool
 func (s *TransactionState) RemoveExtrinsicFromPool(ext types.Extrinsic) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.pool.Remove(ext.Hash())
 }
 
-// AddToPool adds a transaction to the pool
+// RemoveAndBan removes the extrinsics with the given hashes from the queue and pool,
+// notifies them as dropped and bans them for the ban duration, even if they are not
+// pending. The transactions requiring the tags no longer provided once they are removed
+// are removed as well, without being banned. It returns the hashes of the extrinsics
+// which were removed.
+func (s *TransactionState) RemoveAndBan(hashes []common.Hash) (removed []common.Hash) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for hash, expiry := range s.banned {
+		if now.After(expiry) {
+			delete(s.banned, hash)
+		}
+	}
+
+	for _, hash := range hashes {
+		s.banned[hash] = now.Add(s.banDuration)
+	}
+
+	toRemove := append([]common.Hash{}, hashes...)
+	for len(toRemove) > 0 {
+		hash := toRemove[0]
+		toRemove = toRemove[1:]
+
+		vt := s.pool.Get(hash)
+		if vt == nil {
+			vt = s.queue.Get(hash)
+		}
+		if vt == nil {
+			continue
+		}
+
+		s.pool.Remove(hash)
+		s.queue.RemoveExtrinsic(vt.Extrinsic)
+		s.notifyStatus(vt.Extrinsic, transaction.Dropped)
+		removed = append(removed, hash)
+
+		// the dependent transactions could never become ready again
+		var released [][]byte
+		for _, tag := range vt.Validity.Provides {
+			if !s.queue.IsProvided(tag) {
+				released = append(released, tag)
+			}
+		}
+		for _, dependent := range append(s.queue.Requiring(released), s.pool.Requiring(released)...) {
+			toRemove = append(toRemove, dependent.Extrinsic.Hash())
+		}
+	}
+
+	return removed
+}
+
+// IsBanned returns true if the extrinsic with the given hash has been removed
+// with RemoveAndBan and its ban has not expired yet.
+func (s *TransactionState) IsBanned(hash common.Hash) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	expiry, ok := s.banned[hash]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(expiry) {
+		delete(s.banned, hash)
+		return false
+	}
+	return true
+}
+
+// SetBlockNumber sets the number of the latest imported block, from which the longevity of
+// the transactions added afterwards is counted, and removes the transactions of the queue
+// and pool whose longevity has expired at this block.
+func (s *TransactionState) SetBlockNumber(number uint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	expired := append(s.queue.RemoveExpired(number), s.pool.RemoveExpired(number)...)
+	for _, vt := range expired {
+		s.notifyStatus(vt.Extrinsic, transaction.Invalid)
+	}
+}
+
+// AddToPool adds a transaction to the pool. The transactions providing the same tags are
+// replaced if their priority is lower, otherwise the transaction is dropped.
 func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) common.Hash {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.replace(vt)
+	if err != nil {
+		logger.Debugf("dropping transaction %s: %s", vt.Extrinsic, err)
+		s.notifyStatus(vt.Extrinsic, transaction.Dropped)
+		return vt.Extrinsic.Hash()
+	}
+
+	return s.addToPool(vt)
+}
+
+func (s *TransactionState) addToPool(vt *transaction.ValidTransaction) common.Hash {
 	s.notifyStatus(vt.Extrinsic, transaction.Future)
 
 	hash := s.pool.Insert(vt)
@@ -99,6 +293,12 @@ func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) common.Ha
 	return hash
 }
 
+// NotifyBroadcast notifies the status notifier channels of the extrinsic that it has been
+// broadcast to the network.
+func (s *TransactionState) NotifyBroadcast(ext types.Extrinsic) {
+	s.notifyStatus(ext, transaction.Broadcast)
+}
+
 // GetStatusNotifierChannel creates and returns a status notifier channel.
 func (s *TransactionState) GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status {
 	s.notifierLock.Lock()
@@ -99,14 +99,16 @@ func TestTransactionState_NotifierChannels(t *testing.T) {
 	for i := 0; i < expectedFutureCount; i++ {
 		dummyTransactions[i] = &transaction.ValidTransaction{
 			Extrinsic: ext,
-			Validity:  transaction.NewValidity(0, [][]byte{{}}, [][]byte{{}}, 0, false),
+			Validity:  transaction.NewValidity(0, nil, [][]byte{{}}, 0, false),
 		}
 
 		ts.AddToPool(dummyTransactions[i])
 	}
 
 	for i := 0; i < expectedReadyCount; i++ {
-		ts.Push(dummyTransactions[i])
+		_, err := ts.Push(dummyTransactions[i])
+		require.NoError(t, err)
+		ts.Pop()
 	}
 
 	close(notifierChannel)
@@ -123,3 +125,203 @@ func TestTransactionState_NotifierChannels(t *testing.T) {
 	require.Equal(t, expectedFutureCount, futureCount)
 	require.Equal(t, expectedReadyCount, readyCount)
 }
+
+func TestTransactionState_NotifyBroadcast(t *testing.T) {
+	ts := NewTransactionState(nil)
+
+	ext := types.Extrinsic{1}
+	notifierChannel := ts.GetStatusNotifierChannel(ext)
+	defer ts.FreeStatusNotifierChannel(notifierChannel)
+	otherChannel := ts.GetStatusNotifierChannel(types.Extrinsic{2})
+	defer ts.FreeStatusNotifierChannel(otherChannel)
+
+	ts.NotifyBroadcast(ext)
+
+	require.Equal(t, transaction.Broadcast, <-notifierChannel)
+	require.Empty(t, otherChannel)
+}
+
+func TestTransactionState_Push_tags(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	ts := NewTransactionState(telemetryMock)
+
+	nonce0 := transaction.NewValidTransaction(types.Extrinsic("nonce0"),
+		transaction.NewValidity(1, nil, [][]byte{{0}}, 64, true))
+	nonce1 := transaction.NewValidTransaction(types.Extrinsic("nonce1"),
+		transaction.NewValidity(1, [][]byte{{0}}, [][]byte{{1}}, 64, true))
+	nonce2 := transaction.NewValidTransaction(types.Extrinsic("nonce2"),
+		transaction.NewValidity(1, [][]byte{{1}}, [][]byte{{2}}, 64, true))
+
+	// the requirements of nonce2 and nonce1 are not provided yet
+	_, err := ts.Push(nonce2)
+	require.NoError(t, err)
+	_, err = ts.Push(nonce1)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*transaction.ValidTransaction{nonce1, nonce2}, ts.PendingInPool())
+	require.Nil(t, ts.Peek())
+
+	// pushing nonce0 moves nonce1 and nonce2 to the queue
+	_, err = ts.Push(nonce0)
+	require.NoError(t, err)
+	require.Empty(t, ts.PendingInPool())
+
+	require.Equal(t, nonce0, ts.Pop())
+	require.Equal(t, nonce1, ts.Pop())
+	require.Equal(t, nonce2, ts.Pop())
+	require.Nil(t, ts.Pop())
+}
+
+func TestTransactionState_replace(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	ts := NewTransactionState(telemetryMock)
+
+	original := transaction.NewValidTransaction(types.Extrinsic("original"),
+		transaction.NewValidity(2, nil, [][]byte{{0}}, 64, true))
+	lower := transaction.NewValidTransaction(types.Extrinsic("lower"),
+		transaction.NewValidity(2, nil, [][]byte{{0}}, 64, true))
+	higher := transaction.NewValidTransaction(types.Extrinsic("higher"),
+		transaction.NewValidity(3, nil, [][]byte{{0}}, 64, true))
+
+	_, err := ts.Push(original)
+	require.NoError(t, err)
+
+	_, err = ts.Push(lower)
+	require.ErrorIs(t, err, transaction.ErrTooLowPriority)
+
+	ts.AddToPool(lower)
+	require.Empty(t, ts.PendingInPool())
+
+	notifierChannel := ts.GetStatusNotifierChannel(original.Extrinsic)
+	defer ts.FreeStatusNotifierChannel(notifierChannel)
+
+	_, err = ts.Push(higher)
+	require.NoError(t, err)
+	require.Equal(t, transaction.Usurped, <-notifierChannel)
+	require.False(t, ts.Exists(original.Extrinsic))
+	require.Equal(t, []*transaction.ValidTransaction{higher}, ts.Pending())
+}
+
+func TestTransactionState_SetBlockNumber(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	ts := NewTransactionState(telemetryMock)
+	ts.SetBlockNumber(100)
+
+	ready := transaction.NewValidTransaction(types.Extrinsic("ready"),
+		transaction.NewValidity(1, nil, [][]byte{{0}}, 4, true))
+	future := transaction.NewValidTransaction(types.Extrinsic("future"),
+		transaction.NewValidity(1, [][]byte{{1}}, [][]byte{{2}}, 8, true))
+
+	_, err := ts.Push(ready)
+	require.NoError(t, err)
+	ts.AddToPool(future)
+
+	ts.SetBlockNumber(104)
+	require.ElementsMatch(t, []*transaction.ValidTransaction{ready, future}, ts.Pending())
+
+	ts.SetBlockNumber(105)
+	require.Equal(t, []*transaction.ValidTransaction{future}, ts.Pending())
+
+	ts.SetBlockNumber(109)
+	require.Empty(t, ts.Pending())
+}
+
+func TestTransactionState_RemoveAndBan(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	ts := NewTransactionState(telemetryMock)
+
+	ready := transaction.NewValidTransaction(types.Extrinsic("ready"),
+		transaction.NewValidity(1, nil, [][]byte{{0}}, 64, true))
+	future := transaction.NewValidTransaction(types.Extrinsic("future"),
+		transaction.NewValidity(1, [][]byte{{1}}, [][]byte{{2}}, 64, true))
+	unknownHash := common.Hash{1}
+
+	_, err := ts.Push(ready)
+	require.NoError(t, err)
+	ts.AddToPool(future)
+
+	notifierChannel := ts.GetStatusNotifierChannel(ready.Extrinsic)
+	defer ts.FreeStatusNotifierChannel(notifierChannel)
+
+	removed := ts.RemoveAndBan([]common.Hash{ready.Extrinsic.Hash(), future.Extrinsic.Hash(), unknownHash})
+	require.Equal(t, []common.Hash{ready.Extrinsic.Hash(), future.Extrinsic.Hash()}, removed)
+	require.Empty(t, ts.Pending())
+	require.Equal(t, transaction.Dropped, <-notifierChannel)
+
+	// extrinsics which were not pending are banned as well
+	require.True(t, ts.IsBanned(ready.Extrinsic.Hash()))
+	require.True(t, ts.IsBanned(unknownHash))
+	require.False(t, ts.IsBanned(common.Hash{2}))
+
+	// the ban expires after the ban duration
+	ts.banDuration = 0
+	ts.RemoveAndBan([]common.Hash{unknownHash})
+	time.Sleep(time.Millisecond)
+	require.False(t, ts.IsBanned(unknownHash))
+}
+
+func TestTransactionState_RemoveAndBan_dependents(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	ts := NewTransactionState(telemetryMock)
+
+	provider := transaction.NewValidTransaction(types.Extrinsic("provider"),
+		transaction.NewValidity(1, nil, [][]byte{{0}}, 64, true))
+	dependent := transaction.NewValidTransaction(types.Extrinsic("dependent"),
+		transaction.NewValidity(1, [][]byte{{0}}, [][]byte{{1}}, 64, true))
+	transitiveDependent := transaction.NewValidTransaction(types.Extrinsic("transitive"),
+		transaction.NewValidity(1, [][]byte{{1}}, [][]byte{{2}}, 64, true))
+	futureDependent := transaction.NewValidTransaction(types.Extrinsic("future"),
+		transaction.NewValidity(1, [][]byte{{0}, {5}}, [][]byte{{3}}, 64, true))
+	unrelated := transaction.NewValidTransaction(types.Extrinsic("unrelated"),
+		transaction.NewValidity(1, nil, [][]byte{{9}}, 64, true))
+
+	for _, vt := range []*transaction.ValidTransaction{
+		provider, dependent, transitiveDependent, futureDependent, unrelated} {
+		_, err := ts.Push(vt)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []*transaction.ValidTransaction{futureDependent}, ts.PendingInPool())
+
+	notifierChannel := ts.GetStatusNotifierChannel(transitiveDependent.Extrinsic)
+	defer ts.FreeStatusNotifierChannel(notifierChannel)
+
+	// the transactions requiring the tags provided by the removed transaction are
+	// removed too instead of becoming ready
+	removed := ts.RemoveAndBan([]common.Hash{provider.Extrinsic.Hash()})
+	require.ElementsMatch(t, []common.Hash{
+		provider.Extrinsic.Hash(),
+		dependent.Extrinsic.Hash(),
+		transitiveDependent.Extrinsic.Hash(),
+		futureDependent.Extrinsic.Hash(),
+	}, removed)
+	require.Equal(t, []*transaction.ValidTransaction{unrelated}, ts.Pending())
+	require.Equal(t, transaction.Dropped, <-notifierChannel)
+
+	// only the given transactions are banned
+	require.True(t, ts.IsBanned(provider.Extrinsic.Hash()))
+	require.False(t, ts.IsBanned(dependent.Extrinsic.Hash()))
+}
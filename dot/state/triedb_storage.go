@@ -0,0 +1,401 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/state/pruner"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/cache"
+	cache_inmemory "github.com/ChainSafe/gossamer/pkg/trie/cache/inmemory"
+	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory/proof"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+)
+
+// TrieDBStorageState is the storage state backed by database tries which are
+// read lazily, node by node, instead of being fully loaded in memory.
+// Nodes and values read from the database are kept in a shared trie cache.
+type TrieDBStorageState struct {
+	blockState *BlockState
+
+	db    database.Table
+	cache cache.TrieCache
+	sync.RWMutex
+
+	// change notifiers
+	storageObservers
+	pruner pruner.Pruner
+}
+
+// NewTrieDBStorageState creates a new database trie backed storage state
+// using the given block state and database.
+func NewTrieDBStorageState(db database.Database, blockState *BlockState) *TrieDBStorageState {
+	s := &TrieDBStorageState{
+		blockState: blockState,
+		db:         database.NewTable(db, storagePrefix),
+		cache:      cache_inmemory.NewTrieInMemoryCache(),
+		pruner:     &pruner.ArchiveNode{},
+	}
+	s.storageObservers = storageObservers{
+		blockState:   blockState,
+		trieState:    s.TrieState,
+		observerList: []Observer{},
+	}
+	return s
+}
+
+func (s *TrieDBStorageState) setPruner(p pruner.Pruner) {
+	s.pruner = p
+}
+
+// newTrie returns the trie with the given root. Changes made to the returned
+// trie are only written to the database once stored with StoreTrie.
+func (s *TrieDBStorageState) newTrie(root common.Hash) *bufferedTrie {
+	buffer := newBufferedDB(s.db)
+	return &bufferedTrie{
+		TrieDB: triedb.NewTrieDB(root, buffer, triedb.WithCache(s.cache)),
+		db:     buffer,
+	}
+}
+
+// StoreTrie writes the changes of the given trie state to the database
+func (s *TrieDBStorageState) StoreTrie(ts *storage.TrieState, header *types.Header) error {
+	root, err := ts.Trie().Hash()
+	if err != nil {
+		return fmt.Errorf("hashing trie: %w", err)
+	}
+
+	writeNodes := func() error {
+		switch t := ts.Trie().(type) {
+		case *bufferedTrie:
+			return t.db.writeTo(s.db)
+		case *inmemory_trie.InMemoryTrie:
+			return t.WriteDirty(s.db)
+		default:
+			return fmt.Errorf("unsupported trie type %T", t)
+		}
+	}
+
+	if header != nil {
+		err = s.pruner.StoreJournalRecord(writeNodes, root, header.Hash(), int64(header.Number))
+	} else {
+		err = writeNodes()
+	}
+	if err != nil {
+		logger.Warnf("failed to write trie with root %s to database: %s", root, err)
+		return err
+	}
+
+	logger.Tracef("stored trie in storage state: %s", root)
+
+	go s.notifyAll(root)
+	return nil
+}
+
+// TrieState returns the TrieState for a given state root.
+// If no state root is provided, it returns the TrieState for the current chain head.
+func (s *TrieDBStorageState) TrieState(root *common.Hash) (*storage.TrieState, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil {
+		return nil, fmt.Errorf("while loading from database: %w", err)
+	}
+
+	logger.Tracef("returning trie with root %s to be modified", tr.MustHash())
+	return storage.NewTrieState(tr), nil
+}
+
+// LoadFromDB returns the trie with the given root, checking its root node is
+// present in the database.
+func (s *TrieDBStorageState) LoadFromDB(root common.Hash) (trie.Trie, error) {
+	if root != trie.EmptyHash {
+		_, err := s.db.Get(root[:])
+		if err != nil {
+			return nil, fmt.Errorf("getting root node %s: %w", root, err)
+		}
+	}
+
+	return s.newTrie(root), nil
+}
+
+func (s *TrieDBStorageState) loadTrie(root *common.Hash) (*bufferedTrie, error) {
+	if root == nil {
+		sr, err := s.blockState.BestBlockStateRoot()
+		if err != nil {
+			return nil, err
+		}
+		root = &sr
+	}
+
+	t, err := s.LoadFromDB(*root)
+	if err != nil {
+		return nil, fmt.Errorf("trie does not exist at root %s: %w", *root, err)
+	}
+
+	return t.(*bufferedTrie), nil
+}
+
+// ExistsStorage check if the key exists in the storage trie with the given storage hash
+// If no hash is provided, the current chain head is used
+func (s *TrieDBStorageState) ExistsStorage(root *common.Hash, key []byte) (bool, error) {
+	val, err := s.GetStorage(root, key)
+	return val != nil, err
+}
+
+// GetStorage gets the object from the trie using the given key and storage hash
+// If no hash is provided, the current chain head is used
+func (s *TrieDBStorageState) GetStorage(root *common.Hash, key []byte) ([]byte, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.Get(key), nil
+}
+
+// GetStorageByBlockHash returns the value at the given key at the given block hash
+func (s *TrieDBStorageState) GetStorageByBlockHash(bhash *common.Hash, key []byte) ([]byte, error) {
+	root, err := s.GetStateRootFromBlock(bhash)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStorage(root, key)
+}
+
+// GetStateRootFromBlock returns the state root hash of a given block hash
+func (s *TrieDBStorageState) GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error) {
+	if bhash == nil {
+		b := s.blockState.BestBlockHash()
+		bhash = &b
+	}
+
+	header, err := s.blockState.GetHeader(*bhash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &header.StateRoot, nil
+}
+
+// StorageRoot returns the root hash of the current storage trie
+func (s *TrieDBStorageState) StorageRoot() (common.Hash, error) {
+	return s.blockState.BestBlockStateRoot()
+}
+
+// Entries returns Entries from the trie with the given state root
+func (s *TrieDBStorageState) Entries(root *common.Hash) (map[string][]byte, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.Entries(), nil
+}
+
+// GetKeysWithPrefix returns all that match the given prefix for the given hash
+// (or best block state root if hash is nil) in lexicographic order
+func (s *TrieDBStorageState) GetKeysWithPrefix(root *common.Hash, prefix []byte) ([][]byte, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.GetKeysWithPrefix(prefix), nil
+}
+
+// GetClosestDescendantMerkleValue returns the Merkle value of the closest node whose key starts
+// with the given key for the given hash (or best block state root if hash is nil)
+func (s *TrieDBStorageState) GetClosestDescendantMerkleValue(root *common.Hash, key []byte) ([]byte, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.ClosestDescendantMerkleValue(key)
+}
+
+// GetStorageChild returns a child trie, if it exists
+func (s *TrieDBStorageState) GetStorageChild(root *common.Hash, keyToChild []byte) (trie.Trie, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.GetChild(keyToChild)
+}
+
+// GetStorageFromChild get a value from a child trie
+func (s *TrieDBStorageState) GetStorageFromChild(root *common.Hash, keyToChild, key []byte) ([]byte, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.GetFromChild(keyToChild, key)
+}
+
+// LoadCode returns the runtime code (located at :code)
+func (s *TrieDBStorageState) LoadCode(hash *common.Hash) ([]byte, error) {
+	return s.GetStorage(hash, codeKey)
+}
+
+// LoadCodeHash returns the hash of the runtime code (located at :code)
+func (s *TrieDBStorageState) LoadCodeHash(hash *common.Hash) (common.Hash, error) {
+	code, err := s.LoadCode(hash)
+	if err != nil {
+		return common.NewHash([]byte{}), err
+	}
+
+	return common.Blake2bHash(code)
+}
+
+// GenerateTrieProof returns the proofs related to the keys on the state root trie
+func (s *TrieDBStorageState) GenerateTrieProof(stateRoot common.Hash, keys [][]byte) (
+	encodedProofNodes [][]byte, err error) {
+	return proof.Generate(stateRoot[:], keys, s.db)
+}
+
+// bufferedTrie is a database trie writing its changes to an in memory buffer
+// on top of the storage database.
+type bufferedTrie struct {
+	*triedb.TrieDB
+	db *bufferedDB
+}
+
+// bufferedDB buffers the writes made on top of a database. Deletions only
+// apply to buffered entries, since removing entries of the underlying
+// database is the responsibility of the state pruner.
+type bufferedDB struct {
+	base    Getter
+	entries map[string]bufferedEntry
+	mutex   sync.RWMutex
+}
+
+// bufferedEntry is a buffered value together with the number of times it
+// was written, since the same node may be referenced more than once.
+type bufferedEntry struct {
+	value      []byte
+	references uint
+}
+
+func newBufferedDB(base Getter) *bufferedDB {
+	return &bufferedDB{
+		base:    base,
+		entries: make(map[string]bufferedEntry),
+	}
+}
+
+// Get returns the buffered value at the given key, falling back on the
+// underlying database.
+func (b *bufferedDB) Get(key []byte) (value []byte, err error) {
+	b.mutex.RLock()
+	entry, ok := b.entries[string(key)]
+	b.mutex.RUnlock()
+	if ok {
+		return entry.value, nil
+	}
+
+	return b.base.Get(key)
+}
+
+// Put buffers the value at the given key.
+func (b *bufferedDB) Put(key, value []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	entry := b.entries[string(key)]
+	entry.value = value
+	entry.references++
+	b.entries[string(key)] = entry
+	return nil
+}
+
+// Del removes one reference of the buffered value at the given key.
+func (b *bufferedDB) Del(key []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	entry, ok := b.entries[string(key)]
+	if !ok {
+		return nil
+	}
+
+	entry.references--
+	if entry.references == 0 {
+		delete(b.entries, string(key))
+		return nil
+	}
+
+	b.entries[string(key)] = entry
+	return nil
+}
+
+// Flush is a no-op since the buffered entries are written with writeTo.
+func (*bufferedDB) Flush() error {
+	return nil
+}
+
+// NewBatch returns a batch writing to the buffer.
+func (b *bufferedDB) NewBatch() database.Batch {
+	return &bufferedBatch{db: b}
+}
+
+// writeTo writes all the buffered entries to the given database.
+func (b *bufferedDB) writeTo(db database.Table) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	batch := db.NewBatch()
+	for key, entry := range b.entries {
+		err := batch.Put([]byte(key), entry.value)
+		if err != nil {
+			return fmt.Errorf("putting buffered entry in batch: %w", err)
+		}
+	}
+
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing batch: %w", err)
+	}
+
+	return batch.Close()
+}
+
+// bufferedBatch is a batch writing directly to its buffered database.
+type bufferedBatch struct {
+	db        *bufferedDB
+	valueSize int
+}
+
+func (b *bufferedBatch) Put(key, value []byte) error {
+	b.valueSize += len(value)
+	return b.db.Put(key, value)
+}
+
+func (b *bufferedBatch) Del(key []byte) error {
+	return b.db.Del(key)
+}
+
+func (*bufferedBatch) Flush() error {
+	return nil
+}
+
+func (b *bufferedBatch) ValueSize() int {
+	return b.valueSize
+}
+
+func (b *bufferedBatch) Reset() {
+	b.valueSize = 0
+}
+
+func (*bufferedBatch) Close() error {
+	return nil
+}
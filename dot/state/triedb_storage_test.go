@@ -0,0 +1,150 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTrieDBStorageState(t *testing.T) *TrieDBStorageState {
+	db := NewInMemoryDB(t)
+	bs := newTestBlockState(t, newTriesEmpty())
+	return NewTrieDBStorageState(db, bs)
+}
+
+func TestTrieDBStorage_StoreAndRead(t *testing.T) {
+	t.Parallel()
+
+	storage := newTestTrieDBStorageState(t)
+	ts, err := storage.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+	ts.SetVersion(trie.V1)
+
+	entries := map[string][]byte{
+		"key1":    []byte("value1"),
+		"key2":    []byte("value2"),
+		"xyzKey1": []byte("xyzValue1"),
+		"long":    []byte("newvaluewithmorethan32byteslength"),
+	}
+	for key, value := range entries {
+		require.NoError(t, ts.Put([]byte(key), value))
+	}
+	require.NoError(t, ts.SetChildStorage([]byte("child"), []byte("childkey"), []byte("childvalue")))
+
+	root, err := ts.Trie().Hash()
+	require.NoError(t, err)
+	require.NoError(t, storage.StoreTrie(ts, nil))
+
+	for key, value := range entries {
+		stored, err := storage.GetStorage(&root, []byte(key))
+		require.NoError(t, err)
+		assert.Equal(t, value, stored)
+	}
+
+	keys, err := storage.GetKeysWithPrefix(&root, []byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("key1"), []byte("key2")}, keys)
+
+	childValue, err := storage.GetStorageFromChild(&root, []byte("child"), []byte("childkey"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("childvalue"), childValue)
+
+	// the same state stored with the in memory storage state has the same root
+	inmemoryStorage := newTestStorageState(t)
+	inmemoryTs, err := inmemoryStorage.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+	inmemoryTs.SetVersion(trie.V1)
+	for key, value := range entries {
+		require.NoError(t, inmemoryTs.Put([]byte(key), value))
+	}
+	require.NoError(t, inmemoryTs.SetChildStorage([]byte("child"), []byte("childkey"), []byte("childvalue")))
+
+	inmemoryRoot, err := inmemoryTs.Trie().Hash()
+	require.NoError(t, err)
+	assert.Equal(t, inmemoryRoot, root)
+}
+
+func TestTrieDBStorage_UnstoredChangesNotWritten(t *testing.T) {
+	t.Parallel()
+
+	storage := newTestTrieDBStorageState(t)
+	ts, err := storage.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+	require.NoError(t, ts.Put([]byte("key"), []byte("value")))
+
+	root, err := ts.Trie().Hash()
+	require.NoError(t, err)
+
+	_, err = storage.LoadFromDB(root)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	require.NoError(t, storage.StoreTrie(ts, nil))
+
+	_, err = storage.LoadFromDB(root)
+	require.NoError(t, err)
+}
+
+func TestTrieDBStorage_ReadsInMemoryStorage(t *testing.T) {
+	t.Parallel()
+
+	db := NewInMemoryDB(t)
+	tries := newTriesEmpty()
+	bs := newTestBlockState(t, tries)
+
+	inmemoryStorage, err := NewStorageState(db, bs, tries)
+	require.NoError(t, err)
+	ts, err := inmemoryStorage.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+	ts.SetVersion(trie.V1)
+	require.NoError(t, ts.Put([]byte("key"), []byte("value")))
+	require.NoError(t, ts.Put([]byte("long"), []byte("newvaluewithmorethan32byteslength")))
+
+	root, err := ts.Trie().Hash()
+	require.NoError(t, err)
+	require.NoError(t, inmemoryStorage.StoreTrie(ts, nil))
+
+	storage := NewTrieDBStorageState(db, bs)
+	entries, err := storage.Entries(&root)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"key":  []byte("value"),
+		"long": []byte("newvaluewithmorethan32byteslength"),
+	}, entries)
+}
+
+func Test_bufferedDB(t *testing.T) {
+	t.Parallel()
+
+	base := database.NewTable(NewInMemoryDB(t), storagePrefix)
+	require.NoError(t, base.Put([]byte("base"), []byte("basevalue")))
+
+	buffer := newBufferedDB(base)
+	require.NoError(t, buffer.Put([]byte("node"), []byte("nodevalue")))
+	require.NoError(t, buffer.Put([]byte("node"), []byte("nodevalue")))
+
+	// base entries are left to the state pruner
+	require.NoError(t, buffer.Del([]byte("base")))
+	value, err := buffer.Get([]byte("base"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("basevalue"), value)
+
+	// the node is still referenced once
+	require.NoError(t, buffer.Del([]byte("node")))
+	value, err = buffer.Get([]byte("node"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("nodevalue"), value)
+
+	require.NoError(t, buffer.writeTo(base))
+	value, err = base.Get([]byte("node"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("nodevalue"), value)
+
+	require.NoError(t, buffer.Del([]byte("node")))
+	assert.Empty(t, buffer.entries)
+}
@@ -0,0 +1,326 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	cfg "github.com/ChainSafe/gossamer/config"
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/codec"
+	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	stateSnapshotMagic                 = "gossamer-state-snapshot"
+	stateSnapshotFormatVersion   uint8 = 1
+	stateSnapshotMaxChunkEntries       = 4096
+)
+
+var (
+	errInvalidStateSnapshot = errors.New("invalid state snapshot")
+	errStateRootMismatch    = errors.New("rebuilt state root does not match header state root")
+)
+
+// stateSnapshotHeader is the first item of a state snapshot, followed by chunks of trie entries
+type stateSnapshotHeader struct {
+	Magic         []byte
+	FormatVersion uint8
+	StateVersion  uint8
+	Header        types.Header
+}
+
+// stateSnapshotChunk holds consecutive entries of the main trie or of a child trie.
+// The last chunk of a snapshot has no entries.
+type stateSnapshotChunk struct {
+	// KeyToChild is the key of the child trie of the entries, or empty for the main trie.
+	KeyToChild []byte
+	Entries    []stateSnapshotEntry
+}
+
+type stateSnapshotEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// ExportState writes a zstd compressed binary snapshot of the state at the given block,
+// or at the best block if the hash is nil, to the given writer. The snapshot holds the
+// block header, followed by the entries of the main trie and of its child tries.
+func ExportState(config *cfg.Config, blockHash *common.Hash, stateVersion trie.TrieLayout,
+	w io.Writer) (err error) {
+	stateSrvc, err := nodeBuilder{}.createStateService(config)
+	if err != nil {
+		return fmt.Errorf("creating state service: %w", err)
+	}
+
+	err = stateSrvc.Start()
+	if err != nil {
+		return fmt.Errorf("starting state service: %w", err)
+	}
+	defer func() {
+		stopErr := stateSrvc.Stop()
+		if err == nil && stopErr != nil {
+			err = fmt.Errorf("stopping state service: %w", stopErr)
+		}
+	}()
+
+	var header *types.Header
+	if blockHash == nil {
+		header, err = stateSrvc.Block.BestBlockHeader()
+	} else {
+		header, err = stateSrvc.Block.GetHeader(*blockHash)
+	}
+	if err != nil {
+		return fmt.Errorf("getting block header: %w", err)
+	}
+
+	trieState, err := stateSrvc.Storage.TrieState(&header.StateRoot)
+	if err != nil {
+		return fmt.Errorf("getting trie state: %w", err)
+	}
+
+	logger.Infof("exporting state of block #%d (%s) with root %s...",
+		header.Number, header.Hash(), header.StateRoot)
+
+	err = writeStateSnapshot(w, header, stateVersion, trieState.Trie())
+	if err != nil {
+		return fmt.Errorf("writing state snapshot: %w", err)
+	}
+
+	logger.Info("finished state export")
+	return nil
+}
+
+// ImportStateSnapshot imports the state snapshot in the given file to the database with
+// the given path, once the state root rebuilt from its entries matches the one of its header.
+func ImportStateSnapshot(basepath, snapshotFP string, genesisBABEConfig *types.BabeConfiguration,
+	firstSlot uint64) error {
+	file, err := os.Open(filepath.Clean(snapshotFP))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header, tr, stateVersion, err := readStateSnapshot(file)
+	if err != nil {
+		return fmt.Errorf("reading state snapshot: %w", err)
+	}
+
+	logger.Infof("ImportStateSnapshot with header: %v", header)
+
+	config := state.Config{
+		Path:              basepath,
+		LogLevel:          log.Info,
+		GenesisBABEConfig: genesisBABEConfig,
+	}
+	srv := state.NewService(config)
+	return srv.Import(header, tr, stateVersion, firstSlot)
+}
+
+// fullReader fills the whole buffer on each read, since the SCALE decoder
+// expects it and the decompressor can return short reads.
+type fullReader struct {
+	io.Reader
+}
+
+func (f fullReader) Read(b []byte) (n int, err error) {
+	return io.ReadFull(f.Reader, b)
+}
+
+// stateSnapshotWriter groups the trie entries in chunks and writes them to the encoder
+type stateSnapshotWriter struct {
+	encoder *scale.Encoder
+	chunk   stateSnapshotChunk
+}
+
+func (s *stateSnapshotWriter) writeTrie(keyToChild []byte, t trie.Trie) error {
+	s.chunk = stateSnapshotChunk{KeyToChild: keyToChild}
+
+	iterator, err := t.Iter()
+	if err != nil {
+		return fmt.Errorf("iterating over trie: %w", err)
+	}
+
+	for entry := iterator.NextEntry(); entry != nil; entry = iterator.NextEntry() {
+		s.chunk.Entries = append(s.chunk.Entries, stateSnapshotEntry{
+			Key:   codec.NibblesToKeyLE(entry.Key),
+			Value: entry.Value,
+		})
+
+		if len(s.chunk.Entries) == stateSnapshotMaxChunkEntries {
+			err := s.flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.flush()
+}
+
+func (s *stateSnapshotWriter) flush() error {
+	if len(s.chunk.Entries) == 0 {
+		return nil
+	}
+
+	err := s.encoder.Encode(s.chunk)
+	if err != nil {
+		return fmt.Errorf("encoding chunk: %w", err)
+	}
+
+	s.chunk.Entries = s.chunk.Entries[:0]
+	return nil
+}
+
+func writeStateSnapshot(w io.Writer, header *types.Header, stateVersion trie.TrieLayout,
+	t trie.Trie) (err error) {
+	compressor, err := zstd.NewWriter(w)
+	if err != nil {
+		return fmt.Errorf("creating compressor: %w", err)
+	}
+
+	encoder := scale.NewEncoder(compressor)
+	err = encoder.Encode(stateSnapshotHeader{
+		Magic:         []byte(stateSnapshotMagic),
+		FormatVersion: stateSnapshotFormatVersion,
+		StateVersion:  uint8(stateVersion),
+		Header:        *header,
+	})
+	if err != nil {
+		_ = compressor.Close()
+		return fmt.Errorf("encoding snapshot header: %w", err)
+	}
+
+	writer := &stateSnapshotWriter{encoder: encoder}
+	err = writer.writeTrie(nil, t)
+	if err != nil {
+		_ = compressor.Close()
+		return fmt.Errorf("writing main trie: %w", err)
+	}
+
+	for _, key := range t.GetKeysWithPrefix(trie.ChildStorageKeyPrefix) {
+		keyToChild := key[len(trie.ChildStorageKeyPrefix):]
+		child, err := t.GetChild(keyToChild)
+		if err != nil {
+			_ = compressor.Close()
+			return fmt.Errorf("getting child trie 0x%x: %w", keyToChild, err)
+		}
+
+		err = writer.writeTrie(keyToChild, child)
+		if err != nil {
+			_ = compressor.Close()
+			return fmt.Errorf("writing child trie 0x%x: %w", keyToChild, err)
+		}
+	}
+
+	// the empty chunk marks the end of the snapshot
+	err = encoder.Encode(stateSnapshotChunk{})
+	if err != nil {
+		_ = compressor.Close()
+		return fmt.Errorf("encoding last chunk: %w", err)
+	}
+
+	return compressor.Close()
+}
+
+func readStateSnapshot(r io.Reader) (header *types.Header, t *inmemory_trie.InMemoryTrie,
+	stateVersion trie.TrieLayout, err error) {
+	decompressor, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, nil, stateVersion, fmt.Errorf("creating decompressor: %w", err)
+	}
+	defer decompressor.Close()
+
+	decoder := scale.NewDecoder(fullReader{Reader: decompressor})
+	var snapshotHeader stateSnapshotHeader
+	err = decoder.Decode(&snapshotHeader)
+	if err != nil {
+		return nil, nil, stateVersion, fmt.Errorf("decoding snapshot header: %w", err)
+	}
+
+	if string(snapshotHeader.Magic) != stateSnapshotMagic {
+		return nil, nil, stateVersion, fmt.Errorf("%w: unexpected magic 0x%x",
+			errInvalidStateSnapshot, snapshotHeader.Magic)
+	}
+
+	if snapshotHeader.FormatVersion != stateSnapshotFormatVersion {
+		return nil, nil, stateVersion, fmt.Errorf("%w: unsupported format version %d",
+			errInvalidStateSnapshot, snapshotHeader.FormatVersion)
+	}
+
+	stateVersion, err = trie.ParseVersion(snapshotHeader.StateVersion)
+	if err != nil {
+		return nil, nil, stateVersion, fmt.Errorf("%w: %w", errInvalidStateSnapshot, err)
+	}
+
+	t = inmemory_trie.NewEmptyTrie()
+	t.SetVersion(stateVersion)
+	childTries := make(map[string]*inmemory_trie.InMemoryTrie)
+
+	for {
+		var chunk stateSnapshotChunk
+		err = decoder.Decode(&chunk)
+		if err != nil {
+			return nil, nil, stateVersion, fmt.Errorf("decoding chunk: %w", err)
+		}
+
+		if len(chunk.Entries) == 0 {
+			break
+		}
+
+		target := t
+		if len(chunk.KeyToChild) > 0 {
+			target = childTries[string(chunk.KeyToChild)]
+			if target == nil {
+				target = inmemory_trie.NewEmptyTrie()
+				target.SetVersion(stateVersion)
+				childTries[string(chunk.KeyToChild)] = target
+			}
+		}
+
+		for _, entry := range chunk.Entries {
+			// the child trie root hashes are set back when adding the child tries
+			if target == t && bytes.HasPrefix(entry.Key, trie.ChildStorageKeyPrefix) {
+				continue
+			}
+
+			err = target.Put(entry.Key, entry.Value)
+			if err != nil {
+				return nil, nil, stateVersion, fmt.Errorf("putting entry 0x%x: %w", entry.Key, err)
+			}
+		}
+	}
+
+	for keyToChild, child := range childTries {
+		err = t.SetChild([]byte(keyToChild), child)
+		if err != nil {
+			return nil, nil, stateVersion, fmt.Errorf("setting child trie 0x%x: %w", keyToChild, err)
+		}
+	}
+
+	header = &snapshotHeader.Header
+
+	root, err := t.Hash()
+	if err != nil {
+		return nil, nil, stateVersion, fmt.Errorf("hashing rebuilt trie: %w", err)
+	}
+
+	if root != header.StateRoot {
+		return nil, nil, stateVersion, fmt.Errorf("%w: rebuilt root %s and header state root %s",
+			errStateRootMismatch, root, header.StateRoot)
+	}
+
+	return header, t, stateVersion, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshotTrie(t *testing.T) *inmemory_trie.InMemoryTrie {
+	t.Helper()
+
+	tr := inmemory_trie.NewEmptyTrie()
+	tr.SetVersion(trie.V1)
+	// more entries than a chunk holds, and values hashed with the V1 layout
+	for i := 0; i < stateSnapshotMaxChunkEntries+10; i++ {
+		err := tr.Put([]byte(fmt.Sprintf("key%d", i)), bytes.Repeat([]byte{byte(i)}, 40))
+		require.NoError(t, err)
+	}
+
+	err := tr.PutIntoChild([]byte("child1"), []byte("childkey1"), []byte("childvalue1"))
+	require.NoError(t, err)
+	err = tr.PutIntoChild([]byte("child1"), []byte("childkey2"), []byte("childvalue2"))
+	require.NoError(t, err)
+	err = tr.PutIntoChild([]byte("child2"), []byte("childkey1"), []byte("childvalue3"))
+	require.NoError(t, err)
+
+	return tr
+}
+
+func Test_writeStateSnapshot_readStateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	tr := newTestSnapshotTrie(t)
+	root := tr.MustHash()
+	header := types.NewHeader(common.Hash{1}, root, common.Hash{2}, 10, types.NewDigest())
+
+	buffer := bytes.NewBuffer(nil)
+	err := writeStateSnapshot(buffer, header, trie.V1, tr)
+	require.NoError(t, err)
+
+	readHeader, readTrie, stateVersion, err := readStateSnapshot(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, header.Hash(), readHeader.Hash())
+	assert.Equal(t, trie.V1, stateVersion)
+	assert.Equal(t, root, readTrie.MustHash())
+
+	value, err := readTrie.GetFromChild([]byte("child1"), []byte("childkey2"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("childvalue2"), value)
+}
+
+func Test_readStateSnapshot_errors(t *testing.T) {
+	t.Parallel()
+
+	tr := newTestSnapshotTrie(t)
+	root := tr.MustHash()
+
+	wrongRootHeader := types.NewHeader(common.Hash{1}, common.Hash{3}, common.Hash{2}, 10, types.NewDigest())
+	wrongRootSnapshot := bytes.NewBuffer(nil)
+	err := writeStateSnapshot(wrongRootSnapshot, wrongRootHeader, trie.V1, tr)
+	require.NoError(t, err)
+
+	wrongVersionHeader := types.NewHeader(common.Hash{1}, root, common.Hash{2}, 10, types.NewDigest())
+	wrongVersionSnapshot := bytes.NewBuffer(nil)
+	err = writeStateSnapshot(wrongVersionSnapshot, wrongVersionHeader, trie.V0, tr)
+	require.NoError(t, err)
+
+	invalidMagicSnapshot := bytes.NewBuffer(nil)
+	compressor, err := zstd.NewWriter(invalidMagicSnapshot)
+	require.NoError(t, err)
+	err = scale.NewEncoder(compressor).Encode(stateSnapshotHeader{
+		Magic:         []byte("invalid"),
+		FormatVersion: stateSnapshotFormatVersion,
+	})
+	require.NoError(t, err)
+	require.NoError(t, compressor.Close())
+
+	testCases := map[string]struct {
+		snapshot   *bytes.Buffer
+		errWrapped error
+		errMessage string
+	}{
+		"wrong_state_root": {
+			snapshot:   wrongRootSnapshot,
+			errWrapped: errStateRootMismatch,
+			errMessage: "rebuilt state root does not match header state root: rebuilt root " +
+				root.String() + " and header state root " + common.Hash{3}.String(),
+		},
+		"wrong_state_version": {
+			snapshot:   wrongVersionSnapshot,
+			errWrapped: errStateRootMismatch,
+		},
+		"invalid_magic": {
+			snapshot:   invalidMagicSnapshot,
+			errWrapped: errInvalidStateSnapshot,
+			errMessage: "invalid state snapshot: unexpected magic 0x696e76616c6964",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, _, err := readStateSnapshot(testCase.snapshot)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
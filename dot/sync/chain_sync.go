@@ -33,6 +33,7 @@
 const (
 	bootstrap chainSyncState = iota
 	tip
+	warp
 )
 
 type blockOrigin byte
@@ -40,6 +41,8 @@
 const (
 	networkInitialSync blockOrigin = iota
 	networkBroadcast
+	// offlineImport is the origin of the blocks read from a file by the OfflineImporter
+	offlineImport
 )
 
 func (s chainSyncState) String() string {
@@ -48,6 +51,8 @@ func (s chainSyncState) String() string {
 		return "bootstrap"
 	case tip:
 		return "tip"
+	case warp:
+		return "warp"
 	default:
 		return "unknown"
 	}
@@ -124,6 +129,13 @@ type chainSync struct {
 	badBlocks          []string
 	requestMaker       network.RequestMaker
 	waitPeersDuration  time.Duration
+
+	// warpSyncPending is true until warp sync was attempted, it is
+	// only ever set if warp sync is enabled in the configuration.
+	warpSyncPending      atomic.Bool
+	grandpaState         GrandpaState
+	warpSyncRequestMaker network.RequestMaker
+	stateRequestMaker    network.RequestMaker
 }
 
 type chainSyncConfig struct {
@@ -141,12 +153,17 @@ type chainSyncConfig struct {
 	telemetry          Telemetry
 	badBlocks          []string
 	waitPeersDuration  time.Duration
+
+	warpSync             bool
+	grandpaState         GrandpaState
+	warpSyncRequestMaker network.RequestMaker
+	stateRequestMaker    network.RequestMaker
 }
 
 func newChainSync(cfg chainSyncConfig) *chainSync {
 	atomicState := atomic.Value{}
 	atomicState.Store(tip)
-	return &chainSync{
+	cs := &chainSync{
 		stopCh:             make(chan struct{}),
 		storageState:       cfg.storageState,
 		transactionState:   cfg.transactionState,
@@ -166,7 +183,13 @@ func newChainSync(cfg chainSyncConfig) *chainSync {
 		badBlocks:          cfg.badBlocks,
 		requestMaker:       cfg.requestMaker,
 		waitPeersDuration:  cfg.waitPeersDuration,
+
+		grandpaState:         cfg.grandpaState,
+		warpSyncRequestMaker: cfg.warpSyncRequestMaker,
+		stateRequestMaker:    cfg.stateRequestMaker,
 	}
+	cs.warpSyncPending.Store(cfg.warpSync)
+	return cs
 }
 
 func (cs *chainSync) waitWorkersAndTarget() {
@@ -293,7 +316,7 @@ func (cs *chainSync) onBlockAnnounceHandshake(who peer.ID, bestHash common.Hash,
 	cs.workerPool.fromBlockAnnounce(who)
 	cs.peerViewSet.update(who, bestHash, bestNumber)
 
-	if cs.getSyncMode() == bootstrap {
+	if cs.getSyncMode() != tip {
 		return nil
 	}
 
@@ -308,6 +331,17 @@ func (cs *chainSync) onBlockAnnounceHandshake(who peer.ID, bestHash common.Hash,
 	}
 
 	// we are more than 128 blocks behind the head, switch to bootstrap
+	// or to warp if it was enabled and never attempted
+	if cs.warpSyncPending.CompareAndSwap(true, false) {
+		cs.syncMode.Store(warp)
+		isSyncedGauge.Set(0)
+		logger.Infof("🔁 switched sync mode to %s", warp.String())
+
+		cs.wg.Add(1)
+		go cs.warpThenBootstrapSync()
+		return nil
+	}
+
 	cs.syncMode.Store(bootstrap)
 	isSyncedGauge.Set(0)
 	logger.Infof("🔁 switched sync mode to %s", bootstrap.String())
@@ -317,6 +351,20 @@ func (cs *chainSync) onBlockAnnounceHandshake(who peer.ID, bestHash common.Hash,
 	return nil
 }
 
+// warpThenBootstrapSync jumps to the latest finalised block using warp sync
+// and then continues with bootstrap sync from there. If warp sync fails,
+// bootstrap sync continues from our own highest finalised block.
+func (cs *chainSync) warpThenBootstrapSync() {
+	err := cs.warpSync()
+	if err != nil {
+		logger.Errorf("warp sync failed, continuing with full sync: %s", err)
+	}
+
+	cs.syncMode.Store(bootstrap)
+	logger.Infof("🔁 switched sync mode to %s", bootstrap.String())
+	cs.bootstrapSync()
+}
+
 func (cs *chainSync) onBlockAnnounce(announced announcedBlock) error {
 	// TODO: https://github.com/ChainSafe/gossamer/issues/3432
 	if cs.pendingBlocks.hasBlock(announced.header.Hash()) {
@@ -329,7 +377,7 @@ func (cs *chainSync) onBlockAnnounce(announced announcedBlock) error {
 		return fmt.Errorf("while adding pending block header: %w", err)
 	}
 
-	if cs.getSyncMode() == bootstrap {
+	if cs.getSyncMode() != tip {
 		return nil
 	}
 
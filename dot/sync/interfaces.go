@@ -39,6 +39,8 @@ type BlockState interface {
 	GetHeaderByNumber(num uint) (*types.Header, error)
 	GetAllBlocksAtNumber(num uint) ([]common.Hash, error)
 	IsDescendantOf(parent, child common.Hash) (bool, error)
+	SetWarpSyncTarget(header *types.Header, justification []byte, round, setID uint64) error
+	HandleRuntimeChanges(newState *rtstorage.TrieState, in runtime.Instance, bHash common.Hash) error
 
 	IsPaused() bool
 	Pause() error
@@ -47,9 +49,23 @@ type BlockState interface {
 // StorageState is the interface for the storage state
 type StorageState interface {
 	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
+	StoreTrie(ts *rtstorage.TrieState, header *types.Header) error
+	GenerateTrieProof(stateRoot common.Hash, keys [][]byte) ([][]byte, error)
+	GenerateReadProof(stateRoot common.Hash, keys [][]byte) ([][]byte, error)
+	GenerateChildReadProof(stateRoot common.Hash, keyToChild []byte, keys [][]byte) ([][]byte, error)
+	GenerateExecutionProof(stateRoot common.Hash, execute func(*rtstorage.TrieState) error) ([][]byte, error)
 	sync.Locker
 }
 
+// GrandpaState is the interface for the GRANDPA authority set methods
+type GrandpaState interface {
+	GetCurrentSetID() (uint64, error)
+	GetAuthorities(setID uint64) ([]types.GrandpaVoter, error)
+	GetSetIDChange(setID uint64) (blockNumber uint, err error)
+	GetSetIDByBlockNumber(blockNumber uint) (uint64, error)
+	AddAuthoritySetChange(setID uint64, authorities []types.GrandpaVoter, number uint) error
+}
+
 // TransactionState is the interface for transaction queue methods
 type TransactionState interface {
 	RemoveExtrinsic(ext types.Extrinsic)
@@ -0,0 +1,137 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+var errInvalidLightRequestBlock = errors.New("invalid light request block")
+
+// CreateRemoteCallResponse executes the requested runtime call on the state of
+// the requested block, using an instance isolated from block import, and responds
+// with the SCALE encoded proof of the storage read during the execution.
+func (s *Service) CreateRemoteCallResponse(req *network.RemoteCallRequest) (*network.RemoteCallResponse, error) {
+	header, err := s.lightRequestHeader(req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := s.blockState.GetRuntime(header.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("getting runtime: %w", err)
+	}
+
+	proof, err := s.storageState.GenerateExecutionProof(header.StateRoot, func(ts *rtstorage.TrieState) error {
+		_, err := instance.ExecWithStorage(ts, req.Method, req.Data)
+		if err != nil {
+			return fmt.Errorf("executing %s: %w", req.Method, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generating execution proof: %w", err)
+	}
+
+	encodedProof, err := scale.Marshal(proof)
+	if err != nil {
+		return nil, fmt.Errorf("encoding execution proof: %w", err)
+	}
+
+	return &network.RemoteCallResponse{Proof: encodedProof}, nil
+}
+
+// CreateRemoteReadResponse responds with the SCALE encoded proof of the
+// requested keys in the state of the requested block.
+func (s *Service) CreateRemoteReadResponse(req *network.RemoteReadRequest) (*network.RemoteReadResponse, error) {
+	header, err := s.lightRequestHeader(req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := s.storageState.GenerateReadProof(header.StateRoot, req.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("generating read proof: %w", err)
+	}
+
+	encodedProof, err := scale.Marshal(proof)
+	if err != nil {
+		return nil, fmt.Errorf("encoding read proof: %w", err)
+	}
+
+	return &network.RemoteReadResponse{Proof: encodedProof}, nil
+}
+
+// CreateRemoteReadChildResponse responds with the SCALE encoded proof of the
+// requested keys in the requested child trie, in the state of the requested block.
+func (s *Service) CreateRemoteReadChildResponse(req *network.RemoteReadChildRequest) (
+	*network.RemoteReadResponse, error) {
+	header, err := s.lightRequestHeader(req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := s.storageState.GenerateChildReadProof(header.StateRoot, req.StorageKey, req.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("generating child read proof: %w", err)
+	}
+
+	encodedProof, err := scale.Marshal(proof)
+	if err != nil {
+		return nil, fmt.Errorf("encoding child read proof: %w", err)
+	}
+
+	return &network.RemoteReadResponse{Proof: encodedProof}, nil
+}
+
+// CreateRemoteHeaderResponse responds with the header of the requested block
+// number, along with the justification of the block if it has one.
+func (s *Service) CreateRemoteHeaderResponse(req *network.RemoteHeaderRequest) (
+	*network.RemoteHeaderResponse, error) {
+	// the block number is SCALE encoded as an unsigned 32 bits integer
+	if len(req.Block) != 4 {
+		return nil, fmt.Errorf("%w: expected a 4 bytes block number, got %d bytes",
+			errInvalidLightRequestBlock, len(req.Block))
+	}
+	number := binary.LittleEndian.Uint32(req.Block)
+
+	header, err := s.blockState.GetHeaderByNumber(uint(number))
+	if err != nil {
+		return nil, fmt.Errorf("getting header #%d: %w", number, err)
+	}
+
+	justification, err := s.blockState.GetJustification(header.Hash())
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("getting justification of block #%d: %w", number, err)
+	}
+
+	return &network.RemoteHeaderResponse{
+		Header: []*types.Header{header},
+		Proof:  justification,
+	}, nil
+}
+
+// lightRequestHeader returns the header of the block hash of a light request.
+func (s *Service) lightRequestHeader(block []byte) (*types.Header, error) {
+	if len(block) != common.HashLength {
+		return nil, fmt.Errorf("%w: expected a %d bytes hash, got %d bytes",
+			errInvalidLightRequestBlock, common.HashLength, len(block))
+	}
+
+	header, err := s.blockState.GetHeader(common.BytesToHash(block))
+	if err != nil {
+		return nil, fmt.Errorf("getting header: %w", err)
+	}
+
+	return header, nil
+}
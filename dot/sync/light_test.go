@@ -0,0 +1,176 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Service_CreateRemoteCallResponse(t *testing.T) {
+	t.Parallel()
+
+	header := types.NewHeader(common.Hash{}, common.Hash{1}, common.Hash{}, 1, types.NewDigest())
+	blockHash := header.Hash()
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+	storageState := NewMockStorageState(ctrl)
+	instance := NewMockInstance(ctrl)
+
+	trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+	blockState.EXPECT().GetHeader(blockHash).Return(header, nil)
+	blockState.EXPECT().GetRuntime(blockHash).Return(instance, nil)
+	storageState.EXPECT().GenerateExecutionProof(header.StateRoot, gomock.Any()).
+		DoAndReturn(func(_ common.Hash, execute func(*rtstorage.TrieState) error) ([][]byte, error) {
+			return [][]byte{{1}, {2}}, execute(trieState)
+		})
+	instance.EXPECT().ExecWithStorage(trieState, "Core_version", []byte{3}).Return([]byte{4}, nil)
+
+	service := &Service{
+		blockState:   blockState,
+		storageState: storageState,
+	}
+
+	response, err := service.CreateRemoteCallResponse(&network.RemoteCallRequest{
+		Block:  blockHash.ToBytes(),
+		Method: "Core_version",
+		Data:   []byte{3},
+	})
+	require.NoError(t, err)
+
+	expectedProof, err := scale.Marshal([][]byte{{1}, {2}})
+	require.NoError(t, err)
+	assert.Equal(t, expectedProof, response.Proof)
+}
+
+func Test_Service_CreateRemoteReadResponse(t *testing.T) {
+	t.Parallel()
+
+	header := types.NewHeader(common.Hash{}, common.Hash{1}, common.Hash{}, 1, types.NewDigest())
+	blockHash := header.Hash()
+	keys := [][]byte{{1}, {2}}
+
+	testCases := map[string]struct {
+		request           network.RemoteReadRequest
+		childRequest      *network.RemoteReadChildRequest
+		setupMocks        func(blockState *MockBlockState, storageState *MockStorageState)
+		expectedProof     [][]byte
+		errWrapped        error
+		errMessage        string
+		expectedChildCall bool
+	}{
+		"invalid_block_hash": {
+			request:    network.RemoteReadRequest{Block: []byte{1}, Keys: keys},
+			setupMocks: func(*MockBlockState, *MockStorageState) {},
+			errWrapped: errInvalidLightRequestBlock,
+			errMessage: "invalid light request block: expected a 32 bytes hash, got 1 bytes",
+		},
+		"unknown_block": {
+			request: network.RemoteReadRequest{Block: blockHash.ToBytes(), Keys: keys},
+			setupMocks: func(blockState *MockBlockState, _ *MockStorageState) {
+				blockState.EXPECT().GetHeader(blockHash).Return(nil, database.ErrNotFound)
+			},
+			errWrapped: database.ErrNotFound,
+			errMessage: "getting header: pebble: not found",
+		},
+		"read": {
+			request: network.RemoteReadRequest{Block: blockHash.ToBytes(), Keys: keys},
+			setupMocks: func(blockState *MockBlockState, storageState *MockStorageState) {
+				blockState.EXPECT().GetHeader(blockHash).Return(header, nil)
+				storageState.EXPECT().GenerateReadProof(header.StateRoot, keys).Return([][]byte{{3}}, nil)
+			},
+			expectedProof: [][]byte{{3}},
+		},
+		"child_read": {
+			childRequest: &network.RemoteReadChildRequest{
+				Block:      blockHash.ToBytes(),
+				StorageKey: []byte("child"),
+				Keys:       keys,
+			},
+			setupMocks: func(blockState *MockBlockState, storageState *MockStorageState) {
+				blockState.EXPECT().GetHeader(blockHash).Return(header, nil)
+				storageState.EXPECT().GenerateChildReadProof(header.StateRoot, []byte("child"), keys).
+					Return([][]byte{{4}}, nil)
+			},
+			expectedProof: [][]byte{{4}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			blockState := NewMockBlockState(ctrl)
+			storageState := NewMockStorageState(ctrl)
+			testCase.setupMocks(blockState, storageState)
+
+			service := &Service{
+				blockState:   blockState,
+				storageState: storageState,
+			}
+
+			var response *network.RemoteReadResponse
+			var err error
+			if testCase.childRequest != nil {
+				response, err = service.CreateRemoteReadChildResponse(testCase.childRequest)
+			} else {
+				response, err = service.CreateRemoteReadResponse(&testCase.request)
+			}
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+
+			expectedProof, err := scale.Marshal(testCase.expectedProof)
+			require.NoError(t, err)
+			assert.Equal(t, expectedProof, response.Proof)
+		})
+	}
+}
+
+func Test_Service_CreateRemoteHeaderResponse(t *testing.T) {
+	t.Parallel()
+
+	header := types.NewHeader(common.Hash{}, common.Hash{1}, common.Hash{}, 5, types.NewDigest())
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHeaderByNumber(uint(5)).Return(header, nil).Times(2)
+	blockState.EXPECT().GetJustification(header.Hash()).Return([]byte{1, 2}, nil)
+	blockState.EXPECT().GetJustification(header.Hash()).Return(nil, database.ErrNotFound)
+
+	service := &Service{blockState: blockState}
+
+	block, err := scale.Marshal(uint32(5))
+	require.NoError(t, err)
+
+	response, err := service.CreateRemoteHeaderResponse(&network.RemoteHeaderRequest{Block: block})
+	require.NoError(t, err)
+	assert.Equal(t, []*types.Header{header}, response.Header)
+	assert.Equal(t, []byte{1, 2}, response.Proof)
+
+	// blocks without justification are answered without proof
+	response, err = service.CreateRemoteHeaderResponse(&network.RemoteHeaderRequest{Block: block})
+	require.NoError(t, err)
+	assert.Equal(t, []*types.Header{header}, response.Header)
+	assert.Empty(t, response.Proof)
+
+	_, err = service.CreateRemoteHeaderResponse(&network.RemoteHeaderRequest{Block: []byte{1}})
+	assert.ErrorIs(t, err, errInvalidLightRequestBlock)
+}
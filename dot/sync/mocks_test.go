@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/ChainSafe/gossamer/dot/sync (interfaces: BlockState,StorageState,TransactionState,BabeVerifier,FinalityGadget,BlockImportHandler,Network)
+// Source: github.com/ChainSafe/gossamer/dot/sync (interfaces: BlockState,StorageState,TransactionState,BabeVerifier,FinalityGadget,BlockImportHandler,Network,GrandpaState)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks_test.go -package=sync . BlockState,StorageState,TransactionState,BabeVerifier,FinalityGadget,BlockImportHandler,Network
+//	mockgen -destination=mocks_test.go -package=sync . BlockState,StorageState,TransactionState,BabeVerifier,FinalityGadget,BlockImportHandler,Network,GrandpaState
 //
 
 // Package sync is a generated GoMock package.
@@ -267,6 +267,20 @@ func (mr *MockBlockStateMockRecorder) GetRuntime(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntime", reflect.TypeOf((*MockBlockState)(nil).GetRuntime), arg0)
 }
 
+// HandleRuntimeChanges mocks base method.
+func (m *MockBlockState) HandleRuntimeChanges(arg0 *storage.TrieState, arg1 runtime.Instance, arg2 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleRuntimeChanges", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleRuntimeChanges indicates an expected call of HandleRuntimeChanges.
+func (mr *MockBlockStateMockRecorder) HandleRuntimeChanges(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRuntimeChanges", reflect.TypeOf((*MockBlockState)(nil).HandleRuntimeChanges), arg0, arg1, arg2)
+}
+
 // HasHeader mocks base method.
 func (m *MockBlockState) HasHeader(arg0 common.Hash) (bool, error) {
 	m.ctrl.T.Helper()
@@ -383,6 +397,20 @@ func (mr *MockBlockStateMockRecorder) SetJustification(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJustification", reflect.TypeOf((*MockBlockState)(nil).SetJustification), arg0, arg1)
 }
 
+// SetWarpSyncTarget mocks base method.
+func (m *MockBlockState) SetWarpSyncTarget(arg0 *types.Header, arg1 []byte, arg2, arg3 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWarpSyncTarget", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWarpSyncTarget indicates an expected call of SetWarpSyncTarget.
+func (mr *MockBlockStateMockRecorder) SetWarpSyncTarget(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWarpSyncTarget", reflect.TypeOf((*MockBlockState)(nil).SetWarpSyncTarget), arg0, arg1, arg2, arg3)
+}
+
 // StoreRuntime mocks base method.
 func (m *MockBlockState) StoreRuntime(arg0 common.Hash, arg1 runtime.Instance) {
 	m.ctrl.T.Helper()
@@ -418,6 +446,66 @@ func (m *MockStorageState) EXPECT() *MockStorageStateMockRecorder {
 	return m.recorder
 }
 
+// GenerateChildReadProof mocks base method.
+func (m *MockStorageState) GenerateChildReadProof(arg0 common.Hash, arg1 []byte, arg2 [][]byte) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateChildReadProof", arg0, arg1, arg2)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateChildReadProof indicates an expected call of GenerateChildReadProof.
+func (mr *MockStorageStateMockRecorder) GenerateChildReadProof(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateChildReadProof", reflect.TypeOf((*MockStorageState)(nil).GenerateChildReadProof), arg0, arg1, arg2)
+}
+
+// GenerateExecutionProof mocks base method.
+func (m *MockStorageState) GenerateExecutionProof(arg0 common.Hash, arg1 func(*storage.TrieState) error) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateExecutionProof", arg0, arg1)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateExecutionProof indicates an expected call of GenerateExecutionProof.
+func (mr *MockStorageStateMockRecorder) GenerateExecutionProof(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateExecutionProof", reflect.TypeOf((*MockStorageState)(nil).GenerateExecutionProof), arg0, arg1)
+}
+
+// GenerateReadProof mocks base method.
+func (m *MockStorageState) GenerateReadProof(arg0 common.Hash, arg1 [][]byte) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateReadProof", arg0, arg1)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateReadProof indicates an expected call of GenerateReadProof.
+func (mr *MockStorageStateMockRecorder) GenerateReadProof(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateReadProof", reflect.TypeOf((*MockStorageState)(nil).GenerateReadProof), arg0, arg1)
+}
+
+// GenerateTrieProof mocks base method.
+func (m *MockStorageState) GenerateTrieProof(arg0 common.Hash, arg1 [][]byte) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateTrieProof", arg0, arg1)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateTrieProof indicates an expected call of GenerateTrieProof.
+func (mr *MockStorageStateMockRecorder) GenerateTrieProof(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTrieProof", reflect.TypeOf((*MockStorageState)(nil).GenerateTrieProof), arg0, arg1)
+}
+
 // Lock mocks base method.
 func (m *MockStorageState) Lock() {
 	m.ctrl.T.Helper()
@@ -430,6 +518,20 @@ func (mr *MockStorageStateMockRecorder) Lock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockStorageState)(nil).Lock))
 }
 
+// StoreTrie mocks base method.
+func (m *MockStorageState) StoreTrie(arg0 *storage.TrieState, arg1 *types.Header) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreTrie", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreTrie indicates an expected call of StoreTrie.
+func (mr *MockStorageStateMockRecorder) StoreTrie(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTrie", reflect.TypeOf((*MockStorageState)(nil).StoreTrie), arg0, arg1)
+}
+
 // TrieState mocks base method.
 func (m *MockStorageState) TrieState(arg0 *common.Hash) (*storage.TrieState, error) {
 	m.ctrl.T.Helper()
@@ -681,3 +783,100 @@ func (mr *MockNetworkMockRecorder) ReportPeer(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportPeer", reflect.TypeOf((*MockNetwork)(nil).ReportPeer), arg0, arg1)
 }
+
+// MockGrandpaState is a mock of GrandpaState interface.
+type MockGrandpaState struct {
+	ctrl     *gomock.Controller
+	recorder *MockGrandpaStateMockRecorder
+}
+
+// MockGrandpaStateMockRecorder is the mock recorder for MockGrandpaState.
+type MockGrandpaStateMockRecorder struct {
+	mock *MockGrandpaState
+}
+
+// NewMockGrandpaState creates a new mock instance.
+func NewMockGrandpaState(ctrl *gomock.Controller) *MockGrandpaState {
+	mock := &MockGrandpaState{ctrl: ctrl}
+	mock.recorder = &MockGrandpaStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGrandpaState) EXPECT() *MockGrandpaStateMockRecorder {
+	return m.recorder
+}
+
+// AddAuthoritySetChange mocks base method.
+func (m *MockGrandpaState) AddAuthoritySetChange(arg0 uint64, arg1 []types.GrandpaVoter, arg2 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAuthoritySetChange", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAuthoritySetChange indicates an expected call of AddAuthoritySetChange.
+func (mr *MockGrandpaStateMockRecorder) AddAuthoritySetChange(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAuthoritySetChange", reflect.TypeOf((*MockGrandpaState)(nil).AddAuthoritySetChange), arg0, arg1, arg2)
+}
+
+// GetAuthorities mocks base method.
+func (m *MockGrandpaState) GetAuthorities(arg0 uint64) ([]types.GrandpaVoter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorities", arg0)
+	ret0, _ := ret[0].([]types.GrandpaVoter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorities indicates an expected call of GetAuthorities.
+func (mr *MockGrandpaStateMockRecorder) GetAuthorities(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorities", reflect.TypeOf((*MockGrandpaState)(nil).GetAuthorities), arg0)
+}
+
+// GetCurrentSetID mocks base method.
+func (m *MockGrandpaState) GetCurrentSetID() (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentSetID")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentSetID indicates an expected call of GetCurrentSetID.
+func (mr *MockGrandpaStateMockRecorder) GetCurrentSetID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentSetID", reflect.TypeOf((*MockGrandpaState)(nil).GetCurrentSetID))
+}
+
+// GetSetIDByBlockNumber mocks base method.
+func (m *MockGrandpaState) GetSetIDByBlockNumber(arg0 uint) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSetIDByBlockNumber", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSetIDByBlockNumber indicates an expected call of GetSetIDByBlockNumber.
+func (mr *MockGrandpaStateMockRecorder) GetSetIDByBlockNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSetIDByBlockNumber", reflect.TypeOf((*MockGrandpaState)(nil).GetSetIDByBlockNumber), arg0)
+}
+
+// GetSetIDChange mocks base method.
+func (m *MockGrandpaState) GetSetIDChange(arg0 uint64) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSetIDChange", arg0)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSetIDChange indicates an expected call of GetSetIDChange.
+func (mr *MockGrandpaStateMockRecorder) GetSetIDChange(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSetIDChange", reflect.TypeOf((*MockGrandpaState)(nil).GetSetIDChange), arg0)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+)
+
+var errMissingHeaderOrBody = errors.New("block data is missing header or body")
+
+// OfflineImportConfig is the configuration for the OfflineImporter.
+type OfflineImportConfig struct {
+	BlockState         BlockState
+	StorageState       StorageState
+	TransactionState   TransactionState
+	BabeVerifier       BabeVerifier
+	FinalityGadget     FinalityGadget
+	BlockImportHandler BlockImportHandler
+	Telemetry          Telemetry
+}
+
+// OfflineImporter verifies and imports blocks without networking, through the
+// same path as the blocks downloaded by the chain sync.
+type OfflineImporter struct {
+	chainSync *chainSync
+}
+
+// NewOfflineImporter returns a new *OfflineImporter
+func NewOfflineImporter(cfg *OfflineImportConfig) *OfflineImporter {
+	// imported blocks are never announced, as in bootstrap mode
+	syncMode := atomic.Value{}
+	syncMode.Store(bootstrap)
+
+	return &OfflineImporter{
+		chainSync: &chainSync{
+			blockState:         cfg.BlockState,
+			storageState:       cfg.StorageState,
+			transactionState:   cfg.TransactionState,
+			babeVerifier:       cfg.BabeVerifier,
+			finalityGadget:     cfg.FinalityGadget,
+			blockImportHandler: cfg.BlockImportHandler,
+			telemetry:          cfg.Telemetry,
+			syncMode:           syncMode,
+		},
+	}
+}
+
+// ImportBlock verifies the block header, executes the block on top of its parent state
+// and imports it along with its justification, if any. Blocks already in the database
+// are skipped, and the returned imported is false for them.
+func (o *OfflineImporter) ImportBlock(blockData *types.BlockData) (imported bool, err error) {
+	if blockData.Header == nil || blockData.Body == nil {
+		return false, fmt.Errorf("%w: %s", errMissingHeaderOrBody, blockData.Hash)
+	}
+
+	has, err := o.chainSync.blockState.HasHeader(blockData.Hash)
+	if err != nil {
+		return false, fmt.Errorf("checking if block is known: %w", err)
+	}
+
+	if has {
+		logger.Debugf("skipping already imported block #%d (%s)", blockData.Header.Number, blockData.Hash)
+		return false, nil
+	}
+
+	err = o.chainSync.processBlockData(*blockData, offlineImport)
+	if err != nil {
+		return false, fmt.Errorf("processing block #%d (%s): %w",
+			blockData.Header.Number, blockData.Hash, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOfflineImporter_ImportBlock(t *testing.T) {
+	t.Parallel()
+
+	parentHeader := types.NewHeader(common.NewHash([]byte{0}), trie.EmptyHash,
+		trie.EmptyHash, 0, types.NewDigest())
+	blockData := createSuccesfullBlockResponse(t, parentHeader.Hash(), 1, 1).BlockData[0]
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		blockData  *types.BlockData
+		setupMocks func(ctrl *gomock.Controller, cfg *OfflineImportConfig)
+		imported   bool
+		errWrapped error
+		errMessage string
+	}{
+		"missing_body": {
+			blockData:  &types.BlockData{Hash: blockData.Hash, Header: blockData.Header},
+			setupMocks: func(*gomock.Controller, *OfflineImportConfig) {},
+			errWrapped: errMissingHeaderOrBody,
+			errMessage: "block data is missing header or body: " + blockData.Hash.String(),
+		},
+		"already_imported": {
+			blockData: blockData,
+			setupMocks: func(ctrl *gomock.Controller, cfg *OfflineImportConfig) {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().HasHeader(blockData.Hash).Return(true, nil)
+				cfg.BlockState = blockState
+			},
+		},
+		"babe_verification_error": {
+			blockData: blockData,
+			setupMocks: func(ctrl *gomock.Controller, cfg *OfflineImportConfig) {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().HasHeader(blockData.Hash).Return(false, nil)
+				cfg.BlockState = blockState
+				babeVerifier := NewMockBabeVerifier(ctrl)
+				babeVerifier.EXPECT().VerifyBlock(blockData.Header).Return(errTest)
+				cfg.BabeVerifier = babeVerifier
+			},
+			errWrapped: errTest,
+			errMessage: "processing block #1 (" + blockData.Hash.String() + "): " +
+				"processing block data with header and body: babe verifying block: test error",
+		},
+		"imported": {
+			blockData: blockData,
+			setupMocks: func(ctrl *gomock.Controller, cfg *OfflineImportConfig) {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().HasHeader(blockData.Hash).Return(false, nil)
+				babeVerifier := NewMockBabeVerifier(ctrl)
+				storageState := NewMockStorageState(ctrl)
+				transactionState := NewMockTransactionState(ctrl)
+				importHandler := NewMockBlockImportHandler(ctrl)
+				telemetryMock := NewMockTelemetry(ctrl)
+
+				const announceBlock = false
+				ensureSuccessfulBlockImportFlow(t, parentHeader, []*types.BlockData{blockData}, blockState,
+					babeVerifier, storageState, importHandler, telemetryMock, offlineImport, announceBlock)
+
+				cfg.BlockState = blockState
+				cfg.BabeVerifier = babeVerifier
+				cfg.StorageState = storageState
+				cfg.TransactionState = transactionState
+				cfg.BlockImportHandler = importHandler
+				cfg.Telemetry = telemetryMock
+			},
+			imported: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			cfg := &OfflineImportConfig{}
+			testCase.setupMocks(ctrl, cfg)
+			importer := NewOfflineImporter(cfg)
+
+			imported, err := importer.ImportBlock(testCase.blockData)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			require.Equal(t, testCase.imported, imported)
+		})
+	}
+}
@@ -0,0 +1,517 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	"github.com/ChainSafe/gossamer/dot/peerset"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory/proof"
+)
+
+// maxStateResponseBytes is the soft limit on the size of the key value
+// pairs we put in a single state response.
+const maxStateResponseBytes = 2 * 1024 * 1024
+
+var (
+	errTooManyStartKeys        = errors.New("state request has more than two start keys")
+	errEmptyStateResponse      = errors.New("state response without any entry")
+	errUnknownChildTrie        = errors.New("state response contains entries of unknown child trie")
+	errStateRootMismatch       = errors.New("downloaded state does not match state root")
+	errNoPeerProvidedState     = errors.New("no peer provided a valid state response")
+	errStateSyncStopped        = errors.New("state sync stopped")
+	errProofOnlyStateResponses = errors.New("state response contains a proof but no entries")
+)
+
+// CreateStateResponse creates the response to a state request. The response
+// holds the key value pairs of the top level trie following the first start key,
+// and the ones of the child tries found along the way, up to maxStateResponseBytes.
+// If the request has two start keys, the iteration resumes in the child trie stored
+// at the first key, after the second key. Unless NoProof is set, the response also
+// contains the SCALE encoded proof of the top level key value pairs.
+func (s *Service) CreateStateResponse(req *messages.StateRequest) (*messages.StateResponse, error) {
+	if len(req.Start) > 2 {
+		return nil, fmt.Errorf("%w: %d", errTooManyStartKeys, len(req.Start))
+	}
+
+	header, err := s.blockState.GetHeader(req.Block)
+	if err != nil {
+		return nil, fmt.Errorf("getting header: %w", err)
+	}
+
+	trieState, err := s.storageState.TrieState(&header.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("getting trie state: %w", err)
+	}
+
+	entries, err := collectStateEntries(trieState.Trie(), req.Start, maxStateResponseBytes)
+	if err != nil {
+		return nil, fmt.Errorf("collecting state entries: %w", err)
+	}
+
+	response := &messages.StateResponse{Entries: entries}
+	if req.NoProof {
+		return response, nil
+	}
+
+	keys := stateProofKeys(trieState.Trie(), req.Start, entries[0].StateEntries)
+	proof, err := s.storageState.GenerateTrieProof(header.StateRoot, keys)
+	if err != nil {
+		return nil, fmt.Errorf("generating trie proof: %w", err)
+	}
+
+	response.Proof, err = scale.Marshal(proof)
+	if err != nil {
+		return nil, fmt.Errorf("encoding trie proof: %w", err)
+	}
+
+	return response, nil
+}
+
+// stateProofKeys returns the keys to prove for the given top level key value
+// pairs of a state response. The start key is part of them for the requester
+// to verify no key lies between it and the first key of the response.
+func stateProofKeys(t trie.Trie, start [][]byte, entries trie.Entries) (keys [][]byte) {
+	keys = make([][]byte, 0, 1+len(entries))
+	if len(start) > 0 && t.Get(start[0]) != nil {
+		keys = append(keys, start[0])
+	}
+
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+
+	return keys
+}
+
+// collectStateEntries returns the key value pairs of the given trie following the
+// start keys. The first returned entry always holds the top level key value pairs,
+// the following ones the key value pairs of child tries.
+func collectStateEntries(t trie.Trie, start [][]byte, limit int) ([]messages.KeyValueStateEntry, error) {
+	collector := &stateEntriesCollector{limit: limit}
+	entries := []messages.KeyValueStateEntry{{}}
+
+	var cursor []byte
+	if len(start) > 0 {
+		cursor = start[0]
+	}
+
+	if len(start) == 2 {
+		child, err := collector.collectChild(t, start[0], start[1])
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, child)
+		if !child.Complete {
+			return entries, nil
+		}
+	}
+
+	for key := t.NextKey(cursor); key != nil; key = t.NextKey(key) {
+		value := t.Get(key)
+		entries[0].StateEntries = append(entries[0].StateEntries, trie.Entry{Key: key, Value: value})
+		collector.size += len(key) + len(value)
+
+		if bytes.HasPrefix(key, inmemory.ChildStorageKeyPrefix) {
+			child, err := collector.collectChild(t, key, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, child)
+			if !child.Complete {
+				return entries, nil
+			}
+		}
+
+		if collector.full() {
+			return entries, nil
+		}
+	}
+
+	entries[0].Complete = true
+	return entries, nil
+}
+
+type stateEntriesCollector struct {
+	size  int
+	limit int
+}
+
+func (c *stateEntriesCollector) full() bool {
+	return c.size >= c.limit
+}
+
+// collectChild collects the key value pairs of the child trie stored at
+// the given top level key, following the given child key.
+func (c *stateEntriesCollector) collectChild(t trie.Trie, parentKey, cursor []byte) (
+	entry messages.KeyValueStateEntry, err error) {
+	keyToChild := bytes.TrimPrefix(parentKey, inmemory.ChildStorageKeyPrefix)
+	child, err := t.GetChild(keyToChild)
+	if err != nil {
+		return entry, fmt.Errorf("getting child trie at key 0x%x: %w", parentKey, err)
+	}
+
+	entry.StateRoot, err = child.Hash()
+	if err != nil {
+		return entry, fmt.Errorf("hashing child trie at key 0x%x: %w", parentKey, err)
+	}
+
+	for key := child.NextKey(cursor); key != nil; key = child.NextKey(key) {
+		if c.full() {
+			return entry, nil
+		}
+
+		value := child.Get(key)
+		entry.StateEntries = append(entry.StateEntries, trie.Entry{Key: key, Value: value})
+		c.size += len(key) + len(value)
+	}
+
+	entry.Complete = true
+	return entry, nil
+}
+
+// stateDownload accumulates the key value pairs received in state responses
+// and keeps track of the start keys of the next state request.
+type stateDownload struct {
+	top      trie.Entries
+	children map[common.Hash]trie.Entries
+	// childKeys maps child trie roots to the top level keys they are stored at
+	childKeys map[common.Hash][][]byte
+
+	lastKey    []byte
+	childStart [][]byte
+	complete   bool
+}
+
+func newStateDownload() *stateDownload {
+	return &stateDownload{
+		children:  make(map[common.Hash]trie.Entries),
+		childKeys: make(map[common.Hash][][]byte),
+	}
+}
+
+// nextStart returns the start keys of the next state request
+func (d *stateDownload) nextStart() [][]byte {
+	if d.childStart != nil {
+		return d.childStart
+	}
+
+	if d.lastKey != nil {
+		return [][]byte{d.lastKey}
+	}
+
+	return nil
+}
+
+// importResponse adds the key value pairs of the response to the download.
+// The download is left untouched if the response is invalid.
+func (d *stateDownload) importResponse(response *messages.StateResponse) error {
+	if len(response.Entries) == 0 {
+		if len(response.Proof) > 0 {
+			return errProofOnlyStateResponses
+		}
+		return errEmptyStateResponse
+	}
+
+	imported := 0
+	topComplete := false
+	newChildKeys := make(map[common.Hash][][]byte)
+	for _, entry := range response.Entries {
+		imported += len(entry.StateEntries)
+		if !entry.StateRoot.IsEmpty() {
+			continue
+		}
+
+		topComplete = entry.Complete
+		for _, keyValue := range entry.StateEntries {
+			if bytes.HasPrefix(keyValue.Key, inmemory.ChildStorageKeyPrefix) {
+				childRoot := common.BytesToHash(keyValue.Value)
+				newChildKeys[childRoot] = append(newChildKeys[childRoot], keyValue.Key)
+			}
+		}
+	}
+
+	if imported == 0 && !topComplete {
+		return errEmptyStateResponse
+	}
+
+	for _, entry := range response.Entries {
+		if entry.StateRoot.IsEmpty() {
+			continue
+		}
+
+		_, known := d.childKeys[entry.StateRoot]
+		_, isNew := newChildKeys[entry.StateRoot]
+		if !known && !isNew {
+			return fmt.Errorf("%w: %s", errUnknownChildTrie, entry.StateRoot)
+		}
+	}
+
+	for childRoot, parentKeys := range newChildKeys {
+		d.childKeys[childRoot] = append(d.childKeys[childRoot], parentKeys...)
+	}
+
+	var childStart [][]byte
+	for _, entry := range response.Entries {
+		if entry.StateRoot.IsEmpty() {
+			d.top = append(d.top, entry.StateEntries...)
+			if len(entry.StateEntries) > 0 {
+				d.lastKey = entry.StateEntries[len(entry.StateEntries)-1].Key
+			}
+			continue
+		}
+
+		d.children[entry.StateRoot] = append(d.children[entry.StateRoot], entry.StateEntries...)
+		if entry.Complete {
+			continue
+		}
+
+		parentKeys := d.childKeys[entry.StateRoot]
+		parentKey := parentKeys[len(parentKeys)-1]
+		lastChildKey := []byte{}
+		if len(entry.StateEntries) > 0 {
+			lastChildKey = entry.StateEntries[len(entry.StateEntries)-1].Key
+		} else if d.childStart != nil && bytes.Equal(d.childStart[0], parentKey) {
+			lastChildKey = d.childStart[1]
+		}
+		childStart = [][]byte{parentKey, lastChildKey}
+	}
+
+	d.childStart = childStart
+	d.complete = topComplete && childStart == nil
+	return nil
+}
+
+// verifyResponse verifies the top level key value pairs of the response are
+// the ones following the current start key in the trie of the given state root,
+// using the proof of the response. The key value pairs of the child tries the
+// response completes are verified against the child trie roots, which are
+// themselves top level values. It must be called before importResponse.
+func (d *stateDownload) verifyResponse(stateRoot common.Hash, response *messages.StateResponse) error {
+	var encodedProofNodes [][]byte
+	err := scale.Unmarshal(response.Proof, &encodedProofNodes)
+	if err != nil {
+		return fmt.Errorf("decoding proof: %w", err)
+	}
+
+	var start []byte
+	if nextStart := d.nextStart(); nextStart != nil {
+		start = nextStart[0]
+	}
+
+	for _, entry := range response.Entries {
+		if entry.StateRoot.IsEmpty() {
+			err = proof.VerifyRange(encodedProofNodes, stateRoot[:], start, entry.StateEntries, entry.Complete)
+			if err != nil {
+				return fmt.Errorf("verifying top level entries: %w", err)
+			}
+			continue
+		}
+
+		if !entry.Complete {
+			continue
+		}
+
+		childEntries := append(slices.Clone(d.children[entry.StateRoot]), entry.StateEntries...)
+		err = verifyTrieRoot(entry.StateRoot, childEntries)
+		if err != nil {
+			return fmt.Errorf("verifying child trie entries: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// verifyTrieRoot checks the trie made of the given key value pairs has the
+// given root, with any of the trie layouts.
+func verifyTrieRoot(root common.Hash, entries trie.Entries) error {
+	for _, version := range []trie.TrieLayout{trie.V1, trie.V0} {
+		t, err := newTrieFromEntries(entries, version)
+		if err != nil {
+			return err
+		}
+
+		hash, err := t.Hash()
+		if err != nil {
+			return fmt.Errorf("hashing trie: %w", err)
+		}
+
+		if hash == root {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", errStateRootMismatch, root)
+}
+
+func newTrieFromEntries(entries trie.Entries, version trie.TrieLayout) (*inmemory.InMemoryTrie, error) {
+	t := inmemory.NewEmptyTrie()
+	t.SetVersion(version)
+
+	for _, keyValue := range entries {
+		err := t.Put(keyValue.Key, keyValue.Value)
+		if err != nil {
+			return nil, fmt.Errorf("putting key 0x%x: %w", keyValue.Key, err)
+		}
+	}
+
+	return t, nil
+}
+
+// buildTrie builds the trie from the downloaded key value pairs and checks its
+// root matches the given state root. Since the state version is only known to
+// the runtime, both trie layouts are tried.
+func (d *stateDownload) buildTrie(stateRoot common.Hash) (*inmemory.InMemoryTrie, error) {
+	for _, version := range []trie.TrieLayout{trie.V1, trie.V0} {
+		t, err := d.buildTrieWithVersion(version)
+		if err != nil {
+			return nil, err
+		}
+
+		root, err := t.Hash()
+		if err != nil {
+			return nil, fmt.Errorf("hashing trie: %w", err)
+		}
+
+		if root == stateRoot {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errStateRootMismatch, stateRoot)
+}
+
+func (d *stateDownload) buildTrieWithVersion(version trie.TrieLayout) (*inmemory.InMemoryTrie, error) {
+	t, err := newTrieFromEntries(d.top, version)
+	if err != nil {
+		return nil, err
+	}
+
+	for childRoot, entries := range d.children {
+		child, err := newTrieFromEntries(entries, version)
+		if err != nil {
+			return nil, fmt.Errorf("building child trie %s: %w", childRoot, err)
+		}
+
+		for _, parentKey := range d.childKeys[childRoot] {
+			keyToChild := bytes.TrimPrefix(parentKey, inmemory.ChildStorageKeyPrefix)
+			err := t.SetChild(keyToChild, child)
+			if err != nil {
+				return nil, fmt.Errorf("setting child trie at key 0x%x: %w", parentKey, err)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// stateSync downloads the state of the given block from our peers and
+// returns it once verified against the state root of the block header.
+func (cs *chainSync) stateSync(header *types.Header) (*rtstorage.TrieState, error) {
+	blockHash := header.Hash()
+	download := newStateDownload()
+
+	for !download.complete {
+		select {
+		case <-cs.stopCh:
+			return nil, errStateSyncStopped
+		default:
+		}
+
+		err := cs.requestState(header, download)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Debugf("📦 downloaded %d top level keys of state at block #%d (%s)",
+			len(download.top), header.Number, blockHash)
+	}
+
+	t, err := download.buildTrie(header.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("📦 downloaded state at block #%d (%s) with %d top level keys and %d child tries",
+		header.Number, blockHash, len(download.top), len(download.children))
+	return rtstorage.NewTrieState(t), nil
+}
+
+// storeWarpSyncState stores the downloaded state of the warp sync target
+// and the runtime instance to use to import its descendants.
+func (cs *chainSync) storeWarpSyncState(header *types.Header, trieState *rtstorage.TrieState,
+	runtimeInstance runtime.Instance) error {
+	cs.storageState.Lock()
+	defer cs.storageState.Unlock()
+
+	err := cs.storageState.StoreTrie(trieState, header)
+	if err != nil {
+		return fmt.Errorf("storing trie: %w", err)
+	}
+
+	blockHash := header.Hash()
+	cs.blockState.StoreRuntime(blockHash, runtimeInstance)
+	err = cs.blockState.HandleRuntimeChanges(trieState, runtimeInstance, blockHash)
+	if err != nil {
+		return fmt.Errorf("handling runtime changes: %w", err)
+	}
+
+	return nil
+}
+
+// requestState requests the next part of the state from our connected peers,
+// one at a time, until one of them answers with a valid response. Responses
+// are verified against their proof as they arrive, so an invalid response is
+// rejected without downloading the rest of the state.
+func (cs *chainSync) requestState(header *types.Header, download *stateDownload) error {
+	request := &messages.StateRequest{
+		Block: header.Hash(),
+		Start: download.nextStart(),
+	}
+
+	for _, who := range cs.network.AllConnectedPeersIDs() {
+		response := new(messages.StateResponse)
+		err := cs.stateRequestMaker.Do(who, request, response)
+		if err != nil {
+			logger.Debugf("requesting state from peer %s: %s", who, err)
+			continue
+		}
+
+		err = download.verifyResponse(header.StateRoot, response)
+		if err == nil {
+			err = download.importResponse(response)
+		}
+		if err != nil {
+			logger.Debugf("invalid state response from peer %s: %s", who, err)
+			cs.reportBadStateResponse(who)
+			continue
+		}
+
+		return nil
+	}
+
+	return errNoPeerProvidedState
+}
+
+func (cs *chainSync) reportBadStateResponse(who peer.ID) {
+	cs.network.ReportPeer(peerset.ReputationChange{
+		Value:  peerset.BadMessageValue,
+		Reason: peerset.BadMessageReason,
+	}, who)
+}
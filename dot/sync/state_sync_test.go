@@ -0,0 +1,294 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory/proof"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newStateSyncTestTrie(t *testing.T, version trie.TrieLayout) *inmemory.InMemoryTrie {
+	t.Helper()
+
+	tt := inmemory.NewEmptyTrie()
+	tt.SetVersion(version)
+
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key%03d", i))
+		value := []byte(fmt.Sprintf("value of some length to exceed inlined values %03d", i))
+		require.NoError(t, tt.Put(key, value))
+	}
+
+	for i := 0; i < 50; i++ {
+		key := []byte(fmt.Sprintf("child%03d", i))
+		require.NoError(t, tt.PutIntoChild([]byte("first"), key, []byte{byte(i)}))
+		require.NoError(t, tt.PutIntoChild([]byte("second"), key, []byte{byte(i), 1}))
+	}
+
+	return tt
+}
+
+func Test_stateDownload(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		version trie.TrieLayout
+		limit   int
+	}{
+		"v0_single_response": {
+			version: trie.V0,
+			limit:   maxStateResponseBytes,
+		},
+		"v1_single_response": {
+			version: trie.V1,
+			limit:   maxStateResponseBytes,
+		},
+		"v1_many_responses": {
+			version: trie.V1,
+			limit:   100,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := newStateSyncTestTrie(t, testCase.version)
+			stateRoot := source.MustHash()
+			db, err := database.NewPebble("", true)
+			require.NoError(t, err)
+			require.NoError(t, source.WriteDirty(db))
+
+			download := newStateDownload()
+			for requests := 0; !download.complete; requests++ {
+				require.Less(t, requests, 1000, "state download does not progress")
+
+				start := download.nextStart()
+				entries, err := collectStateEntries(source, start, testCase.limit)
+				require.NoError(t, err)
+
+				encodedProofNodes, err := proof.Generate(stateRoot[:],
+					stateProofKeys(source, start, entries[0].StateEntries), db)
+				require.NoError(t, err)
+				encodedProof, err := scale.Marshal(encodedProofNodes)
+				require.NoError(t, err)
+
+				encoded, err := (&messages.StateResponse{Entries: entries, Proof: encodedProof}).Encode()
+				require.NoError(t, err)
+
+				response := new(messages.StateResponse)
+				require.NoError(t, response.Decode(encoded))
+				require.NoError(t, download.verifyResponse(stateRoot, response))
+				require.NoError(t, download.importResponse(response))
+			}
+
+			downloaded, err := download.buildTrie(stateRoot)
+			require.NoError(t, err)
+			assert.Equal(t, stateRoot, downloaded.MustHash())
+			assert.Len(t, downloaded.GetChildTries(), 2)
+		})
+	}
+}
+
+func Test_stateDownload_verifyResponse(t *testing.T) {
+	t.Parallel()
+
+	source := newStateSyncTestTrie(t, trie.V1)
+	stateRoot := source.MustHash()
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	require.NoError(t, source.WriteDirty(db))
+
+	// newResponse returns a valid response holding the whole state,
+	// modified by the given function before its proof is generated.
+	newResponse := func(t *testing.T, modify func(entries []messages.KeyValueStateEntry)) *messages.StateResponse {
+		t.Helper()
+
+		entries, err := collectStateEntries(source, nil, maxStateResponseBytes)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		encodedProofNodes, err := proof.Generate(stateRoot[:],
+			stateProofKeys(source, nil, entries[0].StateEntries), db)
+		require.NoError(t, err)
+		encodedProof, err := scale.Marshal(encodedProofNodes)
+		require.NoError(t, err)
+
+		modify(entries)
+		return &messages.StateResponse{Entries: entries, Proof: encodedProof}
+	}
+
+	testCases := map[string]struct {
+		modify     func(entries []messages.KeyValueStateEntry)
+		errWrapped error
+	}{
+		"valid_response": {
+			modify: func([]messages.KeyValueStateEntry) {},
+		},
+		"invalid_top_level_value": {
+			modify: func(entries []messages.KeyValueStateEntry) {
+				entries[0].StateEntries[1].Value = []byte{1}
+			},
+			errWrapped: proof.ErrValueMismatchProofTrie,
+		},
+		"omitted_top_level_key": {
+			modify: func(entries []messages.KeyValueStateEntry) {
+				entries[0].StateEntries = slices.Delete(entries[0].StateEntries, 1, 2)
+			},
+			errWrapped: proof.ErrUnexpectedKey,
+		},
+		"incomplete_top_level": {
+			modify: func(entries []messages.KeyValueStateEntry) {
+				entries[0].StateEntries = entries[0].StateEntries[:10]
+			},
+			errWrapped: proof.ErrMissingKeys,
+		},
+		"invalid_child_value": {
+			modify: func(entries []messages.KeyValueStateEntry) {
+				entries[1].StateEntries[0].Value = []byte{9, 9}
+			},
+			errWrapped: errStateRootMismatch,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			response := newResponse(t, testCase.modify)
+
+			err := newStateDownload().verifyResponse(stateRoot, response)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
+
+func Test_stateDownload_importResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		response   *messages.StateResponse
+		errWrapped error
+		errMessage string
+	}{
+		"empty_response": {
+			response:   &messages.StateResponse{},
+			errWrapped: errEmptyStateResponse,
+			errMessage: "state response without any entry",
+		},
+		"proof_only_response": {
+			response:   &messages.StateResponse{Proof: []byte{1}},
+			errWrapped: errProofOnlyStateResponses,
+			errMessage: "state response contains a proof but no entries",
+		},
+		"no_progress": {
+			response: &messages.StateResponse{
+				Entries: []messages.KeyValueStateEntry{{}},
+			},
+			errWrapped: errEmptyStateResponse,
+			errMessage: "state response without any entry",
+		},
+		"unknown_child_trie": {
+			response: &messages.StateResponse{
+				Entries: []messages.KeyValueStateEntry{
+					{StateEntries: trie.Entries{{Key: []byte{1}, Value: []byte{2}}}},
+					{
+						StateRoot:    common.Hash{3},
+						StateEntries: trie.Entries{{Key: []byte{1}, Value: []byte{2}}},
+					},
+				},
+			},
+			errWrapped: errUnknownChildTrie,
+			errMessage: "state response contains entries of unknown child trie: " +
+				"0x0300000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			download := newStateDownload()
+			err := download.importResponse(testCase.response)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			assert.EqualError(t, err, testCase.errMessage)
+			assert.Empty(t, download.top)
+			assert.Nil(t, download.nextStart())
+		})
+	}
+}
+
+func Test_stateDownload_buildTrie_rootMismatch(t *testing.T) {
+	t.Parallel()
+
+	download := newStateDownload()
+	err := download.importResponse(&messages.StateResponse{
+		Entries: []messages.KeyValueStateEntry{{
+			StateEntries: trie.Entries{{Key: []byte{1}, Value: []byte{2}}},
+			Complete:     true,
+		}},
+	})
+	require.NoError(t, err)
+	require.True(t, download.complete)
+
+	_, err = download.buildTrie(common.Hash{1})
+	assert.ErrorIs(t, err, errStateRootMismatch)
+}
+
+func Test_Service_CreateStateResponse(t *testing.T) {
+	t.Parallel()
+
+	source := newStateSyncTestTrie(t, trie.V1)
+	header := types.NewHeader(common.Hash{}, source.MustHash(), common.Hash{}, 1, types.NewDigest())
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+	storageState := NewMockStorageState(ctrl)
+
+	stateRoot := header.StateRoot
+	blockState.EXPECT().GetHeader(header.Hash()).Return(header, nil)
+	storageState.EXPECT().TrieState(&stateRoot).Return(rtstorage.NewTrieState(source), nil)
+	storageState.EXPECT().GenerateTrieProof(stateRoot, gomock.Len(102)).Return([][]byte{{1}, {2}}, nil)
+
+	service := &Service{
+		blockState:   blockState,
+		storageState: storageState,
+	}
+
+	response, err := service.CreateStateResponse(&messages.StateRequest{Block: header.Hash()})
+	require.NoError(t, err)
+
+	require.Len(t, response.Entries, 3)
+	assert.True(t, response.Entries[0].Complete)
+	assert.Len(t, response.Entries[0].StateEntries, 102)
+	for _, child := range response.Entries[1:] {
+		assert.True(t, child.Complete)
+		assert.Len(t, child.StateEntries, 50)
+	}
+
+	expectedProof, err := scale.Marshal([][]byte{{1}, {2}})
+	require.NoError(t, err)
+	assert.Equal(t, expectedProof, response.Proof)
+
+	_, err = service.CreateStateResponse(&messages.StateRequest{Start: [][]byte{{1}, {2}, {3}}})
+	assert.ErrorIs(t, err, errTooManyStartKeys)
+}
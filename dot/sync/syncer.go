@@ -27,6 +27,9 @@ type Service struct {
 	chainSync  ChainSync
 	network    Network
 
+	grandpaState GrandpaState
+	storageState StorageState
+
 	seenBlockSyncRequests *lrucache.LRUCache[common.Hash, uint]
 }
 
@@ -50,6 +53,13 @@ type Config struct {
 	Telemetry          Telemetry
 	BadBlocks          []string
 	RequestMaker       network.RequestMaker
+
+	// WarpSync enables warp sync to the latest finalised block when the node is far behind.
+	WarpSync             bool
+	GrandpaState         GrandpaState
+	WarpSyncRequestMaker network.RequestMaker
+	// StateRequestMaker is used to download the state of the block reached by warp sync.
+	StateRequestMaker network.RequestMaker
 }
 
 // NewService returns a new *sync.Service
@@ -74,6 +84,11 @@ func NewService(cfg *Config) (*Service, error) {
 		badBlocks:          cfg.BadBlocks,
 		requestMaker:       cfg.RequestMaker,
 		waitPeersDuration:  100 * time.Millisecond,
+
+		warpSync:             cfg.WarpSync,
+		grandpaState:         cfg.GrandpaState,
+		warpSyncRequestMaker: cfg.WarpSyncRequestMaker,
+		stateRequestMaker:    cfg.StateRequestMaker,
 	}
 	chainSync := newChainSync(csCfg)
 
@@ -81,6 +96,8 @@ func NewService(cfg *Config) (*Service, error) {
 		blockState:            cfg.BlockState,
 		chainSync:             chainSync,
 		network:               cfg.Network,
+		grandpaState:          cfg.GrandpaState,
+		storageState:          cfg.StorageState,
 		seenBlockSyncRequests: lrucache.NewLRUCache[common.Hash, uint](100),
 	}, nil
 }
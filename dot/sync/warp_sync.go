@@ -0,0 +1,455 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	"github.com/ChainSafe/gossamer/dot/peerset"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	client_grandpa "github.com/ChainSafe/gossamer/internal/client/consensus/grandpa"
+	finality_grandpa "github.com/ChainSafe/gossamer/pkg/finality-grandpa"
+)
+
+var (
+	errWarpSyncBeginNotFinalised       = errors.New("warp sync begin block is not finalised")
+	errEmptyWarpSyncProof              = errors.New("empty warp sync proof")
+	errMissingAuthoritySetChange       = errors.New("header is missing authority set change digest")
+	errWarpSyncFragmentNotAscending    = errors.New("warp sync fragments are not in ascending order")
+	errMissingSetChangeJustification   = errors.New("missing justification for authority set change block")
+	errNoPeerProvidedWarpSyncProof     = errors.New("no peer provided a valid warp sync proof")
+	errWarpSyncFragmentBeforeFinalised = errors.New("warp sync fragment is not above our finalised block")
+)
+
+// CreateWarpSyncProof creates a warp sync proof starting at the given finalised block.
+// The proof contains one fragment per authority set change after the begin block, each
+// one being the block enacting the change and the justification finalising it, followed
+// by our latest finalised block if the whole proof fits in messages.MaxWarpSyncProofSize.
+func (s *Service) CreateWarpSyncProof(begin common.Hash) (*messages.WarpSyncProof, error) {
+	beginHeader, err := s.blockState.GetHeader(begin)
+	if err != nil {
+		return nil, fmt.Errorf("getting begin header: %w", err)
+	}
+
+	finalisedHeader, err := s.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return nil, fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	if beginHeader.Number > finalisedHeader.Number {
+		return nil, fmt.Errorf("%w: #%d (%s)", errWarpSyncBeginNotFinalised, beginHeader.Number, begin)
+	}
+
+	canonicalHash, err := s.blockState.GetHashByNumber(beginHeader.Number)
+	if err != nil {
+		return nil, fmt.Errorf("getting canonical hash by number: %w", err)
+	}
+
+	if canonicalHash != begin {
+		return nil, fmt.Errorf("%w: #%d (%s)", errWarpSyncBeginNotFinalised, beginHeader.Number, begin)
+	}
+
+	beginSetID, err := s.grandpaState.GetSetIDByBlockNumber(beginHeader.Number)
+	if err != nil {
+		return nil, fmt.Errorf("getting set id of begin block: %w", err)
+	}
+
+	currentSetID, err := s.grandpaState.GetCurrentSetID()
+	if err != nil {
+		return nil, fmt.Errorf("getting current set id: %w", err)
+	}
+
+	proof := &messages.WarpSyncProof{}
+	proofSize := 0
+	lastNumber := beginHeader.Number
+
+	for setID := beginSetID + 1; setID <= currentSetID; setID++ {
+		changeNumber, err := s.grandpaState.GetSetIDChange(setID)
+		if err != nil {
+			return nil, fmt.Errorf("getting block number of set id %d change: %w", setID, err)
+		}
+
+		if changeNumber <= lastNumber {
+			continue
+		}
+
+		header, err := s.blockState.GetHeaderByNumber(changeNumber)
+		if err != nil {
+			return nil, fmt.Errorf("getting header #%d: %w", changeNumber, err)
+		}
+
+		justification, err := s.blockState.GetJustification(header.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("%w: #%d (%s): %s",
+				errMissingSetChangeJustification, header.Number, header.Hash(), err)
+		}
+
+		fragment := messages.WarpSyncFragment{
+			Header:        *header,
+			Justification: justification,
+		}
+
+		fragmentSize, err := encodedFragmentSize(fragment)
+		if err != nil {
+			return nil, fmt.Errorf("encoding fragment for block #%d: %w", header.Number, err)
+		}
+
+		if proofSize+fragmentSize > messages.MaxWarpSyncProofSize {
+			return proof, nil
+		}
+
+		proof.Proofs = append(proof.Proofs, fragment)
+		proofSize += fragmentSize
+		lastNumber = header.Number
+	}
+
+	if finalisedHeader.Number > lastNumber {
+		justification, err := s.blockState.GetJustification(finalisedHeader.Hash())
+		if err == nil {
+			fragment := messages.WarpSyncFragment{
+				Header:        *finalisedHeader,
+				Justification: justification,
+			}
+
+			fragmentSize, err := encodedFragmentSize(fragment)
+			if err != nil {
+				return nil, fmt.Errorf("encoding fragment for block #%d: %w", finalisedHeader.Number, err)
+			}
+
+			if proofSize+fragmentSize > messages.MaxWarpSyncProofSize {
+				return proof, nil
+			}
+
+			proof.Proofs = append(proof.Proofs, fragment)
+		} else {
+			logger.Debugf("no justification for latest finalised block #%d (%s): %s",
+				finalisedHeader.Number, finalisedHeader.Hash(), err)
+		}
+	}
+
+	proof.IsFinished = true
+	return proof, nil
+}
+
+func encodedFragmentSize(fragment messages.WarpSyncFragment) (int, error) {
+	encodedHeader, err := scale.Marshal(fragment.Header)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(encodedHeader) + len(fragment.Justification), nil
+}
+
+// warpSyncFragment is a warp sync fragment whose justification has been verified
+type warpSyncFragment struct {
+	header        *types.Header
+	justification []byte
+	round         uint64
+	// setID is the id of the authority set which finalised the header
+	setID uint64
+	// nextAuthorities are the authorities scheduled in the header, or nil
+	// if the header does not contain an authority set change.
+	nextAuthorities []types.GrandpaVoter
+}
+
+// verifyWarpSyncProof verifies the fragments of a warp sync proof, starting with the given
+// authority set, and returns them in order. Every fragment except the last one of a finished
+// proof must schedule the authority set used to verify the next fragment.
+func verifyWarpSyncProof(proof *messages.WarpSyncProof, lastFinalised uint, setID uint64,
+	authorities []types.GrandpaVoter) ([]warpSyncFragment, error) {
+	if len(proof.Proofs) == 0 {
+		return nil, errEmptyWarpSyncProof
+	}
+
+	verified := make([]warpSyncFragment, len(proof.Proofs))
+	previousNumber := lastFinalised
+	for i := range proof.Proofs {
+		fragment := proof.Proofs[i]
+		header := &fragment.Header
+
+		if header.Number <= lastFinalised {
+			return nil, fmt.Errorf("%w: #%d", errWarpSyncFragmentBeforeFinalised, header.Number)
+		}
+
+		if i > 0 && header.Number <= previousNumber {
+			return nil, fmt.Errorf("%w: #%d after #%d", errWarpSyncFragmentNotAscending,
+				header.Number, previousNumber)
+		}
+
+		round, err := verifyFragmentJustification(header, fragment.Justification, setID, authorities)
+		if err != nil {
+			return nil, fmt.Errorf("verifying justification of block #%d (%s): %w",
+				header.Number, header.Hash(), err)
+		}
+
+		nextAuthorities, err := findScheduledChange(header)
+		if err != nil {
+			return nil, fmt.Errorf("finding scheduled change in block #%d: %w", header.Number, err)
+		}
+
+		isLast := i == len(proof.Proofs)-1
+		if nextAuthorities == nil && !(isLast && proof.IsFinished) {
+			return nil, fmt.Errorf("%w: #%d (%s)", errMissingAuthoritySetChange, header.Number, header.Hash())
+		}
+
+		verified[i] = warpSyncFragment{
+			header:          header,
+			justification:   fragment.Justification,
+			round:           round,
+			setID:           setID,
+			nextAuthorities: nextAuthorities,
+		}
+
+		if nextAuthorities != nil {
+			setID++
+			authorities = nextAuthorities
+		}
+		previousNumber = header.Number
+	}
+
+	return verified, nil
+}
+
+func verifyFragmentJustification(header *types.Header, justification []byte, setID uint64,
+	authorities []types.GrandpaVoter) (round uint64, err error) {
+	idsAndWeights := make([]finality_grandpa.IDWeight[string], len(authorities))
+	for idx, auth := range authorities {
+		idsAndWeights[idx] = finality_grandpa.IDWeight[string]{
+			ID:     string(auth.Key.Encode()),
+			Weight: 1,
+		}
+	}
+
+	voters := finality_grandpa.NewVoterSet(idsAndWeights)
+	if voters == nil {
+		return 0, fmt.Errorf("invalid authority set %d", setID)
+	}
+
+	target := client_grandpa.HashNumber[hash.H256, uint32]{
+		Hash:   hash.H256(header.Hash().ToBytes()),
+		Number: uint32(header.Number),
+	}
+
+	decoded, err := client_grandpa.DecodeGrandpaJustificationVerifyFinalizes[hash.H256, uint32, runtime.BlakeTwo256](
+		justification, target, setID, *voters)
+	if err != nil {
+		return 0, err
+	}
+
+	return decoded.Justification.Round, nil
+}
+
+// findScheduledChange returns the authorities of the GRANDPA scheduled change
+// contained in the header digest, or nil if there is none.
+func findScheduledChange(header *types.Header) ([]types.GrandpaVoter, error) {
+	for _, item := range header.Digest {
+		value, err := item.Value()
+		if err != nil {
+			return nil, fmt.Errorf("getting digest item value: %w", err)
+		}
+
+		consensusDigest, ok := value.(types.ConsensusDigest)
+		if !ok || consensusDigest.ConsensusEngineID != types.GrandpaEngineID {
+			continue
+		}
+
+		grandpaDigest := types.NewGrandpaConsensusDigest()
+		err = scale.Unmarshal(consensusDigest.Data, &grandpaDigest)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling grandpa consensus digest: %w", err)
+		}
+
+		grandpaValue, err := grandpaDigest.Value()
+		if err != nil {
+			return nil, fmt.Errorf("getting grandpa consensus digest value: %w", err)
+		}
+
+		scheduledChange, ok := grandpaValue.(types.GrandpaScheduledChange)
+		if !ok {
+			continue
+		}
+
+		return grandpaVotersFromAuthoritiesRaw(scheduledChange.Auths)
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// grandpaVotersFromAuthoritiesRaw returns the voters of the scheduled authorities.
+// Each key is copied before its public key is created, since the public key
+// references the bytes it is created from.
+func grandpaVotersFromAuthoritiesRaw(auths []types.GrandpaAuthoritiesRaw) ([]types.GrandpaVoter, error) {
+	voters := make([]types.GrandpaVoter, len(auths))
+	for i := range auths {
+		key := auths[i].Key
+		publicKey, err := ed25519.NewPublicKey(key[:])
+		if err != nil {
+			return nil, fmt.Errorf("creating public key of authority %d: %w", i, err)
+		}
+
+		voters[i] = types.GrandpaVoter{
+			Key: *publicKey,
+			ID:  auths[i].ID,
+		}
+	}
+
+	return voters, nil
+}
+
+// authoritySetChange is a verified authority set change of a warp sync proof
+type authoritySetChange struct {
+	setID       uint64
+	authorities []types.GrandpaVoter
+	number      uint
+}
+
+// warpSync requests warp sync proofs from our peers, starting at our highest
+// finalised block, until a peer reports its proof as finished. The state of the
+// last fragment is downloaded and the last fragment becomes our highest finalised
+// block, then the verified authority set changes are stored. They are only stored
+// once the target state is, so a failed warp sync leaves the current set id to the
+// full sync falling back on our own chain.
+func (cs *chainSync) warpSync() error {
+	finalisedHeader, err := cs.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	setID, err := cs.grandpaState.GetCurrentSetID()
+	if err != nil {
+		return fmt.Errorf("getting current set id: %w", err)
+	}
+
+	authorities, err := cs.grandpaState.GetAuthorities(setID)
+	if err != nil {
+		return fmt.Errorf("getting authorities of set id %d: %w", setID, err)
+	}
+
+	begin := finalisedHeader.Hash()
+	lastFinalised := finalisedHeader.Number
+	var target *warpSyncFragment
+	var setChanges []authoritySetChange
+
+	for {
+		select {
+		case <-cs.stopCh:
+			return nil
+		default:
+		}
+
+		fragments, finished, err := cs.requestWarpSyncProof(begin, lastFinalised, setID, authorities)
+		if err != nil {
+			return err
+		}
+
+		for i := range fragments {
+			fragment := fragments[i]
+			if fragment.nextAuthorities != nil {
+				setID = fragment.setID + 1
+				authorities = fragment.nextAuthorities
+				setChanges = append(setChanges, authoritySetChange{
+					setID:       setID,
+					authorities: authorities,
+					number:      fragment.header.Number,
+				})
+			}
+			target = &fragment
+		}
+
+		if target == nil {
+			logger.Info("⏩ warp sync found no finalised blocks above our own")
+			return nil
+		}
+
+		begin = target.header.Hash()
+		lastFinalised = target.header.Number
+		logger.Infof("⏩ warp sync verified %d fragments up to block #%d (%s), set id %d",
+			len(fragments), target.header.Number, begin.Short(), setID)
+
+		if finished {
+			break
+		}
+	}
+
+	// the runtime of our finalised block is reused for the target block,
+	// unless the downloaded state holds a different runtime code.
+	runtimeInstance, err := cs.blockState.GetRuntime(finalisedHeader.Hash())
+	if err != nil {
+		return fmt.Errorf("getting runtime of finalised block: %w", err)
+	}
+
+	trieState, err := cs.stateSync(target.header)
+	if err != nil {
+		return fmt.Errorf("syncing state of block #%d: %w", target.header.Number, err)
+	}
+
+	err = cs.blockState.SetWarpSyncTarget(target.header, target.justification, target.round, target.setID)
+	if err != nil {
+		return fmt.Errorf("setting warp sync target: %w", err)
+	}
+
+	err = cs.storeWarpSyncState(target.header, trieState, runtimeInstance)
+	if err != nil {
+		return fmt.Errorf("storing state of block #%d: %w", target.header.Number, err)
+	}
+
+	for _, change := range setChanges {
+		err = cs.grandpaState.AddAuthoritySetChange(change.setID, change.authorities, change.number)
+		if err != nil {
+			return fmt.Errorf("adding authority set change at block #%d: %w", change.number, err)
+		}
+	}
+
+	logger.Infof("⏩ warp sync finished at block #%d (%s)", target.header.Number, target.header.Hash())
+	return nil
+}
+
+// requestWarpSyncProof requests a warp sync proof from our connected peers, one at a
+// time, until one of them answers with a valid non empty proof.
+func (cs *chainSync) requestWarpSyncProof(begin common.Hash, lastFinalised uint, setID uint64,
+	authorities []types.GrandpaVoter) (fragments []warpSyncFragment, finished bool, err error) {
+	request := &messages.WarpProofRequest{Begin: begin}
+
+	for _, who := range cs.network.AllConnectedPeersIDs() {
+		proof := new(messages.WarpSyncProof)
+		err := cs.warpSyncRequestMaker.Do(who, request, proof)
+		if err != nil {
+			logger.Debugf("requesting warp sync proof from peer %s: %s", who, err)
+			continue
+		}
+
+		if len(proof.Proofs) == 0 && proof.IsFinished {
+			// the peer has nothing finalised above our begin block
+			return nil, true, nil
+		}
+
+		fragments, err := verifyWarpSyncProof(proof, lastFinalised, setID, authorities)
+		if err != nil {
+			logger.Debugf("invalid warp sync proof from peer %s: %s", who, err)
+			cs.reportBadWarpSyncProof(who)
+			continue
+		}
+
+		return fragments, proof.IsFinished, nil
+	}
+
+	return nil, false, errNoPeerProvidedWarpSyncProof
+}
+
+func (cs *chainSync) reportBadWarpSyncProof(who peer.ID) {
+	cs.network.ReportPeer(peerset.ReputationChange{
+		Value:  peerset.BadJustificationValue,
+		Reason: peerset.BadJustificationReason,
+	}, who)
+}
@@ -0,0 +1,346 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	"github.com/ChainSafe/gossamer/dot/types"
+	primitives "github.com/ChainSafe/gossamer/internal/primitives/consensus/grandpa"
+	"github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/internal/primitives/keyring/ed25519"
+	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
+	"github.com/ChainSafe/gossamer/lib/common"
+	libed25519 "github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	grandpa "github.com/ChainSafe/gossamer/pkg/finality-grandpa"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newWarpSyncVoters(t *testing.T, keyrings ...ed25519.Keyring) []types.GrandpaVoter {
+	t.Helper()
+
+	voters := make([]types.GrandpaVoter, len(keyrings))
+	for i, keyring := range keyrings {
+		public := keyring.Pair().Public().Bytes()
+		key, err := libed25519.NewPublicKey(public[:])
+		require.NoError(t, err)
+		voters[i] = types.GrandpaVoter{Key: *key, ID: 1}
+	}
+	return voters
+}
+
+func newWarpSyncHeader(t *testing.T, parent common.Hash, number uint,
+	nextAuthorities []types.GrandpaVoter) *types.Header {
+	t.Helper()
+
+	digest := types.NewDigest()
+	if nextAuthorities != nil {
+		auths := make([]types.GrandpaAuthoritiesRaw, len(nextAuthorities))
+		for i, voter := range nextAuthorities {
+			copy(auths[i].Key[:], voter.Key.Encode())
+			auths[i].ID = voter.ID
+		}
+
+		grandpaDigest := types.NewGrandpaConsensusDigest()
+		err := grandpaDigest.SetValue(types.GrandpaScheduledChange{Auths: auths})
+		require.NoError(t, err)
+		data, err := scale.Marshal(grandpaDigest)
+		require.NoError(t, err)
+
+		err = digest.Add(types.ConsensusDigest{
+			ConsensusEngineID: types.GrandpaEngineID,
+			Data:              data,
+		})
+		require.NoError(t, err)
+	}
+
+	return types.NewHeader(parent, common.Hash{0x01}, common.Hash{0x02}, number, digest)
+}
+
+func newWarpSyncJustification(t *testing.T, header *types.Header, round, setID uint64,
+	keyrings ...ed25519.Keyring) []byte {
+	t.Helper()
+
+	target := grandpa.Precommit[hash.H256, uint32]{
+		TargetHash:   hash.H256(header.Hash().ToBytes()),
+		TargetNumber: uint32(header.Number),
+	}
+
+	precommits := make([]grandpa.SignedPrecommit[hash.H256, uint32,
+		primitives.AuthoritySignature, primitives.AuthorityID], len(keyrings))
+	for i, keyring := range keyrings {
+		payload := primitives.NewLocalizedPayload(primitives.RoundNumber(round),
+			primitives.SetID(setID), grandpa.NewMessage(target))
+		precommits[i] = grandpa.SignedPrecommit[hash.H256, uint32,
+			primitives.AuthoritySignature, primitives.AuthorityID]{
+			Precommit: target,
+			Signature: keyring.Sign(payload),
+			ID:        keyring.Pair().Public().(primitives.AuthorityID),
+		}
+	}
+
+	justification := primitives.GrandpaJustification[hash.H256, uint32]{
+		Round: round,
+		Commit: primitives.Commit[hash.H256, uint32]{
+			TargetHash:   target.TargetHash,
+			TargetNumber: target.TargetNumber,
+			Precommits:   precommits,
+		},
+		VoteAncestries: []runtime.Header[uint32, hash.H256]{},
+	}
+
+	encoded, err := scale.Marshal(justification)
+	require.NoError(t, err)
+	return encoded
+}
+
+func Test_WarpSyncProof_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	setZero := newWarpSyncVoters(t, ed25519.Alice, ed25519.Bob)
+	setOne := newWarpSyncVoters(t, ed25519.Charlie)
+
+	changeHeader := newWarpSyncHeader(t, common.Hash{0xaa}, 10, setOne)
+	finalHeader := newWarpSyncHeader(t, changeHeader.Hash(), 20, nil)
+
+	proof := &messages.WarpSyncProof{
+		Proofs: []messages.WarpSyncFragment{
+			{
+				Header:        *changeHeader,
+				Justification: newWarpSyncJustification(t, changeHeader, 1, 0, ed25519.Alice, ed25519.Bob),
+			},
+			{
+				Header:        *finalHeader,
+				Justification: newWarpSyncJustification(t, finalHeader, 3, 1, ed25519.Charlie),
+			},
+		},
+		IsFinished: true,
+	}
+
+	encoded, err := proof.Encode()
+	require.NoError(t, err)
+
+	decoded := new(messages.WarpSyncProof)
+	err = decoded.Decode(encoded)
+	require.NoError(t, err)
+
+	require.Len(t, decoded.Proofs, 2)
+	assert.True(t, decoded.IsFinished)
+	for i := range proof.Proofs {
+		assert.Equal(t, proof.Proofs[i].Header.Hash(), decoded.Proofs[i].Header.Hash())
+		assert.Equal(t, proof.Proofs[i].Justification, decoded.Proofs[i].Justification)
+	}
+
+	fragments, err := verifyWarpSyncProof(decoded, 0, 0, setZero)
+	require.NoError(t, err)
+	require.Len(t, fragments, 2)
+}
+
+func Test_verifyWarpSyncProof(t *testing.T) {
+	t.Parallel()
+
+	setZero := newWarpSyncVoters(t, ed25519.Alice, ed25519.Bob)
+	setOne := newWarpSyncVoters(t, ed25519.Charlie, ed25519.Dave)
+
+	changeHeader := newWarpSyncHeader(t, common.Hash{0xaa}, 10, setOne)
+	changeJustification := newWarpSyncJustification(t, changeHeader, 1, 0, ed25519.Alice, ed25519.Bob)
+	finalHeader := newWarpSyncHeader(t, changeHeader.Hash(), 20, nil)
+	finalJustification := newWarpSyncJustification(t, finalHeader, 3, 1, ed25519.Charlie, ed25519.Dave)
+
+	testCases := map[string]struct {
+		proof         *messages.WarpSyncProof
+		lastFinalised uint
+		fragments     []warpSyncFragment
+		errWrapped    error
+		errMessage    string
+	}{
+		"empty_proof": {
+			proof:      &messages.WarpSyncProof{IsFinished: true},
+			errWrapped: errEmptyWarpSyncProof,
+			errMessage: "empty warp sync proof",
+		},
+		"set_change_then_finalised_block": {
+			proof: &messages.WarpSyncProof{
+				Proofs: []messages.WarpSyncFragment{
+					{Header: *changeHeader, Justification: changeJustification},
+					{Header: *finalHeader, Justification: finalJustification},
+				},
+				IsFinished: true,
+			},
+			fragments: []warpSyncFragment{
+				{
+					header:          changeHeader,
+					justification:   changeJustification,
+					round:           1,
+					setID:           0,
+					nextAuthorities: setOne,
+				},
+				{
+					header:        finalHeader,
+					justification: finalJustification,
+					round:         3,
+					setID:         1,
+				},
+			},
+		},
+		"unfinished_proof_without_set_change": {
+			proof: &messages.WarpSyncProof{
+				Proofs: []messages.WarpSyncFragment{
+					{Header: *changeHeader, Justification: changeJustification},
+					{Header: *finalHeader, Justification: finalJustification},
+				},
+			},
+			errWrapped: errMissingAuthoritySetChange,
+			errMessage: "header is missing authority set change digest: #20 (" +
+				finalHeader.Hash().String() + ")",
+		},
+		"justification_from_wrong_set": {
+			proof: &messages.WarpSyncProof{
+				Proofs: []messages.WarpSyncFragment{
+					{Header: *finalHeader, Justification: finalJustification},
+				},
+				IsFinished: true,
+			},
+			errMessage: "verifying justification of block #20 (" + finalHeader.Hash().String() +
+				"): bad justification for header: invalid commit in grandpa justification",
+		},
+		"fragment_below_finalised": {
+			proof: &messages.WarpSyncProof{
+				Proofs: []messages.WarpSyncFragment{
+					{Header: *changeHeader, Justification: changeJustification},
+				},
+			},
+			lastFinalised: 10,
+			errWrapped:    errWarpSyncFragmentBeforeFinalised,
+			errMessage:    "warp sync fragment is not above our finalised block: #10",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fragments, err := verifyWarpSyncProof(testCase.proof, testCase.lastFinalised, 0, setZero)
+
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+			}
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, fragments, len(testCase.fragments))
+			for i, expected := range testCase.fragments {
+				assert.Equal(t, expected.header.Hash(), fragments[i].header.Hash())
+				assert.Equal(t, expected.justification, fragments[i].justification)
+				assert.Equal(t, expected.round, fragments[i].round)
+				assert.Equal(t, expected.setID, fragments[i].setID)
+				assert.Equal(t, expected.nextAuthorities, fragments[i].nextAuthorities)
+			}
+		})
+	}
+}
+
+func Test_Service_CreateWarpSyncProof(t *testing.T) {
+	t.Parallel()
+
+	setOne := newWarpSyncVoters(t, ed25519.Charlie)
+	beginHeader := newWarpSyncHeader(t, common.Hash{}, 1, nil)
+	changeHeader := newWarpSyncHeader(t, beginHeader.Hash(), 10, setOne)
+	finalHeader := newWarpSyncHeader(t, changeHeader.Hash(), 20, nil)
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+	grandpaState := NewMockGrandpaState(ctrl)
+
+	blockState.EXPECT().GetHeader(beginHeader.Hash()).Return(beginHeader, nil)
+	blockState.EXPECT().GetHighestFinalisedHeader().Return(finalHeader, nil)
+	blockState.EXPECT().GetHashByNumber(uint(1)).Return(beginHeader.Hash(), nil)
+	grandpaState.EXPECT().GetSetIDByBlockNumber(uint(1)).Return(uint64(0), nil)
+	grandpaState.EXPECT().GetCurrentSetID().Return(uint64(1), nil)
+	grandpaState.EXPECT().GetSetIDChange(uint64(1)).Return(uint(10), nil)
+	blockState.EXPECT().GetHeaderByNumber(uint(10)).Return(changeHeader, nil)
+	blockState.EXPECT().GetJustification(changeHeader.Hash()).Return([]byte{1}, nil)
+	blockState.EXPECT().GetJustification(finalHeader.Hash()).Return([]byte{2}, nil)
+
+	service := &Service{
+		blockState:   blockState,
+		grandpaState: grandpaState,
+	}
+
+	proof, err := service.CreateWarpSyncProof(beginHeader.Hash())
+	require.NoError(t, err)
+
+	expected := &messages.WarpSyncProof{
+		Proofs: []messages.WarpSyncFragment{
+			{Header: *changeHeader, Justification: []byte{1}},
+			{Header: *finalHeader, Justification: []byte{2}},
+		},
+		IsFinished: true,
+	}
+	assert.Equal(t, expected, proof)
+}
+
+func Test_chainSync_warpSync_stateSyncFails(t *testing.T) {
+	t.Parallel()
+
+	setZero := newWarpSyncVoters(t, ed25519.Alice, ed25519.Bob)
+	setOne := newWarpSyncVoters(t, ed25519.Charlie, ed25519.Dave)
+
+	finalisedHeader := newWarpSyncHeader(t, common.Hash{}, 1, nil)
+	changeHeader := newWarpSyncHeader(t, finalisedHeader.Hash(), 10, setOne)
+	changeJustification := newWarpSyncJustification(t, changeHeader, 1, 0, ed25519.Alice, ed25519.Bob)
+	finalHeader := newWarpSyncHeader(t, changeHeader.Hash(), 20, nil)
+	finalJustification := newWarpSyncJustification(t, finalHeader, 3, 1, ed25519.Charlie, ed25519.Dave)
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHighestFinalisedHeader().Return(finalisedHeader, nil)
+	blockState.EXPECT().GetRuntime(finalisedHeader.Hash()).Return(NewMockInstance(ctrl), nil)
+
+	// the authority set change must not be stored, since the target state is not
+	grandpaState := NewMockGrandpaState(ctrl)
+	grandpaState.EXPECT().GetCurrentSetID().Return(uint64(0), nil)
+	grandpaState.EXPECT().GetAuthorities(uint64(0)).Return(setZero, nil)
+
+	const who = peer.ID("peer")
+	network := NewMockNetwork(ctrl)
+	network.EXPECT().AllConnectedPeersIDs().Return([]peer.ID{who}).Times(2)
+
+	warpSyncRequestMaker := NewMockRequestMaker(ctrl)
+	warpSyncRequestMaker.EXPECT().Do(who, &messages.WarpProofRequest{Begin: finalisedHeader.Hash()}, gomock.Any()).
+		DoAndReturn(func(_ peer.ID, _ messages.P2PMessage, response messages.P2PMessage) error {
+			*response.(*messages.WarpSyncProof) = messages.WarpSyncProof{
+				Proofs: []messages.WarpSyncFragment{
+					{Header: *changeHeader, Justification: changeJustification},
+					{Header: *finalHeader, Justification: finalJustification},
+				},
+				IsFinished: true,
+			}
+			return nil
+		})
+
+	stateRequestMaker := NewMockRequestMaker(ctrl)
+	stateRequestMaker.EXPECT().Do(who, gomock.Any(), gomock.Any()).Return(errors.New("test error"))
+
+	cs := &chainSync{
+		stopCh:               make(chan struct{}),
+		blockState:           blockState,
+		grandpaState:         grandpaState,
+		network:              network,
+		warpSyncRequestMaker: warpSyncRequestMaker,
+		stateRequestMaker:    stateRequestMaker,
+	}
+
+	err := cs.warpSync()
+	assert.ErrorIs(t, err, errNoPeerProvidedState)
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// NewAuraPreRuntimeDigest returns a PreRuntimeDigest with the Aura consensus ID
+// claiming the given slot.
+func NewAuraPreRuntimeDigest(slotNumber uint64) (*PreRuntimeDigest, error) {
+	data, err := scale.Marshal(slotNumber)
+	if err != nil {
+		return nil, fmt.Errorf("encoding slot number: %w", err)
+	}
+
+	return &PreRuntimeDigest{
+		ConsensusEngineID: AuraEngineID,
+		Data:              data,
+	}, nil
+}
+
+// DecodeAuraPreDigest decodes the slot number of an Aura pre-runtime digest
+func DecodeAuraPreDigest(in []byte) (slotNumber uint64, err error) {
+	err = scale.Unmarshal(in, &slotNumber)
+	if err != nil {
+		return 0, fmt.Errorf("decoding aura slot number: %w", err)
+	}
+
+	return slotNumber, nil
+}
+
+// AuraAuthorityRaw is the raw public key of an Aura authority
+type AuraAuthorityRaw [sr25519.PublicKeyLength]byte
+
+// DecodeAuraAuthorities decodes the Aura authorities returned by the
+// AuraApi_authorities runtime call.
+func DecodeAuraAuthorities(in []byte) ([]Authority, error) {
+	var raw []AuraAuthorityRaw
+	err := scale.Unmarshal(in, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("decoding authorities: %w", err)
+	}
+
+	authorities := make([]Authority, len(raw))
+	for i, key := range raw {
+		pub, err := sr25519.NewPublicKey(key[:])
+		if err != nil {
+			return nil, fmt.Errorf("decoding authority %d public key: %w", i, err)
+		}
+
+		authorities[i] = *NewAuthority(pub, 1)
+	}
+
+	return authorities, nil
+}
+
+// AuraAuthoritiesChange is the digest announcing a change of the Aura authorities.
+type AuraAuthoritiesChange struct {
+	Authorities []AuraAuthorityRaw
+}
+
+func (a AuraAuthoritiesChange) String() string {
+	return fmt.Sprintf("AuraAuthoritiesChange{Authorities=%x}", a.Authorities)
+}
+
+// AuraOnDisabled represents an Aura authority being disabled
+type AuraOnDisabled struct {
+	ID uint32
+}
+
+func (a AuraOnDisabled) String() string {
+	return fmt.Sprintf("AuraOnDisabled{ID=%d}", a.ID)
+}
+
+type AuraConsensusDigestValues interface {
+	AuraAuthoritiesChange | AuraOnDisabled
+}
+
+type AuraConsensusDigest struct {
+	inner any
+}
+
+func setAuraConsensusDigest[Value AuraConsensusDigestValues](mvdt *AuraConsensusDigest, value Value) {
+	mvdt.inner = value
+}
+
+func (mvdt *AuraConsensusDigest) SetValue(value any) (err error) {
+	switch value := value.(type) {
+	case AuraAuthoritiesChange:
+		setAuraConsensusDigest(mvdt, value)
+		return
+
+	case AuraOnDisabled:
+		setAuraConsensusDigest(mvdt, value)
+		return
+
+	default:
+		return fmt.Errorf("unsupported type")
+	}
+}
+
+func (mvdt AuraConsensusDigest) IndexValue() (index uint, value any, err error) {
+	switch mvdt.inner.(type) {
+	case AuraAuthoritiesChange:
+		return 1, mvdt.inner, nil
+
+	case AuraOnDisabled:
+		return 2, mvdt.inner, nil
+
+	}
+	return 0, nil, scale.ErrUnsupportedVaryingDataTypeValue
+}
+
+func (mvdt AuraConsensusDigest) Value() (value any, err error) {
+	_, value, err = mvdt.IndexValue()
+	return
+}
+func (mvdt AuraConsensusDigest) ValueAt(index uint) (value any, err error) {
+	switch index {
+	case 1:
+		return *new(AuraAuthoritiesChange), nil
+
+	case 2:
+		return *new(AuraOnDisabled), nil
+
+	}
+	return nil, scale.ErrUnknownVaryingDataTypeValue
+}
+
+// NewAuraConsensusDigest constructs a vdt representing an aura consensus digest
+func NewAuraConsensusDigest() AuraConsensusDigest {
+	return AuraConsensusDigest{}
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package types
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuraPreRuntimeDigest(t *testing.T) {
+	t.Parallel()
+
+	preDigest, err := NewAuraPreRuntimeDigest(99)
+	require.NoError(t, err)
+	require.Equal(t, AuraEngineID, preDigest.ConsensusEngineID)
+	require.Equal(t, common.MustHexToBytes("0x6300000000000000"), preDigest.Data)
+
+	slotNumber, err := DecodeAuraPreDigest(preDigest.Data)
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), slotNumber)
+
+	digest := NewDigest()
+	err = digest.Add(*preDigest)
+	require.NoError(t, err)
+
+	header := NewHeader(common.Hash{}, common.Hash{}, common.Hash{}, 1, digest)
+	slotNumber, err = header.SlotNumber()
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), slotNumber)
+	require.True(t, header.IsAuraBlock())
+
+	babeDigest := NewDigest()
+	err = babeDigest.Add(*NewBABEPreRuntimeDigest(common.MustHexToBytes("0x0201000000ef55a50f00000000")))
+	require.NoError(t, err)
+
+	babeHeader := NewHeader(common.Hash{}, common.Hash{}, common.Hash{}, 1, babeDigest)
+	require.False(t, babeHeader.IsAuraBlock())
+}
+
+func TestDecodeAuraAuthorities(t *testing.T) {
+	t.Parallel()
+
+	alice := common.MustHexToBytes("0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d")
+	pub, err := sr25519.NewPublicKey(alice)
+	require.NoError(t, err)
+
+	authorities, err := DecodeAuraAuthorities(append(common.MustHexToBytes("0x04"), alice...))
+	require.NoError(t, err)
+	require.Equal(t, []Authority{*NewAuthority(pub, 1)}, authorities)
+
+	_, err = DecodeAuraAuthorities(common.MustHexToBytes("0x04"))
+	require.EqualError(t, err, "decoding authorities: EOF")
+}
+
+func TestAuraConsensusDigest_EncodeAndDecode(t *testing.T) {
+	t.Parallel()
+
+	alice := common.MustHexToBytes("0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d")
+	expected := append(common.MustHexToBytes("0x0104"), alice...)
+
+	var authority AuraAuthorityRaw
+	copy(authority[:], alice)
+
+	digest := NewAuraConsensusDigest()
+	err := digest.SetValue(AuraAuthoritiesChange{
+		Authorities: []AuraAuthorityRaw{authority},
+	})
+	require.NoError(t, err)
+
+	enc, err := scale.Marshal(digest)
+	require.NoError(t, err)
+	require.Equal(t, expected, enc)
+
+	decoded := NewAuraConsensusDigest()
+	err = scale.Unmarshal(enc, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, digest, decoded)
+}
@@ -143,6 +143,9 @@ func (h ConsensusEngineID) String() string {
 // GrandpaEngineID is the hard-coded grandpa ID
 var GrandpaEngineID = ConsensusEngineID{'F', 'R', 'N', 'K'}
 
+// AuraEngineID is the hard-coded aura ID
+var AuraEngineID = ConsensusEngineID{'a', 'u', 'r', 'a'}
+
 // PreRuntimeDigest contains messages from the consensus engine to the runtime.
 type PreRuntimeDigest digestItem
 
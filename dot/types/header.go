@@ -100,6 +100,23 @@ func (bh *Header) Hash() common.Hash {
 	return bh.hash
 }
 
+// IsAuraBlock returns true if the header has an aura pre-runtime digest
+func (bh *Header) IsAuraBlock() bool {
+	for _, item := range bh.Digest {
+		value, err := item.Value()
+		if err != nil {
+			continue
+		}
+
+		preRuntimeDigest, ok := value.(PreRuntimeDigest)
+		if ok && preRuntimeDigest.ConsensusEngineID == AuraEngineID {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (bh *Header) SlotNumber() (uint64, error) {
 	for _, d := range bh.Digest {
 		digestValue, err := d.Value()
@@ -111,6 +128,10 @@ func (bh *Header) SlotNumber() (uint64, error) {
 			continue
 		}
 
+		if predigest.ConsensusEngineID == AuraEngineID {
+			return DecodeAuraPreDigest(predigest.Data)
+		}
+
 		digest, err := DecodeBabePreDigest(predigest.Data)
 		if err != nil {
 			return 0, fmt.Errorf("failed to decode babe header: %w", err)
@@ -27,6 +27,8 @@
 	Parachn0
 	// Newheads is an inherent key for new minimally-attested parachain heads.
 	Newheads
+	// Auraslot is the Aura inherent identifier.
+	Auraslot
 )
 
 // Bytes returns a byte array of given inherent identifier.
@@ -44,6 +46,8 @@ func (ii InherentIdentifier) Bytes() [8]byte {
 		copy(kb[:], []byte("parachn0"))
 	case Newheads:
 		copy(kb[:], []byte("newheads"))
+	case Auraslot:
+		copy(kb[:], []byte("auraslot"))
 	default:
 		panic("invalid inherent identifier")
 	}
@@ -0,0 +1,261 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package aura
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+)
+
+var logger = log.NewFromGlobal(log.AddContext("pkg", "aura"))
+
+// Service authors blocks with Aura: the authorities returned by the runtime take
+// turns to author a block in each slot, in a round-robin fashion.
+type Service struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	authority bool
+
+	slotDuration time.Duration
+
+	// Storage interfaces
+	blockState       BlockState
+	storageState     StorageState
+	transactionState TransactionState
+
+	blockImportHandler BlockImportHandler
+
+	// Aura authority keypair
+	keypair *sr25519.Keypair
+
+	// State variables
+	sync.Mutex
+	pause chan struct{}
+	wg    sync.WaitGroup
+}
+
+// ServiceConfig represents an Aura configuration
+type ServiceConfig struct {
+	LogLvl             log.Level
+	BlockState         BlockState
+	StorageState       StorageState
+	TransactionState   TransactionState
+	BlockImportHandler BlockImportHandler
+	Keypair            *sr25519.Keypair
+	SlotDuration       time.Duration
+	Authority          bool
+}
+
+// NewService returns a new Aura Service
+func NewService(cfg *ServiceConfig) (*Service, error) {
+	if cfg.Keypair == nil && cfg.Authority {
+		return nil, errNoAuthorityKeyProvided
+	}
+
+	logger.Patch(log.SetLevel(cfg.LogLvl))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	auraService := &Service{
+		ctx:                ctx,
+		cancel:             cancel,
+		authority:          cfg.Authority,
+		slotDuration:       cfg.SlotDuration,
+		blockState:         cfg.BlockState,
+		storageState:       cfg.StorageState,
+		transactionState:   cfg.TransactionState,
+		blockImportHandler: cfg.BlockImportHandler,
+		keypair:            cfg.Keypair,
+		pause:              make(chan struct{}),
+	}
+
+	logger.Debugf("created service with block producer ID=%v and slot duration %s",
+		cfg.Authority, cfg.SlotDuration)
+
+	return auraService, nil
+}
+
+// Start starts Aura block authoring
+func (s *Service) Start() error {
+	if !s.authority {
+		return nil
+	}
+
+	s.wg.Add(1)
+	go s.run(s.pause)
+	return nil
+}
+
+// Stop stops the service. If stop is called, it cannot be resumed.
+func (s *Service) Stop() error {
+	if !s.authority {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.ctx.Err() != nil {
+		return errServiceStopped
+	}
+
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// SlotDuration returns the slot duration in milliseconds
+func (s *Service) SlotDuration() uint64 {
+	return uint64(s.slotDuration.Milliseconds())
+}
+
+// EpochLength returns 0 since Aura does not have epochs
+func (*Service) EpochLength() uint64 {
+	return 0
+}
+
+// Pause pauses the service ie. halts block production
+func (s *Service) Pause() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.IsPaused() {
+		return nil
+	}
+
+	close(s.pause)
+	s.wg.Wait()
+	return nil
+}
+
+// Resume resumes the service ie. resumes block production
+func (s *Service) Resume() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.IsPaused() {
+		return nil
+	}
+
+	s.pause = make(chan struct{})
+	if s.authority {
+		s.wg.Add(1)
+		go s.run(s.pause)
+	}
+
+	logger.Debug("service resumed")
+	return nil
+}
+
+// IsPaused returns if the service is paused or not (ie. producing blocks)
+func (s *Service) IsPaused() bool {
+	select {
+	case <-s.pause:
+		return true
+	default:
+		return false
+	}
+}
+
+// run authors blocks for each slot of the node until the service is
+// stopped or the given pause channel is closed.
+func (s *Service) run(pause <-chan struct{}) {
+	defer s.wg.Done()
+
+	for {
+		slot := newSlot(getCurrentSlot(s.slotDuration)+1, s.slotDuration)
+
+		timer := time.NewTimer(time.Until(slot.start))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-pause:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		err := s.handleSlot(slot)
+		if errors.Is(err, errNotSlotAuthor) {
+			logger.Tracef("not authoring %s: %s", slot, err)
+		} else if err != nil {
+			logger.Warnf("failed to author block for %s: %s", slot, err)
+		}
+	}
+}
+
+// handleSlot builds and imports a block on top of the best block if the
+// node is the author of the given slot.
+func (s *Service) handleSlot(slot Slot) error {
+	parent, err := s.blockState.BestBlockHeader()
+	if err != nil {
+		return fmt.Errorf("getting best block header: %w", err)
+	}
+
+	if parent.Number > 0 {
+		parentSlot, err := parent.SlotNumber()
+		if err != nil {
+			return fmt.Errorf("getting slot of best block: %w", err)
+		}
+
+		if parentSlot >= slot.number {
+			return fmt.Errorf("%w: best block slot is %d and got slot %d",
+				errLaggingSlot, parentSlot, slot.number)
+		}
+	}
+
+	s.storageState.Lock()
+	defer s.storageState.Unlock()
+
+	ts, err := s.storageState.TrieState(&parent.StateRoot)
+	if err != nil {
+		return fmt.Errorf("getting parent trie state: %w", err)
+	}
+
+	rt, err := s.blockState.GetRuntime(parent.Hash())
+	if err != nil {
+		return fmt.Errorf("getting parent runtime: %w", err)
+	}
+
+	authorities, err := readAuthorities(rt, ts)
+	if err != nil {
+		return fmt.Errorf("getting aura authorities: %w", err)
+	}
+
+	author, err := slotAuthor(slot.number, authorities)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(author.Key.Encode(), s.keypair.Public().Encode()) {
+		return fmt.Errorf("%w: author is %s", errNotSlotAuthor, author.Key.Hex())
+	}
+
+	// set runtime trie before building block, like babe does, so the
+	// resulting trie is stored in the storage state when the block is imported
+	rt.SetContextStorage(ts)
+
+	block, err := s.buildBlock(parent, slot, rt)
+	if err != nil {
+		return fmt.Errorf("building block: %w", err)
+	}
+
+	logger.Infof("built block %d with hash %s, state root %s and slot %d",
+		block.Header.Number, block.Header.Hash(), block.Header.StateRoot, slot.number)
+
+	err = s.blockImportHandler.HandleBlockProduced(block, ts)
+	if err != nil {
+		return fmt.Errorf("importing built block: %w", err)
+	}
+
+	return nil
+}
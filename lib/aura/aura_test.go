@@ -0,0 +1,157 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package aura
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Service_handleSlot(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newTestKeypairs(t)
+	encodedAuthorities := encodeTestAuthorities(t, alice, bob)
+
+	slot := aliceSlot()
+	genesis := types.NewHeader(common.Hash{}, common.Hash{3}, common.Hash{}, 0, types.NewDigest())
+	parent := newTestHeader(t, genesis.Hash(), 1, slot-1)
+
+	testCases := map[string]struct {
+		slot              uint64
+		expectAuthorities bool
+		author            bool
+		errWrapped        error
+	}{
+		"lagging_slot": {
+			slot:       slot - 1,
+			errWrapped: errLaggingSlot,
+		},
+		"not_slot_author": {
+			slot:              slot + 1,
+			expectAuthorities: true,
+			errWrapped:        errNotSlotAuthor,
+		},
+		"slot_author": {
+			slot:              slot,
+			expectAuthorities: true,
+			author:            true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			blockState := NewMockBlockState(ctrl)
+			blockState.EXPECT().BestBlockHeader().Return(parent, nil)
+
+			storageState := NewMockStorageState(ctrl)
+			transactionState := NewMockTransactionState(ctrl)
+			blockImportHandler := NewMockBlockImportHandler(ctrl)
+
+			trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+			instance := mocks.NewMockInstance(ctrl)
+			if testCase.expectAuthorities {
+				storageState.EXPECT().Lock()
+				storageState.EXPECT().TrieState(&parent.StateRoot).Return(trieState, nil)
+				storageState.EXPECT().Unlock()
+				blockState.EXPECT().GetRuntime(parent.Hash()).Return(instance, nil)
+				instance.EXPECT().ExecWithStorage(trieState, runtime.AuraAPIAuthorities, []byte{}).
+					Return(encodedAuthorities, nil)
+			}
+
+			var produced *types.Block
+			if testCase.author {
+				instance.EXPECT().SetContextStorage(trieState)
+				instance.EXPECT().InitializeBlock(gomock.Any()).
+					DoAndReturn(func(header *types.Header) error {
+						assert.Equal(t, parent.Hash(), header.ParentHash)
+						assert.Equal(t, uint(2), header.Number)
+						return nil
+					})
+
+				inherent := []byte{1, 2, 3}
+				encodedInherents, err := scale.Marshal([][]byte{inherent})
+				require.NoError(t, err)
+				instance.EXPECT().InherentExtrinsics(gomock.Any()).Return(encodedInherents, nil)
+				encodedInherent, err := scale.Marshal(inherent)
+				require.NoError(t, err)
+				instance.EXPECT().ApplyExtrinsic(types.Extrinsic(encodedInherent)).Return([]byte{0, 0}, nil)
+
+				transactionState.EXPECT().PopWithTimer(gomock.Any()).Return(nil)
+
+				finalised := newTestHeader(t, parent.Hash(), 2, slot)
+				finalised.StateRoot = common.Hash{4}
+				instance.EXPECT().FinalizeBlock().Return(finalised, nil)
+
+				blockImportHandler.EXPECT().HandleBlockProduced(gomock.Any(), trieState).
+					DoAndReturn(func(block *types.Block, _ *rtstorage.TrieState) error {
+						produced = block
+						return nil
+					})
+			}
+
+			service, err := NewService(&ServiceConfig{
+				BlockState:         blockState,
+				StorageState:       storageState,
+				TransactionState:   transactionState,
+				BlockImportHandler: blockImportHandler,
+				Keypair:            alice,
+				SlotDuration:       testSlotDuration,
+				Authority:          true,
+			})
+			require.NoError(t, err)
+
+			err = service.handleSlot(newSlot(testCase.slot, testSlotDuration))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if !testCase.author {
+				return
+			}
+
+			require.NotNil(t, produced)
+			assert.Equal(t, types.Body{{1, 2, 3}}, produced.Body)
+
+			producedSlot, seal, err := auraDigests(&produced.Header)
+			require.NoError(t, err)
+			assert.Equal(t, slot, producedSlot)
+
+			hash := hashWithDigest(&produced.Header, produced.Header.Digest[:len(produced.Header.Digest)-1])
+			ok, err := alice.Public().Verify(hash[:], seal.Data)
+			require.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func Test_Service_PauseResume(t *testing.T) {
+	t.Parallel()
+
+	service, err := NewService(&ServiceConfig{
+		SlotDuration: time.Hour,
+	})
+	require.NoError(t, err)
+
+	err = service.Pause()
+	require.NoError(t, err)
+	assert.True(t, service.IsPaused())
+
+	err = service.Resume()
+	require.NoError(t, err)
+	assert.False(t, service.IsPaused())
+}
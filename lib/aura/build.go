@@ -0,0 +1,192 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package aura
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// buildBlock constructs a block for the given slot on top of the given parent,
+// using the runtime instance set on the parent state.
+func (s *Service) buildBlock(parent *types.Header, slot Slot, rt runtime.Instance) (*types.Block, error) {
+	preRuntimeDigest, err := types.NewAuraPreRuntimeDigest(slot.number)
+	if err != nil {
+		return nil, fmt.Errorf("creating pre-runtime digest: %w", err)
+	}
+
+	digest := types.NewDigest()
+	err = digest.Add(*preRuntimeDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	header := types.NewHeader(parent.Hash(), common.Hash{}, common.Hash{}, parent.Number+1, digest)
+	err = rt.InitializeBlock(header)
+	if err != nil {
+		return nil, fmt.Errorf("initialising block: %w", err)
+	}
+
+	inherents, err := buildBlockInherents(slot, rt)
+	if err != nil {
+		return nil, fmt.Errorf("building inherents: %w", err)
+	}
+
+	included := s.buildBlockExtrinsics(slot, rt)
+
+	header, err = rt.FinalizeBlock()
+	if err != nil {
+		s.addToQueue(included)
+		return nil, fmt.Errorf("finalising block: %w", err)
+	}
+
+	seal, err := s.buildBlockSeal(header)
+	if err != nil {
+		return nil, fmt.Errorf("sealing block: %w", err)
+	}
+
+	err = header.Digest.Add(*seal)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := extrinsicsToBody(inherents, included)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Block{
+		Header: *header,
+		Body:   body,
+	}, nil
+}
+
+// buildBlockSeal creates the seal of the block header, which is the signature
+// of the hash of the header by the block author.
+func (s *Service) buildBlockSeal(header *types.Header) (*types.SealDigest, error) {
+	hash := hashWithDigest(header, header.Digest)
+	sig, err := s.keypair.Sign(hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SealDigest{
+		ConsensusEngineID: types.AuraEngineID,
+		Data:              sig,
+	}, nil
+}
+
+// buildBlockExtrinsics applies extrinsics from the transaction queue to the block
+// until two thirds of the slot have passed, and returns the included extrinsics.
+func (s *Service) buildBlockExtrinsics(slot Slot, rt runtime.Instance) []*transaction.ValidTransaction {
+	var included []*transaction.ValidTransaction
+
+	slotEnd := slot.start.Add(slot.duration * 2 / 3) // reserve last 1/3 of slot for block finalisation
+	slotTimer := time.NewTimer(time.Until(slotEnd))
+	defer slotTimer.Stop()
+
+	for {
+		txn := s.transactionState.PopWithTimer(slotTimer.C)
+		if txn == nil {
+			break
+		}
+
+		ret, err := rt.ApplyExtrinsic(txn.Extrinsic)
+		if err != nil {
+			logger.Warnf("applying extrinsic %s: %s", txn.Extrinsic, err)
+			continue
+		}
+
+		// the extrinsic is included unless it is an invalid transaction,
+		// including when its call dispatch fails.
+		if len(ret) == 0 || ret[0] != 0 {
+			logger.Debugf("dropping invalid extrinsic %s: apply result 0x%x", txn.Extrinsic, ret)
+			continue
+		}
+
+		included = append(included, txn)
+	}
+
+	return included
+}
+
+func buildBlockInherents(slot Slot, rt runtime.Instance) ([][]byte, error) {
+	idata := types.NewInherentData()
+	err := idata.SetInherent(types.Timstap0, uint64(slot.start.UnixMilli()))
+	if err != nil {
+		return nil, fmt.Errorf("setting inherent %q: %w", types.Timstap0, err)
+	}
+
+	err = idata.SetInherent(types.Auraslot, slot.number)
+	if err != nil {
+		return nil, fmt.Errorf("setting inherent %q: %w", types.Auraslot, err)
+	}
+
+	ienc, err := idata.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	inherentExts, err := rt.InherentExtrinsics(ienc)
+	if err != nil {
+		return nil, err
+	}
+
+	var exts [][]byte
+	err = scale.Unmarshal(inherentExts, &exts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ext := range exts {
+		in, err := scale.Marshal(ext)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := rt.ApplyExtrinsic(in)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(ret, []byte{0, 0}) {
+			return nil, fmt.Errorf("%w: 0x%x", errInvalidInherentApplyRet, ret)
+		}
+	}
+
+	return exts, nil
+}
+
+func (s *Service) addToQueue(txs []*transaction.ValidTransaction) {
+	for _, t := range txs {
+		hash, err := s.transactionState.Push(t)
+		if err != nil {
+			logger.Tracef("Failed to add transaction to queue: %s", err)
+		} else {
+			logger.Tracef("Added transaction with hash %s to queue", hash)
+		}
+	}
+}
+
+func extrinsicsToBody(inherents [][]byte, txs []*transaction.ValidTransaction) (types.Body, error) {
+	extrinsics := types.BytesArrayToExtrinsics(inherents)
+
+	for _, tx := range txs {
+		var decExt []byte
+		err := scale.Unmarshal(tx.Extrinsic, &decExt)
+		if err != nil {
+			return nil, err
+		}
+		extrinsics = append(extrinsics, decExt)
+	}
+
+	return types.Body(extrinsics), nil
+}
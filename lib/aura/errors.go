@@ -0,0 +1,29 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package aura
+
+import "errors"
+
+var (
+	// ErrNoAuthorities is returned when the runtime has no Aura authorities
+	ErrNoAuthorities = errors.New("no aura authorities")
+
+	// ErrBadSignature is returned when a seal is invalid
+	ErrBadSignature = errors.New("could not verify signature")
+
+	// ErrFutureSlot is returned when a block is produced for a slot that has not started yet
+	ErrFutureSlot = errors.New("block slot is in the future")
+
+	// ErrSlotNotIncreasing is returned when a block slot is not greater than the slot of its parent
+	ErrSlotNotIncreasing = errors.New("block slot is not greater than parent slot")
+
+	errMissingDigestItems      = errors.New("block header is missing digest items")
+	errFirstDigestItemNotAura  = errors.New("first digest item is not an aura pre-runtime digest")
+	errLastDigestItemNotSeal   = errors.New("last digest item is not an aura seal")
+	errNoAuthorityKeyProvided  = errors.New("cannot create aura service as authority; no keypair provided")
+	errNotSlotAuthor           = errors.New("not the author of the slot")
+	errLaggingSlot             = errors.New("cannot claim slot, current slot is smaller than slot of best block")
+	errInvalidInherentApplyRet = errors.New("invalid inherent apply result")
+	errServiceStopped          = errors.New("service already stopped")
+)
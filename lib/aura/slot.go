@@ -0,0 +1,50 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package aura
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+)
+
+// Slot represents an Aura slot
+type Slot struct {
+	start    time.Time
+	duration time.Duration
+	number   uint64
+}
+
+// newSlot returns the slot with the given number, starting at the
+// multiple of the slot duration since the Unix epoch.
+func newSlot(number uint64, duration time.Duration) Slot {
+	return Slot{
+		start:    getSlotStartTime(number, duration),
+		duration: duration,
+		number:   number,
+	}
+}
+
+func getCurrentSlot(slotDuration time.Duration) uint64 {
+	return uint64(time.Now().UnixNano()) / uint64(slotDuration.Nanoseconds())
+}
+
+func getSlotStartTime(slot uint64, slotDuration time.Duration) time.Time {
+	return time.Unix(0, int64(slot)*slotDuration.Nanoseconds())
+}
+
+// slotAuthor returns the authority expected to author the given slot,
+// the authorities taking turns in a round-robin fashion.
+func slotAuthor(slot uint64, authorities []types.Authority) (*types.Authority, error) {
+	if len(authorities) == 0 {
+		return nil, ErrNoAuthorities
+	}
+
+	return &authorities[slot%uint64(len(authorities))], nil
+}
+
+func (s Slot) String() string {
+	return fmt.Sprintf("slot %d starting at %s", s.number, s.start)
+}
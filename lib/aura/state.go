@@ -0,0 +1,39 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package aura
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+)
+
+// BlockState interface for block state methods
+type BlockState interface {
+	BestBlockHeader() (*types.Header, error)
+	GetHeader(common.Hash) (*types.Header, error)
+	GetRuntime(blockHash common.Hash) (runtime runtime.Instance, err error)
+}
+
+// StorageState interface for storage state methods
+type StorageState interface {
+	TrieState(hash *common.Hash) (*rtstorage.TrieState, error)
+	sync.Locker
+}
+
+// TransactionState is the interface for transaction queue methods
+type TransactionState interface {
+	Push(vt *transaction.ValidTransaction) (common.Hash, error)
+	PopWithTimer(timerCh <-chan time.Time) (tx *transaction.ValidTransaction)
+}
+
+// BlockImportHandler is the interface for the handler of newly produced blocks
+type BlockImportHandler interface {
+	HandleBlockProduced(block *types.Block, state *rtstorage.TrieState) error
+}
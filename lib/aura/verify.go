@@ -0,0 +1,158 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package aura
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+)
+
+// Verifier verifies the Aura slot claim and seal of block headers
+type Verifier struct {
+	blockState   BlockState
+	storageState StorageState
+	slotDuration time.Duration
+}
+
+// NewVerifier returns a new Aura Verifier
+func NewVerifier(blockState BlockState, storageState StorageState, slotDuration time.Duration) *Verifier {
+	return &Verifier{
+		blockState:   blockState,
+		storageState: storageState,
+		slotDuration: slotDuration,
+	}
+}
+
+// VerifyBlock verifies that the block was sealed by the Aura authority of its slot,
+// taken from the runtime at the parent block, and that its slot is greater than
+// the parent slot and not in the future.
+func (v *Verifier) VerifyBlock(header *types.Header) error {
+	slot, seal, err := auraDigests(header)
+	if err != nil {
+		return err
+	}
+
+	currentSlot := getCurrentSlot(v.slotDuration)
+	if slot > currentSlot {
+		return fmt.Errorf("%w: block slot is %d and current slot is %d", ErrFutureSlot, slot, currentSlot)
+	}
+
+	parent, err := v.blockState.GetHeader(header.ParentHash)
+	if err != nil {
+		return fmt.Errorf("getting parent header: %w", err)
+	}
+
+	if parent.Number > 0 {
+		parentSlot, err := parent.SlotNumber()
+		if err != nil {
+			return fmt.Errorf("getting parent slot: %w", err)
+		}
+
+		if slot <= parentSlot {
+			return fmt.Errorf("%w: block slot is %d and parent slot is %d", ErrSlotNotIncreasing, slot, parentSlot)
+		}
+	}
+
+	authorities, err := v.authorities(parent)
+	if err != nil {
+		return fmt.Errorf("getting authorities at parent block: %w", err)
+	}
+
+	author, err := slotAuthor(slot, authorities)
+	if err != nil {
+		return err
+	}
+
+	hash := hashWithDigest(header, header.Digest[:len(header.Digest)-1])
+	ok, err := author.Key.Verify(hash[:], seal.Data)
+	if err != nil {
+		return fmt.Errorf("verifying seal: %w", err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: for block %s and author %s", ErrBadSignature, header.Hash(), author.Key.Hex())
+	}
+
+	return nil
+}
+
+// authorities returns the Aura authorities from the runtime at the given block
+func (v *Verifier) authorities(header *types.Header) ([]types.Authority, error) {
+	v.storageState.Lock()
+	ts, err := v.storageState.TrieState(&header.StateRoot)
+	v.storageState.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("getting trie state: %w", err)
+	}
+
+	rt, err := v.blockState.GetRuntime(header.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("getting runtime: %w", err)
+	}
+
+	return readAuthorities(rt, ts)
+}
+
+// readAuthorities reads the Aura authorities from the given trie state on an
+// isolated instance of the runtime, leaving the storage of the runtime and the
+// trie state unchanged.
+func readAuthorities(rt runtime.Instance, ts *rtstorage.TrieState) ([]types.Authority, error) {
+	ts.StartTransaction()
+	defer ts.RollbackTransaction()
+
+	ret, err := rt.ExecWithStorage(ts, runtime.AuraAPIAuthorities, []byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	return types.DecodeAuraAuthorities(ret)
+}
+
+// auraDigests returns the slot claimed by the Aura pre-runtime digest of the
+// header, which must be its first digest item, and the Aura seal of the header,
+// which must be its last digest item.
+func auraDigests(header *types.Header) (slot uint64, seal *types.SealDigest, err error) {
+	if len(header.Digest) < 2 {
+		return 0, nil, errMissingDigestItems
+	}
+
+	preDigestValue, err := header.Digest[0].Value()
+	if err != nil {
+		return 0, nil, fmt.Errorf("getting pre digest item value: %w", err)
+	}
+
+	preDigest, ok := preDigestValue.(types.PreRuntimeDigest)
+	if !ok || preDigest.ConsensusEngineID != types.AuraEngineID {
+		return 0, nil, fmt.Errorf("%w: got %v", errFirstDigestItemNotAura, preDigestValue)
+	}
+
+	slot, err = types.DecodeAuraPreDigest(preDigest.Data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	sealValue, err := header.Digest[len(header.Digest)-1].Value()
+	if err != nil {
+		return 0, nil, fmt.Errorf("getting seal item value: %w", err)
+	}
+
+	sealDigest, ok := sealValue.(types.SealDigest)
+	if !ok || sealDigest.ConsensusEngineID != types.AuraEngineID {
+		return 0, nil, fmt.Errorf("%w: got %v", errLastDigestItemNotSeal, sealValue)
+	}
+
+	return slot, &sealDigest, nil
+}
+
+// hashWithDigest returns the hash of the header with the given digest, without
+// using nor caching the hash of the header itself.
+func hashWithDigest(header *types.Header, digest types.Digest) common.Hash {
+	return types.NewHeader(header.ParentHash, header.StateRoot, header.ExtrinsicsRoot,
+		header.Number, digest).Hash()
+}
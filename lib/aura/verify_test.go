@@ -0,0 +1,192 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package aura
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testSlotDuration = 6 * time.Second
+
+func newTestKeypairs(t *testing.T) (alice, bob *sr25519.Keypair) {
+	t.Helper()
+
+	keyring, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	return keyring.Alice().(*sr25519.Keypair), keyring.Bob().(*sr25519.Keypair)
+}
+
+func newTestHeader(t *testing.T, parentHash common.Hash, number uint, slot uint64) *types.Header {
+	t.Helper()
+
+	preDigest, err := types.NewAuraPreRuntimeDigest(slot)
+	require.NoError(t, err)
+
+	digest := types.NewDigest()
+	err = digest.Add(*preDigest)
+	require.NoError(t, err)
+
+	return types.NewHeader(parentHash, common.Hash{1}, common.Hash{2}, number, digest)
+}
+
+// encodeTestAuthorities encodes the public keys of the given keypairs as
+// returned by the AuraApi_authorities runtime call.
+func encodeTestAuthorities(t *testing.T, keypairs ...*sr25519.Keypair) []byte {
+	t.Helper()
+
+	raw := make([]types.AuraAuthorityRaw, len(keypairs))
+	for i, kp := range keypairs {
+		raw[i] = kp.Public().(*sr25519.PublicKey).AsBytes()
+	}
+
+	encoded, err := scale.Marshal(raw)
+	require.NoError(t, err)
+
+	return encoded
+}
+
+func sealTestHeader(t *testing.T, header *types.Header, kp *sr25519.Keypair) *types.Header {
+	t.Helper()
+
+	hash := hashWithDigest(header, header.Digest)
+	sig, err := kp.Sign(hash[:])
+	require.NoError(t, err)
+
+	err = header.Digest.Add(types.SealDigest{
+		ConsensusEngineID: types.AuraEngineID,
+		Data:              sig,
+	})
+	require.NoError(t, err)
+
+	return header
+}
+
+// aliceSlot returns the latest slot authored by the first of two authorities
+func aliceSlot() uint64 {
+	slot := getCurrentSlot(testSlotDuration)
+	return slot - slot%2
+}
+
+func Test_Verifier_VerifyBlock(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newTestKeypairs(t)
+	encodedAuthorities := encodeTestAuthorities(t, alice, bob)
+
+	slot := aliceSlot()
+	genesis := types.NewHeader(common.Hash{}, common.Hash{3}, common.Hash{}, 0, types.NewDigest())
+	parent := newTestHeader(t, genesis.Hash(), 1, slot-2)
+
+	babeHeader := types.NewHeader(parent.Hash(), common.Hash{}, common.Hash{}, 2, types.NewDigest())
+	babePreDigest := types.NewBABEPreRuntimeDigest([]byte{1})
+	require.NoError(t, babeHeader.Digest.Add(*babePreDigest))
+
+	testCases := map[string]struct {
+		header            *types.Header
+		parent            *types.Header
+		expectAuthorities bool
+		errWrapped        error
+	}{
+		"missing_digest_items": {
+			header:     newTestHeader(t, parent.Hash(), 2, slot),
+			errWrapped: errMissingDigestItems,
+		},
+		"babe_pre_runtime_digest": {
+			header:     sealTestHeader(t, babeHeader, alice),
+			errWrapped: errFirstDigestItemNotAura,
+		},
+		"future_slot": {
+			header:     sealTestHeader(t, newTestHeader(t, parent.Hash(), 2, slot+10), alice),
+			errWrapped: ErrFutureSlot,
+		},
+		"slot_not_increasing": {
+			header:     sealTestHeader(t, newTestHeader(t, parent.Hash(), 2, slot-2), alice),
+			parent:     parent,
+			errWrapped: ErrSlotNotIncreasing,
+		},
+		"not_slot_author": {
+			header:            sealTestHeader(t, newTestHeader(t, parent.Hash(), 2, slot), bob),
+			parent:            parent,
+			expectAuthorities: true,
+			errWrapped:        ErrBadSignature,
+		},
+		"valid_block": {
+			header:            sealTestHeader(t, newTestHeader(t, parent.Hash(), 2, slot), alice),
+			parent:            parent,
+			expectAuthorities: true,
+		},
+		"valid_first_block": {
+			header:            sealTestHeader(t, newTestHeader(t, genesis.Hash(), 1, slot), alice),
+			parent:            genesis,
+			expectAuthorities: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			blockState := NewMockBlockState(ctrl)
+			storageState := NewMockStorageState(ctrl)
+			if testCase.parent != nil {
+				blockState.EXPECT().GetHeader(testCase.header.ParentHash).Return(testCase.parent, nil)
+			}
+
+			if testCase.expectAuthorities {
+				trieState := rtstorage.NewTrieState(inmemory.NewEmptyTrie())
+				storageState.EXPECT().Lock()
+				storageState.EXPECT().TrieState(&testCase.parent.StateRoot).Return(trieState, nil)
+				storageState.EXPECT().Unlock()
+
+				instance := mocks.NewMockInstance(ctrl)
+				instance.EXPECT().ExecWithStorage(trieState, runtime.AuraAPIAuthorities, []byte{}).
+					Return(encodedAuthorities, nil)
+				blockState.EXPECT().GetRuntime(testCase.parent.Hash()).Return(instance, nil)
+			}
+
+			verifier := NewVerifier(blockState, storageState, testSlotDuration)
+			err := verifier.VerifyBlock(testCase.header)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
+
+func Test_slotAuthor(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newTestKeypairs(t)
+	authorities := []types.Authority{
+		*types.NewAuthority(alice.Public(), 1),
+		*types.NewAuthority(bob.Public(), 1),
+	}
+
+	_, err := slotAuthor(1, nil)
+	assert.ErrorIs(t, err, ErrNoAuthorities)
+
+	author, err := slotAuthor(4, authorities)
+	require.NoError(t, err)
+	assert.Equal(t, alice.Public(), author.Key)
+
+	author, err = slotAuthor(5, authorities)
+	require.NoError(t, err)
+	assert.Equal(t, bob.Public(), author.Key)
+}
@@ -59,6 +59,36 @@ func (mr *MockInstanceMockRecorder) ApplyExtrinsic(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyExtrinsic", reflect.TypeOf((*MockInstance)(nil).ApplyExtrinsic), arg0)
 }
 
+// AuraAuthorities mocks base method.
+func (m *MockInstance) AuraAuthorities() ([]types.Authority, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuraAuthorities")
+	ret0, _ := ret[0].([]types.Authority)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuraAuthorities indicates an expected call of AuraAuthorities.
+func (mr *MockInstanceMockRecorder) AuraAuthorities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuraAuthorities", reflect.TypeOf((*MockInstance)(nil).AuraAuthorities))
+}
+
+// AuraSlotDuration mocks base method.
+func (m *MockInstance) AuraSlotDuration() (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuraSlotDuration")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuraSlotDuration indicates an expected call of AuraSlotDuration.
+func (mr *MockInstanceMockRecorder) AuraSlotDuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuraSlotDuration", reflect.TypeOf((*MockInstance)(nil).AuraSlotDuration))
+}
+
 // BabeConfiguration mocks base method.
 func (m *MockInstance) BabeConfiguration() (*types.BabeConfiguration, error) {
 	m.ctrl.T.Helper()
@@ -115,6 +145,20 @@ func (mr *MockInstanceMockRecorder) CheckInherents() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInherents", reflect.TypeOf((*MockInstance)(nil).CheckInherents))
 }
 
+// CompilationCacheDir mocks base method.
+func (m *MockInstance) CompilationCacheDir() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompilationCacheDir")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CompilationCacheDir indicates an expected call of CompilationCacheDir.
+func (mr *MockInstanceMockRecorder) CompilationCacheDir() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompilationCacheDir", reflect.TypeOf((*MockInstance)(nil).CompilationCacheDir))
+}
+
 // DecodeSessionKeys mocks base method.
 func (m *MockInstance) DecodeSessionKeys(arg0 []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +189,21 @@ func (mr *MockInstanceMockRecorder) Exec(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockInstance)(nil).Exec), arg0, arg1)
 }
 
+// ExecWithStorage mocks base method.
+func (m *MockInstance) ExecWithStorage(arg0 runtime.Storage, arg1 string, arg2 []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecWithStorage", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecWithStorage indicates an expected call of ExecWithStorage.
+func (mr *MockInstanceMockRecorder) ExecWithStorage(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecWithStorage", reflect.TypeOf((*MockInstance)(nil).ExecWithStorage), arg0, arg1, arg2)
+}
+
 // ExecuteBlock mocks base method.
 func (m *MockInstance) ExecuteBlock(arg0 *types.Block) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -176,15 +235,18 @@ func (mr *MockInstanceMockRecorder) FinalizeBlock() *gomock.Call {
 }
 
 // GenerateSessionKeys mocks base method.
-func (m *MockInstance) GenerateSessionKeys() {
+func (m *MockInstance) GenerateSessionKeys(arg0 *[]byte) ([]byte, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "GenerateSessionKeys")
+	ret := m.ctrl.Call(m, "GenerateSessionKeys", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GenerateSessionKeys indicates an expected call of GenerateSessionKeys.
-func (mr *MockInstanceMockRecorder) GenerateSessionKeys() *gomock.Call {
+func (mr *MockInstanceMockRecorder) GenerateSessionKeys(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSessionKeys", reflect.TypeOf((*MockInstance)(nil).GenerateSessionKeys))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSessionKeys", reflect.TypeOf((*MockInstance)(nil).GenerateSessionKeys), arg0)
 }
 
 // GetCodeHash mocks base method.
@@ -332,15 +394,17 @@ func (mr *MockInstanceMockRecorder) NodeStorage() *gomock.Call {
 }
 
 // OffchainWorker mocks base method.
-func (m *MockInstance) OffchainWorker() {
+func (m *MockInstance) OffchainWorker(arg0 *types.Header) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OffchainWorker")
+	ret := m.ctrl.Call(m, "OffchainWorker", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // OffchainWorker indicates an expected call of OffchainWorker.
-func (mr *MockInstanceMockRecorder) OffchainWorker() *gomock.Call {
+func (mr *MockInstanceMockRecorder) OffchainWorker(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffchainWorker", reflect.TypeOf((*MockInstance)(nil).OffchainWorker))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffchainWorker", reflect.TypeOf((*MockInstance)(nil).OffchainWorker), arg0)
 }
 
 // PaymentQueryInfo mocks base method.
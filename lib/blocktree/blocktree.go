@@ -306,6 +306,38 @@ func (bt *BlockTree) Prune(finalised Hash) (pruned []Hash) {
 	return pruned
 }
 
+// Remove removes the block with the given hash and all its descendants from the blocktree,
+// and returns their hashes. The root of the blocktree cannot be removed.
+func (bt *BlockTree) Remove(hash Hash) (removed []Hash, err error) {
+	bt.Lock()
+	defer bt.Unlock()
+
+	n := bt.getNode(hash)
+	if n == nil {
+		return nil, fmt.Errorf("%w: for block hash %s", ErrNodeNotFound, hash)
+	}
+
+	if n == bt.root {
+		return nil, fmt.Errorf("%w: %s", errRemoveRoot, hash)
+	}
+
+	for _, leaf := range n.getLeaves(nil) {
+		bt.leaves.delete(leaf.hash)
+	}
+
+	parent := n.parent
+	parent.deleteChild(n)
+	if len(parent.children) == 0 {
+		bt.leaves.store(parent.hash, parent)
+	}
+
+	removed = n.getAllDescendants(nil)
+	bt.runtimes.onRemoval(removed)
+
+	leavesGauge.Set(float64(len(bt.leaves.nodes())))
+	return removed, nil
+}
+
 // String utilises github.com/disiqueira/gotree to create a printable tree
 func (bt *BlockTree) String() string {
 	bt.RLock()
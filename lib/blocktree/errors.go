@@ -44,4 +44,5 @@
 	ErrNoCommonAncestor = errors.New("no common ancestor between two nodes")
 
 	errUnexpectedNumber = errors.New("block number is not parent number + 1")
+	errRemoveRoot       = errors.New("cannot remove the root of the blocktree")
 )
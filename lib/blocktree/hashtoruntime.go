@@ -106,3 +106,31 @@ func (h *hashToRuntime) onFinalisation(newCanonicalBlockHashes []common.Hash) {
 		}
 	}
 }
+
+// onRemoval deletes the runtimes of the blocks removed from the blocktree,
+// and stops the ones which are no longer used by any remaining block.
+func (h *hashToRuntime) onRemoval(removedBlockHashes []common.Hash) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	removedRuntimes := make(map[runtime.Instance]struct{})
+	for _, hash := range removedBlockHashes {
+		instance, ok := h.mapping[hash]
+		if !ok {
+			continue
+		}
+
+		delete(h.mapping, hash)
+		removedRuntimes[instance] = struct{}{}
+	}
+
+	for _, instance := range h.mapping {
+		delete(removedRuntimes, instance)
+	}
+
+	for instance := range removedRuntimes {
+		instance.Stop()
+	}
+
+	inMemoryRuntimesGauge.Set(float64(len(h.mapping)))
+}
@@ -47,6 +47,10 @@ func (lm *leafMap) load(key Hash) (*node, error) {
 	return v.(*node), nil
 }
 
+func (lm *leafMap) delete(key Hash) {
+	lm.smap.Delete(key)
+}
+
 // Replace deletes the old node from the map and inserts the new one
 func (lm *leafMap) replace(oldNode, newNode *node) {
 	lm.Lock()
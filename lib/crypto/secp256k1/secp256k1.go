@@ -11,6 +11,8 @@
 
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
+
+	"github.com/ChainSafe/go-schnorrkel"
 	secp256k1 "github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -26,6 +28,12 @@
 // MessageLength is the fixed Message Length
 const MessageLength = 32
 
+// PublicKeyLength is the fixed length of a compressed Public Key
+const PublicKeyLength = 33
+
+// SeedLength is the fixed Seed Length
+const SeedLength = 32
+
 // Keypair holds the pub,pk keys
 type Keypair struct {
 	public  *PublicKey
@@ -127,6 +135,43 @@ func NewKeypairFromPrivateKeyString(in string) (*Keypair, error) {
 	return NewKeypairFromPrivate(priv)
 }
 
+// NewKeypairFromSeed returns a Keypair whose private key is the given 32 bytes seed
+func NewKeypairFromSeed(seed []byte) (*Keypair, error) {
+	if len(seed) != SeedLength {
+		return nil, fmt.Errorf("cannot generate key from seed: seed is not 32 bytes long")
+	}
+
+	priv, err := secp256k1.ToECDSA(seed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate key from seed: %w", err)
+	}
+
+	return NewKeypair(*priv), nil
+}
+
+// NewKeypairFromMnenomic returns a new Keypair using the given mnemonic and password.
+func NewKeypairFromMnenomic(mnemonic, password string) (*Keypair, error) {
+	seed, err := schnorrkel.SeedFromMnemonic(mnemonic, password)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeypairFromSeed(seed[:SeedLength])
+}
+
+// NewPublicKey returns a secp256k1 public key from its 33 bytes compressed encoding
+func NewPublicKey(in []byte) (*PublicKey, error) {
+	if len(in) != PublicKeyLength {
+		return nil, fmt.Errorf("cannot create public key: input is not 33 bytes")
+	}
+
+	pub := new(PublicKey)
+	err := pub.Decode(in)
+	if err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
 // GenerateKeypair will generate a Keypair
 func GenerateKeypair() (*Keypair, error) {
 	priv, err := secp256k1.GenerateKey()
@@ -201,3 +201,48 @@ func TestVerifySignature(t *testing.T) {
 	}
 
 }
+
+func TestNewKeypairFromSeed(t *testing.T) {
+	t.Parallel()
+
+	seed := common.MustHexToBytes("0x9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	kp, err := NewKeypairFromSeed(seed)
+	require.NoError(t, err)
+
+	expectedPublic := common.MustHexToBytes("0x028db55b05db86c0b1786ca49f095d76344c9e6056b2f02701a7e7f3c20aabfd91")
+	require.Equal(t, expectedPublic, kp.Public().Encode())
+
+	_, err = NewKeypairFromSeed(seed[1:])
+	require.EqualError(t, err, "cannot generate key from seed: seed is not 32 bytes long")
+}
+
+func TestNewKeypairFromMnenomic(t *testing.T) {
+	t.Parallel()
+
+	mnemonic, err := crypto.NewBIP39Mnemonic()
+	require.NoError(t, err)
+
+	kp, err := NewKeypairFromMnenomic(mnemonic, "")
+	require.NoError(t, err)
+	again, err := NewKeypairFromMnenomic(mnemonic, "")
+	require.NoError(t, err)
+	require.Equal(t, kp.Public(), again.Public())
+
+	withPassword, err := NewKeypairFromMnenomic(mnemonic, "password")
+	require.NoError(t, err)
+	require.NotEqual(t, kp.Public(), withPassword.Public())
+}
+
+func TestNewPublicKey(t *testing.T) {
+	t.Parallel()
+
+	kp, err := GenerateKeypair()
+	require.NoError(t, err)
+
+	pub, err := NewPublicKey(kp.Public().Encode())
+	require.NoError(t, err)
+	require.Equal(t, kp.Public(), pub)
+
+	_, err = NewPublicKey(kp.Public().Encode()[1:])
+	require.EqualError(t, err, "cannot create public key: input is not 33 bytes")
+}
@@ -5,8 +5,9 @@
 
 import (
 	"errors"
+	"runtime"
 	"sync"
-	"time"
+	"sync/atomic"
 )
 
 var ErrSignatureVerificationFailed = errors.New("failed to verify signature")
@@ -14,7 +15,8 @@
 // SigVerifyFunc verifies a signature given a public key and a message
 type SigVerifyFunc func(pubkey, sig, msg []byte) (err error)
 
-// SignatureInfo ...
+// SignatureInfo holds a signature to verify, together with its public key,
+// message and the verification function of its signature scheme.
 type SignatureInfo struct {
 	PubKey     []byte
 	Sign       []byte
@@ -22,16 +24,19 @@ type SignatureInfo struct {
 	VerifyFunc SigVerifyFunc
 }
 
-// SignatureVerifier ...
+// SignatureVerifier verifies a batch of signatures in parallel on a pool of workers.
 type SignatureVerifier struct {
-	batch   []*SignatureInfo
-	init    bool // Indicates whether the batch processing is started.
-	invalid bool // Set to true if any signature verification fails.
 	logger  Erroer
-	closeCh chan struct{}
-	sync.RWMutex
-	sync.Once
-	sync.WaitGroup
+	workers int
+
+	mutex   sync.Mutex
+	started bool
+	// pending holds the signatures added before the batch is started.
+	pending []*SignatureInfo
+	jobs    chan *SignatureInfo
+	wg      sync.WaitGroup
+	// invalid is set to true if any signature verification fails.
+	invalid atomic.Bool
 }
 
 // NewSignatureVerifier initialises SignatureVerifier which does background verification of signatures.
@@ -40,115 +45,118 @@ type SignatureVerifier struct {
 // Signatures can be added to the batch using Add().
 func NewSignatureVerifier(logger Erroer) *SignatureVerifier {
 	return &SignatureVerifier{
-		batch:   make([]*SignatureInfo, 0),
-		init:    false,
-		invalid: false,
 		logger:  logger,
-		RWMutex: sync.RWMutex{},
-		closeCh: make(chan struct{}),
+		workers: runtime.NumCPU(),
 	}
 }
 
-// Start signature verification in batch.
+// Start starts a batch of signature verifications. The signatures already
+// added and the ones added until Finish is called are verified in parallel.
 func (sv *SignatureVerifier) Start() {
-	// Update the init state.
-	sv.Lock()
-	sv.init = true
-	sv.Unlock()
-
-	sv.WaitGroup.Add(1)
-
-	go func() {
-		defer sv.Done()
-		for {
-			select {
-			case <-sv.closeCh:
-				return
-			default:
-				signature := sv.Remove()
-				if signature == nil {
-					continue
-				}
-				err := signature.VerifyFunc(signature.PubKey, signature.Sign, signature.Msg)
-				if err != nil {
-					sv.logger.Errorf("[ext_crypto_start_batch_verify_version_1]: %s", err)
-					sv.Invalid()
-					return
-				}
-			}
+	sv.mutex.Lock()
+	defer sv.mutex.Unlock()
+
+	if !sv.started {
+		sv.start()
+	}
+}
+
+// start starts the workers and sends them the pending signatures.
+// It must be called with the mutex locked.
+func (sv *SignatureVerifier) start() {
+	sv.started = true
+
+	sv.jobs = make(chan *SignatureInfo, sv.workers)
+	sv.wg.Add(sv.workers)
+	for i := 0; i < sv.workers; i++ {
+		go sv.verify(sv.jobs)
+	}
+
+	for _, signature := range sv.pending {
+		sv.jobs <- signature
+	}
+	sv.pending = nil
+}
+
+func (sv *SignatureVerifier) verify(jobs <-chan *SignatureInfo) {
+	defer sv.wg.Done()
+
+	for signature := range jobs {
+		if sv.invalid.Load() {
+			// the batch is already invalid, drain the remaining signatures
+			continue
 		}
-	}()
+
+		err := signature.VerifyFunc(signature.PubKey, signature.Sign, signature.Msg)
+		if err != nil {
+			sv.logger.Errorf("[ext_crypto_start_batch_verify_version_1]: %s", err)
+			sv.invalid.Store(true)
+		}
+	}
 }
 
-// IsStarted ...
+// IsStarted returns true if a batch of signature verifications is started.
 func (sv *SignatureVerifier) IsStarted() bool {
-	sv.RLock()
-	defer sv.RUnlock()
-	return sv.init
+	sv.mutex.Lock()
+	defer sv.mutex.Unlock()
+	return sv.started
 }
 
-// IsInvalid ...
+// IsInvalid returns true if any signature of the batch failed to verify.
 func (sv *SignatureVerifier) IsInvalid() bool {
-	sv.RLock()
-	defer sv.RUnlock()
-	return sv.invalid
+	return sv.invalid.Load()
 }
 
-// Invalid ...
+// Invalid marks the batch as invalid.
 func (sv *SignatureVerifier) Invalid() {
-	sv.RLock()
-	defer sv.RUnlock()
-	sv.invalid = true
+	sv.invalid.Store(true)
 }
 
-// Add ...
+// Add adds a signature to the batch. It is verified right away by a worker
+// if the batch is started, or once it is started otherwise.
 func (sv *SignatureVerifier) Add(s *SignatureInfo) {
 	if sv.IsInvalid() {
 		return
 	}
 
-	sv.Lock()
-	defer sv.Unlock()
-	sv.batch = append(sv.batch, s)
-}
+	sv.mutex.Lock()
+	defer sv.mutex.Unlock()
 
-// Remove returns the first signature from the batch. Returns nil if batch is empty.
-func (sv *SignatureVerifier) Remove() *SignatureInfo {
-	sv.Lock()
-	defer sv.Unlock()
-	if len(sv.batch) == 0 {
-		return nil
+	if !sv.started {
+		sv.pending = append(sv.pending, s)
+		return
 	}
-	sign := sv.batch[0]
-	sv.batch = sv.batch[1:len(sv.batch)]
-	return sign
+	sv.jobs <- s
 }
 
 // Reset reset the signature verifier for reuse.
 func (sv *SignatureVerifier) Reset() {
-	sv.Lock()
-	defer sv.Unlock()
-	sv.init = false
-	sv.batch = make([]*SignatureInfo, 0)
-	sv.invalid = false
-	sv.closeCh = make(chan struct{})
+	sv.mutex.Lock()
+	defer sv.mutex.Unlock()
+
+	if sv.started {
+		close(sv.jobs)
+		sv.wg.Wait()
+		sv.started = false
+	}
+	sv.pending = nil
+	sv.invalid.Store(false)
 }
 
 // Finish waits till batch is finished. Returns true if all the signatures are valid, Otherwise returns false.
+// The signature verifier is then reset for reuse.
 func (sv *SignatureVerifier) Finish() bool {
-	for {
-		time.Sleep(100 * time.Millisecond)
-		sv.Lock()
-		if sv.invalid || len(sv.batch) == 0 {
-			close(sv.closeCh)
-			sv.Unlock()
-			break
-		}
-		sv.RUnlock()
+	sv.mutex.Lock()
+	defer sv.mutex.Unlock()
+
+	if !sv.started {
+		// verify the signatures added before the batch was started
+		sv.start()
 	}
-	// Wait till start function to finish and then reset it.
-	sv.Wait()
-	isInvalid := sv.IsInvalid()
-	sv.Reset()
-	return !isInvalid
+
+	close(sv.jobs)
+	sv.wg.Wait()
+	sv.started = false
+
+	return !sv.invalid.Swap(false)
 }
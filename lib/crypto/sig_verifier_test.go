@@ -124,3 +124,59 @@ func TestVerifySignature(t *testing.T) {
 	}
 
 }
+
+func TestSignatureVerifier_batch(t *testing.T) {
+	t.Parallel()
+
+	keypair, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	signatures := make([]*crypto.SignatureInfo, 100)
+	for i := range signatures {
+		message := []byte{byte(i)}
+		signature, err := keypair.Sign(message)
+		require.NoError(t, err)
+
+		signatures[i] = &crypto.SignatureInfo{
+			PubKey:     keypair.Public().Encode(),
+			Sign:       signature,
+			Msg:        message,
+			VerifyFunc: ed25519.VerifySignature,
+		}
+	}
+
+	signVerify := crypto.NewSignatureVerifier(log.New(log.SetWriter(io.Discard)))
+
+	signVerify.Start()
+	require.True(t, signVerify.IsStarted())
+	for _, signature := range signatures {
+		signVerify.Add(signature)
+	}
+	require.True(t, signVerify.Finish())
+	require.False(t, signVerify.IsStarted())
+
+	// the verifier is reusable after a batch is finished
+	invalid := *signatures[50]
+	invalid.Msg = []byte("other message")
+
+	signVerify.Start()
+	for _, signature := range signatures[:50] {
+		signVerify.Add(signature)
+	}
+	signVerify.Add(&invalid)
+	for _, signature := range signatures[51:] {
+		signVerify.Add(signature)
+	}
+	require.False(t, signVerify.Finish())
+
+	// an invalid batch does not leak into the next one
+	signVerify.Start()
+	signVerify.Add(signatures[0])
+	require.True(t, signVerify.Finish())
+
+	// a reset batch discards its signatures
+	signVerify.Add(&invalid)
+	signVerify.Reset()
+	signVerify.Start()
+	require.True(t, signVerify.Finish())
+}
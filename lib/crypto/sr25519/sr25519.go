@@ -169,6 +169,19 @@ func NewPrivateKey(in []byte) (*PrivateKey, error) {
 	return priv, err
 }
 
+// NewPrivateKeyFromEd25519Bytes creates a new private key from the 64 bytes ed25519 formatted
+// secret key, made of the secret scalar multiplied by the cofactor followed by the nonce.
+// This is the format used by polkadot-js and schnorrkel's `SecretKey::to_ed25519_bytes`.
+func NewPrivateKeyFromEd25519Bytes(in []byte) (*PrivateKey, error) {
+	if len(in) != 2*PrivateKeyLength {
+		return nil, errors.New("input to create sr25519 private key from ed25519 bytes is not 64 bytes")
+	}
+
+	b := [2 * PrivateKeyLength]byte{}
+	copy(b[:], in)
+	return &PrivateKey{key: sr25519.NewSecretKeyFromEd25519Bytes(b)}, nil
+}
+
 // GenerateKeypair returns a new sr25519 keypair
 func GenerateKeypair() (*Keypair, error) {
 	priv, pub, err := sr25519.GenerateKeypair()
@@ -270,6 +283,30 @@ func (k *PrivateKey) Encode() []byte {
 	return enc[:]
 }
 
+// Ed25519Bytes returns the 64 bytes ed25519 formatted encoding of the private key, made of the
+// secret scalar multiplied by the cofactor followed by the nonce. The nonce is not kept by
+// the private key so it is encoded as zeroes.
+func (k *PrivateKey) Ed25519Bytes() []byte {
+	if k.key == nil {
+		return nil
+	}
+
+	enc := make([]byte, 2*PrivateKeyLength)
+	key := k.key.Encode()
+	copy(enc, key[:])
+
+	// https://github.com/w3f/schnorrkel/blob/718678e51006d84c7d8e4b6cde758906172e74f8/src/scalars.rs#L33
+	high := byte(0)
+	for i := 0; i < PrivateKeyLength; i++ {
+		r := enc[i] & 0xe0
+		enc[i] <<= 3
+		enc[i] += high
+		high = r >> 5
+	}
+
+	return enc
+}
+
 // Decode decodes the input bytes into a private key and sets the receiver the decoded key
 // Input must be 32 bytes, or else this function will error
 func (k *PrivateKey) Decode(in []byte) error {
@@ -5,6 +5,7 @@
 
 import (
 	"crypto/rand"
+	"crypto/sha512"
 	"errors"
 	"fmt"
 	"testing"
@@ -70,6 +71,32 @@ func TestEncodeAndDecodePrivateKey(t *testing.T) {
 	require.Equal(t, exp, res.key.Encode())
 }
 
+func TestPrivateKey_Ed25519Bytes(t *testing.T) {
+	miniSecret := make([]byte, 32)
+	_, err := rand.Read(miniSecret)
+	require.NoError(t, err)
+
+	kp, err := NewKeypairFromSeed(miniSecret)
+	require.NoError(t, err)
+
+	// the ed25519 formatted scalar is the clamped expansion of the mini secret key
+	expanded := sha512.Sum512(miniSecret)
+	expanded[0] &= 248
+	expanded[31] &= 63
+	expanded[31] |= 64
+
+	enc := kp.private.Ed25519Bytes()
+	require.Equal(t, expanded[:32], enc[:32])
+	require.Equal(t, make([]byte, 32), enc[32:])
+
+	priv, err := NewPrivateKeyFromEd25519Bytes(enc)
+	require.NoError(t, err)
+	require.Equal(t, kp.private.Encode(), priv.Encode())
+
+	_, err = NewPrivateKeyFromEd25519Bytes(enc[:32])
+	require.EqualError(t, err, "input to create sr25519 private key from ed25519 bytes is not 64 bytes")
+}
+
 func TestEncodeAndDecodePublicKey(t *testing.T) {
 	kp, err := GenerateKeypair()
 	require.NoError(t, err)
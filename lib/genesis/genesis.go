@@ -10,6 +10,10 @@
 	"github.com/ChainSafe/gossamer/lib/common"
 )
 
+// AuraConsensusEngine is the chain spec consensus engine of chains
+// producing blocks with Aura instead of BABE.
+const AuraConsensusEngine = "aura"
+
 // Genesis stores the data parsed from the genesis configuration file
 type Genesis struct {
 	Name               string                 `json:"name"`
@@ -407,12 +407,34 @@ func (h *MessageHandler) verifyPreCommitJustification(msg *CatchUpResponse) erro
 func (s *Service) VerifyBlockJustification(finalizedHash common.Hash, finalizedNumber uint, encoded []byte) (
 	round uint64, setID uint64, err error,
 ) {
-	setID, err = s.grandpaState.GetSetIDByBlockNumber(finalizedNumber)
+	return verifyBlockJustification(s.grandpaState, finalizedHash, finalizedNumber, encoded)
+}
+
+// JustificationVerifier verifies block justifications against the authority sets
+// stored in the grandpa state, without running the GRANDPA service.
+type JustificationVerifier struct {
+	grandpaState GrandpaState
+}
+
+// NewJustificationVerifier returns a new JustificationVerifier
+func NewJustificationVerifier(grandpaState GrandpaState) *JustificationVerifier {
+	return &JustificationVerifier{grandpaState: grandpaState}
+}
+
+// VerifyBlockJustification verifies the finality justification for a block
+func (v *JustificationVerifier) VerifyBlockJustification(finalizedHash common.Hash, finalizedNumber uint,
+	encoded []byte) (round uint64, setID uint64, err error) {
+	return verifyBlockJustification(v.grandpaState, finalizedHash, finalizedNumber, encoded)
+}
+
+func verifyBlockJustification(grandpaState GrandpaState, finalizedHash common.Hash, finalizedNumber uint,
+	encoded []byte) (round uint64, setID uint64, err error) {
+	setID, err = grandpaState.GetSetIDByBlockNumber(finalizedNumber)
 	if err != nil {
 		return 0, 0, fmt.Errorf("cannot get set ID from block number: %w", err)
 	}
 
-	auths, err := s.grandpaState.GetAuthorities(setID)
+	auths, err := grandpaState.GetAuthorities(setID)
 	if err != nil {
 		return 0, 0, fmt.Errorf("cannot get authorities for set ID: %w", err)
 	}
@@ -20,20 +20,93 @@
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 
 	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/scrypt"
 )
 
-// EncryptedKeystore holds Type PublicKey and Ciphertext
+const (
+	// LegacyKeystoreVersion is the version of keystore files written before versioning, where
+	// the encryption key is the blake2b hash of the password.
+	LegacyKeystoreVersion = 0
+	// ScryptKeystoreVersion is the version of keystore files where the encryption key is derived
+	// from the password using scrypt with a per-file salt.
+	ScryptKeystoreVersion = 1
+	// CurrentKeystoreVersion is the version of the keystore files written.
+	CurrentKeystoreVersion = ScryptKeystoreVersion
+)
+
+const (
+	scryptSaltLength = 32
+	scryptKeyLength  = 32
+	// scrypt parameters recommended for interactive logins, also used by polkadot-js
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	errUnsupportedKeystoreVersion = errors.New("unsupported keystore version")
+	errMissingScryptParams        = errors.New("missing scrypt parameters")
+	errCiphertextTooShort         = errors.New("ciphertext too short")
+	errExcessiveScryptParams      = errors.New("scrypt parameters above the defaults")
+)
+
+// EncryptedKeystore holds Type PublicKey and Ciphertext, along with the version of the file
+// format and the parameters used to derive the encryption key from the password
 type EncryptedKeystore struct {
+	Version    int `json:",omitempty"`
 	Type       string
 	PublicKey  string
 	Ciphertext []byte
+	Scrypt     *ScryptParams `json:",omitempty"`
+}
+
+// ScryptParams holds the scrypt parameters used to derive the encryption key from the password
+type ScryptParams struct {
+	Salt []byte
+	N    int
+	R    int
+	P    int
+}
+
+// NewScryptParams returns scrypt parameters with a new random salt
+func NewScryptParams() (*ScryptParams, error) {
+	salt := make([]byte, scryptSaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+
+	return &ScryptParams{
+		Salt: salt,
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+	}, nil
 }
 
-// gcmFromPassphrase creates a symmetric AES key given a password
-func gcmFromPassphrase(password []byte) (cipher.AEAD, error) {
-	hash := blake2b.Sum256(password)
+// gcmFromPassphrase creates a symmetric AES key given a password. The key is derived using
+// scrypt with the given parameters, or is the blake2b hash of the password for legacy
+// keystores if the parameters are nil.
+func gcmFromPassphrase(password []byte, params *ScryptParams) (cipher.AEAD, error) {
+	var key []byte
+	if params == nil {
+		hash := blake2b.Sum256(password)
+		key = hash[:]
+	} else {
+		// as for polkadot-js keystores, parameters above the defaults are rejected
+		// to avoid excessive resources usage when deriving the key
+		if params.N > scryptN || params.R > scryptR || params.P > scryptP {
+			return nil, fmt.Errorf("%w: N=%d, r=%d, p=%d",
+				errExcessiveScryptParams, params.N, params.R, params.P)
+		}
+
+		var err error
+		key, err = scrypt.Key(password, params.Salt, params.N, params.R, params.P, scryptKeyLength)
+		if err != nil {
+			return nil, fmt.Errorf("deriving key: %w", err)
+		}
+	}
 
-	block, err := aes.NewCipher(hash[:])
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
@@ -46,9 +119,10 @@ func gcmFromPassphrase(password []byte) (cipher.AEAD, error) {
 	return gcm, nil
 }
 
-// Encrypt uses AES to encrypt `msg` with the symmetric key deterministically created from `password`
-func Encrypt(msg, password []byte) ([]byte, error) {
-	gcm, err := gcmFromPassphrase(password)
+// Encrypt uses AES to encrypt `msg` with the symmetric key derived from `password` using the
+// scrypt parameters, or the legacy blake2b derivation if the parameters are nil
+func Encrypt(msg, password []byte, params *ScryptParams) ([]byte, error) {
+	gcm, err := gcmFromPassphrase(password, params)
 	if err != nil {
 		return nil, err
 	}
@@ -62,14 +136,19 @@ func Encrypt(msg, password []byte) ([]byte, error) {
 	return ciphertext, nil
 }
 
-// Decrypt uses AES to decrypt ciphertext with the symmetric key deterministically created from `password`
-func Decrypt(data, password []byte) ([]byte, error) {
-	gcm, err := gcmFromPassphrase(password)
+// Decrypt uses AES to decrypt ciphertext with the symmetric key derived from `password` using
+// the scrypt parameters, or the legacy blake2b derivation if the parameters are nil
+func Decrypt(data, password []byte, params *ScryptParams) ([]byte, error) {
+	gcm, err := gcmFromPassphrase(password, params)
 	if err != nil {
 		return nil, err
 	}
 
 	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("%w: %d bytes", errCiphertextTooShort, len(data))
+	}
+
 	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
 	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
@@ -79,17 +158,16 @@ func Decrypt(data, password []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
-// EncryptPrivateKey uses AES to encrypt an encoded `crypto.PrivateKey` with a symmetric key deterministically
-// created from `password`
-func EncryptPrivateKey(pk crypto.PrivateKey, password []byte) ([]byte, error) {
-	return Encrypt(pk.Encode(), password)
+// EncryptPrivateKey uses AES to encrypt an encoded `crypto.PrivateKey` with a symmetric key
+// derived from `password`
+func EncryptPrivateKey(pk crypto.PrivateKey, password []byte, params *ScryptParams) ([]byte, error) {
+	return Encrypt(pk.Encode(), password, params)
 }
 
 // DecryptPrivateKey uses AES to decrypt the ciphertext into a
-// `crypto.PrivateKey` with a symmetric key deterministically
-// created from `password`
-func DecryptPrivateKey(data, password []byte, keytype string) (crypto.PrivateKey, error) {
-	pk, err := Decrypt(data, password)
+// `crypto.PrivateKey` with a symmetric key derived from `password`
+func DecryptPrivateKey(data, password []byte, keytype string, params *ScryptParams) (crypto.PrivateKey, error) {
+	pk, err := Decrypt(data, password, params)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +177,12 @@ func DecryptPrivateKey(data, password []byte, keytype string) (crypto.PrivateKey
 
 // EncryptAndWriteToFile encrypts the `crypto.PrivateKey` using the password and saves it to the specified file
 func EncryptAndWriteToFile(path string, pk crypto.PrivateKey, password []byte) error {
-	ciphertext, err := EncryptPrivateKey(pk, password)
+	params, err := NewScryptParams()
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := EncryptPrivateKey(pk, password, params)
 	if err != nil {
 		return err
 	}
@@ -127,9 +210,11 @@ func EncryptAndWriteToFile(path string, pk crypto.PrivateKey, password []byte) e
 	}
 
 	keydata := &EncryptedKeystore{
+		Version:    CurrentKeystoreVersion,
 		Type:       keytype,
 		PublicKey:  pub.Hex(),
 		Ciphertext: ciphertext,
+		Scrypt:     params,
 	}
 
 	data, err := json.MarshalIndent(keydata, "", "\t")
@@ -145,7 +230,8 @@ func EncryptAndWriteToFile(path string, pk crypto.PrivateKey, password []byte) e
 	return nil
 }
 
-// ReadFromFileAndDecrypt reads ciphertext from a file and decrypts it using the password into a `crypto.PrivateKey`
+// ReadFromFileAndDecrypt reads ciphertext from a file and decrypts it using the password into a
+// `crypto.PrivateKey`. Files written with the legacy format are upgraded to the current format.
 func ReadFromFileAndDecrypt(filename string, password []byte) (crypto.PrivateKey, error) {
 	fp, err := filepath.Abs(filename)
 	if err != nil {
@@ -163,5 +249,25 @@ func ReadFromFileAndDecrypt(filename string, password []byte) (crypto.PrivateKey
 		return nil, err
 	}
 
-	return DecryptPrivateKey(keydata.Ciphertext, password, keydata.Type)
+	switch keydata.Version {
+	case LegacyKeystoreVersion:
+		priv, err := DecryptPrivateKey(keydata.Ciphertext, password, keydata.Type, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		err = EncryptAndWriteToFile(fp, priv, password)
+		if err != nil {
+			return nil, fmt.Errorf("upgrading keystore file: %w", err)
+		}
+
+		return priv, nil
+	case ScryptKeystoreVersion:
+		if keydata.Scrypt == nil {
+			return nil, errMissingScryptParams
+		}
+		return DecryptPrivateKey(keydata.Ciphertext, password, keydata.Type, keydata.Scrypt)
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnsupportedKeystoreVersion, keydata.Version)
+	}
 }
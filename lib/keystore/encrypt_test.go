@@ -6,25 +6,34 @@
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEncryptAndDecrypt(t *testing.T) {
 	password := []byte("noot")
 	msg := []byte("helloworld")
 
-	ciphertext, err := Encrypt(msg, password)
+	params, err := NewScryptParams()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	res, err := Decrypt(ciphertext, password)
+	ciphertext, err := Encrypt(msg, password, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Decrypt(ciphertext, password, params)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -48,12 +57,17 @@ func TestEncryptAndDecryptPrivateKey(t *testing.T) {
 
 	password := []byte("noot")
 
-	data, err := EncryptPrivateKey(priv, password)
+	params, err := NewScryptParams()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	res, err := DecryptPrivateKey(data, password, "ed25519")
+	data, err := EncryptPrivateKey(priv, password, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := DecryptPrivateKey(data, password, "ed25519", params)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -138,3 +152,98 @@ func TestEncryptAndDecryptFromFile_Secp256k1(t *testing.T) {
 		t.Fatalf("Fail: got %v expected %v", res, priv)
 	}
 }
+
+func TestEncryptAndDecrypt_WrongPassword(t *testing.T) {
+	params, err := NewScryptParams()
+	require.NoError(t, err)
+
+	ciphertext, err := Encrypt([]byte("helloworld"), []byte("noot"), params)
+	require.NoError(t, err)
+
+	_, err = Decrypt(ciphertext, []byte("toon"), params)
+	require.EqualError(t, err, "cipher: message authentication failed")
+
+	otherParams, err := NewScryptParams()
+	require.NoError(t, err)
+	_, err = Decrypt(ciphertext, []byte("noot"), otherParams)
+	require.EqualError(t, err, "cipher: message authentication failed")
+
+	_, err = Decrypt([]byte{1}, []byte("noot"), params)
+	require.ErrorIs(t, err, errCiphertextTooShort)
+}
+
+func TestDecrypt_ExcessiveScryptParams(t *testing.T) {
+	params, err := NewScryptParams()
+	require.NoError(t, err)
+
+	ciphertext, err := Encrypt([]byte("helloworld"), []byte("noot"), params)
+	require.NoError(t, err)
+
+	testCases := map[string]*ScryptParams{
+		"N": {Salt: params.Salt, N: scryptN << 1, R: scryptR, P: scryptP},
+		"r": {Salt: params.Salt, N: scryptN, R: scryptR + 1, P: scryptP},
+		"p": {Salt: params.Salt, N: scryptN, R: scryptR, P: scryptP + 1},
+	}
+
+	for name, excessive := range testCases {
+		_, err = Decrypt(ciphertext, []byte("noot"), excessive)
+		require.ErrorIs(t, err, errExcessiveScryptParams, name)
+	}
+}
+
+func TestReadFromFileAndDecrypt_UpgradesLegacyKeystore(t *testing.T) {
+	password := []byte("noot")
+	path := filepath.Join(t.TempDir(), "test_key")
+
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	ciphertext, err := EncryptPrivateKey(kp.Private(), password, nil)
+	require.NoError(t, err)
+	legacy, err := json.Marshal(&EncryptedKeystore{
+		Type:       crypto.Sr25519Type,
+		PublicKey:  kp.Public().Hex(),
+		Ciphertext: ciphertext,
+	})
+	require.NoError(t, err)
+	err = os.WriteFile(path, legacy, 0600)
+	require.NoError(t, err)
+
+	// a wrong password leaves the file untouched
+	_, err = ReadFromFileAndDecrypt(path, []byte("toon"))
+	require.Error(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, legacy, data)
+
+	res, err := ReadFromFileAndDecrypt(path, password)
+	require.NoError(t, err)
+	require.Equal(t, kp.Private().Encode(), res.Encode())
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	keydata := new(EncryptedKeystore)
+	err = json.Unmarshal(data, keydata)
+	require.NoError(t, err)
+	require.Equal(t, CurrentKeystoreVersion, keydata.Version)
+	require.Equal(t, kp.Public().Hex(), keydata.PublicKey)
+	require.NotNil(t, keydata.Scrypt)
+	require.Len(t, keydata.Scrypt.Salt, scryptSaltLength)
+
+	res, err = ReadFromFileAndDecrypt(path, password)
+	require.NoError(t, err)
+	require.Equal(t, kp.Private().Encode(), res.Encode())
+}
+
+func TestReadFromFileAndDecrypt_UnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test_key")
+
+	data, err := json.Marshal(&EncryptedKeystore{Version: 99, Type: crypto.Sr25519Type})
+	require.NoError(t, err)
+	err = os.WriteFile(path, data, 0600)
+	require.NoError(t, err)
+
+	_, err = ReadFromFileAndDecrypt(path, []byte("noot"))
+	require.ErrorIs(t, err, errUnsupportedKeystoreVersion)
+	require.EqualError(t, err, "unsupported keystore version: 99")
+}
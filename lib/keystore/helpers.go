@@ -57,6 +57,8 @@ func DecodeKeyPairFromHex(keystr []byte, keytype crypto.KeyType) (kp KeyPair, er
 		kp, err = sr25519.NewKeypairFromSeed(keystr)
 	case crypto.Ed25519Type:
 		kp, err = ed25519.NewKeypairFromSeed(keystr)
+	case crypto.Secp256k1Type:
+		kp, err = secp256k1.NewKeypairFromSeed(keystr)
 	default:
 		return nil, errors.New("cannot decode key: invalid key type")
 	}
@@ -288,6 +290,8 @@ func DetermineKeyType(t string) crypto.KeyType {
 	case "acco", "babe", "para", "asgn",
 		"aura", "imon", "audi", "dumy":
 		return crypto.Sr25519Type
+	case "beef":
+		return crypto.Secp256k1Type
 	}
 	return crypto.UnknownType
 }
@@ -307,6 +311,8 @@ func HasKey(pubKeyStr, keyType string, keystore AddressKeypairGetter) (bool, err
 		pubKey, err = sr25519.NewPublicKey(keyBytes)
 	case crypto.Ed25519Type:
 		pubKey, err = ed25519.NewPublicKey(keyBytes)
+	case crypto.Secp256k1Type:
+		pubKey, err = secp256k1.NewPublicKey(keyBytes)
 	default:
 		err = fmt.Errorf("unknown key type: %s", keyType)
 	}
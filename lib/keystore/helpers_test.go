@@ -15,6 +15,7 @@
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/utils"
 
@@ -51,6 +52,7 @@ func TestLoadKeystore(t *testing.T) {
 	{testType: "imon", expectedType: crypto.Sr25519Type},
 	{testType: "audi", expectedType: crypto.Sr25519Type},
 	{testType: "dumy", expectedType: crypto.Sr25519Type},
+	{testType: "beef", expectedType: crypto.Secp256k1Type},
 	{testType: "xxxx", expectedType: crypto.UnknownType},
 }
 
@@ -394,6 +396,17 @@ func TestDecodeKeyPairFromHex(t *testing.T) {
 	expectedPublic = "0xd3db685ed1f94c195dc3e72803fa3d8549df45381388e313fa8170f0b397895c"
 	require.Equal(t, kp.Public().Hex(), expectedPublic)
 
+	keytype = DetermineKeyType("beef")
+	keyBytes, err = common.HexToBytes("0x9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	require.NoError(t, err)
+
+	kp, err = DecodeKeyPairFromHex(keyBytes, keytype)
+	require.NoError(t, err)
+	require.IsType(t, &secp256k1.Keypair{}, kp)
+
+	expectedPublic = "0x028db55b05db86c0b1786ca49f095d76344c9e6056b2f02701a7e7f3c20aabfd91"
+	require.Equal(t, kp.Public().Hex(), expectedPublic)
+
 	_, err = DecodeKeyPairFromHex(nil, "")
 	require.Error(t, err, "cannot decode key: invalid key type")
 }
@@ -0,0 +1,299 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/utils"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// polkadotJSKeystoreVersion is the version of the polkadot-js keystore format supported
+const polkadotJSKeystoreVersion = "3"
+
+const (
+	polkadotJSSecretKeyLength = 64
+	polkadotJSPublicKeyLength = 32
+	polkadotJSNonceLength     = 24
+	// the encoded scrypt parameters are the salt followed by N, p and r as little endian u32
+	polkadotJSScryptParamsLength = scryptSaltLength + 3*4
+	polkadotJSScryptKeyLength    = 64
+)
+
+var (
+	polkadotJSEncodingType = []string{"scrypt", "xsalsa20-poly1305"}
+	// pkcs8 header and divider used by polkadot-js to wrap the secret and public keys
+	pkcs8Header  = []byte{48, 83, 2, 1, 1, 48, 5, 6, 3, 43, 101, 112, 4, 34, 4, 32}
+	pkcs8Divider = []byte{161, 35, 3, 33, 0}
+)
+
+var (
+	errUnsupportedPolkadotJSEncoding = errors.New("unsupported polkadot-js keystore encoding")
+	errUnsupportedPolkadotJSKeyType  = errors.New("unsupported polkadot-js key type")
+	errInvalidPolkadotJSEncoded      = errors.New("invalid polkadot-js encoded data")
+	errInvalidPolkadotJSPassword     = errors.New("invalid password for polkadot-js keystore")
+	errPolkadotJSPublicKeyMismatch   = errors.New("public key does not match secret key")
+)
+
+// PolkadotJSKeystore is the JSON keystore format used by polkadot-js to export and import
+// accounts. The key pair is pkcs8 encoded and encrypted with xsalsa20-poly1305 using a key
+// derived from the password with scrypt.
+type PolkadotJSKeystore struct {
+	Encoded  string                     `json:"encoded"`
+	Encoding PolkadotJSKeystoreEncoding `json:"encoding"`
+	Address  string                     `json:"address"`
+	Meta     map[string]interface{}     `json:"meta"`
+}
+
+// PolkadotJSKeystoreEncoding describes the content and the encryption of a polkadot-js keystore
+type PolkadotJSKeystoreEncoding struct {
+	Content []string `json:"content"`
+	Type    []string `json:"type"`
+	Version string   `json:"version"`
+}
+
+// EncodePolkadotJSKeystore encrypts the sr25519 or ed25519 `crypto.PrivateKey` using the password
+// and returns it encoded in the polkadot-js JSON keystore format
+func EncodePolkadotJSKeystore(priv crypto.PrivateKey, password []byte) ([]byte, error) {
+	var keytype string
+	var secretKey []byte
+	switch priv := priv.(type) {
+	case *sr25519.PrivateKey:
+		keytype = crypto.Sr25519Type
+		secretKey = priv.Ed25519Bytes()
+	case *ed25519.PrivateKey:
+		keytype = crypto.Ed25519Type
+		secretKey = priv.Encode()
+	default:
+		return nil, fmt.Errorf("%w: %T", errUnsupportedPolkadotJSKeyType, priv)
+	}
+
+	pub, err := priv.Public()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get public key: %w", err)
+	}
+
+	plaintext := bytes.Join([][]byte{pkcs8Header, secretKey, pkcs8Divider, pub.Encode()}, nil)
+
+	params, err := NewScryptParams()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := polkadotJSKey(password, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [polkadotJSNonceLength]byte
+	if _, err = io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	encoded := make([]byte, 0, polkadotJSScryptParamsLength+polkadotJSNonceLength+secretbox.Overhead+len(plaintext))
+	encoded = append(encoded, params.Salt...)
+	encoded = binary.LittleEndian.AppendUint32(encoded, uint32(params.N))
+	encoded = binary.LittleEndian.AppendUint32(encoded, uint32(params.P))
+	encoded = binary.LittleEndian.AppendUint32(encoded, uint32(params.R))
+	encoded = append(encoded, nonce[:]...)
+	encoded = secretbox.Seal(encoded, plaintext, &nonce, key)
+
+	keydata := &PolkadotJSKeystore{
+		Encoded: base64.StdEncoding.EncodeToString(encoded),
+		Encoding: PolkadotJSKeystoreEncoding{
+			Content: []string{"pkcs8", keytype},
+			Type:    polkadotJSEncodingType,
+			Version: polkadotJSKeystoreVersion,
+		},
+		Address: string(crypto.PublicKeyToAddress(pub)),
+		Meta:    map[string]interface{}{},
+	}
+
+	return json.MarshalIndent(keydata, "", "\t")
+}
+
+// DecodePolkadotJSKeystore decrypts the polkadot-js JSON keystore using the password into a
+// `crypto.PrivateKey`
+func DecodePolkadotJSKeystore(data, password []byte) (crypto.PrivateKey, error) {
+	keydata := new(PolkadotJSKeystore)
+	err := json.Unmarshal(data, keydata)
+	if err != nil {
+		return nil, err
+	}
+
+	if keydata.Encoding.Version != polkadotJSKeystoreVersion ||
+		!slices.Equal(keydata.Encoding.Type, polkadotJSEncodingType) ||
+		len(keydata.Encoding.Content) != 2 || keydata.Encoding.Content[0] != "pkcs8" {
+		return nil, fmt.Errorf("%w: version %q, type %v, content %v", errUnsupportedPolkadotJSEncoding,
+			keydata.Encoding.Version, keydata.Encoding.Type, keydata.Encoding.Content)
+	}
+
+	encoded, err := base64.StdEncoding.DecodeString(keydata.Encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64: %w", err)
+	}
+
+	if len(encoded) < polkadotJSScryptParamsLength+polkadotJSNonceLength+secretbox.Overhead {
+		return nil, fmt.Errorf("%w: %d bytes is too short", errInvalidPolkadotJSEncoded, len(encoded))
+	}
+
+	params := &ScryptParams{
+		Salt: encoded[:scryptSaltLength],
+		N:    int(binary.LittleEndian.Uint32(encoded[scryptSaltLength:])),
+		P:    int(binary.LittleEndian.Uint32(encoded[scryptSaltLength+4:])),
+		R:    int(binary.LittleEndian.Uint32(encoded[scryptSaltLength+8:])),
+	}
+	encoded = encoded[polkadotJSScryptParamsLength:]
+
+	// as polkadot-js does, only the default parameters are accepted to avoid excessive
+	// resources usage when deriving the key
+	if params.N != scryptN || params.P != scryptP || params.R != scryptR {
+		return nil, fmt.Errorf("%w: unexpected scrypt parameters N=%d, p=%d, r=%d",
+			errInvalidPolkadotJSEncoded, params.N, params.P, params.R)
+	}
+
+	key, err := polkadotJSKey(password, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [polkadotJSNonceLength]byte
+	copy(nonce[:], encoded)
+	plaintext, ok := secretbox.Open(nil, encoded[polkadotJSNonceLength:], &nonce, key)
+	if !ok {
+		return nil, errInvalidPolkadotJSPassword
+	}
+
+	secretKey, publicKey, err := decodePkcs8(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	var priv crypto.PrivateKey
+	switch keydata.Encoding.Content[1] {
+	case crypto.Sr25519Type:
+		priv, err = sr25519.NewPrivateKeyFromEd25519Bytes(secretKey)
+	case crypto.Ed25519Type:
+		priv, err = ed25519.NewPrivateKey(secretKey)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedPolkadotJSKeyType, keydata.Encoding.Content[1])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding private key: %w", err)
+	}
+
+	pub, err := priv.Public()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get public key: %w", err)
+	}
+
+	if !bytes.Equal(pub.Encode(), publicKey) {
+		return nil, fmt.Errorf("%w: expected 0x%x, got %s", errPolkadotJSPublicKeyMismatch, publicKey, pub.Hex())
+	}
+
+	return priv, nil
+}
+
+// ImportPolkadotJSKeypair decrypts the polkadot-js keystore file using the password, and saves
+// the key pair to basepath/keystore/[public key].key encrypted using the same password
+func ImportPolkadotJSKeypair(fp, basepath string, password []byte) (string, error) {
+	data, err := os.ReadFile(filepath.Clean(fp))
+	if err != nil {
+		return "", fmt.Errorf("failed to read keystore file: %w", err)
+	}
+
+	priv, err := DecodePolkadotJSKeystore(data, password)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode polkadot-js keystore: %w", err)
+	}
+
+	pub, err := priv.Public()
+	if err != nil {
+		return "", fmt.Errorf("cannot get public key: %w", err)
+	}
+
+	keyDir, err := utils.KeystoreDir(basepath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get keystore directory: %w", err)
+	}
+
+	keyFilePath, err := filepath.Abs(filepath.Join(keyDir, hex.EncodeToString(pub.Encode())+".key"))
+	if err != nil {
+		return "", fmt.Errorf("failed to create keystore filepath: %w", err)
+	}
+
+	err = EncryptAndWriteToFile(keyFilePath, priv, password)
+	if err != nil {
+		return "", fmt.Errorf("failed to write key to file: %w", err)
+	}
+
+	return keyFilePath, nil
+}
+
+// ExportPolkadotJSKeypair decrypts the keystore file using the password, and writes the key pair
+// to the destination file in the polkadot-js JSON keystore format encrypted using the same password
+func ExportPolkadotJSKeypair(fp, destination string, password []byte) error {
+	priv, err := ReadFromFileAndDecrypt(fp, password)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt key file: %w", err)
+	}
+
+	data, err := EncodePolkadotJSKeystore(priv, password)
+	if err != nil {
+		return fmt.Errorf("failed to encode polkadot-js keystore: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Clean(destination), append(data, byte('\n')), 0600)
+	if err != nil {
+		return fmt.Errorf("cannot write to destination file: %w", err)
+	}
+
+	return nil
+}
+
+// polkadotJSKey derives the xsalsa20-poly1305 key from the password, polkadot-js uses the
+// first 32 bytes of a 64 bytes scrypt derived key.
+func polkadotJSKey(password []byte, params *ScryptParams) (*[32]byte, error) {
+	derived, err := scrypt.Key(password, params.Salt, params.N, params.R, params.P, polkadotJSScryptKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+
+	var key [32]byte
+	copy(key[:], derived)
+	return &key, nil
+}
+
+// decodePkcs8 returns the secret and public keys of the pkcs8 encoded key pair
+func decodePkcs8(in []byte) (secretKey, publicKey []byte, err error) {
+	const expectedLength = 16 + polkadotJSSecretKeyLength + 5 + polkadotJSPublicKeyLength
+	if len(in) != expectedLength || !bytes.HasPrefix(in, pkcs8Header) {
+		return nil, nil, fmt.Errorf("%w: invalid pkcs8 header", errInvalidPolkadotJSEncoded)
+	}
+
+	in = in[len(pkcs8Header):]
+	secretKey, in = in[:polkadotJSSecretKeyLength], in[polkadotJSSecretKeyLength:]
+	if !bytes.HasPrefix(in, pkcs8Divider) {
+		return nil, nil, fmt.Errorf("%w: invalid pkcs8 divider", errInvalidPolkadotJSEncoded)
+	}
+
+	return secretKey, in[len(pkcs8Divider):], nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package keystore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeAndDecodePolkadotJSKeystore(t *testing.T) {
+	t.Parallel()
+
+	sr25519Keypair, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	ed25519Keypair, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		keypair PublicPrivater
+		keytype string
+	}{
+		"sr25519": {keypair: sr25519Keypair, keytype: crypto.Sr25519Type},
+		"ed25519": {keypair: ed25519Keypair, keytype: crypto.Ed25519Type},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			password := []byte("noot")
+			data, err := EncodePolkadotJSKeystore(testCase.keypair.Private(), password)
+			require.NoError(t, err)
+
+			keydata := new(PolkadotJSKeystore)
+			err = json.Unmarshal(data, keydata)
+			require.NoError(t, err)
+			assert.Equal(t, PolkadotJSKeystoreEncoding{
+				Content: []string{"pkcs8", testCase.keytype},
+				Type:    []string{"scrypt", "xsalsa20-poly1305"},
+				Version: "3",
+			}, keydata.Encoding)
+			assert.Equal(t, string(crypto.PublicKeyToAddress(testCase.keypair.Public())), keydata.Address)
+
+			priv, err := DecodePolkadotJSKeystore(data, password)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.keypair.Private().Encode(), priv.Encode())
+
+			_, err = DecodePolkadotJSKeystore(data, []byte("toon"))
+			assert.ErrorIs(t, err, errInvalidPolkadotJSPassword)
+		})
+	}
+}
+
+func TestEncodePolkadotJSKeystore_UnsupportedKeyType(t *testing.T) {
+	t.Parallel()
+
+	kp, err := secp256k1.GenerateKeypair()
+	require.NoError(t, err)
+
+	_, err = EncodePolkadotJSKeystore(kp.Private(), []byte("noot"))
+	assert.ErrorIs(t, err, errUnsupportedPolkadotJSKeyType)
+}
+
+func TestDecodePolkadotJSKeystore_Errors(t *testing.T) {
+	t.Parallel()
+
+	validEncoding := PolkadotJSKeystoreEncoding{
+		Content: []string{"pkcs8", "sr25519"},
+		Type:    []string{"scrypt", "xsalsa20-poly1305"},
+		Version: "3",
+	}
+	// salt followed by N=1<<14, p=1 and r=8
+	unexpectedParams := append(make([]byte, 32), 0, 0x40, 0, 0, 1, 0, 0, 0, 8, 0, 0, 0)
+	unexpectedParams = append(unexpectedParams, make([]byte, 24+16)...)
+
+	testCases := map[string]struct {
+		keystore   PolkadotJSKeystore
+		errWrapped error
+		errMessage string
+	}{
+		"unencrypted": {
+			keystore: PolkadotJSKeystore{Encoding: PolkadotJSKeystoreEncoding{
+				Content: []string{"pkcs8", "sr25519"},
+				Type:    []string{"none"},
+				Version: "3",
+			}},
+			errWrapped: errUnsupportedPolkadotJSEncoding,
+			errMessage: "unsupported polkadot-js keystore encoding: " +
+				"version \"3\", type [none], content [pkcs8 sr25519]",
+		},
+		"too_short": {
+			keystore: PolkadotJSKeystore{
+				Encoded:  base64.StdEncoding.EncodeToString([]byte{1, 2, 3}),
+				Encoding: validEncoding,
+			},
+			errWrapped: errInvalidPolkadotJSEncoded,
+			errMessage: "invalid polkadot-js encoded data: 3 bytes is too short",
+		},
+		"unexpected_scrypt_params": {
+			keystore: PolkadotJSKeystore{
+				Encoded:  base64.StdEncoding.EncodeToString(unexpectedParams),
+				Encoding: validEncoding,
+			},
+			errWrapped: errInvalidPolkadotJSEncoded,
+			errMessage: "invalid polkadot-js encoded data: unexpected scrypt parameters N=16384, p=1, r=8",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(testCase.keystore)
+			require.NoError(t, err)
+
+			_, err = DecodePolkadotJSKeystore(data, []byte("noot"))
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			assert.EqualError(t, err, testCase.errMessage)
+		})
+	}
+}
+
+func TestImportAndExportPolkadotJSKeypair(t *testing.T) {
+	t.Parallel()
+
+	testdir := t.TempDir()
+	password := []byte("noot")
+
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	data, err := EncodePolkadotJSKeystore(kp.Private(), password)
+	require.NoError(t, err)
+	polkadotJSFile := filepath.Join(testdir, "polkadotjs.json")
+	err = os.WriteFile(polkadotJSFile, data, 0600)
+	require.NoError(t, err)
+
+	keyfile, err := ImportPolkadotJSKeypair(polkadotJSFile, testdir, password)
+	require.NoError(t, err)
+	assert.Equal(t, kp.Public().Hex()[2:]+".key", filepath.Base(keyfile))
+
+	priv, err := ReadFromFileAndDecrypt(keyfile, password)
+	require.NoError(t, err)
+	assert.Equal(t, kp.Private().Encode(), priv.Encode())
+
+	exportedFile := filepath.Join(testdir, "exported.json")
+	err = ExportPolkadotJSKeypair(keyfile, exportedFile, password)
+	require.NoError(t, err)
+
+	data, err = os.ReadFile(exportedFile)
+	require.NoError(t, err)
+	priv, err = DecodePolkadotJSKeystore(data, password)
+	require.NoError(t, err)
+	assert.Equal(t, kp.Private().Encode(), priv.Encode())
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+)
+
+// EncodeBlockToExecute returns the SCALE encoding of the block given to
+// Core_execute_block, which is the block without its seal digest.
+func EncodeBlockToExecute(block *types.Block) ([]byte, error) {
+	// copy block since we're going to modify it
+	b, err := block.DeepCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	b.Header.Digest = types.NewDigest()
+
+	// remove seal digest only
+	for _, d := range block.Header.Digest {
+		digestValue, err := d.Value()
+		if err != nil {
+			return nil, fmt.Errorf("getting digest type value: %w", err)
+		}
+		switch digestValue.(type) {
+		case types.SealDigest:
+			continue
+		default:
+			err = b.Header.Digest.Add(digestValue)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return b.Encode()
+}
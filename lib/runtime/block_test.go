@@ -0,0 +1,41 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeBlockToExecute(t *testing.T) {
+	t.Parallel()
+
+	preRuntimeDigest := types.PreRuntimeDigest{ConsensusEngineID: types.BabeEngineID, Data: []byte{1}}
+	block := types.Block{
+		Header: types.Header{Number: 1, Digest: types.NewDigest()},
+		Body:   types.Body{{2}},
+	}
+	require.NoError(t, block.Header.Digest.Add(
+		preRuntimeDigest,
+		types.SealDigest{ConsensusEngineID: types.BabeEngineID, Data: []byte{3}},
+	))
+
+	expectedBlock := types.Block{
+		Header: types.Header{Number: 1, Digest: types.NewDigest()},
+		Body:   types.Body{{2}},
+	}
+	require.NoError(t, expectedBlock.Header.Digest.Add(preRuntimeDigest))
+	expected, err := expectedBlock.Encode()
+	require.NoError(t, err)
+
+	encoded, err := EncodeBlockToExecute(&block)
+	require.NoError(t, err)
+	assert.Equal(t, expected, encoded)
+
+	// the given block keeps its seal
+	assert.Len(t, block.Header.Digest, 2)
+}
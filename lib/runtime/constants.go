@@ -54,6 +54,10 @@
 	GrandpaGenerateKeyOwnershipProof = "GrandpaApi_generate_key_ownership_proof"
 	// BabeAPIConfiguration is the runtime API call BabeApi_configuration
 	BabeAPIConfiguration = "BabeApi_configuration"
+	// AuraAPISlotDuration is the runtime API call AuraApi_slot_duration
+	AuraAPISlotDuration = "AuraApi_slot_duration"
+	// AuraAPIAuthorities is the runtime API call AuraApi_authorities
+	AuraAPIAuthorities = "AuraApi_authorities"
 	// BlockBuilderInherentExtrinsics is the runtime API call BlockBuilder_inherent_extrinsics
 	BlockBuilderInherentExtrinsics = "BlockBuilder_inherent_extrinsics"
 	// BlockBuilderApplyExtrinsic is the runtime API call BlockBuilder_apply_extrinsic
@@ -62,10 +66,14 @@
 	BlockBuilderFinalizeBlock = "BlockBuilder_finalize_block"
 	// DecodeSessionKeys is the runtime API call SessionKeys_decode_session_keys
 	DecodeSessionKeys = "SessionKeys_decode_session_keys"
+	// GenerateSessionKeys is the runtime API call SessionKeys_generate_session_keys
+	GenerateSessionKeys = "SessionKeys_generate_session_keys"
 	// TransactionPaymentAPIQueryInfo returns information of a given extrinsic
 	TransactionPaymentAPIQueryInfo = "TransactionPaymentApi_query_info"
 	// TransactionPaymentCallAPIQueryCallInfo returns call query call info
 	TransactionPaymentCallAPIQueryCallInfo = "TransactionPaymentCallApi_query_call_info"
 	// TransactionPaymentCallAPIQueryCallFeeDetails returns call query call fee details
 	TransactionPaymentCallAPIQueryCallFeeDetails = "TransactionPaymentCallApi_query_call_fee_details"
+	// OffchainWorkerAPIOffchainWorker is the runtime API call OffchainWorkerApi_offchain_worker
+	OffchainWorkerAPIOffchainWorker = "OffchainWorkerApi_offchain_worker"
 )
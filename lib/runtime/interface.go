@@ -18,13 +18,17 @@ type Instance interface {
 	NetworkService() BasicNetwork
 	Keystore() *keystore.GlobalKeystore
 	Validator() bool
+	CompilationCacheDir() string
 	Exec(function string, data []byte) ([]byte, error)
+	ExecWithStorage(storage Storage, function string, data []byte) ([]byte, error)
 	SetContextStorage(s Storage)
 	GetCodeHash() common.Hash
 	Version() (Version, error)
 	Metadata() (metadata []byte, err error)
 	BabeConfiguration() (*types.BabeConfiguration, error)
 	GrandpaAuthorities() ([]types.Authority, error)
+	AuraSlotDuration() (uint64, error)
+	AuraAuthorities() ([]types.Authority, error)
 	ValidateTransaction(e types.Extrinsic) (*transaction.Validity, error)
 	InitializeBlock(header *types.Header) error
 	InherentExtrinsics(data []byte) ([]byte, error)
@@ -41,8 +45,8 @@ type Instance interface {
 		keyOwnershipProof types.OpaqueKeyOwnershipProof,
 	) error
 	RandomSeed()
-	OffchainWorker()
-	GenerateSessionKeys()
+	OffchainWorker(header *types.Header) error
+	GenerateSessionKeys(seed *[]byte) ([]byte, error)
 	GrandpaGenerateKeyOwnershipProof(authSetID uint64, authorityID ed25519.PublicKeyBytes) (
 		types.GrandpaOpaqueKeyOwnershipProof, error)
 	GrandpaSubmitReportEquivocationUnsignedExtrinsic(
@@ -4,6 +4,7 @@
 package runtime
 
 import (
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/pkg/trie"
@@ -72,3 +73,8 @@ type BasicStorage interface {
 type TransactionState interface {
 	AddToPool(vt *transaction.ValidTransaction) common.Hash
 }
+
+// TransactionSubmitter interface for submitting the extrinsics of offchain workers to the transaction pool
+type TransactionSubmitter interface {
+	HandleSubmittedExtrinsic(ext types.Extrinsic) error
+}
@@ -45,6 +45,50 @@ func (_m *Instance) ApplyExtrinsic(data types.Extrinsic) ([]byte, error) {
 	return r0, r1
 }
 
+// AuraAuthorities provides a mock function with given fields:
+func (_m *Instance) AuraAuthorities() ([]types.Authority, error) {
+	ret := _m.Called()
+
+	var r0 []types.Authority
+	if rf, ok := ret.Get(0).(func() []types.Authority); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Authority)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuraSlotDuration provides a mock function with given fields:
+func (_m *Instance) AuraSlotDuration() (uint64, error) {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BabeConfiguration provides a mock function with given fields:
 func (_m *Instance) BabeConfiguration() (*types.BabeConfiguration, error) {
 	ret := _m.Called()
@@ -73,6 +117,20 @@ func (_m *Instance) CheckInherents() {
 	_m.Called()
 }
 
+// CompilationCacheDir provides a mock function with given fields:
+func (_m *Instance) CompilationCacheDir() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // DecodeSessionKeys provides a mock function with given fields: enc
 func (_m *Instance) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	ret := _m.Called(enc)
@@ -119,6 +177,29 @@ func (_m *Instance) Exec(function string, data []byte) ([]byte, error) {
 	return r0, r1
 }
 
+// ExecWithStorage provides a mock function with given fields: storage, function, data
+func (_m *Instance) ExecWithStorage(storage runtime.Storage, function string, data []byte) ([]byte, error) {
+	ret := _m.Called(storage, function, data)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(runtime.Storage, string, []byte) []byte); ok {
+		r0 = rf(storage, function, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(runtime.Storage, string, []byte) error); ok {
+		r1 = rf(storage, function, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExecuteBlock provides a mock function with given fields: block
 func (_m *Instance) ExecuteBlock(block *types.Block) ([]byte, error) {
 	ret := _m.Called(block)
@@ -165,9 +246,27 @@ func (_m *Instance) FinalizeBlock() (*types.Header, error) {
 	return r0, r1
 }
 
-// GenerateSessionKeys provides a mock function with given fields:
-func (_m *Instance) GenerateSessionKeys() {
-	_m.Called()
+// GenerateSessionKeys provides a mock function with given fields: seed
+func (_m *Instance) GenerateSessionKeys(seed *[]byte) ([]byte, error) {
+	ret := _m.Called(seed)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(*[]byte) []byte); ok {
+		r0 = rf(seed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*[]byte) error); ok {
+		r1 = rf(seed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetCodeHash provides a mock function with given fields:
@@ -352,9 +451,18 @@ func (_m *Instance) NodeStorage() runtime.NodeStorage {
 	return r0
 }
 
-// OffchainWorker provides a mock function with given fields:
-func (_m *Instance) OffchainWorker() {
-	_m.Called()
+// OffchainWorker provides a mock function with given fields: header
+func (_m *Instance) OffchainWorker(header *types.Header) error {
+	ret := _m.Called(header)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Header) error); ok {
+		r0 = rf(header)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // PaymentQueryInfo provides a mock function with given fields: ext
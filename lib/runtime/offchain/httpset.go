@@ -4,23 +4,23 @@
 package offchain
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
-)
-
-type contextKey string
-
-const (
-	waitingKey contextKey = "waiting"
-	invalidKey contextKey = "invalid"
+	"time"
 )
 
 const maxConcurrentRequests = 1000
 
+// bodyChunkSize is the maximum size of the response body chunks read from the connection
+const bodyChunkSize = 16 * 1024
+
 var (
 	errIntBufferEmpty        = errors.New("int buffer exhausted")
 	errIntBufferFull         = errors.New("int buffer is full")
@@ -29,6 +29,60 @@
 	errInvalidHeaderKey      = errors.New("invalid header key")
 )
 
+// HTTPError is the error of an offchain HTTP request operation, encoded
+// for the runtime as the HttpError enum whose indices start at 1.
+type HTTPError uint8
+
+const (
+	// ErrDeadlineReached is returned when the deadline is reached before the operation completes.
+	ErrDeadlineReached HTTPError = iota + 1
+	// ErrIO is returned when an error occurs while sending the request or receiving the response.
+	ErrIO
+	// ErrInvalidID is returned when the request id is unknown or the
+	// request cannot perform the operation in its current state.
+	ErrInvalidID
+)
+
+func (e HTTPError) Error() string {
+	switch e {
+	case ErrDeadlineReached:
+		return "deadline reached"
+	case ErrIO:
+		return "io error"
+	case ErrInvalidID:
+		return "invalid request id"
+	default:
+		return fmt.Sprintf("unknown http error %d", uint8(e))
+	}
+}
+
+// HTTPRequestStatus is the status of an offchain HTTP request returned by HTTPSet.Wait,
+// encoded for the runtime as the HttpRequestStatus enum.
+type HTTPRequestStatus struct {
+	// Finished is true if the response of the request was received.
+	Finished bool
+	// StatusCode is the HTTP status code of the response of a finished request.
+	StatusCode uint16
+	// Err is the error of a request which is not finished.
+	Err HTTPError
+}
+
+// MarshalSCALE encodes the request status as the HttpRequestStatus enum, whose
+// error variants are in the HttpError order starting at index 0, and where the
+// finished variant has the index 3 and holds the status code.
+func (s HTTPRequestStatus) MarshalSCALE() ([]byte, error) {
+	if !s.Finished {
+		return []byte{byte(s.Err - ErrDeadlineReached)}, nil
+	}
+	return []byte{3, byte(s.StatusCode), byte(s.StatusCode >> 8)}, nil
+}
+
+// HTTPHeader is a HTTP header name and value pair.
+type HTTPHeader struct {
+	Name  []byte
+	Value []byte
+}
+
 // requestIDBuffer created to control the amount of available non-duplicated ids
 type requestIDBuffer chan int16
 
@@ -60,16 +114,47 @@ func (b requestIDBuffer) put(i int16) error {
 	}
 }
 
-// Request holds the request object and update the invalid and waiting status whenever
-// the request starts or is waiting to be read
+// requestState is the lifecycle state of a request
+type requestState uint8
+
+const (
+	// requestPending is the state of a request accepting headers and body chunks
+	requestPending requestState = iota
+	// requestWritingBody is the state of a request accepting body chunks only
+	requestWritingBody
+	// requestDispatched is the state of a request whose body is finalised and which is being sent
+	requestDispatched
+)
+
+// Request holds the request object along its lifecycle: headers and body chunks are
+// added until the body is finalised, then the request is sent and its response can be read.
 type Request struct {
 	Request *http.Request
+
+	client *http.Client
+	mutex  sync.Mutex
+	state  requestState
+	body   bytes.Buffer
+	cancel context.CancelFunc
+
+	// responseReady is closed once the response headers are received or sending the request failed
+	responseReady chan struct{}
+	response      *http.Response
+	err           error
+
+	// chunks receives the response body chunks and is closed once the body is read
+	chunks chan []byte
+	// unread is the part of the last received chunk not read yet
+	unread  []byte
+	bodyErr error
 }
 
 // AddHeader adds a new HTTP header into request property, only if request is valid
 func (r *Request) AddHeader(name, value string) error {
-	invalid, ok := r.Request.Context().Value(invalidKey).(bool)
-	if ok && invalid {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.state != requestPending {
 		return errRequestInvalid
 	}
 
@@ -82,20 +167,221 @@ func (r *Request) AddHeader(name, value string) error {
 	return nil
 }
 
+// WriteBody appends the chunk given to the request body. An empty chunk finalises
+// the body and sends the request. No header can be added once a chunk is written.
+func (r *Request) WriteBody(chunk []byte, deadline *time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.state == requestDispatched {
+		return ErrInvalidID
+	}
+
+	if deadline != nil && !time.Now().Before(*deadline) {
+		return ErrDeadlineReached
+	}
+
+	if len(chunk) == 0 {
+		r.dispatch()
+		return nil
+	}
+
+	r.state = requestWritingBody
+	r.body.Write(chunk)
+	return nil
+}
+
+// dispatch finalises the request body and sends the request in the background.
+// It must be called with the request mutex locked.
+func (r *Request) dispatch() {
+	if r.state == requestDispatched {
+		return
+	}
+	r.state = requestDispatched
+
+	client := r.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	ctx, cancel := context.WithCancel(r.Request.Context())
+	r.cancel = cancel
+	request := r.Request.WithContext(ctx)
+	if r.body.Len() > 0 {
+		request.Body = io.NopCloser(bytes.NewReader(r.body.Bytes()))
+		request.ContentLength = int64(r.body.Len())
+	}
+
+	r.responseReady = make(chan struct{})
+	r.chunks = make(chan []byte)
+
+	go func() {
+		response, err := client.Do(request) //nolint:bodyclose
+		r.mutex.Lock()
+		r.response, r.err = response, err
+		r.mutex.Unlock()
+		close(r.responseReady)
+
+		if err != nil {
+			close(r.chunks)
+			return
+		}
+		r.readBody(ctx, response.Body)
+	}()
+}
+
+// readBody sends the response body chunks to the chunks channel until the
+// body is fully read or the request is cancelled.
+func (r *Request) readBody(ctx context.Context, body io.ReadCloser) {
+	defer body.Close()
+	defer close(r.chunks)
+
+	for {
+		buffer := make([]byte, bodyChunkSize)
+		n, err := body.Read(buffer)
+		if n > 0 {
+			select {
+			case r.chunks <- buffer[:n]:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return
+		} else if err != nil {
+			r.mutex.Lock()
+			r.bodyErr = err
+			r.mutex.Unlock()
+			return
+		}
+	}
+}
+
+// waitResponse sends the request if its body is not finalised yet, and waits for
+// its response until the deadline channel given is closed.
+func (r *Request) waitResponse(deadline <-chan struct{}) HTTPRequestStatus {
+	r.mutex.Lock()
+	r.dispatch()
+	responseReady := r.responseReady
+	r.mutex.Unlock()
+
+	if !received(responseReady, deadline) {
+		return HTTPRequestStatus{Err: ErrDeadlineReached}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		return HTTPRequestStatus{Err: ErrIO}
+	}
+	return HTTPRequestStatus{Finished: true, StatusCode: uint16(r.response.StatusCode)}
+}
+
+// responseHeaders returns the response headers sorted by name, or nil if the response is not received.
+func (r *Request) responseHeaders() []HTTPHeader {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.response == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.response.Header))
+	for name := range r.response.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var headers []HTTPHeader
+	for _, name := range names {
+		for _, value := range r.response.Header[name] {
+			headers = append(headers, HTTPHeader{
+				Name:  []byte(strings.ToLower(name)),
+				Value: []byte(value),
+			})
+		}
+	}
+	return headers
+}
+
+// readResponseBody reads the response body into the buffer given until the deadline
+// channel given is closed. It returns zero once the body is fully read.
+func (r *Request) readResponseBody(buffer []byte, deadline <-chan struct{}) (n int, err error) {
+	r.mutex.Lock()
+	state, responseReady, chunks := r.state, r.responseReady, r.chunks
+	r.mutex.Unlock()
+
+	if state != requestDispatched {
+		return 0, ErrInvalidID
+	}
+
+	if !received(responseReady, deadline) {
+		return 0, ErrDeadlineReached
+	}
+
+	r.mutex.Lock()
+	responseErr, unread := r.err, r.unread
+	r.mutex.Unlock()
+
+	if responseErr != nil {
+		return 0, ErrIO
+	}
+
+	if len(unread) == 0 {
+		var ok bool
+		select {
+		case unread, ok = <-chunks:
+		default:
+			select {
+			case unread, ok = <-chunks:
+			case <-deadline:
+				return 0, ErrDeadlineReached
+			}
+		}
+
+		if !ok {
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			if r.bodyErr != nil {
+				return 0, ErrIO
+			}
+			return 0, nil
+		}
+	}
+
+	n = copy(buffer, unread)
+	r.mutex.Lock()
+	r.unread = unread[n:]
+	r.mutex.Unlock()
+	return n, nil
+}
+
+func (r *Request) close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
 // HTTPSet holds a pool of concurrent http request calls
 type HTTPSet struct {
 	*sync.Mutex
 	reqs   map[int16]*Request
 	idBuff requestIDBuffer
+	client *http.Client
 }
 
 // NewHTTPSet creates a offchain http set that can be used
 // by runtime as HTTP clients, the max concurrent requests is 1000
 func NewHTTPSet() *HTTPSet {
 	return &HTTPSet{
-		new(sync.Mutex),
-		make(map[int16]*Request),
-		newIntBuffer(maxConcurrentRequests),
+		Mutex:  new(sync.Mutex),
+		reqs:   make(map[int16]*Request),
+		idBuff: newIntBuffer(maxConcurrentRequests),
+		client: new(http.Client),
 	}
 }
 
@@ -105,29 +391,24 @@ func (p *HTTPSet) StartRequest(method, uri string) (int16, error) {
 	p.Lock()
 	defer p.Unlock()
 
-	id, err := p.idBuff.get()
+	req, err := http.NewRequest(method, uri, nil)
 	if err != nil {
 		return 0, err
 	}
-
-	if _, ok := p.reqs[id]; ok {
-		return 0, errRequestIDNotAvailable
-	}
-
-	req, err := http.NewRequest(method, uri, nil)
 	req.Header = make(http.Header)
 
-	ctx := context.WithValue(req.Context(), waitingKey, false)
-	ctx = context.WithValue(ctx, invalidKey, false)
-
-	req = req.WithContext(ctx)
-
+	id, err := p.idBuff.get()
 	if err != nil {
 		return 0, err
 	}
 
+	if _, ok := p.reqs[id]; ok {
+		return 0, errRequestIDNotAvailable
+	}
+
 	p.reqs[id] = &Request{
 		Request: req,
+		client:  p.client,
 	}
 
 	return id, nil
@@ -138,6 +419,11 @@ func (p *HTTPSet) Remove(id int16) error {
 	p.Lock()
 	defer p.Unlock()
 
+	req, ok := p.reqs[id]
+	if !ok {
+		return nil
+	}
+	req.close()
 	delete(p.reqs, id)
 
 	return p.idBuff.put(id)
@@ -150,3 +436,96 @@ func (p *HTTPSet) Get(id int16) *Request {
 
 	return p.reqs[id]
 }
+
+// WriteBody writes a chunk of the body of the request with the given id,
+// an empty chunk finalising the body. The deadline is optional.
+func (p *HTTPSet) WriteBody(id int16, chunk []byte, deadline *time.Time) error {
+	req := p.Get(id)
+	if req == nil {
+		return ErrInvalidID
+	}
+
+	return req.WriteBody(chunk, deadline)
+}
+
+// Wait sends the requests with the given ids whose body is not finalised yet, and waits
+// until their responses are received, the optional deadline is reached or the context
+// is done. It returns the status of each request, in the order of the ids given.
+func (p *HTTPSet) Wait(ctx context.Context, ids []int16, deadline *time.Time) []HTTPRequestStatus {
+	ctx, cancel := withDeadline(ctx, deadline)
+	defer cancel()
+
+	statuses := make([]HTTPRequestStatus, len(ids))
+	for i, id := range ids {
+		req := p.Get(id)
+		if req == nil {
+			statuses[i] = HTTPRequestStatus{Err: ErrInvalidID}
+			continue
+		}
+
+		statuses[i] = req.waitResponse(ctx.Done())
+		if !statuses[i].Finished && statuses[i].Err == ErrIO {
+			_ = p.Remove(id)
+		}
+	}
+
+	return statuses
+}
+
+// ResponseHeaders returns the response headers of the request with the given id,
+// or nil if the request is unknown or its response is not received yet.
+func (p *HTTPSet) ResponseHeaders(id int16) []HTTPHeader {
+	req := p.Get(id)
+	if req == nil {
+		return nil
+	}
+	return req.responseHeaders()
+}
+
+// ReadBody reads the response body of the request with the given id into the buffer
+// given, waiting for the response until the optional deadline or until the context is
+// done, the latter being reported as the deadline being reached. It returns the number
+// of bytes read, zero meaning the body is fully read. The request is removed once its
+// body is fully read or when an error other than the deadline being reached occurs.
+func (p *HTTPSet) ReadBody(ctx context.Context, id int16, buffer []byte, deadline *time.Time) (n int, err error) {
+	req := p.Get(id)
+	if req == nil {
+		return 0, ErrInvalidID
+	}
+
+	ctx, cancel := withDeadline(ctx, deadline)
+	defer cancel()
+
+	n, err = req.readResponseBody(buffer, ctx.Done())
+	if (err == nil && n == 0) || (err != nil && !errors.Is(err, ErrDeadlineReached)) {
+		_ = p.Remove(id)
+	}
+	return n, err
+}
+
+// received returns true once the channel given is closed, or false if the deadline is
+// reached first. A closed channel is received even if the deadline is already reached.
+func received(ch <-chan struct{}, deadline <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+	}
+
+	select {
+	case <-ch:
+		return true
+	case <-deadline:
+		return false
+	}
+}
+
+// withDeadline returns a copy of the context given which is done once the optional
+// deadline is reached, so waits are always bounded by the context of the runtime call
+// even when the runtime gives no deadline.
+func withDeadline(ctx context.Context, deadline *time.Time) (context.Context, context.CancelFunc) {
+	if deadline == nil {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, *deadline)
+}
@@ -6,9 +6,14 @@
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,26 +59,25 @@ func TestHTTPSetGet(t *testing.T) {
 func TestOffchainRequest_AddHeader(t *testing.T) {
 	t.Parallel()
 
-	invalidCtx := context.WithValue(context.Background(), invalidKey, true)
-	invalidReq, err := http.NewRequestWithContext(invalidCtx, http.MethodGet, "http://test.com", nil)
+	invalidReq, err := http.NewRequest(http.MethodGet, "http://test.com", nil)
 	require.NoError(t, err)
 
 	cases := map[string]struct {
-		offReq           Request
+		offReq           *Request
 		err              error
 		headerK, headerV string
 	}{
 		"should_return_invalid_request": {
-			offReq: Request{invalidReq},
+			offReq: &Request{Request: invalidReq, state: requestWritingBody},
 			err:    errRequestInvalid,
 		},
 		"should_add_header": {
-			offReq:  Request{Request: &http.Request{Header: make(http.Header)}},
+			offReq:  &Request{Request: &http.Request{Header: make(http.Header)}},
 			headerK: "key",
 			headerV: "value",
 		},
 		"should_return_invalid_empty_header": {
-			offReq:  Request{Request: &http.Request{Header: make(http.Header)}},
+			offReq:  &Request{Request: &http.Request{Header: make(http.Header)}},
 			headerK: "",
 			headerV: "value",
 			err:     fmt.Errorf("%w: %s", errInvalidHeaderKey, "empty header key"),
@@ -101,3 +105,194 @@ func TestOffchainRequest_AddHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPSet_RequestLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		w.Header().Set("X-Echo", r.Header.Get("X-Request"))
+		w.WriteHeader(http.StatusCreated)
+		_, err = w.Write(append([]byte("echo "), body...))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	set := NewHTTPSet()
+	id, err := set.StartRequest(http.MethodPost, server.URL)
+	require.NoError(t, err)
+
+	err = set.Get(id).AddHeader("X-Request", "price")
+	require.NoError(t, err)
+
+	err = set.WriteBody(id, []byte("hello "), nil)
+	require.NoError(t, err)
+	err = set.WriteBody(id, []byte("world"), nil)
+	require.NoError(t, err)
+
+	err = set.Get(id).AddHeader("X-Late", "value")
+	require.ErrorIs(t, err, errRequestInvalid)
+
+	assert.Nil(t, set.ResponseHeaders(id))
+
+	deadline := time.Now().Add(time.Minute)
+	statuses := set.Wait(context.Background(), []int16{id, id + 1}, &deadline)
+	expectedStatuses := []HTTPRequestStatus{
+		{Finished: true, StatusCode: http.StatusCreated},
+		{Err: ErrInvalidID},
+	}
+	require.Equal(t, expectedStatuses, statuses)
+
+	err = set.WriteBody(id, []byte("too late"), nil)
+	require.ErrorIs(t, err, ErrInvalidID)
+
+	headers := set.ResponseHeaders(id)
+	assert.Contains(t, headers, HTTPHeader{Name: []byte("x-echo"), Value: []byte("price")})
+
+	var body []byte
+	buffer := make([]byte, 4)
+	for {
+		n, err := set.ReadBody(context.Background(), id, buffer, &deadline)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+		body = append(body, buffer[:n]...)
+	}
+	assert.Equal(t, "echo hello world", string(body))
+
+	// the request is removed once its body is read
+	assert.Nil(t, set.Get(id))
+	_, err = set.ReadBody(context.Background(), id, buffer, nil)
+	assert.ErrorIs(t, err, ErrInvalidID)
+}
+
+func TestHTTPSet_Wait_deadlineReached(t *testing.T) {
+	t.Parallel()
+
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(unblock)
+		server.Close()
+	})
+
+	set := NewHTTPSet()
+	id, err := set.StartRequest(http.MethodGet, server.URL)
+	require.NoError(t, err)
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	statuses := set.Wait(context.Background(), []int16{id}, &deadline)
+	assert.Equal(t, []HTTPRequestStatus{{Err: ErrDeadlineReached}}, statuses)
+
+	_, err = set.ReadBody(context.Background(), id, make([]byte, 1), &deadline)
+	assert.ErrorIs(t, err, ErrDeadlineReached)
+
+	// the request is kept after its deadline is reached
+	require.NotNil(t, set.Get(id))
+	require.NoError(t, set.Remove(id))
+}
+
+func TestHTTPSet_Wait_contextDone(t *testing.T) {
+	t.Parallel()
+
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/body" {
+			// only the body of the response hangs
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(unblock)
+		server.Close()
+	})
+
+	set := NewHTTPSet()
+	headersID, err := set.StartRequest(http.MethodGet, server.URL+"/headers")
+	require.NoError(t, err)
+	bodyID, err := set.StartRequest(http.MethodGet, server.URL+"/body")
+	require.NoError(t, err)
+
+	// without deadline, the waits are bounded by the context of the runtime call
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	statuses := set.Wait(ctx, []int16{headersID}, nil)
+	assert.Equal(t, []HTTPRequestStatus{{Err: ErrDeadlineReached}}, statuses)
+	_, err = set.ReadBody(ctx, headersID, make([]byte, 1), nil)
+	assert.ErrorIs(t, err, ErrDeadlineReached)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	statuses = set.Wait(ctx, []int16{bodyID}, nil)
+	require.Equal(t, []HTTPRequestStatus{{Finished: true, StatusCode: http.StatusOK}}, statuses)
+	_, err = set.ReadBody(ctx, bodyID, make([]byte, 1), nil)
+	assert.ErrorIs(t, err, ErrDeadlineReached)
+
+	require.NoError(t, set.Remove(headersID))
+	require.NoError(t, set.Remove(bodyID))
+}
+
+func TestHTTPSet_Wait_ioError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	set := NewHTTPSet()
+	id, err := set.StartRequest(http.MethodGet, server.URL)
+	require.NoError(t, err)
+
+	statuses := set.Wait(context.Background(), []int16{id}, nil)
+	assert.Equal(t, []HTTPRequestStatus{{Err: ErrIO}}, statuses)
+	assert.Nil(t, set.Get(id))
+}
+
+func TestHTTPError_encoding(t *testing.T) {
+	t.Parallel()
+
+	errs := []HTTPError{ErrDeadlineReached, ErrIO, ErrInvalidID}
+
+	encoded, err := scale.Marshal(errs)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3 << 2, 1, 2, 3}, encoded)
+
+	result := scale.NewResult(nil, HTTPError(0))
+	err = result.Set(scale.Err, ErrIO)
+	require.NoError(t, err)
+	encoded, err = scale.Marshal(result)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2}, encoded)
+
+	var decoded []HTTPError
+	err = scale.Unmarshal([]byte{3 << 2, 1, 2, 3}, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, errs, decoded)
+}
+
+func TestHTTPRequestStatus_MarshalSCALE(t *testing.T) {
+	t.Parallel()
+
+	statuses := []HTTPRequestStatus{
+		{Err: ErrDeadlineReached},
+		{Err: ErrIO},
+		{Err: ErrInvalidID},
+		{Finished: true, StatusCode: http.StatusOK},
+	}
+
+	encoded, err := scale.Marshal(statuses)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4 << 2, 0, 1, 2, 3, 200, 0}, encoded)
+}
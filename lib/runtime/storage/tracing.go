@@ -0,0 +1,145 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package storage
+
+// Storage access methods recorded by the TracingTrieState
+const (
+	TraceMethodGet              = "Get"
+	TraceMethodPut              = "Put"
+	TraceMethodClear            = "Clear"
+	TraceMethodNextKey          = "NextKey"
+	TraceMethodClearPrefix      = "ClearPrefix"
+	TraceMethodChildGet         = "ChildGet"
+	TraceMethodChildPut         = "ChildPut"
+	TraceMethodChildClear       = "ChildClear"
+	TraceMethodChildNextKey     = "ChildNextKey"
+	TraceMethodChildClearPrefix = "ChildClearPrefix"
+	TraceMethodKillChild        = "KillChild"
+)
+
+// TraceEvent is a storage access recorded by the TracingTrieState
+type TraceEvent struct {
+	Method string
+	// KeyToChild is the key of the child trie accessed, or nil for the main trie.
+	KeyToChild []byte
+	Key        []byte
+	// Value is the value read or written, or nil if there is none.
+	Value []byte
+}
+
+// TracingTrieState is a TrieState which records the storage accesses
+// made by the runtime on top of its transactions.
+type TracingTrieState struct {
+	*TrieState
+	record func(event TraceEvent)
+}
+
+// NewTracingTrieState returns a TracingTrieState wrapping the given trie state
+// and calling record for each storage access.
+func NewTracingTrieState(trieState *TrieState, record func(event TraceEvent)) *TracingTrieState {
+	return &TracingTrieState{
+		TrieState: trieState,
+		record:    record,
+	}
+}
+
+// Put puts a key-value pair in the trie
+func (t *TracingTrieState) Put(key, value []byte) error {
+	t.record(TraceEvent{Method: TraceMethodPut, Key: key, Value: value})
+	return t.TrieState.Put(key, value)
+}
+
+// Get gets a value from the state trie
+func (t *TracingTrieState) Get(key []byte) []byte {
+	value := t.TrieState.Get(key)
+	t.record(TraceEvent{Method: TraceMethodGet, Key: key, Value: value})
+	return value
+}
+
+// Delete deletes a key from the trie
+func (t *TracingTrieState) Delete(key []byte) error {
+	t.record(TraceEvent{Method: TraceMethodClear, Key: key})
+	return t.TrieState.Delete(key)
+}
+
+// NextKey returns the next key in the trie in lexicographical order
+func (t *TracingTrieState) NextKey(key []byte) []byte {
+	next := t.TrieState.NextKey(key)
+	t.record(TraceEvent{Method: TraceMethodNextKey, Key: key, Value: next})
+	return next
+}
+
+// ClearPrefix deletes all key-value pairs from the trie where the key starts with the given prefix
+func (t *TracingTrieState) ClearPrefix(prefix []byte) error {
+	t.record(TraceEvent{Method: TraceMethodClearPrefix, Key: prefix})
+	return t.TrieState.ClearPrefix(prefix)
+}
+
+// ClearPrefixLimit deletes key-value pairs from the trie where the key starts with the given prefix till limit reached
+func (t *TracingTrieState) ClearPrefixLimit(prefix []byte, limit uint32) (
+	deleted uint32, allDeleted bool, err error) {
+	t.record(TraceEvent{Method: TraceMethodClearPrefix, Key: prefix})
+	return t.TrieState.ClearPrefixLimit(prefix, limit)
+}
+
+// SetChildStorage sets a key-value pair in a child trie
+func (t *TracingTrieState) SetChildStorage(keyToChild, key, value []byte) error {
+	t.record(TraceEvent{Method: TraceMethodChildPut, KeyToChild: keyToChild, Key: key, Value: value})
+	return t.TrieState.SetChildStorage(keyToChild, key, value)
+}
+
+// GetChildStorage returns a value from a child trie
+func (t *TracingTrieState) GetChildStorage(keyToChild, key []byte) ([]byte, error) {
+	value, err := t.TrieState.GetChildStorage(keyToChild, key)
+	if err != nil {
+		return nil, err
+	}
+
+	t.record(TraceEvent{Method: TraceMethodChildGet, KeyToChild: keyToChild, Key: key, Value: value})
+	return value, nil
+}
+
+// ClearChildStorage removes the child storage entry from the trie
+func (t *TracingTrieState) ClearChildStorage(keyToChild, key []byte) error {
+	t.record(TraceEvent{Method: TraceMethodChildClear, KeyToChild: keyToChild, Key: key})
+	return t.TrieState.ClearChildStorage(keyToChild, key)
+}
+
+// ClearPrefixInChild clears all the keys from the child trie that have the given prefix
+func (t *TracingTrieState) ClearPrefixInChild(keyToChild, prefix []byte) error {
+	t.record(TraceEvent{Method: TraceMethodChildClearPrefix, KeyToChild: keyToChild, Key: prefix})
+	return t.TrieState.ClearPrefixInChild(keyToChild, prefix)
+}
+
+// ClearPrefixInChildWithLimit clears all the keys from the child trie that have the given prefix
+// till limit reached
+func (t *TracingTrieState) ClearPrefixInChildWithLimit(keyToChild, prefix []byte, limit uint32) (
+	uint32, bool, error) {
+	t.record(TraceEvent{Method: TraceMethodChildClearPrefix, KeyToChild: keyToChild, Key: prefix})
+	return t.TrieState.ClearPrefixInChildWithLimit(keyToChild, prefix, limit)
+}
+
+// GetChildNextKey returns the next lexicographical larger key from child storage
+func (t *TracingTrieState) GetChildNextKey(keyToChild, key []byte) ([]byte, error) {
+	next, err := t.TrieState.GetChildNextKey(keyToChild, key)
+	if err != nil {
+		return nil, err
+	}
+
+	t.record(TraceEvent{Method: TraceMethodChildNextKey, KeyToChild: keyToChild, Key: key, Value: next})
+	return next, nil
+}
+
+// DeleteChild deletes a child trie from the main trie
+func (t *TracingTrieState) DeleteChild(keyToChild []byte) error {
+	t.record(TraceEvent{Method: TraceMethodKillChild, KeyToChild: keyToChild})
+	return t.TrieState.DeleteChild(keyToChild)
+}
+
+// DeleteChildLimit deletes up to limit of database entries by lexicographic order.
+func (t *TracingTrieState) DeleteChildLimit(keyToChild []byte, limit *[]byte) (
+	deleted uint32, allDeleted bool, err error) {
+	t.record(TraceEvent{Method: TraceMethodKillChild, KeyToChild: keyToChild})
+	return t.TrieState.DeleteChildLimit(keyToChild, limit)
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package storage
+
+import (
+	"testing"
+
+	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingTrieState(t *testing.T) {
+	t.Parallel()
+
+	var events []TraceEvent
+	ts := NewTracingTrieState(NewTrieState(inmemory_trie.NewEmptyTrie()), func(event TraceEvent) {
+		events = append(events, event)
+	})
+
+	ts.StartTransaction()
+	require.NoError(t, ts.Put([]byte("key1"), []byte("value1")))
+	require.Equal(t, []byte("value1"), ts.Get([]byte("key1")))
+	require.Equal(t, []byte("key1"), ts.NextKey([]byte("key0")))
+	require.NoError(t, ts.Delete([]byte("key1")))
+	require.NoError(t, ts.SetChildStorage([]byte("child"), []byte("key2"), []byte("value2")))
+	ts.CommitTransaction()
+
+	value, err := ts.GetChildStorage([]byte("child"), []byte("key2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value2"), value)
+	require.NoError(t, ts.DeleteChild([]byte("child")))
+
+	expected := []TraceEvent{
+		{Method: TraceMethodPut, Key: []byte("key1"), Value: []byte("value1")},
+		{Method: TraceMethodGet, Key: []byte("key1"), Value: []byte("value1")},
+		{Method: TraceMethodNextKey, Key: []byte("key0"), Value: []byte("key1")},
+		{Method: TraceMethodClear, Key: []byte("key1")},
+		{Method: TraceMethodChildPut, KeyToChild: []byte("child"), Key: []byte("key2"), Value: []byte("value2")},
+		{Method: TraceMethodChildGet, KeyToChild: []byte("child"), Key: []byte("key2"), Value: []byte("value2")},
+		{Method: TraceMethodKillChild, KeyToChild: []byte("child")},
+	}
+	require.Equal(t, expected, events)
+}
@@ -188,7 +188,13 @@ func (t *TrieState) NextKey(key []byte) []byte {
 			nextKey = []byte(currentTx.sortedKeys[pos])
 		}
 
-		nextKeyOnState := t.state.PrefixedIter(key).NextKeyFunc(func(nextKey []byte) bool {
+		iter, err := t.state.PrefixedIter(key)
+		if err != nil {
+			// the state keys cannot be read, as when calling NextKey on the state
+			return nextKey
+		}
+
+		nextKeyOnState := iter.NextKeyFunc(func(nextKey []byte) bool {
 			_, deleted := currentTx.deletes[string(nextKey)]
 			return !deleted
 		})
@@ -214,7 +220,10 @@ func (t *TrieState) ClearPrefix(prefix []byte) error {
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		keysOnState := make([]string, 0)
 
-		iter := t.state.PrefixedIter(prefix)
+		iter, err := t.state.PrefixedIter(prefix)
+		if err != nil {
+			return fmt.Errorf("iterating over state: %w", err)
+		}
 		for key := iter.NextKey(); bytes.HasPrefix(key, prefix); key = iter.NextKey() {
 			keysOnState = append(keysOnState, string(key))
 		}
@@ -235,7 +244,10 @@ func (t *TrieState) ClearPrefixLimit(prefix []byte, limit uint32) (
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		keysOnState := make([]string, 0)
 
-		iter := t.state.PrefixedIter(prefix)
+		iter, err := t.state.PrefixedIter(prefix)
+		if err != nil {
+			return 0, false, fmt.Errorf("iterating over state: %w", err)
+		}
 		for key := iter.NextKey(); bytes.HasPrefix(key, prefix); key = iter.NextKey() {
 			keysOnState = append(keysOnState, string(key))
 		}
@@ -429,8 +441,12 @@ func (t *TrieState) ClearPrefixInChild(keyToChild, prefix []byte) error {
 			return err
 		}
 
+		iter, err := child.PrefixedIter(prefix)
+		if err != nil {
+			return fmt.Errorf("iterating over child trie located at key 0x%x: %w", keyToChild, err)
+		}
+
 		var onStateKeys []string
-		iter := child.PrefixedIter(prefix)
 		for key := iter.NextKey(); bytes.HasPrefix(key, prefix); key = iter.NextKey() {
 			onStateKeys = append(onStateKeys, string(key))
 		}
@@ -465,8 +481,12 @@ func (t *TrieState) ClearPrefixInChildWithLimit(keyToChild, prefix []byte, limit
 			return 0, false, err
 		}
 
+		iter, err := child.PrefixedIter(prefix)
+		if err != nil {
+			return 0, false, fmt.Errorf("iterating over child trie located at key 0x%x: %w", keyToChild, err)
+		}
+
 		var onStateKeys []string
-		iter := child.PrefixedIter(prefix)
 		for key := iter.NextKey(); bytes.HasPrefix(key, prefix); key = iter.NextKey() {
 			onStateKeys = append(onStateKeys, string(key))
 		}
@@ -516,7 +536,12 @@ func (t *TrieState) GetChildNextKey(keyToChild, key []byte) ([]byte, error) {
 				return nil, err
 			}
 
-			nextKeyOnState := childTrie.PrefixedIter(key).NextKeyFunc(func(nextKey []byte) bool {
+			iter, err := childTrie.PrefixedIter(key)
+			if err != nil {
+				return nil, fmt.Errorf("iterating over child trie located at key 0x%x: %w", keyToChild, err)
+			}
+
+			nextKeyOnState := iter.NextKeyFunc(func(nextKey []byte) bool {
 				_, deleted := childChanges.deletes[string(nextKey)]
 				return !deleted
 			})
@@ -52,14 +52,15 @@ type Allocator interface {
 
 // Context is the context for the wasm interpreter's imported functions
 type Context struct {
-	Storage         Storage
-	Allocator       Allocator
-	Keystore        *keystore.GlobalKeystore
-	Validator       bool
-	NodeStorage     NodeStorage
-	Network         BasicNetwork
-	Transaction     TransactionState
-	SigVerifier     *crypto.SignatureVerifier
-	OffchainHTTPSet *offchain.HTTPSet
-	Version         *Version
+	Storage              Storage
+	Allocator            Allocator
+	Keystore             *keystore.GlobalKeystore
+	Validator            bool
+	NodeStorage          NodeStorage
+	Network              BasicNetwork
+	Transaction          TransactionState
+	TransactionSubmitter TransactionSubmitter
+	SigVerifier          *crypto.SignatureVerifier
+	OffchainHTTPSet      *offchain.HTTPSet
+	Version              *Version
 }
@@ -0,0 +1,49 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero_runtime
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// compilationCaches holds the compilation caches persisted to disk, by
+// directory, so the instances of a node share the same cache across
+// runtime upgrades and instance pools.
+var compilationCaches = struct {
+	sync.Mutex
+	byDir map[string]wazero.CompilationCache
+}{
+	byDir: make(map[string]wazero.CompilationCache),
+}
+
+// compilationCache returns the compilation cache persisted to the given
+// directory, where wazero stores compiled modules keyed by the hash of their
+// code so they are not compiled again after a restart. If the directory is
+// empty, a new in-memory compilation cache is returned. The shared return
+// value is true if the cache is shared with other instances and must not be
+// closed by the instance.
+func compilationCache(dir string) (cache wazero.CompilationCache, shared bool, err error) {
+	if dir == "" {
+		return wazero.NewCompilationCache(), false, nil
+	}
+
+	compilationCaches.Lock()
+	defer compilationCaches.Unlock()
+
+	cache, ok := compilationCaches.byDir[dir]
+	if ok {
+		return cache, true, nil
+	}
+
+	cache, err = wazero.NewCompilationCacheWithDir(dir)
+	if err != nil {
+		return nil, false, fmt.Errorf("creating compilation cache in %s: %w", dir, err)
+	}
+
+	compilationCaches.byDir[dir] = cache
+	return cache, true, nil
+}
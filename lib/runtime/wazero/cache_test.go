@@ -0,0 +1,31 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero_runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_compilationCache(t *testing.T) {
+	t.Parallel()
+
+	cache, shared, err := compilationCache("")
+	require.NoError(t, err)
+	assert.False(t, shared)
+	otherCache, _, err := compilationCache("")
+	require.NoError(t, err)
+	assert.NotSame(t, cache, otherCache)
+
+	dir := t.TempDir()
+	cache, shared, err = compilationCache(dir)
+	require.NoError(t, err)
+	assert.True(t, shared)
+	otherCache, shared, err = compilationCache(dir)
+	require.NoError(t, err)
+	assert.True(t, shared)
+	assert.Equal(t, cache, otherCache)
+}
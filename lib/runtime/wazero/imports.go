@@ -8,12 +8,14 @@
 	"context"
 	"crypto/rand"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
 	"reflect"
 	"time"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
@@ -21,6 +23,7 @@
 	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
@@ -113,8 +116,174 @@ func ext_logging_log_version_1(_ context.Context, m api.Module, level int32, tar
 	}
 }
 
-func ext_crypto_ecdsa_generate_version_1(ctx context.Context, m api.Module, _ uint32, _ uint64) uint32 {
-	panic("TODO impl: see https://github.com/ChainSafe/gossamer/issues/3769 ")
+func ext_crypto_ecdsa_generate_version_1(
+	ctx context.Context, m api.Module, keyTypeID uint32, seedSpan uint64) uint32 {
+	id, ok := m.Memory().Read(keyTypeID, 4)
+	if !ok {
+		panic("out of range read")
+	}
+	seedBytes := read(m, seedSpan)
+
+	var seed *[]byte
+	err := scale.Unmarshal(seedBytes, &seed)
+	if err != nil {
+		logger.Warnf("cannot generate key: %s", err)
+		return 0
+	}
+
+	var kp *secp256k1.Keypair
+
+	if seed != nil {
+		kp, err = secp256k1.NewKeypairFromMnenomic(string(*seed), "")
+	} else {
+		kp, err = secp256k1.GenerateKeypair()
+	}
+
+	if err != nil {
+		logger.Warnf("cannot generate key: %s", err)
+		return 0
+	}
+
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	ks, err := rtCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id 0x%x: %s", id, err)
+		return 0
+	}
+
+	err = ks.Insert(kp)
+	if err != nil {
+		logger.Warnf("failed to insert key: %s", err)
+		return 0
+	}
+
+	ret, err := write(m, rtCtx.Allocator, kp.Public().Encode())
+	if err != nil {
+		logger.Warnf("failed to allocate memory: %s", err)
+		return 0
+	}
+
+	logger.Debug("generated ecdsa keypair with public key: " + kp.Public().Hex())
+	return uint32(ret)
+}
+
+func ext_crypto_ecdsa_public_keys_version_1(ctx context.Context, m api.Module, keyTypeID uint32) uint64 {
+	id, ok := m.Memory().Read(keyTypeID, 4)
+	if !ok {
+		panic("out of range read")
+	}
+
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	emptyKeys := scale.MustMarshal([][secp256k1.PublicKeyLength]byte{})
+
+	ks, err := rtCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id 0x%x: %s", id, err)
+		return mustWrite(m, rtCtx.Allocator, emptyKeys)
+	}
+
+	if ks.Type() != crypto.Secp256k1Type && ks.Type() != crypto.UnknownType {
+		logger.Warnf(
+			"error for id 0x%x: keystore type is %s and not the expected secp256k1",
+			id, ks.Type())
+		return mustWrite(m, rtCtx.Allocator, emptyKeys)
+	}
+
+	var keys [][secp256k1.PublicKeyLength]byte
+	for _, key := range ks.PublicKeys() {
+		if _, isEcdsa := key.(*secp256k1.PublicKey); !isEcdsa {
+			continue
+		}
+
+		var encodedKey [secp256k1.PublicKeyLength]byte
+		copy(encodedKey[:], key.Encode())
+		keys = append(keys, encodedKey)
+	}
+
+	if keys == nil {
+		return mustWrite(m, rtCtx.Allocator, emptyKeys)
+	}
+	return mustWrite(m, rtCtx.Allocator, scale.MustMarshal(keys))
+}
+
+func ext_crypto_ecdsa_sign_version_1(ctx context.Context, m api.Module, keyTypeID, key uint32, msg uint64) uint64 {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	hash, err := common.Blake2bHash(read(m, msg))
+	if err != nil {
+		logger.Errorf("failed to hash message: %s", err)
+		return mustWrite(m, rtCtx.Allocator, noneEncoded)
+	}
+
+	return ecdsaSignPrehashed(m, rtCtx, keyTypeID, key, hash)
+}
+
+func ext_crypto_ecdsa_sign_prehashed_version_1(
+	ctx context.Context, m api.Module, keyTypeID, key, msg uint32) uint64 {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	hash, ok := m.Memory().Read(msg, secp256k1.MessageLength)
+	if !ok {
+		panic("out of range read")
+	}
+
+	return ecdsaSignPrehashed(m, rtCtx, keyTypeID, key, common.BytesToHash(hash))
+}
+
+// ecdsaSignPrehashed signs the message hash given with the ecdsa key of the keystore
+// given, and returns a pointer-size to the optional 65 bytes recoverable signature.
+func ecdsaSignPrehashed(m api.Module, rtCtx *runtime.Context, keyTypeID, key uint32, hash common.Hash) uint64 {
+	id, ok := m.Memory().Read(keyTypeID, 4)
+	if !ok {
+		panic("out of range read")
+	}
+
+	pubKeyData, ok := m.Memory().Read(key, secp256k1.PublicKeyLength)
+	if !ok {
+		panic("out of range read")
+	}
+
+	pubKey, err := secp256k1.NewPublicKey(pubKeyData)
+	if err != nil {
+		logger.Errorf("failed to get public key: %s", err)
+		return mustWrite(m, rtCtx.Allocator, noneEncoded)
+	}
+
+	ks, err := rtCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id 0x%x: %s", id, err)
+		return mustWrite(m, rtCtx.Allocator, noneEncoded)
+	}
+
+	signingKey := ks.GetKeypair(pubKey)
+	if signingKey == nil {
+		logger.Error("could not find public key " + pubKey.Hex() + " in keystore")
+		return mustWrite(m, rtCtx.Allocator, noneEncoded)
+	}
+
+	sig, err := signingKey.Sign(hash[:])
+	if err != nil {
+		logger.Errorf("could not sign message: %s", err)
+		return mustWrite(m, rtCtx.Allocator, noneEncoded)
+	}
+
+	var fixedSize [secp256k1.SignatureLengthRecovery]byte
+	copy(fixedSize[:], sig)
+	return mustWrite(m, rtCtx.Allocator, scale.MustMarshal(&fixedSize))
 }
 
 func ext_crypto_ed25519_generate_version_1(
@@ -379,6 +548,65 @@ func ext_crypto_secp256k1_ecdsa_recover_version_2(ctx context.Context, m api.Mod
 	return ext_crypto_secp256k1_ecdsa_recover_version_1(ctx, m, sig, msg)
 }
 
+func ext_crypto_ecdsa_verify_version_1(ctx context.Context, m api.Module, sig uint32, msg uint64, key uint32) uint32 {
+	return ext_crypto_ecdsa_verify_version_2(ctx, m, sig, msg, key)
+}
+
+func ext_crypto_ecdsa_verify_prehashed_version_1(ctx context.Context, m api.Module, sig, msg, key uint32) uint32 {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	sigVerifier := rtCtx.SigVerifier
+
+	memory := m.Memory()
+	signature, ok := memory.Read(sig, secp256k1.SignatureLength)
+	if !ok {
+		panic("read overflow")
+	}
+	hash, ok := memory.Read(msg, secp256k1.MessageLength)
+	if !ok {
+		panic("read overflow")
+	}
+	pubKeyData, ok := memory.Read(key, secp256k1.PublicKeyLength)
+	if !ok {
+		panic("read overflow")
+	}
+
+	pub, err := secp256k1.NewPublicKey(pubKeyData)
+	if err != nil {
+		logger.Errorf("failed to decode public key: %s", err)
+		return 0
+	}
+
+	if sigVerifier.IsStarted() {
+		// the signature and hash read are views of the memory which the runtime
+		// may reuse before the batch is verified.
+		signature := crypto.SignatureInfo{
+			PubKey:     pub.Encode(),
+			Sign:       bytes.Clone(signature),
+			Msg:        bytes.Clone(hash),
+			VerifyFunc: secp256k1.VerifySignature,
+		}
+		sigVerifier.Add(&signature)
+		return 1
+	}
+
+	ok, err = pub.Verify(hash, signature)
+	if err != nil || !ok {
+		message := validateSignatureFail
+		if err != nil {
+			message += ": " + err.Error()
+		}
+		logger.Errorf(message)
+		return 0
+	}
+
+	logger.Debug("validated signature")
+	return 1
+}
+
 func ext_crypto_ecdsa_verify_version_2(ctx context.Context, m api.Module, sig uint32, msg uint64, key uint32) uint32 {
 	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
 	if rtCtx == nil {
@@ -662,14 +890,7 @@ func ext_crypto_sr25519_sign_version_1(ctx context.Context, m api.Module, keyTyp
 	return mustWrite(m, rtCtx.Allocator, scale.MustMarshal(&fixedSig))
 }
 
-func ext_crypto_sr25519_verify_version_1(ctx context.Context, m api.Module, sig uint32, msg uint64, key uint32) uint32 {
-	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
-	if rtCtx == nil {
-		panic("nil runtime context")
-	}
-
-	sigVerifier := rtCtx.SigVerifier
-
+func ext_crypto_sr25519_verify_version_1(_ context.Context, m api.Module, sig uint32, msg uint64, key uint32) uint32 {
 	message := read(m, msg)
 	signature, ok := m.Memory().Read(sig, 64)
 	if !ok {
@@ -690,17 +911,8 @@ func ext_crypto_sr25519_verify_version_1(ctx context.Context, m api.Module, sig
 		"pub=%s message=0x%x signature=0x%x",
 		pub.Hex(), message, signature)
 
-	if sigVerifier.IsStarted() {
-		signature := crypto.SignatureInfo{
-			PubKey:     pub.Encode(),
-			Sign:       signature,
-			Msg:        message,
-			VerifyFunc: sr25519.VerifySignature,
-		}
-		sigVerifier.Add(&signature)
-		return 1
-	}
-
+	// the signature is not added to a started batch since this
+	// version of the verification never fails, see below.
 	ok, err = pub.VerifyDeprecated(message, signature)
 	if err != nil || !ok {
 		message := validateSignatureFail
@@ -777,15 +989,35 @@ func ext_crypto_sr25519_verify_version_2(ctx context.Context, m api.Module, sig
 	return 1
 }
 
-func ext_crypto_start_batch_verify_version_1(_ context.Context, _ api.Module) {
-	// TODO: fix and re-enable signature verification (#1405)
-	// beginBatchVerify(context)
+func ext_crypto_start_batch_verify_version_1(ctx context.Context, _ api.Module) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	if rtCtx.SigVerifier.IsStarted() {
+		panic("batch verification already started")
+	}
+
+	rtCtx.SigVerifier.Start()
 }
 
-func ext_crypto_finish_batch_verify_version_1(_ context.Context, _ api.Module) uint32 {
-	// TODO: fix and re-enable signature verification (#1405)
-	// return finishBatchVerify(context)
-	return 1
+func ext_crypto_finish_batch_verify_version_1(ctx context.Context, _ api.Module) uint32 {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	if !rtCtx.SigVerifier.IsStarted() {
+		panic("finish_batch_verify called without start_batch_verify")
+	}
+
+	if rtCtx.SigVerifier.Finish() {
+		return 1
+	}
+
+	logger.Error("batch signature verification failed")
+	return 0
 }
 
 func ext_trie_blake2_256_root_version_1(ctx context.Context, m api.Module, dataSpan uint64) uint32 {
@@ -1832,15 +2064,26 @@ func ext_offchain_random_seed_version_1(ctx context.Context, m api.Module) uint3
 	return ptr
 }
 
-// TODO Investigate via https://github.com/ChainSafe/gossamer/issues/3986
 func ext_offchain_submit_transaction_version_1(ctx context.Context, m api.Module, data uint64) uint64 {
 	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
 	if rtCtx == nil {
 		panic("nil runtime context")
 	}
 
-	// OK case
-	ret, err := write(m, rtCtx.Allocator, []byte{0})
+	// the extrinsic is passed as an encoded OpaqueExtrinsic, which is the
+	// length prefixed extrinsic as found in block bodies.
+	ext := types.Extrinsic(read(m, data))
+
+	result := []byte{0}
+	if rtCtx.TransactionSubmitter == nil {
+		logger.Warn("cannot submit transaction: no transaction submitter configured")
+		result = []byte{1}
+	} else if err := rtCtx.TransactionSubmitter.HandleSubmittedExtrinsic(ext); err != nil {
+		logger.Errorf("failed to submit transaction: %s", err)
+		result = []byte{1}
+	}
+
+	ret, err := write(m, rtCtx.Allocator, result)
 	if err != nil {
 		panic(err)
 	}
@@ -1916,13 +2159,15 @@ func ext_offchain_http_request_add_header_version_1(
 	result := scale.NewResult(nil, nil)
 	resultMode := scale.OK
 
-	err := offchainReq.AddHeader(string(name), string(value))
-	if err != nil {
+	if offchainReq == nil {
+		logger.Errorf("failed to add request header: request id %d not found", reqID)
+		resultMode = scale.Err
+	} else if err := offchainReq.AddHeader(string(name), string(value)); err != nil {
 		logger.Errorf("failed to add request header: %s", err)
 		resultMode = scale.Err
 	}
 
-	err = result.Set(resultMode, nil)
+	err := result.Set(resultMode, nil)
 	if err != nil {
 		logger.Errorf("failed to set the result data: %s", err)
 		return uint64(0)
@@ -1943,6 +2188,132 @@ func ext_offchain_http_request_add_header_version_1(
 	return ptr
 }
 
+func ext_offchain_http_request_write_body_version_1(
+	ctx context.Context, m api.Module, reqID uint32, chunkSpan, deadlineSpan uint64) (pointerSize uint64) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	chunk := read(m, chunkSpan)
+	deadline, err := readOffchainDeadline(m, deadlineSpan)
+	if err != nil {
+		panic(err)
+	}
+
+	result := scale.NewResult(nil, offchain.HTTPError(0))
+	err = rtCtx.OffchainHTTPSet.WriteBody(int16(reqID), chunk, deadline)
+	var httpErr offchain.HTTPError
+	switch {
+	case err == nil:
+		err = result.Set(scale.OK, nil)
+	case errors.As(err, &httpErr):
+		logger.Debugf("failed to write request body: %s", err)
+		err = result.Set(scale.Err, httpErr)
+	default:
+		panic(err)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	return mustWrite(m, rtCtx.Allocator, scale.MustMarshal(result))
+}
+
+func ext_offchain_http_response_wait_version_1(
+	ctx context.Context, m api.Module, idsSpan, deadlineSpan uint64) (pointerSize uint64) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	var encodedIDs []uint16
+	err := scale.Unmarshal(read(m, idsSpan), &encodedIDs)
+	if err != nil {
+		panic(fmt.Sprintf("decoding request ids: %s", err))
+	}
+
+	deadline, err := readOffchainDeadline(m, deadlineSpan)
+	if err != nil {
+		panic(err)
+	}
+
+	ids := make([]int16, len(encodedIDs))
+	for i, id := range encodedIDs {
+		ids[i] = int16(id)
+	}
+
+	statuses := rtCtx.OffchainHTTPSet.Wait(ctx, ids, deadline)
+	return mustWrite(m, rtCtx.Allocator, scale.MustMarshal(statuses))
+}
+
+func ext_offchain_http_response_headers_version_1(
+	ctx context.Context, m api.Module, reqID uint32) (pointerSize uint64) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	headers := rtCtx.OffchainHTTPSet.ResponseHeaders(int16(reqID))
+	if headers == nil {
+		headers = []offchain.HTTPHeader{}
+	}
+	return mustWrite(m, rtCtx.Allocator, scale.MustMarshal(headers))
+}
+
+func ext_offchain_http_response_read_body_version_1(
+	ctx context.Context, m api.Module, reqID uint32, bufferSpan, deadlineSpan uint64) (pointerSize uint64) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	bufferPtr, bufferSize := splitPointerSize(bufferSpan)
+	deadline, err := readOffchainDeadline(m, deadlineSpan)
+	if err != nil {
+		panic(err)
+	}
+
+	result := scale.NewResult(uint32(0), offchain.HTTPError(0))
+	buffer := make([]byte, bufferSize)
+	n, err := rtCtx.OffchainHTTPSet.ReadBody(ctx, int16(reqID), buffer, deadline)
+	var httpErr offchain.HTTPError
+	switch {
+	case err == nil:
+		if !m.Memory().Write(bufferPtr, buffer[:n]) {
+			panic("write overflow")
+		}
+		err = result.Set(scale.OK, uint32(n))
+	case errors.As(err, &httpErr):
+		logger.Debugf("failed to read response body: %s", err)
+		err = result.Set(scale.Err, httpErr)
+	default:
+		panic(err)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	return mustWrite(m, rtCtx.Allocator, scale.MustMarshal(result))
+}
+
+// readOffchainDeadline reads the optional offchain deadline, a timestamp in
+// milliseconds since the unix epoch, from the SCALE encoded memory span given.
+func readOffchainDeadline(m api.Module, deadlineSpan uint64) (*time.Time, error) {
+	var timestamp *uint64
+	err := scale.Unmarshal(read(m, deadlineSpan), &timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("decoding deadline: %w", err)
+	}
+
+	if timestamp == nil {
+		return nil, nil
+	}
+
+	deadline := time.UnixMilli(int64(*timestamp))
+	return &deadline, nil
+}
+
 func storageAppend(storage runtime.Storage, key, valueToAppend []byte) (err error) {
 	// this function assumes the item in storage is a SCALE encoded array of items
 	// the valueToAppend is a new item, so it appends the item and increases the length prefix by 1
@@ -7,7 +7,9 @@
 	"bytes"
 	"context"
 	"encoding/binary"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"sort"
 	"strings"
@@ -24,6 +26,7 @@
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/allocator"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
 	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/ChainSafe/gossamer/pkg/trie"
@@ -382,6 +385,187 @@ func Test_ext_crypto_ecdsa_verify_version_2_Table(t *testing.T) {
 	}
 }
 
+func Test_ext_crypto_ecdsa_generate_version_1(t *testing.T) {
+	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
+
+	idData := []byte(keystore.BeefName)
+	ks, _ := inst.Context.Keystore.GetKeystore(idData)
+	require.Equal(t, 0, ks.Size())
+
+	mnemonic := "vessel track notable smile sign cloth problem unfair join orange snack fly"
+	expectedKeypair, err := secp256k1.NewKeypairFromMnenomic(mnemonic, "")
+	require.NoError(t, err)
+
+	mnemonicBytes := []byte(mnemonic)
+	var data = &mnemonicBytes
+	seedData, err := scale.Marshal(data)
+	require.NoError(t, err)
+
+	params := append(idData, seedData...) //skipcq: CRT-D0001
+
+	pubKeyBytes, err := inst.Exec("rtm_ext_crypto_ecdsa_generate_version_1", params)
+	require.NoError(t, err)
+
+	var pubKeyData []byte
+	err = scale.Unmarshal(pubKeyBytes, &pubKeyData)
+	require.NoError(t, err)
+	require.Equal(t, expectedKeypair.Public().Encode(), pubKeyData)
+
+	pubKey, err := secp256k1.NewPublicKey(pubKeyData)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, ks.Size())
+	kp := ks.GetKeypair(pubKey)
+	require.NotNil(t, kp)
+}
+
+func Test_ext_crypto_ecdsa_public_keys_version_1(t *testing.T) {
+	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
+
+	idData := []byte(keystore.BeefName)
+	ks, _ := inst.Context.Keystore.GetKeystore(idData)
+	require.Equal(t, 0, ks.Size())
+
+	size := 5
+	pubKeys := make([][secp256k1.PublicKeyLength]byte, size)
+	for i := range pubKeys {
+		kp, err := secp256k1.GenerateKeypair()
+		require.NoError(t, err)
+
+		ks.Insert(kp)
+		copy(pubKeys[i][:], kp.Public().Encode())
+	}
+
+	sort.Slice(pubKeys, func(i int, j int) bool {
+		return bytes.Compare(pubKeys[i][:], pubKeys[j][:]) < 0
+	})
+
+	res, err := inst.Exec("rtm_ext_crypto_ecdsa_public_keys_version_1", idData)
+	require.NoError(t, err)
+
+	var out []byte
+	err = scale.Unmarshal(res, &out)
+	require.NoError(t, err)
+
+	var ret [][secp256k1.PublicKeyLength]byte
+	err = scale.Unmarshal(out, &ret)
+	require.NoError(t, err)
+
+	sort.Slice(ret, func(i int, j int) bool {
+		return bytes.Compare(ret[i][:], ret[j][:]) < 0
+	})
+
+	require.Equal(t, pubKeys, ret)
+}
+
+func Test_ext_crypto_ecdsa_sign_version_1(t *testing.T) {
+	msgData := []byte("Hello world!")
+	msgHash, err := common.Blake2bHash(msgData)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		function string
+		msg      []byte
+	}{
+		"sign": {
+			function: "rtm_ext_crypto_ecdsa_sign_version_1",
+			msg:      msgData,
+		},
+		"sign_prehashed": {
+			function: "rtm_ext_crypto_ecdsa_sign_prehashed_version_1",
+			msg:      msgHash.ToBytes(),
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
+
+			kp, err := secp256k1.GenerateKeypair()
+			require.NoError(t, err)
+
+			idData := []byte(keystore.BeefName)
+			ks, _ := inst.Context.Keystore.GetKeystore(idData)
+			ks.Insert(kp)
+
+			encPubKey, err := scale.Marshal(kp.Public().Encode())
+			require.NoError(t, err)
+			encMsg, err := scale.Marshal(tc.msg)
+			require.NoError(t, err)
+
+			res, err := inst.Exec(tc.function, append(append(idData, encPubKey...), encMsg...))
+			require.NoError(t, err)
+
+			var out []byte
+			err = scale.Unmarshal(res, &out)
+			require.NoError(t, err)
+
+			var val *[secp256k1.SignatureLengthRecovery]byte
+			err = scale.Unmarshal(out, &val)
+			require.NoError(t, err)
+			require.NotNil(t, val)
+
+			ok, err := kp.Public().Verify(msgHash.ToBytes(), val[:secp256k1.SignatureLength])
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func Test_ext_crypto_ecdsa_verify_version_1(t *testing.T) {
+	msgData := []byte("Hello world!")
+	msgHash, err := common.Blake2bHash(msgData)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		function string
+		msg      []byte
+	}{
+		"verify": {
+			function: "rtm_ext_crypto_ecdsa_verify_version_1",
+			msg:      msgData,
+		},
+		"verify_prehashed": {
+			function: "rtm_ext_crypto_ecdsa_verify_prehashed_version_1",
+			msg:      msgHash.ToBytes(),
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			kp, err := secp256k1.GenerateKeypair()
+			require.NoError(t, err)
+			otherKp, err := secp256k1.GenerateKeypair()
+			require.NoError(t, err)
+
+			sig, err := kp.Private().Sign(msgHash.ToBytes())
+			require.NoError(t, err)
+
+			encSig, err := scale.Marshal(sig)
+			require.NoError(t, err)
+			encMsg, err := scale.Marshal(tc.msg)
+			require.NoError(t, err)
+
+			for _, check := range []struct {
+				key      []byte
+				expected []byte
+			}{
+				{key: kp.Public().Encode(), expected: []byte{1, 0, 0, 0}},
+				{key: otherKp.Public().Encode(), expected: []byte{0, 0, 0, 0}},
+			} {
+				inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
+
+				encPubKey, err := scale.Marshal(check.key)
+				require.NoError(t, err)
+
+				ret, err := inst.Exec(tc.function, append(append(encSig, encMsg...), encPubKey...))
+				require.NoError(t, err)
+				assert.Equal(t, check.expected, ret)
+			}
+		})
+	}
+}
+
 func Test_ext_crypto_sr25519_generate_version_1(t *testing.T) {
 	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
 
@@ -1875,6 +2059,79 @@ func Test_ext_offchain_http_request_add_header(t *testing.T) {
 	}
 }
 
+func Test_ext_offchain_http_request_write_body_and_response_wait(t *testing.T) {
+	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "ping", string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	reqID, err := inst.Context.OffchainHTTPSet.StartRequest(http.MethodPost, server.URL)
+	require.NoError(t, err)
+
+	deadline := uint64(time.Now().Add(time.Minute).UnixMilli())
+	encDeadline := scale.MustMarshal(&deadline)
+
+	for _, chunk := range [][]byte{[]byte("ping"), {}} {
+		params := append([]byte{}, scale.MustMarshal(uint32(reqID))...)
+		params = append(params, scale.MustMarshal(chunk)...)
+		params = append(params, encDeadline...)
+
+		ret, err := inst.Exec("rtm_ext_offchain_http_request_write_body_version_1", params)
+		require.NoError(t, err)
+
+		result := scale.NewResult(nil, offchain.HTTPError(0))
+		err = scale.Unmarshal(ret, &result)
+		require.NoError(t, err)
+		_, err = result.Unwrap()
+		require.NoError(t, err)
+	}
+
+	params := append([]byte{}, scale.MustMarshal([]uint16{uint16(reqID)})...)
+	params = append(params, encDeadline...)
+	ret, err := inst.Exec("rtm_ext_offchain_http_response_wait_version_1", params)
+	require.NoError(t, err)
+
+	expected := scale.MustMarshal([]offchain.HTTPRequestStatus{
+		{Finished: true, StatusCode: http.StatusAccepted},
+	})
+	require.Equal(t, expected, ret)
+}
+
+func Test_ext_offchain_http_response_wait_version_1_hanging_response(t *testing.T) {
+	const timeout = time.Second
+	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME,
+		TestWithVersion(DefaultVersion), TestWithTimeout(timeout))
+
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(unblock)
+		server.Close()
+	})
+
+	reqID, err := inst.Context.OffchainHTTPSet.StartRequest(http.MethodGet, server.URL)
+	require.NoError(t, err)
+
+	// without deadline, the wait is bounded by the timeout of the runtime call
+	params := append([]byte{}, scale.MustMarshal([]uint16{uint16(reqID)})...)
+	params = append(params, scale.MustMarshal((*uint64)(nil))...)
+
+	start := time.Now()
+	_, err = inst.Exec("rtm_ext_offchain_http_response_wait_version_1", params)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 10*timeout)
+}
+
 func Test_ext_storage_clear_version_1(t *testing.T) {
 	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
 
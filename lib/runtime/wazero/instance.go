@@ -9,6 +9,7 @@
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
@@ -42,6 +43,8 @@ type runtimeContextKeyType struct{
 type wazeroMeta struct {
 	config      wazero.RuntimeConfig
 	cache       wazero.CompilationCache
+	sharedCache bool
+	cacheDir    string
 	guestModule wazero.CompiledModule
 }
 
@@ -53,20 +56,31 @@ type Instance struct {
 	wasmByteCode []byte
 	codeHash     common.Hash
 	metadata     wazeroMeta
+	timeout      time.Duration
+	pool         *instancePool
 	sync.Mutex
 }
 
 // Config is the configuration used to create a Wasmer runtime instance.
+// TransactionSubmitter receives the extrinsics submitted by offchain workers and
+// Timeout, if not zero, limits the duration of each runtime call.
+// CompilationCacheDir, if not empty, is the directory where compiled runtime code
+// is persisted so it is not compiled again on restart, and PoolSize is the maximum
+// number of pooled instances used by ExecWithStorage, DefaultPoolSize if zero.
 type Config struct {
-	Storage        runtime.Storage
-	Keystore       *keystore.GlobalKeystore
-	LogLvl         log.Level
-	Role           common.NetworkRole
-	NodeStorage    runtime.NodeStorage
-	Network        runtime.BasicNetwork
-	Transaction    runtime.TransactionState
-	CodeHash       common.Hash
-	DefaultVersion *runtime.Version
+	Storage              runtime.Storage
+	Keystore             *keystore.GlobalKeystore
+	LogLvl               log.Level
+	Role                 common.NetworkRole
+	NodeStorage          runtime.NodeStorage
+	Network              runtime.BasicNetwork
+	Transaction          runtime.TransactionState
+	TransactionSubmitter runtime.TransactionSubmitter
+	CodeHash             common.Hash
+	DefaultVersion       *runtime.Version
+	Timeout              time.Duration
+	CompilationCacheDir  string
+	PoolSize             int
 }
 
 func decompressWasm(code []byte) ([]byte, error) {
@@ -545,6 +559,30 @@ func newRuntime(ctx context.Context,
 		).
 		Export("ext_offchain_http_request_add_header_version_1").
 		NewFunctionBuilder().
+		WithGoModuleFunction(
+			tripleArgWithReturnFn(ext_offchain_http_request_write_body_version_1),
+			[]api.ValueType{i32, i64, i64}, []api.ValueType{i64},
+		).
+		Export("ext_offchain_http_request_write_body_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			doubleArgWithReturnFn(ext_offchain_http_response_wait_version_1),
+			[]api.ValueType{i64, i64}, []api.ValueType{i64},
+		).
+		Export("ext_offchain_http_response_wait_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			singleArgWithReturnFn(ext_offchain_http_response_headers_version_1),
+			[]api.ValueType{i32}, []api.ValueType{i64},
+		).
+		Export("ext_offchain_http_response_headers_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			tripleArgWithReturnFn(ext_offchain_http_response_read_body_version_1),
+			[]api.ValueType{i32, i64, i64}, []api.ValueType{i64},
+		).
+		Export("ext_offchain_http_response_read_body_version_1").
+		NewFunctionBuilder().
 		WithGoModuleFunction(
 			doubleArgFn(ext_storage_append_version_1),
 			[]api.ValueType{i64, i64}, []api.ValueType{},
@@ -640,6 +678,36 @@ func newRuntime(ctx context.Context,
 			[]api.ValueType{i32, i64}, []api.ValueType{i32},
 		).
 		Export("ext_crypto_ecdsa_generate_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			singleArgWithReturnFn(ext_crypto_ecdsa_public_keys_version_1),
+			[]api.ValueType{i32}, []api.ValueType{i64},
+		).
+		Export("ext_crypto_ecdsa_public_keys_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			tripleArgWithReturnFn(ext_crypto_ecdsa_sign_version_1),
+			[]api.ValueType{i32, i32, i64}, []api.ValueType{i64},
+		).
+		Export("ext_crypto_ecdsa_sign_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			tripleArgWithReturnFn(ext_crypto_ecdsa_sign_prehashed_version_1),
+			[]api.ValueType{i32, i32, i32}, []api.ValueType{i64},
+		).
+		Export("ext_crypto_ecdsa_sign_prehashed_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			tripleArgWithReturnFn(ext_crypto_ecdsa_verify_version_1),
+			[]api.ValueType{i32, i64, i32}, []api.ValueType{i32},
+		).
+		Export("ext_crypto_ecdsa_verify_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			tripleArgWithReturnFn(ext_crypto_ecdsa_verify_prehashed_version_1),
+			[]api.ValueType{i32, i32, i32}, []api.ValueType{i32},
+		).
+		Export("ext_crypto_ecdsa_verify_prehashed_version_1").
 		Compile(ctx)
 
 	if err != nil {
@@ -673,10 +741,45 @@ func NewInstance(code []byte, cfg Config) (instance *Instance, err error) {
 	logger.Debug("instantiating a runtime!")
 	logger.Patch(log.SetLevel(cfg.LogLvl), log.SetCallerFunc(true))
 
-	// Prepare a cache directory.
+	cache, sharedCache, err := compilationCache(cfg.CompilationCacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err = newInstance(code, cfg, cache, sharedCache)
+	if err != nil {
+		if !sharedCache {
+			_ = cache.Close(context.Background())
+		}
+		return nil, err
+	}
+
+	poolSize := cfg.PoolSize
+	if poolSize == 0 {
+		poolSize = DefaultPoolSize
+	}
+
+	// pooled instances share the compilation cache of the instance,
+	// which is closed by the instance once its pool is closed.
+	poolCfg := cfg
+	poolCfg.Storage = nil
+	poolCfg.DefaultVersion = instance.Context.Version
+	instance.pool = newInstancePool(poolSize, func() (*Instance, error) {
+		return newInstance(code, poolCfg, cache, true)
+	})
+
+	return instance, nil
+}
+
+// newInstance instantiates a runtime from raw wasm bytecode using the given
+// compilation cache, without any instance pool.
+func newInstance(code []byte, cfg Config, cache wazero.CompilationCache, sharedCache bool) (
+	instance *Instance, err error) {
 	ctx := context.Background()
-	cache := wazero.NewCompilationCache()
 	config := wazero.NewRuntimeConfig().WithCompilationCache(cache)
+	if cfg.Timeout > 0 {
+		config = config.WithCloseOnContextDone(true)
+	}
 	mod, rt, guestCompiledModule, err := newRuntime(ctx, code, config)
 	if err != nil {
 		return nil, fmt.Errorf("creating runtime instance: %w", err)
@@ -686,19 +789,23 @@ func NewInstance(code []byte, cfg Config) (instance *Instance, err error) {
 		wasmByteCode: code,
 		Runtime:      rt,
 		Context: &runtime.Context{
-			Keystore:        cfg.Keystore,
-			Validator:       cfg.Role == common.AuthorityRole,
-			NodeStorage:     cfg.NodeStorage,
-			Network:         cfg.Network,
-			Transaction:     cfg.Transaction,
-			SigVerifier:     crypto.NewSignatureVerifier(logger),
-			OffchainHTTPSet: offchain.NewHTTPSet(),
+			Keystore:             cfg.Keystore,
+			Validator:            cfg.Role == common.AuthorityRole,
+			NodeStorage:          cfg.NodeStorage,
+			Network:              cfg.Network,
+			Transaction:          cfg.Transaction,
+			TransactionSubmitter: cfg.TransactionSubmitter,
+			SigVerifier:          crypto.NewSignatureVerifier(logger),
+			OffchainHTTPSet:      offchain.NewHTTPSet(),
 		},
 		Module:   mod,
 		codeHash: cfg.CodeHash,
+		timeout:  cfg.Timeout,
 		metadata: wazeroMeta{
 			config:      config,
 			cache:       cache,
+			sharedCache: sharedCache,
+			cacheDir:    cfg.CompilationCacheDir,
 			guestModule: guestCompiledModule,
 		},
 	}
@@ -725,6 +832,11 @@ func (i *Instance) Exec(function string, data []byte) ([]byte, error) {
 	i.Lock()
 	defer i.Unlock()
 
+	return i.exec(function, data)
+}
+
+// exec executes the given function. It must be called with the instance locked.
+func (i *Instance) exec(function string, data []byte) ([]byte, error) {
 	mod, err := i.Runtime.InstantiateModule(context.Background(), i.metadata.guestModule, wazero.NewModuleConfig())
 	if mod == nil {
 		return nil, fmt.Errorf("instantiate guest module: nil")
@@ -769,7 +881,17 @@ func (i *Instance) Exec(function string, data []byte) ([]byte, error) {
 		return nil, fmt.Errorf("%w: %s", ErrExportFunctionNotFound, function)
 	}
 
+	if i.Context.SigVerifier != nil {
+		// do not leak a batch verification left started by a failed call
+		defer i.Context.SigVerifier.Reset()
+	}
+
 	ctx := context.WithValue(context.Background(), runtimeContextKey, i.Context)
+	if i.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.timeout)
+		defer cancel()
+	}
 	values, err := runtimeFunc.Call(ctx, api.EncodeU32(inputPtr), api.EncodeU32(dataLength))
 	if err != nil {
 		return nil, fmt.Errorf("running runtime function: %w", err)
@@ -787,6 +909,36 @@ func (i *Instance) Exec(function string, data []byte) ([]byte, error) {
 	return result, nil
 }
 
+// ExecWithStorage executes the runtime function with the given storage on an
+// instance of the pool of the runtime, so it runs in parallel with the other
+// calls on the runtime instead of waiting for them.
+func (i *Instance) ExecWithStorage(storage runtime.Storage, function string, data []byte) ([]byte, error) {
+	if i.pool == nil {
+		// the storage must not be replaced by another call before the function returns
+		i.Lock()
+		defer i.Unlock()
+
+		i.setContextStorage(storage)
+		return i.exec(function, data)
+	}
+
+	instance, err := i.pool.get()
+	if err != nil {
+		return nil, fmt.Errorf("getting pooled instance: %w", err)
+	}
+	defer i.pool.put(instance)
+
+	instance.SetContextStorage(storage)
+	defer func() {
+		// do not keep the storage alive while the instance is idle
+		instance.Lock()
+		instance.Context.Storage = nil
+		instance.Unlock()
+	}()
+
+	return instance.Exec(function, data)
+}
+
 // Version returns the instance version.
 // This is cheap to call since the instance version is cached.
 // Note the instance version is set at creation and on code update.
@@ -870,6 +1022,32 @@ func (in *Instance) GrandpaAuthorities() ([]types.Authority, error) {
 	return types.GrandpaAuthoritiesRawToAuthorities(gar)
 }
 
+// AuraSlotDuration returns the Aura slot duration in milliseconds from the runtime
+func (in *Instance) AuraSlotDuration() (uint64, error) {
+	ret, err := in.Exec(runtime.AuraAPISlotDuration, []byte{})
+	if err != nil {
+		return 0, err
+	}
+
+	var slotDuration uint64
+	err = scale.Unmarshal(ret, &slotDuration)
+	if err != nil {
+		return 0, fmt.Errorf("decoding slot duration: %w", err)
+	}
+
+	return slotDuration, nil
+}
+
+// AuraAuthorities returns the current Aura authorities from the runtime
+func (in *Instance) AuraAuthorities() ([]types.Authority, error) {
+	ret, err := in.Exec(runtime.AuraAPIAuthorities, []byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	return types.DecodeAuraAuthorities(ret)
+}
+
 // BabeGenerateKeyOwnershipProof returns the babe key ownership proof from the runtime.
 func (in *Instance) BabeGenerateKeyOwnershipProof(slot uint64, authorityID [32]byte) (
 	types.OpaqueKeyOwnershipProof, error) {
@@ -963,32 +1141,7 @@ func (in *Instance) FinalizeBlock() (*types.Header, error) {
 
 // ExecuteBlock calls runtime function Core_execute_block
 func (in *Instance) ExecuteBlock(block *types.Block) ([]byte, error) {
-	// copy block since we're going to modify it
-	b, err := block.DeepCopy()
-	if err != nil {
-		return nil, err
-	}
-
-	b.Header.Digest = types.NewDigest()
-
-	// remove seal digest only
-	for _, d := range block.Header.Digest {
-		digestValue, err := d.Value()
-		if err != nil {
-			return nil, fmt.Errorf("getting digest type value: %w", err)
-		}
-		switch digestValue.(type) {
-		case types.SealDigest:
-			continue
-		default:
-			err = b.Header.Digest.Add(digestValue)
-			if err != nil {
-				return nil, err
-			}
-		}
-	}
-
-	bdEnc, err := b.Encode()
+	bdEnc, err := runtime.EncodeBlockToExecute(block)
 	if err != nil {
 		return nil, err
 	}
@@ -1125,11 +1278,46 @@ func (in *Instance) GrandpaSubmitReportEquivocationUnsignedExtrinsic(
 func (*Instance) RandomSeed() {
 	panic("unimplemented")
 }
-func (*Instance) OffchainWorker() {
-	panic("unimplemented")
+
+// OffchainWorker calls runtime API function OffchainWorkerApi_offchain_worker
+// with the header of the block the worker is started for.
+func (in *Instance) OffchainWorker(header *types.Header) error {
+	encodedHeader, err := scale.Marshal(*header)
+	if err != nil {
+		return fmt.Errorf("encoding header: %w", err)
+	}
+
+	// the HTTP requests left by the previous offchain worker of the instance
+	// are discarded, so they do not use up the request identifiers.
+	in.Lock()
+	in.Context.OffchainHTTPSet = offchain.NewHTTPSet()
+	in.Unlock()
+
+	_, err = in.Exec(runtime.OffchainWorkerAPIOffchainWorker, encodedHeader)
+	return err
 }
-func (*Instance) GenerateSessionKeys() {
-	panic("unimplemented")
+
+// GenerateSessionKeys calls runtime API function SessionKeys_generate_session_keys, which
+// generates the session keys of the runtime in the keystore of the instance, using the
+// optional seed given. It returns the concatenated public keys, as encoded by the runtime.
+func (in *Instance) GenerateSessionKeys(seed *[]byte) ([]byte, error) {
+	encodedSeed, err := scale.Marshal(seed)
+	if err != nil {
+		return nil, fmt.Errorf("encoding seed: %w", err)
+	}
+
+	ret, err := in.Exec(runtime.GenerateSessionKeys, encodedSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessionKeys []byte
+	err = scale.Unmarshal(ret, &sessionKeys)
+	if err != nil {
+		return nil, fmt.Errorf("decoding session keys: %w", err)
+	}
+
+	return sessionKeys, nil
 }
 
 // GetCodeHash returns the code of the instance
@@ -1157,11 +1345,22 @@ func (in *Instance) Validator() bool {
 	return in.Context.Validator
 }
 
+// CompilationCacheDir returns the directory where the compiled code of the
+// instance is persisted, or an empty string if it is only cached in memory.
+func (in *Instance) CompilationCacheDir() string {
+	return in.metadata.cacheDir
+}
+
 // SetContextStorage sets the runtime's storage.
 func (in *Instance) SetContextStorage(s runtime.Storage) {
 	in.Lock()
 	defer in.Unlock()
 
+	in.setContextStorage(s)
+}
+
+// setContextStorage sets the runtime's storage. It must be called with the instance locked.
+func (in *Instance) setContextStorage(s runtime.Storage) {
 	if in.Context.Version == nil {
 		panic("expected runtime version got nil")
 	}
@@ -1176,8 +1375,19 @@ func (in *Instance) SetContextStorage(s runtime.Storage) {
 }
 
 // Stop closes the WASM instance, its imports and clears
-// the context allocator in a thread-safe way.
+// the context allocator in a thread-safe way. The instances of
+// its pool running calls are stopped once they return, and the
+// instance itself is stopped with them since they share its code.
 func (in *Instance) Stop() {
+	if in.pool == nil {
+		in.stop()
+		return
+	}
+
+	in.pool.close(in.stop)
+}
+
+func (in *Instance) stop() {
 	in.Lock()
 	defer in.Unlock()
 	err := in.Runtime.Close(context.Background())
@@ -1185,6 +1395,10 @@ func (in *Instance) Stop() {
 		log.Errorf("runtime failed to close: %v", err)
 	}
 
+	if in.metadata.sharedCache {
+		return
+	}
+
 	err = in.metadata.cache.Close(context.Background())
 	if err != nil {
 		log.Errorf("closing the wazero compilation cache: %v", err)
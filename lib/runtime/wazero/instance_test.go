@@ -18,6 +18,7 @@
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/runtime/wazero/testdata"
@@ -1085,6 +1086,50 @@ func TestInstance_DecodeSessionKeys(t *testing.T) {
 	require.Len(t, *decodedKeys, 6)
 }
 
+func TestInstance_GenerateSessionKeys(t *testing.T) {
+	genesisPath := utils.GetWestendDevRawGenesisPath(t)
+	gen := genesisFromRawJSON(t, genesisPath)
+	genTrie, err := runtime.NewTrieFromGenesis(gen)
+	require.NoError(t, err)
+
+	ks := keystore.NewGlobalKeystore()
+	cfg := Config{
+		Storage:  storage.NewTrieState(genTrie),
+		Keystore: ks,
+		LogLvl:   log.Critical,
+	}
+
+	instance, err := NewRuntimeFromGenesis(cfg)
+	require.NoError(t, err)
+
+	sessionKeys, err := instance.GenerateSessionKeys(nil)
+	require.NoError(t, err)
+
+	encodedSessionKeys, err := scale.Marshal(sessionKeys)
+	require.NoError(t, err)
+	decoded, err := instance.DecodeSessionKeys(encodedSessionKeys)
+	require.NoError(t, err)
+
+	var decodedKeys *[]struct {
+		Data []uint8
+		Type [4]uint8
+	}
+	err = scale.Unmarshal(decoded, &decodedKeys)
+	require.NoError(t, err)
+	require.NotNil(t, decodedKeys)
+	require.NotEmpty(t, *decodedKeys)
+
+	// each generated key is stored in the keystore of its key type
+	for _, key := range *decodedKeys {
+		keyStore, err := ks.GetKeystore(key.Type[:])
+		require.NoError(t, err)
+
+		publicKeys := keyStore.PublicKeys()
+		require.Len(t, publicKeys, 1)
+		assert.Equal(t, key.Data, publicKeys[0].Encode())
+	}
+}
+
 func TestInstance_PaymentQueryInfo(t *testing.T) {
 	tests := []struct {
 		extB       []byte
@@ -1339,3 +1384,91 @@ func TestInstance_GrandpaSubmitReportEquivocationUnsignedExtrinsic(t *testing.T)
 	err = runtime.GrandpaSubmitReportEquivocationUnsignedExtrinsic(equivocationProof, opaqueKeyOwnershipProof)
 	require.NoError(t, err)
 }
+
+func TestInstance_ExecWithStorage(t *testing.T) {
+	t.Parallel()
+
+	genesisPath := utils.GetWestendDevRawGenesisPath(t)
+	gen := genesisFromRawJSON(t, genesisPath)
+	genTrie, err := runtime.NewTrieFromGenesis(gen)
+	require.NoError(t, err)
+
+	cacheDir := t.TempDir()
+	cfg := Config{
+		Storage:             storage.NewTrieState(genTrie),
+		LogLvl:              log.Critical,
+		CompilationCacheDir: cacheDir,
+		PoolSize:            2,
+	}
+
+	instance, err := NewRuntimeFromGenesis(cfg)
+	require.NoError(t, err)
+	defer instance.Stop()
+
+	assert.Equal(t, cacheDir, instance.CompilationCacheDir())
+	cacheEntries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.NotEmpty(t, cacheEntries)
+
+	expected, err := instance.Exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+
+	const parallelCalls = 4
+	results := make(chan []byte, parallelCalls)
+	errs := make(chan error, parallelCalls)
+	for i := 0; i < parallelCalls; i++ {
+		go func() {
+			ts := storage.NewTrieState(genTrie.(*inmemory_trie.InMemoryTrie).Snapshot())
+			result, err := instance.ExecWithStorage(ts, runtime.CoreVersion, []byte{})
+			results <- result
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < parallelCalls; i++ {
+		require.NoError(t, <-errs)
+		assert.Equal(t, expected, <-results)
+	}
+
+	assert.LessOrEqual(t, len(instance.pool.slots), cfg.PoolSize)
+	assert.Zero(t, instance.pool.checkedOut)
+}
+
+func TestInstance_Stop_checkedOutPoolInstance(t *testing.T) {
+	t.Parallel()
+
+	genesisPath := utils.GetWestendDevRawGenesisPath(t)
+	gen := genesisFromRawJSON(t, genesisPath)
+	genTrie, err := runtime.NewTrieFromGenesis(gen)
+	require.NoError(t, err)
+
+	cfg := Config{
+		Storage:  storage.NewTrieState(genTrie),
+		LogLvl:   log.Critical,
+		PoolSize: 2,
+	}
+
+	instance, err := NewRuntimeFromGenesis(cfg)
+	require.NoError(t, err)
+
+	idle, err := instance.pool.get()
+	require.NoError(t, err)
+	checkedOut, err := instance.pool.get()
+	require.NoError(t, err)
+	instance.pool.put(idle)
+
+	instance.Stop()
+
+	_, err = instance.ExecWithStorage(storage.NewTrieState(genTrie), runtime.CoreVersion, []byte{})
+	require.ErrorIs(t, err, errInstancePoolClosed)
+
+	// the checked out instance keeps running its calls until it is released
+	_, err = checkedOut.Exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+
+	instance.pool.put(checkedOut)
+	for _, stopped := range []*Instance{instance, idle, checkedOut} {
+		_, err = stopped.Exec(runtime.CoreVersion, []byte{})
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero_runtime
+
+import (
+	"errors"
+	"sync"
+)
+
+// DefaultPoolSize is the default maximum number of pooled instances of a runtime instance
+const DefaultPoolSize = 4
+
+var errInstancePoolClosed = errors.New("instance pool closed")
+
+// instancePool is a pool of instances sharing the code of a runtime instance,
+// used to execute runtime calls in parallel. Instances are created on demand,
+// up to the size of the pool, and reused once released.
+type instancePool struct {
+	newInstance func() (*Instance, error)
+	idle        chan *Instance
+	slots       chan struct{}
+
+	mutex sync.Mutex
+	// checkedOut is the number of instances obtained with get and not released yet.
+	checkedOut int
+	closed     bool
+	// onStopped is called once the pool is closed and its instances are stopped.
+	onStopped func()
+}
+
+func newInstancePool(size int, newInstance func() (*Instance, error)) *instancePool {
+	return &instancePool{
+		newInstance: newInstance,
+		idle:        make(chan *Instance, size),
+		slots:       make(chan struct{}, size),
+	}
+}
+
+// get returns an idle instance of the pool, creating one if the pool is not
+// full, or waits for an instance to be released otherwise.
+func (p *instancePool) get() (*Instance, error) {
+	p.mutex.Lock()
+	closed := p.closed
+	p.mutex.Unlock()
+	if closed {
+		return nil, errInstancePoolClosed
+	}
+
+	var instance *Instance
+	select {
+	case instance = <-p.idle:
+	default:
+		select {
+		case instance = <-p.idle:
+		case p.slots <- struct{}{}:
+			var err error
+			instance, err = p.newInstance()
+			if err != nil {
+				<-p.slots
+				return nil, err
+			}
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		p.idle <- instance
+		p.stop()
+		return nil, errInstancePoolClosed
+	}
+
+	p.checkedOut++
+	return instance, nil
+}
+
+// put releases an instance obtained with get back to the pool. If the pool
+// was closed while the instance was checked out, the instances are stopped
+// once the last checked out instance is released.
+func (p *instancePool) put(instance *Instance) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.checkedOut--
+	p.idle <- instance
+	if p.closed {
+		p.stop()
+	}
+}
+
+// close closes the pool, so no instance can be obtained from it anymore, and
+// stops its instances. The instances share the compiled code of the runtime,
+// which is released when any of them is stopped, so they are only stopped once
+// no instance is checked out anymore, and the function given is called after
+// that, which may be after close returns.
+func (p *instancePool) close(onStopped func()) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return
+	}
+	p.closed = true
+	p.onStopped = onStopped
+	p.stop()
+}
+
+// stop stops the instances of a closed pool and calls its onStopped function
+// once no instance is checked out anymore. It must be called with the pool mutex locked.
+func (p *instancePool) stop() {
+	if p.checkedOut > 0 {
+		return
+	}
+
+	for {
+		select {
+		case instance := <-p.idle:
+			instance.Stop()
+			continue
+		default:
+		}
+		break
+	}
+
+	if p.onStopped != nil {
+		onStopped := p.onStopped
+		p.onStopped = nil
+		onStopped()
+	}
+}
@@ -8,6 +8,7 @@
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -43,6 +44,12 @@ func TestWithVersion(version *runtime.Version) TestInstanceOption {
 	}
 }
 
+func TestWithTimeout(timeout time.Duration) TestInstanceOption {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
 func NewTestInstance(t *testing.T, targetRuntime string, opts ...TestInstanceOption) *Instance {
 	t.Helper()
 
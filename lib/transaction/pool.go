@@ -17,9 +17,13 @@
 	Help:      "total number of transactions in ready pool",
 })
 
-// Pool represents the transaction pool
+// Pool represents the transaction pool, holding the future transactions
+// waiting for the tags they require to be provided.
 type Pool struct {
 	transactions map[common.Hash]*ValidTransaction
+	validUntil   map[common.Hash]uint
+	tags         *tagIndex
+	blockNumber  uint
 	mu           sync.RWMutex
 }
 
@@ -27,6 +31,8 @@ type Pool struct {
 func NewPool() *Pool {
 	return &Pool{
 		transactions: make(map[common.Hash]*ValidTransaction),
+		validUntil:   make(map[common.Hash]uint),
+		tags:         newTagIndex(),
 	}
 }
 
@@ -53,12 +59,19 @@ func (p *Pool) Transactions() []*ValidTransaction {
 	return txs
 }
 
-// Insert inserts a transaction into the pool
+// Insert inserts a transaction into the pool, replacing the transaction with the same hash
 func (p *Pool) Insert(tx *ValidTransaction) common.Hash {
 	hash := tx.Extrinsic.Hash()
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.remove(hash)
 	p.transactions[hash] = tx
+	p.tags.add(hash, tx.Validity)
+	var longevity uint64
+	if tx.Validity != nil {
+		longevity = tx.Validity.Longevity
+	}
+	p.validUntil[hash] = validUntil(p.blockNumber, longevity)
 	transactionPoolGauge.Set(float64(len(p.transactions)))
 	return hash
 }
@@ -67,8 +80,61 @@ func (p *Pool) Insert(tx *ValidTransaction) common.Hash {
 func (p *Pool) Remove(hash common.Hash) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.remove(hash)
+	transactionPoolGauge.Set(float64(len(p.transactions)))
+}
+
+// remove removes a transaction from the pool. It must be called with the pool locked.
+func (p *Pool) remove(hash common.Hash) {
+	tx, ok := p.transactions[hash]
+	if !ok {
+		return
+	}
+	p.tags.remove(hash, tx.Validity)
 	delete(p.transactions, hash)
+	delete(p.validUntil, hash)
+}
+
+// Providers returns the transactions of the pool providing any of the given tags
+func (p *Pool) Providers(tags [][]byte) []*ValidTransaction {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var txs []*ValidTransaction
+	for _, hash := range p.tags.providers(tags) {
+		txs = append(txs, p.transactions[hash])
+	}
+	return txs
+}
+
+// Requiring returns the transactions of the pool requiring any of the given tags
+func (p *Pool) Requiring(tags [][]byte) []*ValidTransaction {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var txs []*ValidTransaction
+	for _, hash := range p.tags.requiring(tags) {
+		txs = append(txs, p.transactions[hash])
+	}
+	return txs
+}
+
+// RemoveExpired sets the current block number of the pool, and removes and returns the
+// transactions whose longevity has expired at this block number.
+func (p *Pool) RemoveExpired(blockNumber uint) (expired []*ValidTransaction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.blockNumber = blockNumber
+	for hash, until := range p.validUntil {
+		if until < blockNumber {
+			expired = append(expired, p.transactions[hash])
+			p.remove(hash)
+		}
+	}
+
 	transactionPoolGauge.Set(float64(len(p.transactions)))
+	return expired
 }
 
 // Len return the current length of the pool
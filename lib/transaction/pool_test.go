@@ -53,3 +53,51 @@ func TestPool(t *testing.T) {
 	}
 	require.Equal(t, 0, len(p.Transactions()))
 }
+
+func TestPool_Tags(t *testing.T) {
+	t.Parallel()
+
+	provider := &ValidTransaction{
+		Extrinsic: []byte("a"),
+		Validity:  &Validity{Priority: 1, Provides: [][]byte{{0}, {1}}},
+	}
+	dependent := &ValidTransaction{
+		Extrinsic: []byte("b"),
+		Validity:  &Validity{Priority: 1, Requires: [][]byte{{1}}, Provides: [][]byte{{2}}},
+	}
+
+	p := NewPool()
+	p.Insert(provider)
+	p.Insert(dependent)
+
+	require.Equal(t, []*ValidTransaction{provider}, p.Providers([][]byte{{0}, {1}}))
+	require.Equal(t, []*ValidTransaction{dependent}, p.Providers([][]byte{{2}}))
+	require.Empty(t, p.Providers([][]byte{{3}}))
+	require.Equal(t, []*ValidTransaction{dependent}, p.Requiring([][]byte{{1}}))
+
+	p.Remove(dependent.Extrinsic.Hash())
+	require.Empty(t, p.Requiring([][]byte{{1}}))
+	require.Empty(t, p.Providers([][]byte{{2}}))
+}
+
+func TestPool_RemoveExpired(t *testing.T) {
+	t.Parallel()
+
+	shortLived := &ValidTransaction{
+		Extrinsic: []byte("a"),
+		Validity:  &Validity{Longevity: 1},
+	}
+	longLived := &ValidTransaction{
+		Extrinsic: []byte("b"),
+		Validity:  &Validity{Longevity: 10},
+	}
+
+	p := NewPool()
+	p.RemoveExpired(5)
+	p.Insert(shortLived)
+	p.Insert(longLived)
+
+	require.Empty(t, p.RemoveExpired(6))
+	require.Equal(t, []*ValidTransaction{shortLived}, p.RemoveExpired(7))
+	require.Equal(t, []*ValidTransaction{longLived}, p.Transactions())
+}
@@ -6,6 +6,7 @@
 import (
 	"container/heap"
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,8 +16,13 @@
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-// ErrTransactionExists is returned when trying to add a transaction to the queue that already exists
-var ErrTransactionExists = errors.New("transaction is already in queue")
+var (
+	// ErrTransactionExists is returned when trying to add a transaction to the queue that already exists
+	ErrTransactionExists = errors.New("transaction is already in queue")
+	// ErrTooLowPriority is returned when trying to add a transaction providing the same tags as
+	// a transaction already in the queue or pool, without a higher priority
+	ErrTooLowPriority = errors.New("priority is too low to replace transaction")
+)
 
 var transactionQueueGauge = promauto.NewGauge(prometheus.GaugeOpts{
 	Namespace: "gossamer_state_transaction",
@@ -36,7 +42,14 @@ type Item struct {
 	order uint64
 
 	// The index is needed by update and is maintained by the heap.Interface methods.
-	index int // The index of the item in the heap.
+	index int // The index of the item in the heap, or -1 if the item is not in the heap.
+
+	// blockedBy is the number of distinct tags required by the item which are provided by
+	// other items of the queue. The item is only in the heap once it is no longer blocked.
+	blockedBy int
+
+	// validUntil is the last block number at which the transaction is valid.
+	validUntil uint
 }
 
 // A PriorityQueue implements heap.Interface and holds Items.
@@ -76,11 +89,16 @@ func (pq *priorityQueue) Pop() interface{} { //skipcq: GO-W1029
 	return item
 }
 
-// PriorityQueue is a thread safe wrapper over `priorityQueue`
+// PriorityQueue is a thread safe wrapper over `priorityQueue`, holding the ready transactions.
+// A transaction requiring tags provided by other transactions of the queue is only popped
+// once these transactions are popped or removed, the tags it requires being assumed to be
+// provided by the chain otherwise.
 type PriorityQueue struct {
 	pq           priorityQueue
 	currOrder    uint64
 	txs          map[common.Hash]*Item
+	tags         *tagIndex
+	blockNumber  uint
 	pollInterval time.Duration
 	sync.Mutex
 }
@@ -89,6 +107,7 @@ type PriorityQueue struct {
 func NewPriorityQueue() *PriorityQueue {
 	spq := &PriorityQueue{
 		txs:          make(map[common.Hash]*Item),
+		tags:         newTagIndex(),
 		pollInterval: 10 * time.Millisecond,
 	}
 
@@ -101,14 +120,33 @@ func (spq *PriorityQueue) RemoveExtrinsic(ext types.Extrinsic) {
 	spq.Lock()
 	defer spq.Unlock()
 
-	hash := ext.Hash()
-	item, ok := spq.txs[hash]
+	item, ok := spq.txs[ext.Hash()]
 	if !ok {
 		return
 	}
 
-	heap.Remove(&spq.pq, item.index)
-	delete(spq.txs, hash)
+	spq.remove(item)
+	transactionQueueGauge.Set(float64(len(spq.txs)))
+}
+
+// remove removes the item from the queue and unblocks the items requiring the tags it provides.
+// It must be called with the queue locked.
+func (spq *PriorityQueue) remove(item *Item) {
+	if item.index >= 0 {
+		heap.Remove(&spq.pq, item.index)
+	}
+	delete(spq.txs, item.hash)
+
+	// dependents are blocked once by each released tag they require
+	for _, tag := range spq.tags.remove(item.hash, item.data.Validity) {
+		for _, hash := range spq.tags.requiring([][]byte{tag}) {
+			dependent := spq.txs[hash]
+			dependent.blockedBy--
+			if dependent.blockedBy == 0 {
+				heap.Push(&spq.pq, dependent)
+			}
+		}
+	}
 }
 
 // Exists returns true if a hash is in the txs map, false otherwise
@@ -117,6 +155,18 @@ func (spq *PriorityQueue) Exists(extHash common.Hash) bool {
 	return ok
 }
 
+// Get returns the transaction of the queue with the given extrinsic hash, or nil
+func (spq *PriorityQueue) Get(extHash common.Hash) *ValidTransaction {
+	spq.Lock()
+	defer spq.Unlock()
+
+	item, ok := spq.txs[extHash]
+	if !ok {
+		return nil
+	}
+	return item.data
+}
+
 // Push inserts a valid transaction with priority p into the queue
 func (spq *PriorityQueue) Push(txn *ValidTransaction) (common.Hash, error) {
 	spq.Lock()
@@ -128,19 +178,102 @@ func (spq *PriorityQueue) Push(txn *ValidTransaction) (common.Hash, error) {
 	}
 
 	item := &Item{
-		data:     txn,
-		hash:     hash,
-		order:    spq.currOrder,
-		priority: txn.Validity.Priority,
+		data:       txn,
+		hash:       hash,
+		order:      spq.currOrder,
+		priority:   txn.Validity.Priority,
+		index:      -1,
+		validUntil: validUntil(spq.blockNumber, txn.Validity.Longevity),
 	}
 	spq.currOrder++
-	heap.Push(&spq.pq, item)
+
+	for _, tag := range distinctTags(txn.Validity.Requires) {
+		if spq.tags.isProvided(tag) {
+			item.blockedBy++
+		}
+	}
+
+	// the items requiring a tag provided by this transaction now wait for it
+	for _, tag := range distinctTags(txn.Validity.Provides) {
+		if spq.tags.isProvided(tag) {
+			continue
+		}
+
+		for _, dependentHash := range spq.tags.requiring([][]byte{tag}) {
+			dependent := spq.txs[dependentHash]
+			dependent.blockedBy++
+			if dependent.index >= 0 {
+				heap.Remove(&spq.pq, dependent.index)
+			}
+		}
+	}
+
 	spq.txs[hash] = item
+	spq.tags.add(hash, txn.Validity)
+	if item.blockedBy == 0 {
+		heap.Push(&spq.pq, item)
+	}
 
-	transactionQueueGauge.Set(float64(spq.pq.Len()))
+	transactionQueueGauge.Set(float64(len(spq.txs)))
 	return hash, nil
 }
 
+// IsProvided returns true if the tag is provided by a transaction of the queue
+func (spq *PriorityQueue) IsProvided(tag []byte) bool {
+	spq.Lock()
+	defer spq.Unlock()
+
+	return spq.tags.isProvided(tag)
+}
+
+// Providers returns the transactions of the queue providing any of the given tags
+func (spq *PriorityQueue) Providers(tags [][]byte) []*ValidTransaction {
+	spq.Lock()
+	defer spq.Unlock()
+
+	var txns []*ValidTransaction
+	for _, hash := range spq.tags.providers(tags) {
+		txns = append(txns, spq.txs[hash].data)
+	}
+	return txns
+}
+
+// Requiring returns the transactions of the queue requiring any of the given tags
+func (spq *PriorityQueue) Requiring(tags [][]byte) []*ValidTransaction {
+	spq.Lock()
+	defer spq.Unlock()
+
+	var txns []*ValidTransaction
+	for _, hash := range spq.tags.requiring(tags) {
+		txns = append(txns, spq.txs[hash].data)
+	}
+	return txns
+}
+
+// RemoveExpired sets the current block number of the queue, and removes and returns the
+// transactions whose longevity has expired at this block number.
+func (spq *PriorityQueue) RemoveExpired(blockNumber uint) (expired []*ValidTransaction) {
+	spq.Lock()
+	defer spq.Unlock()
+
+	spq.blockNumber = blockNumber
+	for _, item := range spq.txs {
+		if item.validUntil < blockNumber {
+			expired = append(expired, item.data)
+		}
+	}
+
+	for _, txn := range expired {
+		item, ok := spq.txs[txn.Extrinsic.Hash()]
+		if ok {
+			spq.remove(item)
+		}
+	}
+
+	transactionQueueGauge.Set(float64(len(spq.txs)))
+	return expired
+}
+
 // PopWithTimer returns the next valid transaction from the queue.
 // When the timer expires, it returns `nil`.
 func (spq *PriorityQueue) PopWithTimer(timerCh <-chan time.Time) (transaction *ValidTransaction) {
@@ -177,6 +310,7 @@ func (spq *PriorityQueue) PopWithTimer(timerCh <-chan time.Time) (transaction *V
 
 // Pop removes the transaction with has the highest priority value from the queue and returns it.
 // If there are multiple transaction with same priority value then it return them in FIFO order.
+// The transactions requiring the tags it provides can be popped afterwards.
 func (spq *PriorityQueue) Pop() *ValidTransaction {
 	spq.Lock()
 	defer spq.Unlock()
@@ -185,9 +319,9 @@ func (spq *PriorityQueue) Pop() *ValidTransaction {
 	}
 
 	item := heap.Pop(&spq.pq).(*Item)
-	delete(spq.txs, item.hash)
+	spq.remove(item)
 
-	transactionQueueGauge.Set(float64(spq.pq.Len()))
+	transactionQueueGauge.Set(float64(len(spq.txs)))
 	return item.data
 }
 
@@ -201,7 +335,8 @@ func (spq *PriorityQueue) Peek() *ValidTransaction {
 	return spq.pq[0].data
 }
 
-// Pending returns all the transactions currently in the queue
+// Pending returns all the transactions currently in the queue, the ones waiting
+// for the transactions providing the tags they require being returned last.
 func (spq *PriorityQueue) Pending() []*ValidTransaction {
 	spq.Lock()
 	defer spq.Unlock()
@@ -210,6 +345,19 @@ func (spq *PriorityQueue) Pending() []*ValidTransaction {
 	for idx := 0; idx < spq.pq.Len(); idx++ {
 		txns = append(txns, spq.pq[idx].data)
 	}
+
+	blocked := make([]*Item, 0, len(spq.txs)-spq.pq.Len())
+	for _, item := range spq.txs {
+		if item.index < 0 {
+			blocked = append(blocked, item)
+		}
+	}
+	sort.Slice(blocked, func(i, j int) bool {
+		return blocked[i].order < blocked[j].order
+	})
+	for _, item := range blocked {
+		txns = append(txns, item.data)
+	}
 	return txns
 }
 
@@ -218,5 +366,5 @@ func (spq *PriorityQueue) Len() int {
 	spq.Lock()
 	defer spq.Unlock()
 
-	return spq.pq.Len()
+	return len(spq.txs)
 }
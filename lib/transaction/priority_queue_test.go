@@ -9,6 +9,7 @@
 	"testing"
 	"time"
 
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -273,6 +274,20 @@ func TestRemoveExtrinsic(t *testing.T) {
 	}
 }
 
+func TestPriorityQueue_Get(t *testing.T) {
+	vt := &ValidTransaction{
+		Extrinsic: []byte("rats"),
+		Validity:  &Validity{Priority: 5},
+	}
+
+	pq := NewPriorityQueue()
+	_, err := pq.Push(vt)
+	assert.NoError(t, err)
+
+	assert.Equal(t, vt, pq.Get(vt.Extrinsic.Hash()))
+	assert.Nil(t, pq.Get(common.Hash{1}))
+}
+
 func Test_PriorityQueue_PopWithTimer(t *testing.T) {
 	t.Parallel()
 
@@ -345,3 +360,123 @@ func Test_PriorityQueue_PopWithTimer(t *testing.T) {
 		})
 	}
 }
+
+func TestPriorityQueue_RequiredTags(t *testing.T) {
+	t.Parallel()
+
+	nonce0 := &ValidTransaction{
+		Extrinsic: []byte("nonce0"),
+		Validity:  &Validity{Priority: 1, Provides: [][]byte{{0}}},
+	}
+	nonce1 := &ValidTransaction{
+		Extrinsic: []byte("nonce1"),
+		Validity:  &Validity{Priority: 2, Requires: [][]byte{{0}}, Provides: [][]byte{{1}}},
+	}
+	nonce2 := &ValidTransaction{
+		Extrinsic: []byte("nonce2"),
+		Validity:  &Validity{Priority: 3, Requires: [][]byte{{1}}, Provides: [][]byte{{2}}},
+	}
+	other := &ValidTransaction{
+		Extrinsic: []byte("other"),
+		Validity:  &Validity{Priority: 2, Provides: [][]byte{{3}}},
+	}
+	twoTags := &ValidTransaction{
+		Extrinsic: []byte("twoTags"),
+		Validity:  &Validity{Priority: 1, Provides: [][]byte{{4}, {5}, {4}}},
+	}
+	requiresTwoTags := &ValidTransaction{
+		Extrinsic: []byte("requiresTwoTags"),
+		Validity:  &Validity{Priority: 2, Requires: [][]byte{{4}, {5}, {5}}},
+	}
+
+	testCases := map[string]struct {
+		pushed   []*ValidTransaction
+		removed  *ValidTransaction
+		expected []*ValidTransaction
+	}{
+		"dependents_pushed_first": {
+			pushed:   []*ValidTransaction{nonce2, nonce1, other, nonce0},
+			expected: []*ValidTransaction{other, nonce0, nonce1, nonce2},
+		},
+		"providers_pushed_first": {
+			pushed:   []*ValidTransaction{nonce0, nonce1, nonce2, other},
+			expected: []*ValidTransaction{other, nonce0, nonce1, nonce2},
+		},
+		"required_tag_not_in_queue": {
+			pushed:   []*ValidTransaction{nonce2, nonce1},
+			expected: []*ValidTransaction{nonce1, nonce2},
+		},
+		"provider_removed": {
+			pushed:   []*ValidTransaction{nonce0, nonce1, nonce2},
+			removed:  nonce0,
+			expected: []*ValidTransaction{nonce1, nonce2},
+		},
+		"provider_of_several_required_tags_pushed_first": {
+			pushed:   []*ValidTransaction{twoTags, requiresTwoTags},
+			expected: []*ValidTransaction{twoTags, requiresTwoTags},
+		},
+		"dependent_of_several_provided_tags_pushed_first": {
+			pushed:   []*ValidTransaction{requiresTwoTags, twoTags},
+			expected: []*ValidTransaction{twoTags, requiresTwoTags},
+		},
+		"provider_of_several_required_tags_removed": {
+			pushed:   []*ValidTransaction{twoTags, requiresTwoTags},
+			removed:  twoTags,
+			expected: []*ValidTransaction{requiresTwoTags},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pq := NewPriorityQueue()
+			for _, txn := range testCase.pushed {
+				_, err := pq.Push(txn)
+				assert.NoError(t, err)
+			}
+
+			if testCase.removed != nil {
+				pq.RemoveExtrinsic(testCase.removed.Extrinsic)
+			}
+
+			assert.Equal(t, len(testCase.expected), pq.Len())
+			assert.ElementsMatch(t, testCase.expected, pq.Pending())
+
+			var popped []*ValidTransaction
+			for txn := pq.Pop(); txn != nil; txn = pq.Pop() {
+				popped = append(popped, txn)
+			}
+			assert.Equal(t, testCase.expected, popped)
+			assert.Zero(t, pq.Len())
+		})
+	}
+}
+
+func TestPriorityQueue_RemoveExpired(t *testing.T) {
+	t.Parallel()
+
+	shortLived := &ValidTransaction{
+		Extrinsic: []byte("short"),
+		Validity:  &Validity{Priority: 1, Provides: [][]byte{{0}}, Longevity: 2},
+	}
+	longLived := &ValidTransaction{
+		Extrinsic: []byte("long"),
+		Validity:  &Validity{Priority: 1, Requires: [][]byte{{0}}, Longevity: 64},
+	}
+
+	pq := NewPriorityQueue()
+	pq.RemoveExpired(10)
+	_, err := pq.Push(shortLived)
+	assert.NoError(t, err)
+	_, err = pq.Push(longLived)
+	assert.NoError(t, err)
+
+	assert.Empty(t, pq.RemoveExpired(12))
+	assert.Equal(t, []*ValidTransaction{shortLived}, pq.RemoveExpired(13))
+
+	// the tag required by the remaining transaction is no longer provided by the queue
+	assert.False(t, pq.IsProvided([]byte{0}))
+	assert.Equal(t, longLived, pq.Pop())
+}
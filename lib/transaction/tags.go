@@ -0,0 +1,133 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package transaction
+
+import (
+	"math"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// tagIndex indexes transactions by the tags they provide and require.
+type tagIndex struct {
+	// provided maps a tag to the hash of the transaction providing it.
+	provided map[string]common.Hash
+	// requiredBy maps a tag to the hashes of the transactions requiring it.
+	requiredBy map[string]map[common.Hash]struct{}
+}
+
+func newTagIndex() *tagIndex {
+	return &tagIndex{
+		provided:   make(map[string]common.Hash),
+		requiredBy: make(map[string]map[common.Hash]struct{}),
+	}
+}
+
+// add indexes the tags of the transaction with the given hash. A tag already
+// provided by another transaction keeps its provider.
+func (ti *tagIndex) add(hash common.Hash, validity *Validity) {
+	if validity == nil {
+		return
+	}
+
+	for _, tag := range validity.Provides {
+		if _, ok := ti.provided[string(tag)]; !ok {
+			ti.provided[string(tag)] = hash
+		}
+	}
+
+	for _, tag := range validity.Requires {
+		hashes, ok := ti.requiredBy[string(tag)]
+		if !ok {
+			hashes = make(map[common.Hash]struct{})
+			ti.requiredBy[string(tag)] = hashes
+		}
+		hashes[hash] = struct{}{}
+	}
+}
+
+// remove removes the tags of the transaction with the given hash from the index,
+// and returns the tags which are no longer provided.
+func (ti *tagIndex) remove(hash common.Hash, validity *Validity) (released [][]byte) {
+	if validity == nil {
+		return nil
+	}
+
+	for _, tag := range validity.Provides {
+		if provider, ok := ti.provided[string(tag)]; ok && provider == hash {
+			delete(ti.provided, string(tag))
+			released = append(released, tag)
+		}
+	}
+
+	for _, tag := range validity.Requires {
+		hashes := ti.requiredBy[string(tag)]
+		delete(hashes, hash)
+		if len(hashes) == 0 {
+			delete(ti.requiredBy, string(tag))
+		}
+	}
+
+	return released
+}
+
+// isProvided returns true if the tag is provided by an indexed transaction.
+func (ti *tagIndex) isProvided(tag []byte) bool {
+	_, ok := ti.provided[string(tag)]
+	return ok
+}
+
+// providers returns the hashes of the transactions providing any of the given tags.
+func (ti *tagIndex) providers(tags [][]byte) (hashes []common.Hash) {
+	seen := make(map[common.Hash]struct{})
+	for _, tag := range tags {
+		hash, ok := ti.provided[string(tag)]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// requiring returns the hashes of the transactions requiring any of the given tags.
+func (ti *tagIndex) requiring(tags [][]byte) (hashes []common.Hash) {
+	seen := make(map[common.Hash]struct{})
+	for _, tag := range tags {
+		for hash := range ti.requiredBy[string(tag)] {
+			if _, ok := seen[hash]; ok {
+				continue
+			}
+			seen[hash] = struct{}{}
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes
+}
+
+// distinctTags returns the given tags without duplicates, in their order.
+func distinctTags(tags [][]byte) (distinct [][]byte) {
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if _, ok := seen[string(tag)]; ok {
+			continue
+		}
+		seen[string(tag)] = struct{}{}
+		distinct = append(distinct, tag)
+	}
+	return distinct
+}
+
+// validUntil returns the last block number at which a transaction validated at
+// the given block number with the given longevity is still valid.
+func validUntil(blockNumber uint, longevity uint64) uint {
+	if longevity > uint64(math.MaxUint-blockNumber) {
+		return math.MaxUint
+	}
+	return blockNumber + uint(longevity)
+}
@@ -0,0 +1,352 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+var (
+	ErrTrailingBytes        = errors.New("trailing bytes after decoded value")
+	ErrUnknownVariant       = errors.New("unknown variant index")
+	ErrUnknownTypeDef       = errors.New("unknown type definition")
+	ErrUnsupportedPrimitive = errors.New("unsupported primitive type")
+	ErrUnsupportedBitOrder  = errors.New("unsupported bit order type")
+	ErrSequenceTooLong      = errors.New("sequence length exceeds the remaining data")
+)
+
+// CompositeValue is a dynamically decoded struct or tuple struct, with its fields in order
+type CompositeValue []NamedValue
+
+// Field returns the value of the field with the given name, and false if
+// the composite has no such field.
+func (c CompositeValue) Field(name string) (value any, ok bool) {
+	for _, field := range c {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// NamedValue is a dynamically decoded field value, with an empty name for unnamed fields
+type NamedValue struct {
+	Name  string
+	Value any
+}
+
+// VariantValue is a dynamically decoded enum value
+type VariantValue struct {
+	Name   string
+	Index  uint8
+	Fields CompositeValue
+}
+
+// Decode dynamically decodes the SCALE encoded value of the type with the given
+// id in the type registry. The decoded value is
+//   - a CompositeValue for composite types,
+//   - a VariantValue for enum types,
+//   - a []byte for sequences and arrays of u8, and a []any for other sequences,
+//     arrays and tuples,
+//   - a bool, rune, string, uintN or intN for the primitive types, or a *big.Int
+//     for the 128 and 256 bits integers,
+//   - a *big.Int for compact encoded values,
+//   - a []bool for bit sequences.
+func (m *Metadata) Decode(typeID uint, encoded []byte) (any, error) {
+	reader := bytes.NewReader(encoded)
+	value, err := m.decode(typeID, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if reader.Len() > 0 {
+		return nil, fmt.Errorf("%w: %d bytes left decoding type %d", ErrTrailingBytes, reader.Len(), typeID)
+	}
+
+	return value, nil
+}
+
+func (m *Metadata) decode(typeID uint, reader *bytes.Reader) (any, error) {
+	t, err := m.Type(typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := t.Def.Value()
+	if err != nil {
+		return nil, fmt.Errorf("getting definition of type %d: %w", typeID, err)
+	}
+
+	switch def := def.(type) {
+	case TypeDefComposite:
+		return m.decodeFields(def.Fields, reader)
+	case TypeDefVariant:
+		return m.decodeVariant(typeID, def, reader)
+	case TypeDefSequence:
+		var length uint
+		err = scale.NewDecoder(reader).Decode(&length)
+		if err != nil {
+			return nil, fmt.Errorf("decoding sequence length of type %d: %w", typeID, err)
+		}
+		return m.decodeSequence(def.Type, length, reader)
+	case TypeDefArray:
+		return m.decodeSequence(def.Type, uint(def.Len), reader)
+	case TypeDefTuple:
+		values := make([]any, len(def.Fields))
+		for i, fieldType := range def.Fields {
+			values[i], err = m.decode(fieldType, reader)
+			if err != nil {
+				return nil, fmt.Errorf("decoding tuple field %d of type %d: %w", i, typeID, err)
+			}
+		}
+		return values, nil
+	case TypeDefPrimitive:
+		return decodePrimitive(def, reader)
+	case TypeDefCompact:
+		var value *big.Int
+		err = scale.NewDecoder(reader).Decode(&value)
+		if err != nil {
+			return nil, fmt.Errorf("decoding compact of type %d: %w", typeID, err)
+		}
+		return value, nil
+	case TypeDefBitSequence:
+		return m.decodeBitSequence(def, reader)
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownTypeDef, def)
+	}
+}
+
+func (m *Metadata) decodeFields(fields []Field, reader *bytes.Reader) (CompositeValue, error) {
+	composite := make(CompositeValue, len(fields))
+	for i, field := range fields {
+		if field.Name != nil {
+			composite[i].Name = *field.Name
+		}
+
+		value, err := m.decode(field.Type, reader)
+		if err != nil {
+			return nil, fmt.Errorf("decoding field %d: %w", i, err)
+		}
+		composite[i].Value = value
+	}
+
+	return composite, nil
+}
+
+func (m *Metadata) decodeVariant(typeID uint, def TypeDefVariant, reader *bytes.Reader) (VariantValue, error) {
+	index, err := reader.ReadByte()
+	if err != nil {
+		return VariantValue{}, fmt.Errorf("decoding variant index of type %d: %w", typeID, err)
+	}
+
+	for _, variant := range def.Variants {
+		if variant.Index != index {
+			continue
+		}
+
+		fields, err := m.decodeFields(variant.Fields, reader)
+		if err != nil {
+			return VariantValue{}, fmt.Errorf("decoding variant %s of type %d: %w", variant.Name, typeID, err)
+		}
+
+		return VariantValue{
+			Name:   variant.Name,
+			Index:  index,
+			Fields: fields,
+		}, nil
+	}
+
+	return VariantValue{}, fmt.Errorf("%w: %d for type %d", ErrUnknownVariant, index, typeID)
+}
+
+func (m *Metadata) decodeSequence(elementType uint, length uint, reader *bytes.Reader) (any, error) {
+	t, err := m.Type(elementType)
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := t.Def.Value()
+	if err != nil {
+		return nil, fmt.Errorf("getting definition of type %d: %w", elementType, err)
+	}
+
+	if def == PrimitiveU8 {
+		if length > uint(reader.Len()) {
+			return nil, fmt.Errorf("%w: %d bytes for %d bytes left", ErrSequenceTooLong, length, reader.Len())
+		}
+
+		value := make([]byte, length)
+		_, err = io.ReadFull(reader, value)
+		if err != nil {
+			return nil, fmt.Errorf("reading bytes: %w", err)
+		}
+		return value, nil
+	}
+
+	// elements may be zero sized, so the length is only used to bound the capacity
+	values := make([]any, 0, min(length, uint(reader.Len())))
+	for i := uint(0); i < length; i++ {
+		value, err := m.decode(elementType, reader)
+		if err != nil {
+			return nil, fmt.Errorf("decoding element %d: %w", i, err)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func decodePrimitive(primitive TypeDefPrimitive, reader *bytes.Reader) (value any, err error) {
+	decoder := scale.NewDecoder(reader)
+	switch primitive {
+	case PrimitiveBool:
+		value, err = decodeInto[bool](decoder)
+	case PrimitiveChar:
+		var char uint32
+		char, err = decodeInto[uint32](decoder)
+		value = rune(char)
+	case PrimitiveStr:
+		value, err = decodeInto[string](decoder)
+	case PrimitiveU8:
+		value, err = decodeInto[uint8](decoder)
+	case PrimitiveU16:
+		value, err = decodeInto[uint16](decoder)
+	case PrimitiveU32:
+		value, err = decodeInto[uint32](decoder)
+	case PrimitiveU64:
+		value, err = decodeInto[uint64](decoder)
+	case PrimitiveU128:
+		value, err = decodeBigInt(reader, 16, false)
+	case PrimitiveU256:
+		value, err = decodeBigInt(reader, 32, false)
+	case PrimitiveI8:
+		value, err = decodeInto[int8](decoder)
+	case PrimitiveI16:
+		value, err = decodeInto[int16](decoder)
+	case PrimitiveI32:
+		value, err = decodeInto[int32](decoder)
+	case PrimitiveI64:
+		value, err = decodeInto[int64](decoder)
+	case PrimitiveI128:
+		value, err = decodeBigInt(reader, 16, true)
+	case PrimitiveI256:
+		value, err = decodeBigInt(reader, 32, true)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedPrimitive, primitive)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decoding primitive %d: %w", primitive, err)
+	}
+	return value, nil
+}
+
+func decodeInto[T any](decoder *scale.Decoder) (value T, err error) {
+	err = decoder.Decode(&value)
+	return value, err
+}
+
+// decodeBigInt decodes a little endian integer of the given size in bytes,
+// in two's complement if signed.
+func decodeBigInt(reader io.Reader, size int, signed bool) (*big.Int, error) {
+	littleEndian := make([]byte, size)
+	_, err := io.ReadFull(reader, littleEndian)
+	if err != nil {
+		return nil, err
+	}
+
+	bigEndian := make([]byte, size)
+	for i, b := range littleEndian {
+		bigEndian[size-1-i] = b
+	}
+
+	// leading zeros are trimmed so a decoded zero equals big.NewInt(0)
+	value := new(big.Int).SetBytes(bytes.TrimLeft(bigEndian, "\x00"))
+	if signed && bigEndian[0]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(size*8)))
+	}
+
+	return value, nil
+}
+
+func (m *Metadata) decodeBitSequence(def TypeDefBitSequence, reader *bytes.Reader) ([]bool, error) {
+	storeType, err := m.Type(def.StoreType)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := storeType.Def.Value()
+	if err != nil {
+		return nil, fmt.Errorf("getting bit store type definition: %w", err)
+	}
+
+	var storeSize uint
+	switch store {
+	case PrimitiveU8:
+		storeSize = 1
+	case PrimitiveU16:
+		storeSize = 2
+	case PrimitiveU32:
+		storeSize = 4
+	case PrimitiveU64:
+		storeSize = 8
+	default:
+		return nil, fmt.Errorf("%w: bit store type %v", ErrUnsupportedPrimitive, store)
+	}
+
+	orderType, err := m.Type(def.OrderType)
+	if err != nil {
+		return nil, err
+	}
+
+	var msb0 bool
+	switch {
+	case len(orderType.Path) > 0 && orderType.Path[len(orderType.Path)-1] == "Lsb0":
+	case len(orderType.Path) > 0 && orderType.Path[len(orderType.Path)-1] == "Msb0":
+		msb0 = true
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBitOrder, strings.Join(orderType.Path, "::"))
+	}
+
+	var length uint
+	err = scale.NewDecoder(reader).Decode(&length)
+	if err != nil {
+		return nil, fmt.Errorf("decoding bit sequence length: %w", err)
+	}
+
+	storeBits := storeSize * 8
+	storeCount := (length + storeBits - 1) / storeBits
+	if storeCount*storeSize > uint(reader.Len()) {
+		return nil, fmt.Errorf("%w: %d bits for %d bytes left", ErrSequenceTooLong, length, reader.Len())
+	}
+
+	bits := make([]bool, 0, length)
+	store64 := make([]byte, 8)
+	for i := uint(0); i < storeCount; i++ {
+		clear(store64)
+		_, err = io.ReadFull(reader, store64[:storeSize])
+		if err != nil {
+			return nil, fmt.Errorf("reading bit store: %w", err)
+		}
+		element := binary.LittleEndian.Uint64(store64)
+
+		for bit := uint(0); bit < storeBits && uint(len(bits)) < length; bit++ {
+			shift := bit
+			if msb0 {
+				shift = storeBits - 1 - bit
+			}
+			bits = append(bits, element>>shift&1 == 1)
+		}
+	}
+
+	return bits, nil
+}
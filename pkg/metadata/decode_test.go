@@ -0,0 +1,285 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metadata_Decode(t *testing.T) {
+	t.Parallel()
+
+	primitives := []TypeDefPrimitive{
+		PrimitiveBool, PrimitiveChar, PrimitiveStr, PrimitiveU8, PrimitiveU16,
+		PrimitiveU32, PrimitiveU64, PrimitiveU128, PrimitiveU256, PrimitiveI8,
+		PrimitiveI16, PrimitiveI32, PrimitiveI64, PrimitiveI128, PrimitiveI256,
+	}
+	// types 0 to 14 are the primitive types, in the order of their constants
+	var types PortableRegistry
+	for _, primitive := range primitives {
+		types = append(types, PortableType{
+			ID:   uint(primitive),
+			Type: Type{Def: mustNewTypeDef(t, primitive)},
+		})
+	}
+	types = append(types,
+		PortableType{ID: 15, Type: Type{Def: mustNewTypeDef(t, TypeDefSequence{Type: uint(PrimitiveU8)})}},
+		PortableType{ID: 16, Type: Type{Def: mustNewTypeDef(t, TypeDefSequence{Type: uint(PrimitiveU16)})}},
+		PortableType{ID: 17, Type: Type{Def: mustNewTypeDef(t, TypeDefArray{Len: 2, Type: uint(PrimitiveU8)})}},
+		PortableType{ID: 18, Type: Type{Def: mustNewTypeDef(t, TypeDefTuple{
+			Fields: []uint{uint(PrimitiveBool), uint(PrimitiveU16)},
+		})}},
+		PortableType{ID: 19, Type: Type{Def: mustNewTypeDef(t, TypeDefCompact{Type: uint(PrimitiveU64)})}},
+		PortableType{ID: 20, Type: Type{Def: mustNewTypeDef(t, TypeDefComposite{Fields: []Field{
+			{Name: ptrTo("flag"), Type: uint(PrimitiveBool)},
+			{Type: uint(PrimitiveU8)},
+		}})}},
+		PortableType{ID: 21, Type: Type{Def: mustNewTypeDef(t, TypeDefVariant{Variants: []Variant{
+			{Name: "None", Index: 0},
+			{Name: "Some", Index: 1, Fields: []Field{{Type: uint(PrimitiveU8)}}},
+		}})}},
+		PortableType{ID: 22, Type: Type{
+			Path: []string{"bitvec", "order", "Lsb0"},
+			Def:  mustNewTypeDef(t, TypeDefComposite{}),
+		}},
+		PortableType{ID: 23, Type: Type{
+			Path: []string{"bitvec", "order", "Msb0"},
+			Def:  mustNewTypeDef(t, TypeDefComposite{}),
+		}},
+		PortableType{ID: 24, Type: Type{Def: mustNewTypeDef(t, TypeDefBitSequence{
+			StoreType: uint(PrimitiveU8),
+			OrderType: 22,
+		})}},
+		PortableType{ID: 25, Type: Type{Def: mustNewTypeDef(t, TypeDefBitSequence{
+			StoreType: uint(PrimitiveU16),
+			OrderType: 23,
+		})}},
+		PortableType{ID: 26, Type: Type{Def: mustNewTypeDef(t, TypeDefBitSequence{
+			StoreType: uint(PrimitiveU8),
+			OrderType: 0,
+		})}},
+	)
+	metadata := &Metadata{Types: types}
+	metadata.indexTypes()
+
+	testCases := map[string]struct {
+		typeID     uint
+		encoded    []byte
+		value      any
+		errWrapped error
+		errMessage string
+	}{
+		"bool": {
+			typeID:  uint(PrimitiveBool),
+			encoded: []byte{1},
+			value:   true,
+		},
+		"char": {
+			typeID:  uint(PrimitiveChar),
+			encoded: []byte{'a', 0, 0, 0},
+			value:   'a',
+		},
+		"str": {
+			typeID:  uint(PrimitiveStr),
+			encoded: []byte{3 << 2, 'a', 'b', 'c'},
+			value:   "abc",
+		},
+		"u8": {
+			typeID:  uint(PrimitiveU8),
+			encoded: []byte{1},
+			value:   uint8(1),
+		},
+		"u16": {
+			typeID:  uint(PrimitiveU16),
+			encoded: []byte{1, 2},
+			value:   uint16(0x201),
+		},
+		"u32": {
+			typeID:  uint(PrimitiveU32),
+			encoded: []byte{1, 2, 3, 4},
+			value:   uint32(0x4030201),
+		},
+		"u64": {
+			typeID:  uint(PrimitiveU64),
+			encoded: []byte{1, 0, 0, 0, 0, 0, 0, 1},
+			value:   uint64(0x100000000000001),
+		},
+		"u128": {
+			typeID:  uint(PrimitiveU128),
+			encoded: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			value:   big.NewInt(1),
+		},
+		"u256": {
+			typeID:  uint(PrimitiveU256),
+			encoded: append([]byte{2}, make([]byte, 31)...),
+			value:   big.NewInt(2),
+		},
+		"i8": {
+			typeID:  uint(PrimitiveI8),
+			encoded: []byte{0xff},
+			value:   int8(-1),
+		},
+		"i16": {
+			typeID:  uint(PrimitiveI16),
+			encoded: []byte{0xfe, 0xff},
+			value:   int16(-2),
+		},
+		"i32": {
+			typeID:  uint(PrimitiveI32),
+			encoded: []byte{0xfd, 0xff, 0xff, 0xff},
+			value:   int32(-3),
+		},
+		"i64": {
+			typeID:  uint(PrimitiveI64),
+			encoded: []byte{4, 0, 0, 0, 0, 0, 0, 0},
+			value:   int64(4),
+		},
+		"i128_negative": {
+			typeID: uint(PrimitiveI128),
+			encoded: []byte{0xfb, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			value: big.NewInt(-5),
+		},
+		"i128_zero": {
+			typeID:  uint(PrimitiveI128),
+			encoded: make([]byte, 16),
+			value:   big.NewInt(0),
+		},
+		"i256_positive": {
+			typeID:  uint(PrimitiveI256),
+			encoded: append([]byte{6}, make([]byte, 31)...),
+			value:   big.NewInt(6),
+		},
+		"bytes_sequence": {
+			typeID:  15,
+			encoded: []byte{2 << 2, 1, 2},
+			value:   []byte{1, 2},
+		},
+		"sequence": {
+			typeID:  16,
+			encoded: []byte{2 << 2, 1, 0, 2, 0},
+			value:   []any{uint16(1), uint16(2)},
+		},
+		"empty_sequence": {
+			typeID:  16,
+			encoded: []byte{0},
+			value:   []any{},
+		},
+		"bytes_array": {
+			typeID:  17,
+			encoded: []byte{1, 2},
+			value:   []byte{1, 2},
+		},
+		"tuple": {
+			typeID:  18,
+			encoded: []byte{1, 2, 0},
+			value:   []any{true, uint16(2)},
+		},
+		"compact": {
+			typeID:  19,
+			encoded: []byte{0x91, 0x01},
+			value:   big.NewInt(100),
+		},
+		"composite": {
+			typeID:  20,
+			encoded: []byte{1, 2},
+			value: CompositeValue{
+				{Name: "flag", Value: true},
+				{Value: uint8(2)},
+			},
+		},
+		"variant_without_fields": {
+			typeID:  21,
+			encoded: []byte{0},
+			value:   VariantValue{Name: "None", Fields: CompositeValue{}},
+		},
+		"variant_with_fields": {
+			typeID:  21,
+			encoded: []byte{1, 7},
+			value: VariantValue{
+				Name:   "Some",
+				Index:  1,
+				Fields: CompositeValue{{Value: uint8(7)}},
+			},
+		},
+		"bit_sequence_lsb0": {
+			typeID:  24,
+			encoded: []byte{10 << 2, 0b0000_0101, 0b0000_0010},
+			value:   []bool{true, false, true, false, false, false, false, false, false, true},
+		},
+		"bit_sequence_msb0": {
+			typeID:  25,
+			encoded: []byte{3 << 2, 0x00, 0b1010_0000},
+			value:   []bool{true, false, true},
+		},
+		"type_not_found": {
+			typeID:     27,
+			errWrapped: ErrTypeNotFound,
+			errMessage: "type not found: 27",
+		},
+		"trailing_bytes": {
+			typeID:     uint(PrimitiveU8),
+			encoded:    []byte{1, 2},
+			errWrapped: ErrTrailingBytes,
+			errMessage: "trailing bytes after decoded value: 1 bytes left decoding type 3",
+		},
+		"unknown_variant": {
+			typeID:     21,
+			encoded:    []byte{2},
+			errWrapped: ErrUnknownVariant,
+			errMessage: "unknown variant index: 2 for type 21",
+		},
+		"sequence_too_long": {
+			typeID:     15,
+			encoded:    []byte{3 << 2, 1, 2},
+			errWrapped: ErrSequenceTooLong,
+			errMessage: "sequence length exceeds the remaining data: 3 bytes for 2 bytes left",
+		},
+		"bit_sequence_too_long": {
+			typeID:     24,
+			encoded:    []byte{9 << 2, 1},
+			errWrapped: ErrSequenceTooLong,
+			errMessage: "sequence length exceeds the remaining data: 9 bits for 1 bytes left",
+		},
+		"unsupported_bit_order": {
+			typeID:     26,
+			encoded:    []byte{0},
+			errWrapped: ErrUnsupportedBitOrder,
+			errMessage: "unsupported bit order type: ",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := metadata.Decode(testCase.typeID, testCase.encoded)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.value, value)
+		})
+	}
+}
+
+func Test_CompositeValue_Field(t *testing.T) {
+	t.Parallel()
+
+	composite := CompositeValue{{Name: "a", Value: uint8(1)}}
+
+	value, ok := composite.Field("a")
+	require.True(t, ok)
+	assert.Equal(t, uint8(1), value)
+
+	value, ok = composite.Field("b")
+	assert.False(t, ok)
+	assert.Nil(t, value)
+}
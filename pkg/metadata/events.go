@@ -0,0 +1,100 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+var ErrInvalidEventRecord = errors.New("invalid event record")
+
+// EventRecord is an event of the System.Events storage entry, with the phase
+// of the block during which it was emitted.
+type EventRecord struct {
+	Phase  VariantValue
+	Pallet string
+	Name   string
+	Fields CompositeValue
+	Topics []common.Hash
+}
+
+// DecodeEvents decodes the SCALE encoded value of the System.Events storage entry
+func (m *Metadata) DecodeEvents(encoded []byte) ([]EventRecord, error) {
+	value, err := m.DecodeStorageValue("System", "Events", encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding events: %w", err)
+	}
+
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: events are %T", ErrInvalidEventRecord, value)
+	}
+
+	records := make([]EventRecord, len(values))
+	for i, value := range values {
+		records[i], err = newEventRecord(value)
+		if err != nil {
+			return nil, fmt.Errorf("event record %d: %w", i, err)
+		}
+	}
+
+	return records, nil
+}
+
+// newEventRecord returns the event record of a dynamically decoded
+// frame_system::EventRecord value.
+func newEventRecord(value any) (record EventRecord, err error) {
+	composite, ok := value.(CompositeValue)
+	if !ok {
+		return record, fmt.Errorf("%w: record is %T", ErrInvalidEventRecord, value)
+	}
+
+	phase, _ := composite.Field("phase")
+	record.Phase, ok = phase.(VariantValue)
+	if !ok {
+		return record, fmt.Errorf("%w: phase is %T", ErrInvalidEventRecord, phase)
+	}
+
+	// the runtime event is an enum of the pallets events
+	event, _ := composite.Field("event")
+	palletEvent, ok := event.(VariantValue)
+	if !ok || len(palletEvent.Fields) != 1 {
+		return record, fmt.Errorf("%w: event is %v", ErrInvalidEventRecord, event)
+	}
+
+	innerEvent, ok := palletEvent.Fields[0].Value.(VariantValue)
+	if !ok {
+		return record, fmt.Errorf("%w: pallet event is %T", ErrInvalidEventRecord, palletEvent.Fields[0].Value)
+	}
+
+	record.Pallet = palletEvent.Name
+	record.Name = innerEvent.Name
+	record.Fields = innerEvent.Fields
+
+	topics, _ := composite.Field("topics")
+	topicValues, ok := topics.([]any)
+	if !ok {
+		return record, fmt.Errorf("%w: topics are %T", ErrInvalidEventRecord, topics)
+	}
+
+	record.Topics = make([]common.Hash, len(topicValues))
+	for i, topic := range topicValues {
+		// topics are H256 tuple structs of a single byte array
+		hash, ok := topic.(CompositeValue)
+		if !ok || len(hash) != 1 {
+			return record, fmt.Errorf("%w: topic is %v", ErrInvalidEventRecord, topic)
+		}
+
+		hashBytes, ok := hash[0].Value.([]byte)
+		if !ok || len(hashBytes) != common.HashLength {
+			return record, fmt.Errorf("%w: topic is %v", ErrInvalidEventRecord, topic)
+		}
+		record.Topics[i] = common.NewHash(hashBytes)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metadata_DecodeEvents(t *testing.T) {
+	t.Parallel()
+
+	westend, err := Decode(westendMetadata(t))
+	require.NoError(t, err)
+
+	topic := common.Hash{1, 2, 3}
+
+	testCases := map[string]struct {
+		encoded    []byte
+		records    []EventRecord
+		errWrapped error
+		errMessage string
+	}{
+		"no_event": {
+			encoded: []byte{0},
+			records: []EventRecord{},
+		},
+		"extrinsic_success": {
+			encoded: append([]byte{
+				1 << 2,        // one event record
+				0, 1, 0, 0, 0, // phase ApplyExtrinsic(1)
+				0, 0, // event System ExtrinsicSuccess
+				0x91, 0x01, 0, // weight ref_time 100 and proof_size 0
+				0, 0, // class Normal and pays_fee Yes
+				1 << 2, // one topic
+			}, topic[:]...),
+			records: []EventRecord{{
+				Phase: VariantValue{
+					Name:   "ApplyExtrinsic",
+					Fields: CompositeValue{{Value: uint32(1)}},
+				},
+				Pallet: "System",
+				Name:   "ExtrinsicSuccess",
+				Fields: CompositeValue{{
+					Name: "dispatch_info",
+					Value: CompositeValue{
+						{Name: "weight", Value: CompositeValue{
+							{Name: "ref_time", Value: big.NewInt(100)},
+							{Name: "proof_size", Value: big.NewInt(0)},
+						}},
+						{Name: "class", Value: VariantValue{Name: "Normal", Fields: CompositeValue{}}},
+						{Name: "pays_fee", Value: VariantValue{Name: "Yes", Fields: CompositeValue{}}},
+					},
+				}},
+				Topics: []common.Hash{topic},
+			}},
+		},
+		"trailing_bytes": {
+			encoded:    []byte{0, 0},
+			errWrapped: ErrTrailingBytes,
+			errMessage: "decoding events: trailing bytes after decoded value: 1 bytes left decoding type 17",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			records, err := westend.DecodeEvents(testCase.encoded)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.records, records)
+		})
+	}
+}
+
+func Test_newEventRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		value      any
+		errMessage string
+	}{
+		"not_a_composite": {
+			value:      uint8(1),
+			errMessage: "invalid event record: record is uint8",
+		},
+		"missing_phase": {
+			value:      CompositeValue{},
+			errMessage: "invalid event record: phase is <nil>",
+		},
+		"event_without_pallet_event": {
+			value: CompositeValue{
+				{Name: "phase", Value: VariantValue{Name: "Initialization"}},
+				{Name: "event", Value: VariantValue{Name: "System"}},
+			},
+			errMessage: "invalid event record: event is {System 0 []}",
+		},
+		"invalid_topic": {
+			value: CompositeValue{
+				{Name: "phase", Value: VariantValue{Name: "Initialization"}},
+				{Name: "event", Value: VariantValue{Name: "System", Fields: CompositeValue{
+					{Value: VariantValue{Name: "CodeUpdated"}},
+				}}},
+				{Name: "topics", Value: []any{CompositeValue{{Value: []byte{1}}}}},
+			},
+			errMessage: "invalid event record: topic is [{ [1]}]",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newEventRecord(testCase.value)
+
+			assert.ErrorIs(t, err, ErrInvalidEventRecord)
+			assert.EqualError(t, err, testCase.errMessage)
+		})
+	}
+}
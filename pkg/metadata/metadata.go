@@ -0,0 +1,153 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package metadata decodes the V14 and V15 runtime metadata, builds storage keys
+// from pallet and storage entry names, and dynamically decodes SCALE encoded
+// storage values and events using the portable type registry of the metadata.
+package metadata
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+var (
+	ErrInvalidMagicNumber   = errors.New("invalid metadata magic number")
+	ErrUnsupportedVersion   = errors.New("unsupported metadata version")
+	ErrPalletNotFound       = errors.New("pallet not found")
+	ErrStorageEntryNotFound = errors.New("storage entry not found")
+	ErrTypeNotFound         = errors.New("type not found")
+)
+
+// Metadata is the decoded runtime metadata, with its pallets in the V15 format
+// whatever the version of the metadata.
+type Metadata struct {
+	Version uint8
+	Types   PortableRegistry
+	Pallets []PalletMetadata
+	// V14 is set if the metadata version is 14
+	V14 *MetadataV14
+	// V15 is set if the metadata version is 15
+	V15 *MetadataV15
+
+	typesByID map[uint]*Type
+}
+
+// Decode decodes the runtime metadata prefixed by its magic number, as returned
+// by the state_getMetadata RPC method.
+func Decode(encoded []byte) (*Metadata, error) {
+	const versionIndex = 4
+	if len(encoded) <= versionIndex {
+		return nil, fmt.Errorf("%w: metadata is only %d bytes", ErrInvalidMagicNumber, len(encoded))
+	}
+
+	magic := binary.LittleEndian.Uint32(encoded[:versionIndex])
+	if magic != MagicNumber {
+		return nil, fmt.Errorf("%w: 0x%08x", ErrInvalidMagicNumber, magic)
+	}
+
+	version := encoded[versionIndex]
+	if version != 14 && version != 15 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	var prefixed RuntimeMetadataPrefixed
+	err := scale.Unmarshal(encoded, &prefixed)
+	if err != nil {
+		return nil, fmt.Errorf("decoding metadata V%d: %w", version, err)
+	}
+
+	value, err := prefixed.Metadata.Value()
+	if err != nil {
+		return nil, fmt.Errorf("getting metadata value: %w", err)
+	}
+
+	metadata := &Metadata{Version: version}
+	switch value := value.(type) {
+	case MetadataV14:
+		metadata.V14 = &value
+		metadata.Types = value.Types
+		metadata.Pallets = make([]PalletMetadata, len(value.Pallets))
+		for i, pallet := range value.Pallets {
+			metadata.Pallets[i] = PalletMetadata{
+				Name:      pallet.Name,
+				Storage:   pallet.Storage,
+				Calls:     pallet.Calls,
+				Event:     pallet.Event,
+				Constants: pallet.Constants,
+				Error:     pallet.Error,
+				Index:     pallet.Index,
+			}
+		}
+	case MetadataV15:
+		metadata.V15 = &value
+		metadata.Types = value.Types
+		metadata.Pallets = value.Pallets
+	}
+
+	metadata.indexTypes()
+	return metadata, nil
+}
+
+// indexTypes indexes the types of the registry by type id
+func (m *Metadata) indexTypes() {
+	m.typesByID = make(map[uint]*Type, len(m.Types))
+	for i := range m.Types {
+		m.typesByID[m.Types[i].ID] = &m.Types[i].Type
+	}
+}
+
+// DecodeOpaque decodes the runtime metadata wrapped in SCALE encoded bytes,
+// as returned by the runtime API Metadata_metadata.
+func DecodeOpaque(opaque []byte) (*Metadata, error) {
+	var encoded []byte
+	err := scale.Unmarshal(opaque, &encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding opaque metadata: %w", err)
+	}
+
+	return Decode(encoded)
+}
+
+// Type returns the type with the given id from the type registry
+func (m *Metadata) Type(id uint) (*Type, error) {
+	t, ok := m.typesByID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrTypeNotFound, id)
+	}
+
+	return t, nil
+}
+
+// Pallet returns the metadata of the pallet with the given name
+func (m *Metadata) Pallet(name string) (*PalletMetadata, error) {
+	for i := range m.Pallets {
+		if m.Pallets[i].Name == name {
+			return &m.Pallets[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrPalletNotFound, name)
+}
+
+// StorageEntry returns the storage prefix of the pallet with the given name
+// and the metadata of its storage entry with the given name.
+func (m *Metadata) StorageEntry(pallet, entry string) (prefix string, metadata *StorageEntryMetadata, err error) {
+	palletMetadata, err := m.Pallet(pallet)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if palletMetadata.Storage != nil {
+		for i := range palletMetadata.Storage.Entries {
+			if palletMetadata.Storage.Entries[i].Name == entry {
+				return palletMetadata.Storage.Prefix, &palletMetadata.Storage.Entries[i], nil
+			}
+		}
+	}
+
+	return "", nil, fmt.Errorf("%w: %s in pallet %s", ErrStorageEntryNotFound, entry, pallet)
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"io"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// westendMetadataZip is the V14 metadata of the westend runtime 9320
+//
+//go:embed testdata/westend_v14.zip
+var westendMetadataZip []byte
+
+func westendMetadata(t *testing.T) (encoded []byte) {
+	t.Helper()
+
+	zipReader, err := zip.NewReader(bytes.NewReader(westendMetadataZip), int64(len(westendMetadataZip)))
+	require.NoError(t, err)
+	require.Len(t, zipReader.File, 1)
+
+	file, err := zipReader.File[0].Open()
+	require.NoError(t, err)
+	defer file.Close()
+
+	encoded, err = io.ReadAll(file)
+	require.NoError(t, err)
+	return encoded
+}
+
+func ptrTo[T any](value T) *T {
+	return &value
+}
+
+func mustNewTypeDef(t *testing.T, value any) TypeDef {
+	t.Helper()
+	typeDef, err := NewTypeDef(value)
+	require.NoError(t, err)
+	return typeDef
+}
+
+func mustNewStorageEntryType(t *testing.T, value any) StorageEntryType {
+	t.Helper()
+	entryType, err := NewStorageEntryType(value)
+	require.NoError(t, err)
+	return entryType
+}
+
+// newTestMetadataV15 returns a minimal V15 metadata with a Test pallet having
+// a Value storage value of type u32 and a Map storage map from u8 to u32.
+func newTestMetadataV15(t *testing.T) MetadataV15 {
+	t.Helper()
+
+	return MetadataV15{
+		Types: PortableRegistry{
+			{ID: 0, Type: Type{Def: mustNewTypeDef(t, PrimitiveU8)}},
+			{ID: 1, Type: Type{Def: mustNewTypeDef(t, PrimitiveU32)}},
+			{ID: 2, Type: Type{
+				Path: []string{"test", "Event"},
+				Def: mustNewTypeDef(t, TypeDefVariant{Variants: []Variant{{
+					Name:   "Happened",
+					Fields: []Field{{Name: ptrTo("value"), Type: 1, TypeName: ptrTo("u32")}},
+					Index:  0,
+					Docs:   []string{"Something happened"},
+				}}}),
+			}},
+		},
+		Pallets: []PalletMetadata{{
+			Name: "Test",
+			Storage: &PalletStorageMetadata{
+				Prefix: "Test",
+				Entries: []StorageEntryMetadata{{
+					Name:     "Value",
+					Modifier: StorageEntryModifierDefault,
+					Type:     mustNewStorageEntryType(t, StorageEntryTypePlain{Type: 1}),
+					Default:  []byte{7, 0, 0, 0},
+				}, {
+					Name:     "Map",
+					Modifier: StorageEntryModifierOptional,
+					Type: mustNewStorageEntryType(t, StorageEntryTypeMap{
+						Hashers: []StorageHasher{HasherTwox64Concat},
+						Key:     0,
+						Value:   1,
+					}),
+					Default: []byte{},
+				}},
+			},
+			Event:     &PalletEventMetadata{Type: 2},
+			Constants: []PalletConstantMetadata{{Name: "Answer", Type: 1, Value: []byte{42, 0, 0, 0}}},
+			Index:     3,
+			Docs:      []string{"Test pallet"},
+		}},
+		Extrinsic: ExtrinsicMetadataV15{
+			Version: 4,
+			SignedExtensions: []SignedExtensionMetadata{
+				{Identifier: "CheckNonce", Type: 1, AdditionalSigned: 0},
+			},
+		},
+		APIs: []RuntimeAPIMetadata{{
+			Name: "TestApi",
+			Methods: []RuntimeAPIMethodMetadata{{
+				Name:   "answer",
+				Inputs: []RuntimeAPIMethodParamMetadata{{Name: "input", Type: 0}},
+				Output: 1,
+			}},
+		}},
+		OuterEnums: OuterEnums{EventType: 2},
+		Custom: CustomMetadata{Map: map[string]CustomValueMetadata{
+			"answer": {Type: 1, Value: []byte{42, 0, 0, 0}},
+		}},
+	}
+}
+
+func encodeMetadata(t *testing.T, value any) []byte {
+	t.Helper()
+
+	runtimeMetadata, err := NewRuntimeMetadata(value)
+	require.NoError(t, err)
+
+	encoded, err := scale.Marshal(RuntimeMetadataPrefixed{
+		Magic:    MagicNumber,
+		Metadata: runtimeMetadata,
+	})
+	require.NoError(t, err)
+	return encoded
+}
+
+func Test_Decode(t *testing.T) {
+	t.Parallel()
+
+	westend, err := Decode(westendMetadata(t))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(14), westend.Version)
+	require.NotNil(t, westend.V14)
+	assert.Nil(t, westend.V15)
+	assert.Len(t, westend.Types, 685)
+	assert.Len(t, westend.Pallets, 48)
+
+	balances, err := westend.Pallet("Balances")
+	require.NoError(t, err)
+	assert.Equal(t, uint8(4), balances.Index)
+	assert.Equal(t, "Balances", balances.Storage.Prefix)
+
+	testMetadataV15 := newTestMetadataV15(t)
+	v15, err := Decode(encodeMetadata(t, testMetadataV15))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(15), v15.Version)
+	assert.Nil(t, v15.V14)
+	assert.Equal(t, &testMetadataV15, v15.V15)
+	assert.Equal(t, testMetadataV15.Pallets, v15.Pallets)
+
+	eventType, err := v15.Type(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test", "Event"}, eventType.Path)
+
+	_, err = v15.Type(3)
+	assert.ErrorIs(t, err, ErrTypeNotFound)
+	_, err = v15.Pallet("System")
+	assert.ErrorIs(t, err, ErrPalletNotFound)
+	_, _, err = v15.StorageEntry("Test", "Other")
+	assert.ErrorIs(t, err, ErrStorageEntryNotFound)
+}
+
+func Test_Decode_errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		encoded    []byte
+		errWrapped error
+		errMessage string
+	}{
+		"too_short": {
+			encoded:    []byte{'m', 'e', 't', 'a'},
+			errWrapped: ErrInvalidMagicNumber,
+			errMessage: "invalid metadata magic number: metadata is only 4 bytes",
+		},
+		"invalid_magic_number": {
+			encoded:    []byte{'a', 't', 'e', 'm', 14},
+			errWrapped: ErrInvalidMagicNumber,
+			errMessage: "invalid metadata magic number: 0x6d657461",
+		},
+		"unsupported_version": {
+			encoded:    []byte{'m', 'e', 't', 'a', 13},
+			errWrapped: ErrUnsupportedVersion,
+			errMessage: "unsupported metadata version: 13",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			metadata, err := Decode(testCase.encoded)
+
+			assert.Nil(t, metadata)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			assert.EqualError(t, err, testCase.errMessage)
+		})
+	}
+}
+
+func Test_DecodeOpaque(t *testing.T) {
+	t.Parallel()
+
+	opaque, err := scale.Marshal(westendMetadata(t))
+	require.NoError(t, err)
+
+	metadata, err := DecodeOpaque(opaque)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(14), metadata.Version)
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// PalletMetadata is the metadata of a pallet. Docs are only set for V15 metadata.
+type PalletMetadata struct {
+	Name      string
+	Storage   *PalletStorageMetadata
+	Calls     *PalletCallMetadata
+	Event     *PalletEventMetadata
+	Constants []PalletConstantMetadata
+	Error     *PalletErrorMetadata
+	Index     uint8
+	Docs      []string
+}
+
+// PalletStorageMetadata is the metadata of the storage of a pallet
+type PalletStorageMetadata struct {
+	Prefix  string
+	Entries []StorageEntryMetadata
+}
+
+// PalletCallMetadata is the metadata of the calls of a pallet
+type PalletCallMetadata struct {
+	Type uint
+}
+
+// PalletEventMetadata is the metadata of the events of a pallet
+type PalletEventMetadata struct {
+	Type uint
+}
+
+// PalletConstantMetadata is the metadata of a constant of a pallet
+type PalletConstantMetadata struct {
+	Name  string
+	Type  uint
+	Value []byte
+	Docs  []string
+}
+
+// PalletErrorMetadata is the metadata of the errors of a pallet
+type PalletErrorMetadata struct {
+	Type uint
+}
+
+// StorageEntryModifier indicates if a storage entry without value is
+// None or its default value.
+type StorageEntryModifier uint8
+
+const (
+	// StorageEntryModifierOptional is the modifier of entries without default value
+	StorageEntryModifierOptional StorageEntryModifier = iota
+	// StorageEntryModifierDefault is the modifier of entries with a default value
+	StorageEntryModifierDefault
+)
+
+// StorageHasher is the hasher of a key of a storage map
+type StorageHasher uint8
+
+// Storage hashers
+const (
+	HasherBlake2b128 StorageHasher = iota
+	HasherBlake2b256
+	HasherBlake2b128Concat
+	HasherTwox128
+	HasherTwox256
+	HasherTwox64Concat
+	HasherIdentity
+)
+
+// StorageEntryMetadata is the metadata of a storage entry of a pallet
+type StorageEntryMetadata struct {
+	Name     string
+	Modifier StorageEntryModifier
+	Type     StorageEntryType
+	Default  []byte
+	Docs     []string
+}
+
+// StorageEntryTypePlain is the type of a storage value
+type StorageEntryTypePlain struct {
+	Type uint
+}
+
+// StorageEntryTypeMap is the type of a storage map, with a hasher for each of its keys.
+// If there is more than one hasher, the key type is a tuple of the key types.
+type StorageEntryTypeMap struct {
+	Hashers []StorageHasher
+	Key     uint
+	Value   uint
+}
+
+type StorageEntryTypeValues interface {
+	StorageEntryTypePlain | StorageEntryTypeMap
+}
+
+// StorageEntryType is the type of a storage entry
+type StorageEntryType struct {
+	inner any
+}
+
+func setStorageEntryType[Value StorageEntryTypeValues](mvdt *StorageEntryType, value Value) {
+	mvdt.inner = value
+}
+
+func (mvdt *StorageEntryType) SetValue(value any) (err error) {
+	switch value := value.(type) {
+	case StorageEntryTypePlain:
+		setStorageEntryType(mvdt, value)
+		return
+
+	case StorageEntryTypeMap:
+		setStorageEntryType(mvdt, value)
+		return
+
+	default:
+		return fmt.Errorf("unsupported type")
+	}
+}
+
+func (mvdt StorageEntryType) IndexValue() (index uint, value any, err error) {
+	switch mvdt.inner.(type) {
+	case StorageEntryTypePlain:
+		return 0, mvdt.inner, nil
+
+	case StorageEntryTypeMap:
+		return 1, mvdt.inner, nil
+
+	}
+	return 0, nil, scale.ErrUnsupportedVaryingDataTypeValue
+}
+
+func (mvdt StorageEntryType) Value() (value any, err error) {
+	_, value, err = mvdt.IndexValue()
+	return
+}
+
+func (mvdt StorageEntryType) ValueAt(index uint) (value any, err error) {
+	switch index {
+	case 0:
+		return *new(StorageEntryTypePlain), nil
+
+	case 1:
+		return *new(StorageEntryTypeMap), nil
+
+	}
+	return nil, scale.ErrUnknownVaryingDataTypeValue
+}
+
+// NewStorageEntryType constructs a vdt representing a storage entry type
+func NewStorageEntryType(value any) (entryType StorageEntryType, err error) {
+	err = entryType.SetValue(value)
+	return entryType, err
+}
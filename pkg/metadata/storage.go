@@ -0,0 +1,137 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+var (
+	ErrTooManyKeys        = errors.New("too many storage keys")
+	ErrUnknownHasher      = errors.New("unknown storage hasher")
+	ErrUnknownStorageType = errors.New("unknown storage entry type")
+	ErrNoStorageValue     = errors.New("no storage value")
+)
+
+// StorageKey returns the storage key of the storage entry with the given name
+// in the pallet with the given name. The keys of a storage map are SCALE encoded
+// and hashed with the hashers of the map. Fewer keys than the map hashers can be
+// given to build the prefix of the keys of the map.
+func (m *Metadata) StorageKey(pallet, entry string, keys ...any) ([]byte, error) {
+	prefix, entryMetadata, err := m.StorageEntry(pallet, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashers []StorageHasher
+	entryType, err := entryMetadata.Type.Value()
+	if err != nil {
+		return nil, fmt.Errorf("getting storage entry type: %w", err)
+	}
+
+	switch entryType := entryType.(type) {
+	case StorageEntryTypePlain:
+	case StorageEntryTypeMap:
+		hashers = entryType.Hashers
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownStorageType, entryType)
+	}
+
+	if len(keys) > len(hashers) {
+		return nil, fmt.Errorf("%w: %d keys given for %d hashers of %s.%s",
+			ErrTooManyKeys, len(keys), len(hashers), pallet, entry)
+	}
+
+	palletHash, err := common.Twox128Hash([]byte(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("hashing pallet prefix: %w", err)
+	}
+
+	entryHash, err := common.Twox128Hash([]byte(entryMetadata.Name))
+	if err != nil {
+		return nil, fmt.Errorf("hashing storage entry name: %w", err)
+	}
+
+	storageKey := append(palletHash, entryHash...)
+	for i, key := range keys {
+		encodedKey, err := scale.Marshal(key)
+		if err != nil {
+			return nil, fmt.Errorf("encoding key %d: %w", i, err)
+		}
+
+		hashedKey, err := hashers[i].Hash(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("hashing key %d: %w", i, err)
+		}
+
+		storageKey = append(storageKey, hashedKey...)
+	}
+
+	return storageKey, nil
+}
+
+// DecodeStorageValue decodes the SCALE encoded value of the storage entry with
+// the given name in the pallet with the given name. If the value is nil, the
+// default value of the entry is decoded, or ErrNoStorageValue is returned if
+// the entry is optional.
+func (m *Metadata) DecodeStorageValue(pallet, entry string, value []byte) (any, error) {
+	_, entryMetadata, err := m.StorageEntry(pallet, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		if entryMetadata.Modifier != StorageEntryModifierDefault {
+			return nil, fmt.Errorf("%w: for %s.%s", ErrNoStorageValue, pallet, entry)
+		}
+		value = entryMetadata.Default
+	}
+
+	entryType, err := entryMetadata.Type.Value()
+	if err != nil {
+		return nil, fmt.Errorf("getting storage entry type: %w", err)
+	}
+
+	var valueType uint
+	switch entryType := entryType.(type) {
+	case StorageEntryTypePlain:
+		valueType = entryType.Type
+	case StorageEntryTypeMap:
+		valueType = entryType.Value
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownStorageType, entryType)
+	}
+
+	return m.Decode(valueType, value)
+}
+
+// Hash hashes the given encoded storage key with the storage hasher
+func (h StorageHasher) Hash(key []byte) ([]byte, error) {
+	switch h {
+	case HasherBlake2b128:
+		return common.Blake2b128(key)
+	case HasherBlake2b256:
+		hash, err := common.Blake2bHash(key)
+		return hash.ToBytes(), err
+	case HasherBlake2b128Concat:
+		hash, err := common.Blake2b128(key)
+		return append(hash, key...), err
+	case HasherTwox128:
+		return common.Twox128Hash(key)
+	case HasherTwox256:
+		hash, err := common.Twox256(key)
+		return hash.ToBytes(), err
+	case HasherTwox64Concat:
+		hash, err := common.Twox64(key)
+		return append(hash, key...), err
+	case HasherIdentity:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownHasher, h)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var alice = [32]byte(common.MustHexToBytes("0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"))
+
+func Test_Metadata_StorageKey(t *testing.T) {
+	t.Parallel()
+
+	westend, err := Decode(westendMetadata(t))
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		pallet     string
+		entry      string
+		keys       []any
+		storageKey []byte
+		errWrapped error
+		errMessage string
+	}{
+		"storage_value": {
+			pallet:     "System",
+			entry:      "Number",
+			storageKey: common.MustHexToBytes("0x26aa394eea5630e07c48ae0c9558cef702a5c1b19ab7a04f536c519aca4983ac"),
+		},
+		"storage_map": {
+			pallet: "System",
+			entry:  "Account",
+			keys:   []any{alice},
+			storageKey: common.MustHexToBytes("0x26aa394eea5630e07c48ae0c9558cef7b99d880ec681799c0cf30e8886371da9" +
+				"de1e86a9a8c739864cf3cc5ec2bea59fd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"),
+		},
+		"storage_map_prefix": {
+			pallet:     "System",
+			entry:      "Account",
+			storageKey: common.MustHexToBytes("0x26aa394eea5630e07c48ae0c9558cef7b99d880ec681799c0cf30e8886371da9"),
+		},
+		"storage_double_map": {
+			pallet: "Staking",
+			entry:  "ErasStakers",
+			keys:   []any{uint32(1), alice},
+			storageKey: common.MustHexToBytes("0x5f3e4907f716ac89b6347d15ececedca8bde0a0ea8864605e3b68ed9cb2da01b" +
+				"5153cb1f00942ff401000000518366b5b1bc7c99d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"),
+		},
+		"too_many_keys": {
+			pallet:     "System",
+			entry:      "Number",
+			keys:       []any{uint32(1)},
+			errWrapped: ErrTooManyKeys,
+			errMessage: "too many storage keys: 1 keys given for 0 hashers of System.Number",
+		},
+		"pallet_not_found": {
+			pallet:     "Unknown",
+			entry:      "Number",
+			errWrapped: ErrPalletNotFound,
+			errMessage: "pallet not found: Unknown",
+		},
+		"storage_entry_not_found": {
+			pallet:     "System",
+			entry:      "Unknown",
+			errWrapped: ErrStorageEntryNotFound,
+			errMessage: "storage entry not found: Unknown in pallet System",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			storageKey, err := westend.StorageKey(testCase.pallet, testCase.entry, testCase.keys...)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.storageKey, storageKey)
+		})
+	}
+}
+
+func Test_StorageHasher_Hash(t *testing.T) {
+	t.Parallel()
+
+	key := []byte{1, 2, 3}
+	blake2b128, err := common.Blake2b128(key)
+	require.NoError(t, err)
+	blake2b256, err := common.Blake2bHash(key)
+	require.NoError(t, err)
+	twox128, err := common.Twox128Hash(key)
+	require.NoError(t, err)
+	twox256, err := common.Twox256(key)
+	require.NoError(t, err)
+	twox64, err := common.Twox64(key)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		hasher     StorageHasher
+		hash       []byte
+		errWrapped error
+	}{
+		"blake2b_128": {
+			hasher: HasherBlake2b128,
+			hash:   blake2b128,
+		},
+		"blake2b_256": {
+			hasher: HasherBlake2b256,
+			hash:   blake2b256.ToBytes(),
+		},
+		"blake2b_128_concat": {
+			hasher: HasherBlake2b128Concat,
+			hash:   append(append([]byte{}, blake2b128...), key...),
+		},
+		"twox_128": {
+			hasher: HasherTwox128,
+			hash:   twox128,
+		},
+		"twox_256": {
+			hasher: HasherTwox256,
+			hash:   twox256.ToBytes(),
+		},
+		"twox_64_concat": {
+			hasher: HasherTwox64Concat,
+			hash:   append(append([]byte{}, twox64...), key...),
+		},
+		"identity": {
+			hasher: HasherIdentity,
+			hash:   key,
+		},
+		"unknown_hasher": {
+			hasher:     StorageHasher(7),
+			errWrapped: ErrUnknownHasher,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hash, err := testCase.hasher.Hash(key)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			assert.Equal(t, testCase.hash, hash)
+		})
+	}
+}
+
+func Test_Metadata_DecodeStorageValue(t *testing.T) {
+	t.Parallel()
+
+	westend, err := Decode(westendMetadata(t))
+	require.NoError(t, err)
+
+	type accountData struct {
+		Free       *scale.Uint128
+		Reserved   *scale.Uint128
+		MiscFrozen *scale.Uint128
+		FeeFrozen  *scale.Uint128
+	}
+	type accountInfo struct {
+		Nonce       uint32
+		Consumers   uint32
+		Providers   uint32
+		Sufficients uint32
+		Data        accountData
+	}
+	encodedAccount, err := scale.Marshal(accountInfo{
+		Nonce:     5,
+		Providers: 1,
+		Data: accountData{
+			Free:       scale.MustNewUint128(big.NewInt(1_000_000_000_000)),
+			Reserved:   scale.MustNewUint128(big.NewInt(0)),
+			MiscFrozen: scale.MustNewUint128(big.NewInt(0)),
+			FeeFrozen:  scale.MustNewUint128(big.NewInt(0)),
+		},
+	})
+	require.NoError(t, err)
+
+	newAccount := func(nonce, providers uint32, free int64) CompositeValue {
+		return CompositeValue{
+			{Name: "nonce", Value: nonce},
+			{Name: "consumers", Value: uint32(0)},
+			{Name: "providers", Value: providers},
+			{Name: "sufficients", Value: uint32(0)},
+			{Name: "data", Value: CompositeValue{
+				{Name: "free", Value: big.NewInt(free)},
+				{Name: "reserved", Value: big.NewInt(0)},
+				{Name: "misc_frozen", Value: big.NewInt(0)},
+				{Name: "fee_frozen", Value: big.NewInt(0)},
+			}},
+		}
+	}
+
+	testCases := map[string]struct {
+		pallet     string
+		entry      string
+		value      []byte
+		decoded    any
+		errWrapped error
+		errMessage string
+	}{
+		"storage_value": {
+			pallet:  "System",
+			entry:   "Number",
+			value:   []byte{1, 2, 0, 0},
+			decoded: uint32(0x201),
+		},
+		"storage_map_value": {
+			pallet:  "System",
+			entry:   "Account",
+			value:   encodedAccount,
+			decoded: newAccount(5, 1, 1_000_000_000_000),
+		},
+		"default_value": {
+			pallet:  "System",
+			entry:   "Account",
+			decoded: newAccount(0, 0, 0),
+		},
+		"no_value": {
+			pallet:     "System",
+			entry:      "ExecutionPhase",
+			errWrapped: ErrNoStorageValue,
+			errMessage: "no storage value: for System.ExecutionPhase",
+		},
+		"trailing_bytes": {
+			pallet:     "System",
+			entry:      "Number",
+			value:      []byte{1, 2, 0, 0, 0},
+			errWrapped: ErrTrailingBytes,
+			errMessage: "trailing bytes after decoded value: 1 bytes left decoding type 4",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			decoded, err := westend.DecodeStorageValue(testCase.pallet, testCase.entry, testCase.value)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.decoded, decoded)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// PortableRegistry is the registry of all the types referenced by the metadata,
+// which refer to each other by their type id.
+type PortableRegistry []PortableType
+
+// PortableType is a type of the registry with its id
+type PortableType struct {
+	ID   uint
+	Type Type
+}
+
+// Type is the description of a type of the runtime
+type Type struct {
+	Path   []string
+	Params []TypeParameter
+	Def    TypeDef
+	Docs   []string
+}
+
+// TypeParameter is a generic parameter of a type
+type TypeParameter struct {
+	Name string
+	Type *uint
+}
+
+// Field is a field of a composite type or of an enum variant
+type Field struct {
+	Name     *string
+	Type     uint
+	TypeName *string
+	Docs     []string
+}
+
+// Variant is a variant of an enum type
+type Variant struct {
+	Name   string
+	Fields []Field
+	Index  uint8
+	Docs   []string
+}
+
+// TypeDefComposite is a struct or tuple struct type
+type TypeDefComposite struct {
+	Fields []Field
+}
+
+// TypeDefVariant is an enum type
+type TypeDefVariant struct {
+	Variants []Variant
+}
+
+// TypeDefSequence is a variable length sequence type
+type TypeDefSequence struct {
+	Type uint
+}
+
+// TypeDefArray is a fixed length array type
+type TypeDefArray struct {
+	Len  uint32
+	Type uint
+}
+
+// TypeDefTuple is a tuple type
+type TypeDefTuple struct {
+	Fields []uint
+}
+
+// TypeDefPrimitive is a primitive type
+type TypeDefPrimitive uint8
+
+// Primitive types
+const (
+	PrimitiveBool TypeDefPrimitive = iota
+	PrimitiveChar
+	PrimitiveStr
+	PrimitiveU8
+	PrimitiveU16
+	PrimitiveU32
+	PrimitiveU64
+	PrimitiveU128
+	PrimitiveU256
+	PrimitiveI8
+	PrimitiveI16
+	PrimitiveI32
+	PrimitiveI64
+	PrimitiveI128
+	PrimitiveI256
+)
+
+// TypeDefCompact is a compact encoded type
+type TypeDefCompact struct {
+	Type uint
+}
+
+// TypeDefBitSequence is a bit sequence type, stored in elements of the store type
+// with the bit order of the order type.
+type TypeDefBitSequence struct {
+	StoreType uint
+	OrderType uint
+}
+
+type TypeDefValues interface {
+	TypeDefComposite | TypeDefVariant | TypeDefSequence | TypeDefArray | TypeDefTuple |
+		TypeDefPrimitive | TypeDefCompact | TypeDefBitSequence
+}
+
+// TypeDef is the definition of a type
+type TypeDef struct {
+	inner any
+}
+
+func setTypeDef[Value TypeDefValues](mvdt *TypeDef, value Value) {
+	mvdt.inner = value
+}
+
+func (mvdt *TypeDef) SetValue(value any) (err error) {
+	switch value := value.(type) {
+	case TypeDefComposite:
+		setTypeDef(mvdt, value)
+		return
+
+	case TypeDefVariant:
+		setTypeDef(mvdt, value)
+		return
+
+	case TypeDefSequence:
+		setTypeDef(mvdt, value)
+		return
+
+	case TypeDefArray:
+		setTypeDef(mvdt, value)
+		return
+
+	case TypeDefTuple:
+		setTypeDef(mvdt, value)
+		return
+
+	case TypeDefPrimitive:
+		setTypeDef(mvdt, value)
+		return
+
+	case TypeDefCompact:
+		setTypeDef(mvdt, value)
+		return
+
+	case TypeDefBitSequence:
+		setTypeDef(mvdt, value)
+		return
+
+	default:
+		return fmt.Errorf("unsupported type")
+	}
+}
+
+func (mvdt TypeDef) IndexValue() (index uint, value any, err error) {
+	switch mvdt.inner.(type) {
+	case TypeDefComposite:
+		return 0, mvdt.inner, nil
+
+	case TypeDefVariant:
+		return 1, mvdt.inner, nil
+
+	case TypeDefSequence:
+		return 2, mvdt.inner, nil
+
+	case TypeDefArray:
+		return 3, mvdt.inner, nil
+
+	case TypeDefTuple:
+		return 4, mvdt.inner, nil
+
+	case TypeDefPrimitive:
+		return 5, mvdt.inner, nil
+
+	case TypeDefCompact:
+		return 6, mvdt.inner, nil
+
+	case TypeDefBitSequence:
+		return 7, mvdt.inner, nil
+
+	}
+	return 0, nil, scale.ErrUnsupportedVaryingDataTypeValue
+}
+
+func (mvdt TypeDef) Value() (value any, err error) {
+	_, value, err = mvdt.IndexValue()
+	return
+}
+
+func (mvdt TypeDef) ValueAt(index uint) (value any, err error) {
+	switch index {
+	case 0:
+		return *new(TypeDefComposite), nil
+
+	case 1:
+		return *new(TypeDefVariant), nil
+
+	case 2:
+		return *new(TypeDefSequence), nil
+
+	case 3:
+		return *new(TypeDefArray), nil
+
+	case 4:
+		return *new(TypeDefTuple), nil
+
+	case 5:
+		return *new(TypeDefPrimitive), nil
+
+	case 6:
+		return *new(TypeDefCompact), nil
+
+	case 7:
+		return *new(TypeDefBitSequence), nil
+
+	}
+	return nil, scale.ErrUnknownVaryingDataTypeValue
+}
+
+// NewTypeDef constructs a vdt representing a type definition
+func NewTypeDef(value any) (typeDef TypeDef, err error) {
+	err = typeDef.SetValue(value)
+	return typeDef, err
+}
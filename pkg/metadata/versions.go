@@ -0,0 +1,186 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metadata
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// MagicNumber is the "meta" prefix of the encoded runtime metadata
+const MagicNumber uint32 = 0x6174656d
+
+// RuntimeMetadataPrefixed is the runtime metadata prefixed by the magic number,
+// as returned by the runtime API Metadata_metadata.
+type RuntimeMetadataPrefixed struct {
+	Magic    uint32
+	Metadata RuntimeMetadata
+}
+
+// MetadataV14 is the runtime metadata V14
+type MetadataV14 struct {
+	Types     PortableRegistry
+	Pallets   []PalletMetadataV14
+	Extrinsic ExtrinsicMetadataV14
+	Type      uint
+}
+
+// PalletMetadataV14 is the metadata of a pallet in the runtime metadata V14
+type PalletMetadataV14 struct {
+	Name      string
+	Storage   *PalletStorageMetadata
+	Calls     *PalletCallMetadata
+	Event     *PalletEventMetadata
+	Constants []PalletConstantMetadata
+	Error     *PalletErrorMetadata
+	Index     uint8
+}
+
+// ExtrinsicMetadataV14 is the metadata of the extrinsic format in the runtime metadata V14
+type ExtrinsicMetadataV14 struct {
+	Type             uint
+	Version          uint8
+	SignedExtensions []SignedExtensionMetadata
+}
+
+// SignedExtensionMetadata is the metadata of a signed extension
+type SignedExtensionMetadata struct {
+	Identifier       string
+	Type             uint
+	AdditionalSigned uint
+}
+
+// MetadataV15 is the runtime metadata V15
+type MetadataV15 struct {
+	Types      PortableRegistry
+	Pallets    []PalletMetadata
+	Extrinsic  ExtrinsicMetadataV15
+	Type       uint
+	APIs       []RuntimeAPIMetadata
+	OuterEnums OuterEnums
+	Custom     CustomMetadata
+}
+
+// ExtrinsicMetadataV15 is the metadata of the extrinsic format in the runtime metadata V15
+type ExtrinsicMetadataV15 struct {
+	Version          uint8
+	AddressType      uint
+	CallType         uint
+	SignatureType    uint
+	ExtraType        uint
+	SignedExtensions []SignedExtensionMetadata
+}
+
+// RuntimeAPIMetadata is the metadata of a runtime API
+type RuntimeAPIMetadata struct {
+	Name    string
+	Methods []RuntimeAPIMethodMetadata
+	Docs    []string
+}
+
+// RuntimeAPIMethodMetadata is the metadata of a runtime API method
+type RuntimeAPIMethodMetadata struct {
+	Name   string
+	Inputs []RuntimeAPIMethodParamMetadata
+	Output uint
+	Docs   []string
+}
+
+// RuntimeAPIMethodParamMetadata is the metadata of a runtime API method parameter
+type RuntimeAPIMethodParamMetadata struct {
+	Name string
+	Type uint
+}
+
+// OuterEnums are the types of the enums aggregating the calls, events and
+// errors of all the pallets.
+type OuterEnums struct {
+	CallType  uint
+	EventType uint
+	ErrorType uint
+}
+
+// CustomMetadata is the custom metadata of the runtime, by name
+type CustomMetadata struct {
+	Map map[string]CustomValueMetadata
+}
+
+// UnmarshalSCALE implements scale.Unmarshaler, allocating the map of the
+// custom metadata before decoding into it.
+func (c *CustomMetadata) UnmarshalSCALE(reader io.Reader) error {
+	c.Map = make(map[string]CustomValueMetadata)
+	return scale.NewDecoder(reader).Decode(&c.Map)
+}
+
+// CustomValueMetadata is a custom metadata value with its type
+type CustomValueMetadata struct {
+	Type  uint
+	Value []byte
+}
+
+type RuntimeMetadataValues interface {
+	MetadataV14 | MetadataV15
+}
+
+// RuntimeMetadata is the runtime metadata, with the metadata version as index.
+// Only the versions 14 and 15 are supported.
+type RuntimeMetadata struct {
+	inner any
+}
+
+func setRuntimeMetadata[Value RuntimeMetadataValues](mvdt *RuntimeMetadata, value Value) {
+	mvdt.inner = value
+}
+
+func (mvdt *RuntimeMetadata) SetValue(value any) (err error) {
+	switch value := value.(type) {
+	case MetadataV14:
+		setRuntimeMetadata(mvdt, value)
+		return
+
+	case MetadataV15:
+		setRuntimeMetadata(mvdt, value)
+		return
+
+	default:
+		return fmt.Errorf("unsupported type")
+	}
+}
+
+func (mvdt RuntimeMetadata) IndexValue() (index uint, value any, err error) {
+	switch mvdt.inner.(type) {
+	case MetadataV14:
+		return 14, mvdt.inner, nil
+
+	case MetadataV15:
+		return 15, mvdt.inner, nil
+
+	}
+	return 0, nil, scale.ErrUnsupportedVaryingDataTypeValue
+}
+
+func (mvdt RuntimeMetadata) Value() (value any, err error) {
+	_, value, err = mvdt.IndexValue()
+	return
+}
+
+func (mvdt RuntimeMetadata) ValueAt(index uint) (value any, err error) {
+	switch index {
+	case 14:
+		return *new(MetadataV14), nil
+
+	case 15:
+		return *new(MetadataV15), nil
+
+	}
+	return nil, scale.ErrUnknownVaryingDataTypeValue
+}
+
+// NewRuntimeMetadata constructs a vdt representing the runtime metadata
+func NewRuntimeMetadata(value any) (runtimeMetadata RuntimeMetadata, err error) {
+	err = runtimeMetadata.SetValue(value)
+	return runtimeMetadata, err
+}
@@ -50,12 +50,12 @@ func NewTrie(root *node.Node, db db.Database) *InMemoryTrie {
 	}
 }
 
-func (t *InMemoryTrie) Iter() trie.TrieIterator {
-	return NewInMemoryTrieIterator(WithTrie(t))
+func (t *InMemoryTrie) Iter() (trie.TrieIterator, error) {
+	return NewInMemoryTrieIterator(WithTrie(t)), nil
 }
 
-func (t *InMemoryTrie) PrefixedIter(prefix []byte) trie.TrieIterator {
-	return NewInMemoryTrieIterator(WithTrie(t), WithCursorAt(codec.KeyLEToNibbles(prefix)))
+func (t *InMemoryTrie) PrefixedIter(prefix []byte) (trie.TrieIterator, error) {
+	return NewInMemoryTrieIterator(WithTrie(t), WithCursorAt(codec.KeyLEToNibbles(prefix))), nil
 }
 
 func (t *InMemoryTrie) SetVersion(v trie.TrieLayout) {
@@ -599,6 +599,44 @@ func (t *InMemoryTrie) GetKeysWithPrefix(prefixLE []byte) (keysLE [][]byte) {
 	return getKeysWithPrefix(t.root, prefix, key, keysLE)
 }
 
+// ClosestDescendantMerkleValue returns the Merkle value of the closest node whose key
+// starts with the little Endian formatted key given, or nil if there is no such node.
+func (t *InMemoryTrie) ClosestDescendantMerkleValue(keyLE []byte) (merkleValue []byte, err error) {
+	keyNibbles := codec.KeyLEToNibbles(keyLE)
+	closest := closestDescendant(t.root, keyNibbles)
+	if closest == nil {
+		return nil, nil
+	}
+
+	if closest == t.root {
+		return closest.CalculateRootMerkleValue()
+	}
+
+	return closest.CalculateMerkleValue()
+}
+
+// closestDescendant returns the closest node to the parent whose key starts
+// with the key given in nibbles format, or nil if there is no such node.
+func closestDescendant(parent *node.Node, key []byte) *node.Node {
+	for parent != nil {
+		if len(key) <= len(parent.PartialKey) {
+			if bytes.HasPrefix(parent.PartialKey, key) {
+				return parent
+			}
+			return nil
+		}
+
+		if parent.Kind() == node.Leaf || !bytes.HasPrefix(key, parent.PartialKey) {
+			return nil
+		}
+
+		key = key[len(parent.PartialKey):]
+		parent, key = parent.Children[key[0]], key[1:]
+	}
+
+	return nil
+}
+
 // getKeysWithPrefix returns all keys in little Endian format that have the
 // prefix given. The prefix and key byte slices are in nibbles format.
 // TODO pass in map of keysLE if order is not needed.
@@ -707,22 +745,14 @@ func retrieve(db db.DBGetter, parent *node.Node, key []byte) (value []byte) {
 
 func retrieveFromLeaf(db db.DBGetter, leaf *node.Node, key []byte) (value []byte) {
 	if bytes.Equal(leaf.PartialKey, key) {
-		if leaf.IsHashedValue {
-			// We get the node
-			value, err := db.Get(leaf.StorageValue)
-			if err != nil {
-				panic(fmt.Sprintf("retrieving value from leaf %s", err.Error()))
-			}
-			return value
-		}
-		return leaf.StorageValue
+		return retrieveStorageValue(db, leaf)
 	}
 	return nil
 }
 
 func retrieveFromBranch(db db.DBGetter, branch *node.Node, key []byte) (value []byte) {
 	if len(key) == 0 || bytes.Equal(branch.PartialKey, key) {
-		return branch.StorageValue
+		return retrieveStorageValue(db, branch)
 	}
 
 	if len(branch.PartialKey) > len(key) && bytes.HasPrefix(branch.PartialKey, key) {
@@ -736,6 +766,20 @@ func retrieveFromBranch(db db.DBGetter, branch *node.Node, key []byte) (value []
 	return retrieve(db, child, childKey)
 }
 
+// retrieveStorageValue returns the storage value of the node, getting it
+// from the database if the node only holds its hash.
+func retrieveStorageValue(db db.DBGetter, n *node.Node) (value []byte) {
+	if !n.IsHashedValue {
+		return n.StorageValue
+	}
+
+	value, err := db.Get(n.StorageValue)
+	if err != nil {
+		panic(fmt.Sprintf("retrieving hashed value of node: %s", err.Error()))
+	}
+	return value
+}
+
 // ClearPrefixLimit deletes the keys having the prefix given in little
 // Endian format for up to `limit` keys. It returns the number of deleted
 // keys and a boolean indicating if all keys with the prefix were deleted
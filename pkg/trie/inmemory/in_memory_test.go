@@ -1855,6 +1855,64 @@ func Test_Trie_GetKeysWithPrefix(t *testing.T) {
 	}
 }
 
+func Test_Trie_ClosestDescendantMerkleValue(t *testing.T) {
+	t.Parallel()
+
+	trie := NewEmptyTrie()
+	for _, key := range []string{"no", "noot", "not", "test"} {
+		err := trie.Put([]byte(key), []byte(key+"Value"))
+		require.NoError(t, err)
+	}
+	root := trie.MustHash()
+
+	nootMerkleValue, err := closestDescendant(trie.root, codec.KeyLEToNibbles([]byte("noo"))).
+		CalculateMerkleValue()
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		key         []byte
+		merkleValue []byte
+	}{
+		"empty_key": {
+			merkleValue: root[:],
+		},
+		"common_prefix": {
+			key:         []byte("n"),
+			merkleValue: mustClosestDescendantMerkleValue(t, trie, []byte("no")),
+		},
+		"partial_key": {
+			key:         []byte("noo"),
+			merkleValue: nootMerkleValue,
+		},
+		"no_descendant": {
+			key: []byte("x"),
+		},
+		"longer_than_leaf": {
+			key: []byte("noots"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			merkleValue, err := trie.ClosestDescendantMerkleValue(testCase.key)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.merkleValue, merkleValue)
+		})
+	}
+}
+
+func mustClosestDescendantMerkleValue(t *testing.T, trie *InMemoryTrie, key []byte) []byte {
+	t.Helper()
+	merkleValue, err := trie.ClosestDescendantMerkleValue(key)
+	require.NoError(t, err)
+	require.NotNil(t, merkleValue)
+	return merkleValue
+}
+
 func Test_getKeysWithPrefix(t *testing.T) {
 	t.Parallel()
 
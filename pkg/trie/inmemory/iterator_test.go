@@ -42,7 +42,8 @@ func TestInMemoryIteratorGetAllKeysWithPrefix(t *testing.T) {
 	tt.Put([]byte("account_storage:JJK:EEE"), []byte("0x10"))
 
 	prefix := []byte("account_storage")
-	iter := tt.PrefixedIter(prefix)
+	iter, err := tt.PrefixedIter(prefix)
+	require.NoError(t, err)
 
 	keys := make([][]byte, 0)
 	for key := iter.NextKey(); bytes.HasPrefix(key, prefix); key = iter.NextKey() {
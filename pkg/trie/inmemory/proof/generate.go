@@ -64,11 +64,59 @@ func Generate(rootHash []byte, fullKeys [][]byte, database db.DBGetter) (
 
 			encodedProofNodes = append(encodedProofNodes, encodedProofNode)
 		}
+
+		// A value encoded as its hash is not part of the node encoding, so the
+		// value itself is added to the proof for the proof trie to return it.
+		foundNode := findNode(rootNode, fullKeyNibbles)
+		if foundNode == nil || (!foundNode.MustBeHashed && !foundNode.IsHashedValue) {
+			continue
+		}
+
+		value := foundNode.StorageValue
+		if foundNode.IsHashedValue {
+			value, err = database.Get(foundNode.StorageValue)
+			if err != nil {
+				return nil, fmt.Errorf("getting hashed value at key 0x%x: %w", fullKey, err)
+			}
+		}
+
+		valueHash, err := common.Blake2bHash(value)
+		if err != nil {
+			return nil, fmt.Errorf("hashing value: %w", err)
+		}
+
+		if _, seen := nodeHashesSeen[valueHash]; seen {
+			continue
+		}
+		nodeHashesSeen[valueHash] = struct{}{}
+
+		encodedProofNodes = append(encodedProofNodes, value)
 	}
 
 	return encodedProofNodes, nil
 }
 
+// findNode returns the node at the given full key in nibbles, or nil if
+// there is no such node.
+func findNode(parent *node.Node, fullKey []byte) *node.Node {
+	for parent != nil {
+		if bytes.Equal(parent.PartialKey, fullKey) {
+			return parent
+		}
+
+		if parent.Kind() == node.Leaf || len(fullKey) <= len(parent.PartialKey) ||
+			!bytes.HasPrefix(fullKey, parent.PartialKey) {
+			return nil
+		}
+
+		childIndex := fullKey[len(parent.PartialKey)]
+		fullKey = fullKey[len(parent.PartialKey)+1:]
+		parent = parent.Children[childIndex]
+	}
+
+	return nil
+}
+
 func walkRoot(root *node.Node, fullKey []byte) (
 	encodedProofNodes [][]byte, err error) {
 	if root == nil {
@@ -12,6 +12,7 @@
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	"github.com/ChainSafe/gossamer/pkg/trie/db"
 	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -131,3 +132,83 @@ func TestTrieProof(t *testing.T) {
 
 	require.Equal(t, []byte{0x86, 0x5c, 0x4a, 0x2b, 0x7f, 0x1, 0x0, 0x0}, value)
 }
+
+func Test_Generate_VerifyRange(t *testing.T) {
+	t.Parallel()
+
+	tr := inmemory.NewEmptyTrie()
+	tr.SetVersion(trie.V1)
+
+	keys := [][]byte{[]byte("cat"), []byte("catapora"), []byte("catapulta"), []byte("dog"), []byte("doguinho")}
+	entries := make(trie.Entries, len(keys))
+	for i, key := range keys {
+		// every other value is longer than 32 bytes, so it is stored hashed
+		value := []byte(fmt.Sprintf("%x-%d", key, i))
+		if i%2 == 0 {
+			value = append(value, make([]byte, 40)...)
+		}
+		entries[i] = trie.Entry{Key: key, Value: value}
+		require.NoError(t, tr.Put(key, value))
+	}
+
+	rootHash, err := trie.V1.Hash(tr)
+	require.NoError(t, err)
+
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	err = tr.WriteDirty(db)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		proofKeys  [][]byte
+		start      []byte
+		entries    trie.Entries
+		complete   bool
+		errWrapped error
+	}{
+		"full_range": {
+			proofKeys: keys,
+			entries:   entries,
+			complete:  true,
+		},
+		"range_after_start": {
+			proofKeys: keys[1:4],
+			start:     keys[1],
+			entries:   entries[2:4],
+		},
+		"empty_complete_range": {
+			proofKeys: keys[4:],
+			start:     keys[4],
+			complete:  true,
+		},
+		"omitted_key": {
+			proofKeys:  [][]byte{keys[0], keys[2]},
+			entries:    trie.Entries{entries[0], entries[2]},
+			errWrapped: ErrUnexpectedKey,
+		},
+		"incomplete_range": {
+			proofKeys:  keys[:3],
+			entries:    entries[:3],
+			complete:   true,
+			errWrapped: ErrMissingKeys,
+		},
+		"wrong_value": {
+			proofKeys:  keys[:1],
+			entries:    trie.Entries{{Key: keys[0], Value: []byte{1}}},
+			errWrapped: ErrValueMismatchProofTrie,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			proof, err := Generate(rootHash.ToBytes(), testCase.proofKeys, db)
+			require.NoError(t, err)
+
+			err = VerifyRange(proof, rootHash.ToBytes(), testCase.start, testCase.entries, testCase.complete)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
@@ -12,6 +12,7 @@
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/codec"
 	"github.com/ChainSafe/gossamer/pkg/trie/db"
 	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
 	"github.com/ChainSafe/gossamer/pkg/trie/node"
@@ -57,6 +58,56 @@ func Verify(encodedProofNodes [][]byte, rootHash, key, value []byte) (err error)
 	return nil
 }
 
+var (
+	ErrUnexpectedKey = errors.New("unexpected key in range")
+	ErrMissingKeys   = errors.New("keys missing from range")
+)
+
+// VerifyRange verifies the given key value pairs are, in order, the key value
+// pairs of the trie following the start key by creating a proof trie based on
+// the encoded proof nodes given. If complete is true, it also verifies the trie
+// has no key after the last key value pair. The start key must be part of the
+// proof unless it is nil, in which case the range starts at the first key.
+func VerifyRange(encodedProofNodes [][]byte, rootHash, start []byte,
+	entries trie.Entries, complete bool) (err error) {
+	proofDB, err := db.NewMemoryDBFromProof(encodedProofNodes)
+	if err != nil {
+		return err
+	}
+
+	proofTrie, err := buildProofTrie(encodedProofNodes, rootHash, proofDB, true)
+	if err != nil {
+		return fmt.Errorf("building trie from proof encoded nodes: %w", err)
+	}
+
+	// Subtries absent from the proof are kept as hash only nodes, which the
+	// iterator returns as keys as well, so keys omitted from the range are detected.
+	iterator := inmemory.NewInMemoryTrieIterator(
+		inmemory.WithTrie(proofTrie),
+		inmemory.WithCursorAt(codec.KeyLEToNibbles(start)),
+	)
+	for _, entry := range entries {
+		next := iterator.NextEntry()
+		if next == nil || !bytes.Equal(next.Key, codec.KeyLEToNibbles(entry.Key)) {
+			return fmt.Errorf("%w: %s in proof trie for root hash 0x%x",
+				ErrUnexpectedKey, bytesToString(entry.Key), rootHash)
+		}
+
+		proofTrieValue := proofTrie.Get(entry.Key)
+		if !bytes.Equal(entry.Value, proofTrieValue) {
+			return fmt.Errorf("%w: expected value %s but got value %s from proof trie",
+				ErrValueMismatchProofTrie, bytesToString(entry.Value), bytesToString(proofTrieValue))
+		}
+	}
+
+	if complete && iterator.NextEntry() != nil {
+		return fmt.Errorf("%w: after the last key of the range for root hash 0x%x",
+			ErrMissingKeys, rootHash)
+	}
+
+	return nil
+}
+
 var (
 	ErrEmptyProof       = errors.New("proof slice empty")
 	ErrRootNodeNotFound = errors.New("root node not found in proof")
@@ -64,6 +115,18 @@ func Verify(encodedProofNodes [][]byte, rootHash, key, value []byte) (err error)
 
 // buildTrie sets a partial trie based on the proof slice of encoded nodes.
 func buildTrie(encodedProofNodes [][]byte, rootHash []byte, db db.Database) (t trie.Trie, err error) {
+	proofTrie, err := buildProofTrie(encodedProofNodes, rootHash, db, false)
+	if err != nil {
+		return nil, err
+	}
+	return proofTrie, nil
+}
+
+// buildProofTrie sets a partial trie based on the proof slice of encoded nodes.
+// If keepMissing is true, the children missing from the proof are kept as nodes
+// holding only their Merkle value instead of being removed from the trie.
+func buildProofTrie(encodedProofNodes [][]byte, rootHash []byte, db db.Database,
+	keepMissing bool) (t *inmemory.InMemoryTrie, err error) {
 	if len(encodedProofNodes) == 0 {
 		return nil, fmt.Errorf("%w: for Merkle root hash 0x%x",
 			ErrEmptyProof, rootHash)
@@ -125,7 +188,7 @@ func buildTrie(encodedProofNodes [][]byte, rootHash []byte, db db.Database) (t t
 			ErrRootNodeNotFound, rootHash, strings.Join(proofHashDigests, ", "))
 	}
 
-	err = loadProof(digestToEncoding, root)
+	err = loadProofNodes(digestToEncoding, root, keepMissing)
 	if err != nil {
 		return nil, fmt.Errorf("loading proof: %w", err)
 	}
@@ -136,6 +199,10 @@ func buildTrie(encodedProofNodes [][]byte, rootHash []byte, db db.Database) (t t
 // loadProof is a recursive function that will create all the trie paths based
 // on the map from node hash digest to node encoding, starting from the node `n`.
 func loadProof(digestToEncoding map[string][]byte, n *node.Node) (err error) {
+	return loadProofNodes(digestToEncoding, n, false)
+}
+
+func loadProofNodes(digestToEncoding map[string][]byte, n *node.Node, keepMissing bool) (err error) {
 	if n.Kind() != node.Branch {
 		return nil
 	}
@@ -158,7 +225,7 @@ func loadProof(digestToEncoding map[string][]byte, n *node.Node) (err error) {
 				// it becomes used with a database in the future, we set the dirty flag
 				// to true.
 				child.Dirty = true
-			} else {
+			} else if !keepMissing {
 				// hash not found and the child is not inlined,
 				// so clear the child from the branch.
 				branch.Descendants -= 1 + child.Descendants
@@ -185,7 +252,7 @@ func loadProof(digestToEncoding map[string][]byte, n *node.Node) (err error) {
 
 		branch.Children[i] = child
 		branch.Descendants += child.Descendants
-		err = loadProof(digestToEncoding, child)
+		err = loadProofNodes(digestToEncoding, child, keepMissing)
 		if err != nil {
 			return err // do not wrap error since this is recursive
 		}
@@ -13,6 +13,9 @@
 // EmptyHash is the empty trie hash.
 var EmptyHash = common.MustBlake2bHash([]byte{0})
 
+// ChildStorageKeyPrefix is the prefix for all child storage keys
+var ChildStorageKeyPrefix = []byte(":child_storage:default:")
+
 type ChildTriesRead interface {
 	GetChild(keyToChild []byte) (Trie, error)
 	GetFromChild(keyToChild, key []byte) ([]byte, error)
@@ -78,12 +81,15 @@ type TrieRead interface {
 	Hashable
 	ChildTriesRead
 
-	Iter() TrieIterator
-	PrefixedIter(prefix []byte) TrieIterator
+	Iter() (TrieIterator, error)
+	PrefixedIter(prefix []byte) (TrieIterator, error)
 
 	Entries() (keyValueMap map[string][]byte)
 	NextKey(key []byte) []byte
 	GetKeysWithPrefix(prefix []byte) (keysLE [][]byte)
+	// ClosestDescendantMerkleValue returns the Merkle value of the closest node whose key
+	// starts with the given key, or nil if there is no such node.
+	ClosestDescendantMerkleValue(key []byte) (merkleValue []byte, err error)
 }
 
 type Trie interface {
@@ -4,18 +4,145 @@
 package triedb
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 )
 
+// childTrieKey returns the key at which the root hash of the child trie is
+// stored in the main trie, that is :child_storage:default:[keyToChild]
+func childTrieKey(keyToChild []byte) []byte {
+	return bytes.Join([][]byte{trie.ChildStorageKeyPrefix, keyToChild}, nil)
+}
+
+// getInternalChildTrie returns the child trie located in the main trie at key
+// :child_storage:default:[keyToChild], loading it from db if needed.
+func (t *TrieDB) getInternalChildTrie(keyToChild []byte) (*TrieDB, error) {
+	childHash := t.Get(childTrieKey(keyToChild))
+	if childHash == nil {
+		return nil, fmt.Errorf("%w at key 0x%x%x", trie.ErrChildTrieDoesNotExist, trie.ChildStorageKeyPrefix, keyToChild)
+	}
+
+	root := common.BytesToHash(childHash)
+	child, ok := t.childTries[root]
+	if !ok {
+		child = NewTrieDB(root, t.db, WithCache(t.cache), WithRecorder(t.recorder))
+		child.version = t.version
+		t.childTries[root] = child
+	}
+
+	return child, nil
+}
+
+// setChild stores the root hash of the given child trie in the main trie at
+// key :child_storage:default:[keyToChild]
+func (t *TrieDB) setChild(keyToChild []byte, child *TrieDB) error {
+	childHash, err := child.Hash()
+	if err != nil {
+		return fmt.Errorf("hashing child trie: %w", err)
+	}
+
+	err = t.Put(childTrieKey(keyToChild), childHash.ToBytes())
+	if err != nil {
+		return fmt.Errorf("putting child trie root hash %s in trie: %w", childHash, err)
+	}
+
+	t.childTries[childHash] = child
+	return nil
+}
+
+// GetChild returns the child trie at key :child_storage:default:[keyToChild]
 func (t *TrieDB) GetChild(keyToChild []byte) (trie.Trie, error) {
-	panic("not implemented yet")
+	child, err := t.getInternalChildTrie(keyToChild)
+	if err != nil {
+		return nil, err
+	}
+	return child, nil
 }
 
+// GetFromChild retrieves the value at the given key from the child trie
+// located in the main trie at key :child_storage:default:[keyToChild]
 func (t *TrieDB) GetFromChild(keyToChild, key []byte) ([]byte, error) {
-	panic("not implemented yet")
+	child, err := t.getInternalChildTrie(keyToChild)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.Get(key), nil
 }
 
+// GetChildTries returns the child tries loaded or modified in this trie
 func (t *TrieDB) GetChildTries() map[common.Hash]trie.Trie {
-	panic("not implemented yet")
+	children := make(map[common.Hash]trie.Trie, len(t.childTries))
+	for root, child := range t.childTries {
+		children[root] = child
+	}
+	return children
+}
+
+// PutIntoChild puts a key-value pair into the child trie located in the main
+// trie at key :child_storage:default:[keyToChild], creating it if needed.
+func (t *TrieDB) PutIntoChild(keyToChild, key, value []byte) error {
+	child, err := t.getInternalChildTrie(keyToChild)
+	if err != nil {
+		if !errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+			return fmt.Errorf("getting child: %w", err)
+		}
+		child = NewEmptyTrieDB(t.db, WithCache(t.cache), WithRecorder(t.recorder))
+	}
+	child.version = t.version
+
+	origChildHash := child.rootHash
+	err = child.Put(key, value)
+	if err != nil {
+		return fmt.Errorf("putting into child trie located at key 0x%x: %w", keyToChild, err)
+	}
+
+	delete(t.childTries, origChildHash)
+	return t.setChild(keyToChild, child)
+}
+
+// DeleteChild deletes the child trie located in the main trie at key
+// :child_storage:default:[keyToChild]
+func (t *TrieDB) DeleteChild(keyToChild []byte) error {
+	key := childTrieKey(keyToChild)
+	if childHash := t.Get(key); childHash != nil {
+		delete(t.childTries, common.BytesToHash(childHash))
+	}
+
+	err := t.Delete(key)
+	if err != nil {
+		return fmt.Errorf("deleting child trie located at key 0x%x: %w", keyToChild, err)
+	}
+	return nil
+}
+
+// ClearFromChild removes the given key from the child trie located in the main
+// trie at key :child_storage:default:[keyToChild]. The child trie is deleted
+// once it is empty.
+func (t *TrieDB) ClearFromChild(keyToChild, key []byte) error {
+	child, err := t.getInternalChildTrie(keyToChild)
+	if err != nil {
+		return err
+	}
+
+	err = child.Delete(key)
+	if err != nil {
+		return fmt.Errorf("deleting from child trie located at key 0x%x: %w", keyToChild, err)
+	}
+
+	childHash, err := child.Hash()
+	if err != nil {
+		return fmt.Errorf("hashing child trie: %w", err)
+	}
+
+	if childHash == hashedNullNode {
+		return t.DeleteChild(keyToChild)
+	}
+
+	delete(t.childTries, common.BytesToHash(t.Get(childTrieKey(keyToChild))))
+	return t.setChild(keyToChild, child)
 }
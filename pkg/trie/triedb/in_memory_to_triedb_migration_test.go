@@ -119,4 +119,16 @@ func TestReadTrieDB_Migration(t *testing.T) {
 
 		assert.Equal(t, expected, actual)
 	})
+
+	t.Run("closest_descendant_merkle_values_are_the_same", func(t *testing.T) {
+		for _, key := range []string{"", "n", "no", "not", "notab", "dim", "x"} {
+			expected, err := inMemoryTrie.ClosestDescendantMerkleValue([]byte(key))
+			assert.NoError(t, err)
+
+			actual, err := trieDB.ClosestDescendantMerkleValue([]byte(key))
+			assert.NoError(t, err)
+
+			assert.Equal(t, expected, actual, key)
+		}
+	})
 }
@@ -3,12 +3,18 @@
 
 package triedb
 
+import "bytes"
+
 // Entries returns all the key-value pairs in the trie as a map of keys to values
 // where the keys are encoded in Little Endian.
+// Pending changes are committed first, and nil is returned if the trie cannot be read.
 func (t *TrieDB) Entries() (keyValueMap map[string][]byte) {
-	entries := make(map[string][]byte)
+	iter, err := t.Iter()
+	if err != nil {
+		return nil
+	}
 
-	iter := NewTrieDBIterator(t)
+	entries := make(map[string][]byte)
 	for entry := iter.NextEntry(); entry != nil; entry = iter.NextEntry() {
 		entries[string(entry.Key)] = entry.Value
 	}
@@ -18,24 +24,34 @@ func (t *TrieDB) Entries() (keyValueMap map[string][]byte) {
 
 // NextKey returns the next key in the trie in lexicographic order.
 // It returns nil if no next key is found.
+// Pending changes are committed first, and nil is returned if the trie cannot be read.
 func (t *TrieDB) NextKey(key []byte) []byte {
-	iter := NewTrieDBIterator(t)
-
-	// TODO: Seek will potentially skip a lot of keys, we need to find a way to
-	// optimise it, maybe creating a lookupFor
-	iter.Seek(key)
+	iter, err := t.PrefixedIter(key)
+	if err != nil {
+		return nil
+	}
 	return iter.NextKey()
 }
 
 // GetKeysWithPrefix returns all keys in little Endian
 // format from nodes in the trie that have the given little
 // Endian formatted prefix in their key.
+// Pending changes are committed first, and nil is returned if the trie cannot be read.
 func (t *TrieDB) GetKeysWithPrefix(prefix []byte) (keysLE [][]byte) {
-	iter := NewPrefixedTrieDBIterator(t, prefix)
+	err := t.commit()
+	if err != nil {
+		return nil
+	}
+
+	iter, err := NewPrefixedTrieDBIterator(t, prefix)
+	if err != nil {
+		return nil
+	}
+	iter.inclusive = true
 
 	keys := make([][]byte, 0)
 
-	for key := iter.NextKey(); key != nil; key = iter.NextKey() {
+	for key := iter.NextKey(); key != nil && bytes.HasPrefix(key, prefix); key = iter.NextKey() {
 		keys = append(keys, key)
 	}
 
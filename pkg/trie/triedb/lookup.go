@@ -132,8 +132,12 @@ func (l *TrieLookup) lookupNode(keyNibbles []byte) (codec.EncodedNode, error) {
 }
 
 func (l *TrieLookup) lookupValue(keyNibbles []byte) (value []byte, err error) {
+	// the values are cached by the root hash of the lookup and the key, since the
+	// value of a key differs between tries
+	var cacheKey []byte
 	if l.cache != nil {
-		if value = l.cache.GetValue(keyNibbles); value != nil {
+		cacheKey = bytes.Join([][]byte{l.hash[:], keyNibbles}, nil)
+		if value = l.cache.GetValue(cacheKey); value != nil {
 			return value, nil
 		}
 	}
@@ -155,7 +159,7 @@ func (l *TrieLookup) lookupValue(keyNibbles []byte) (value []byte, err error) {
 		}
 
 		if l.cache != nil {
-			l.cache.SetValue(keyNibbles, value)
+			l.cache.SetValue(cacheKey, value)
 		}
 
 		return value, nil
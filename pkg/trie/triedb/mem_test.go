@@ -87,7 +87,8 @@ func Benchmark_NodesCache(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
 			// Iterate through all keys
-			iter := NewTrieDBIterator(trieDB)
+			iter, err := NewTrieDBIterator(trieDB)
+			assert.NoError(b, err)
 			for entry := iter.NextEntry(); entry != nil; entry = iter.NextEntry() {
 			}
 		}
@@ -102,7 +103,8 @@ func Benchmark_NodesCache(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
 			// Iterate through all keys
-			iter := NewTrieDBIterator(trieDB)
+			iter, err := NewTrieDBIterator(trieDB)
+			assert.NoError(b, err)
 			for entry := iter.NextEntry(); entry != nil; entry = iter.NextEntry() {
 			}
 		}
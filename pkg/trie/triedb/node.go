@@ -97,14 +97,14 @@ func (vr newValueRef) getHash() common.Hash {
 func (vr newValueRef) equal(other nodeValue) bool {
 	switch otherValue := other.(type) {
 	case newValueRef:
-		return vr.hash == otherValue.hash
+		return bytes.Equal(vr.data, otherValue.data)
 	default:
 		return false
 	}
 }
 
 func NewValue(data []byte, threshold int) nodeValue {
-	if len(data) >= threshold {
+	if len(data) > threshold {
 		return newValueRef{data: data}
 	}
 
@@ -166,7 +166,7 @@ func (n Branch) getPartialKey() []byte { return n.partialKey }
 
 // Create a new node from the encoded data, decoding this data into a codec.Node
 // and mapping that with this node type
-func newNodeFromEncoded(nodeHash common.Hash, data []byte, storage nodeStorage) (Node, error) {
+func newNodeFromEncoded(nodeHash common.Hash, data []byte, storage *nodeStorage) (Node, error) {
 	reader := bytes.NewReader(data)
 	encodedNode, err := codec.Decode(reader)
 	if err != nil {
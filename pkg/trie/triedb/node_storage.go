@@ -34,7 +34,7 @@ func (persisted) isNodeHandle() {}
 func newFromEncodedMerkleValue(
 	parentHash common.Hash,
 	encodedNodeHandle codec.MerkleValue,
-	storage nodeStorage,
+	storage *nodeStorage,
 ) (NodeHandle, error) {
 	switch encoded := encodedNodeHandle.(type) {
 	case codec.HashedNode:
@@ -0,0 +1,67 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package proof
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"golang.org/x/exp/slices"
+)
+
+// StorageProof is the set of encoded nodes and values read while accessing a
+// trie. Unlike MerkleProof, nodes are neither compacted nor ordered along the
+// trie paths, this is the proof format used by the light client protocol.
+type StorageProof [][]byte
+
+// NewStorageProof returns the storage proof made of the given recorded trie
+// accesses. Duplicated records are only included once and the proof is sorted
+// so it does not depend on the access order.
+func NewStorageProof(records []triedb.Record) StorageProof {
+	proof := make(StorageProof, 0, len(records))
+	seen := make(map[string]struct{}, len(records))
+	for _, record := range records {
+		if _, ok := seen[string(record.Data)]; ok {
+			continue
+		}
+		seen[string(record.Data)] = struct{}{}
+		proof = append(proof, record.Data)
+	}
+
+	slices.SortFunc(proof, bytes.Compare)
+	return proof
+}
+
+// NewReadProof returns the storage proof of reading the given keys in the trie
+// with the given root hash.
+func NewReadProof(db db.RWDatabase, rootHash common.Hash, keys [][]byte) StorageProof {
+	recorder := triedb.NewRecorder()
+	trie := triedb.NewTrieDB(rootHash, db, triedb.WithRecorder(recorder))
+	for _, key := range keys {
+		trie.Get(key)
+	}
+
+	return NewStorageProof(recorder.Drain())
+}
+
+// NewChildReadProof returns the storage proof of reading the given keys in the
+// child trie stored at :child_storage:default:[keyToChild] of the trie with the
+// given root hash. The proof includes the nodes of the trie leading to the root
+// hash of the child trie.
+func NewChildReadProof(db db.RWDatabase, rootHash common.Hash, keyToChild []byte, keys [][]byte) (
+	StorageProof, error) {
+	recorder := triedb.NewRecorder()
+	trie := triedb.NewTrieDB(rootHash, db, triedb.WithRecorder(recorder))
+	for _, key := range keys {
+		_, err := trie.GetFromChild(keyToChild, key)
+		if err != nil {
+			return nil, fmt.Errorf("reading from child trie: %w", err)
+		}
+	}
+
+	return NewStorageProof(recorder.Drain()), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package proof
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewReadProof(t *testing.T) {
+	t.Parallel()
+
+	entries := map[string][]byte{
+		"polkadot": []byte("polkadot"),
+		"go":       []byte("go"),
+		"golang":   []byte("golang"),
+		"gossamer": []byte("gossamer"),
+		"kusama":   []byte("kusama"),
+	}
+
+	testCases := map[string]struct {
+		keys          [][]byte
+		expectedProof StorageProof
+	}{
+		"no_key": {
+			expectedProof: StorageProof{},
+		},
+		"existing_keys": {
+			keys: [][]byte{[]byte("go"), []byte("polkadot"), []byte("go")},
+		},
+		"missing_key": {
+			keys: [][]byte{[]byte("gossip")},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			inmemoryDB := NewMemoryDB(triedb.EmptyNode)
+			trie := triedb.NewEmptyTrieDB(inmemoryDB)
+			for key, value := range entries {
+				require.NoError(t, trie.Put([]byte(key), value))
+			}
+			root := trie.MustHash()
+
+			proof := NewReadProof(inmemoryDB, root, testCase.keys)
+			if testCase.expectedProof != nil {
+				require.Equal(t, testCase.expectedProof, proof)
+			}
+
+			// the proof nodes are enough to read the keys back
+			proofDB := NewMemoryDB(triedb.EmptyNode)
+			for _, node := range proof {
+				proofDB.emplace(common.MustBlake2bHash(node), node)
+			}
+			proofTrie := triedb.NewTrieDB(root, proofDB)
+			for _, key := range testCase.keys {
+				require.Equal(t, entries[string(key)], proofTrie.Get(key))
+			}
+		})
+	}
+}
+
+func Test_NewChildReadProof(t *testing.T) {
+	t.Parallel()
+
+	inmemoryDB := NewMemoryDB(triedb.EmptyNode)
+	tr := triedb.NewEmptyTrieDB(inmemoryDB)
+	require.NoError(t, tr.Put([]byte("top"), []byte("value")))
+	require.NoError(t, tr.PutIntoChild([]byte("child"), []byte("key"), []byte("childvalue")))
+	require.NoError(t, tr.PutIntoChild([]byte("child"), []byte("other"), []byte("othervalue")))
+	root := tr.MustHash()
+
+	proof, err := NewChildReadProof(inmemoryDB, root, []byte("child"), [][]byte{[]byte("key")})
+	require.NoError(t, err)
+
+	proofDB := NewMemoryDB(triedb.EmptyNode)
+	for _, node := range proof {
+		proofDB.emplace(common.MustBlake2bHash(node), node)
+	}
+	proofTrie := triedb.NewTrieDB(root, proofDB)
+	value, err := proofTrie.GetFromChild([]byte("child"), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("childvalue"), value)
+
+	_, err = NewChildReadProof(inmemoryDB, root, []byte("missing"), [][]byte{[]byte("key")})
+	require.ErrorIs(t, err, trie.ErrChildTrieDoesNotExist)
+}
@@ -12,6 +12,7 @@
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	nibbles "github.com/ChainSafe/gossamer/pkg/trie/codec"
 	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/tracking"
 
 	"github.com/ChainSafe/gossamer/internal/database"
 	"github.com/ChainSafe/gossamer/internal/log"
@@ -21,6 +22,10 @@
 
 var ErrIncompleteDB = errors.New("incomplete database")
 
+// ErrUncommittedChanges is returned when iterating over a trie whose changes
+// are not committed to its database yet.
+var ErrUncommittedChanges = errors.New("trie has uncommitted changes")
+
 var (
 	logger = log.NewFromGlobal(log.AddContext("pkg", "triedb"))
 )
@@ -58,11 +63,18 @@ type TrieDB struct {
 	cache cache.TrieCache
 	// Optional recorder for recording trie accesses
 	recorder *Recorder
+	// childTries are the child tries loaded or modified in this trieDB session,
+	// indexed by their root hash
+	childTries map[common.Hash]*TrieDB
+	// insertedNodes and deletedNodes are the hashes of the nodes written to
+	// and deleted from db since the trieDB was created
+	insertedNodes map[common.Hash]struct{}
+	deletedNodes  map[common.Hash]struct{}
 }
 
 func NewEmptyTrieDB(db db.RWDatabase, opts ...TrieDBOpts) *TrieDB {
 	root := hashedNullNode
-	return NewTrieDB(root, db)
+	return NewTrieDB(root, db, opts...)
 }
 
 // NewTrieDB creates a new TrieDB using the given root and db
@@ -70,12 +82,15 @@ func NewTrieDB(rootHash common.Hash, db db.RWDatabase, opts ...TrieDBOpts) *Trie
 	rootHandle := persisted(rootHash)
 
 	trieDB := &TrieDB{
-		rootHash:   rootHash,
-		version:    trie.V0,
-		db:         db,
-		storage:    newNodeStorage(),
-		rootHandle: rootHandle,
-		deathRow:   make(map[string]interface{}),
+		rootHash:      rootHash,
+		version:       trie.V0,
+		db:            db,
+		storage:       newNodeStorage(),
+		rootHandle:    rootHandle,
+		deathRow:      make(map[string]interface{}),
+		childTries:    make(map[common.Hash]*TrieDB),
+		insertedNodes: make(map[common.Hash]struct{}),
+		deletedNodes:  make(map[common.Hash]struct{}),
 	}
 
 	for _, opt := range opts {
@@ -129,15 +144,73 @@ func (t *TrieDB) Get(key []byte) []byte {
 	return val
 }
 
-func (t *TrieDB) lookup(fullKey []byte, partialKey []byte, handle NodeHandle) ([]byte, error) {
-	prefix := fullKey
+// ClosestDescendantMerkleValue returns the Merkle value of the closest node whose key
+// starts with the little Endian formatted key given, or nil if there is no such node.
+func (t *TrieDB) ClosestDescendantMerkleValue(key []byte) (merkleValue []byte, err error) {
+	// pending changes are committed first so all the nodes are in the database
+	rootHash, err := t.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("committing trie: %w", err)
+	}
+
+	if rootHash == trie.EmptyHash {
+		return nil, nil
+	}
+
+	keyNibbles := nibbles.KeyLEToNibbles(key)
+	var current codec.MerkleValue = codec.HashedNode(rootHash)
+	for {
+		node, err := t.getNode(current)
+		if err != nil {
+			return nil, fmt.Errorf("getting node: %w", err)
+		}
+
+		var partialKey []byte
+		switch n := node.(type) {
+		case codec.Empty:
+			return nil, nil
+		case codec.Leaf:
+			partialKey = n.PartialKey
+		case codec.Branch:
+			partialKey = n.PartialKey
+		}
+
+		if len(keyNibbles) <= len(partialKey) {
+			if !bytes.HasPrefix(partialKey, keyNibbles) {
+				return nil, nil
+			}
+
+			switch merkleValue := current.(type) {
+			case codec.HashedNode:
+				return merkleValue[:], nil
+			case codec.InlineNode:
+				return merkleValue, nil
+			}
+		}
+
+		branch, ok := node.(codec.Branch)
+		if !ok || !bytes.HasPrefix(keyNibbles, partialKey) {
+			return nil, nil
+		}
+
+		keyNibbles = keyNibbles[len(partialKey):]
+		current = branch.Children[keyNibbles[0]]
+		if current == nil {
+			return nil, nil
+		}
+		keyNibbles = keyNibbles[1:]
+	}
+}
 
+func (t *TrieDB) lookup(fullKey []byte, partialKey []byte, handle NodeHandle) ([]byte, error) {
 	for {
 		var partialIdx int
 		switch node := handle.(type) {
 		case persisted:
+			// the persisted node is the root of the subtrie containing the
+			// remaining partial key
 			lookup := NewTrieLookup(t.db, common.Hash(node), t.cache, t.recorder)
-			val, err := lookup.lookupValue(fullKey)
+			val, err := lookup.lookupValue(partialKey)
 			if err != nil {
 				return nil, err
 			}
@@ -148,20 +221,24 @@ func (t *TrieDB) lookup(fullKey []byte, partialKey []byte, handle NodeHandle) ([
 				return nil, nil
 			case Leaf:
 				if bytes.Equal(n.partialKey, partialKey) {
-					return inMemoryFetchedValue(n.value, prefix, t.db)
+					return inMemoryFetchedValue(n.value, n.partialKey, t.db)
 				} else {
 					return nil, nil
 				}
 			case Branch:
 				if bytes.Equal(n.partialKey, partialKey) {
-					return inMemoryFetchedValue(n.value, prefix, t.db)
+					if n.value == nil {
+						return nil, nil
+					}
+					return inMemoryFetchedValue(n.value, n.partialKey, t.db)
 				} else if bytes.HasPrefix(partialKey, n.partialKey) {
 					idx := partialKey[len(n.partialKey)]
 					child := n.children[idx]
-					if child != nil {
-						partialIdx = 1 + len(n.partialKey)
-						handle = child
+					if child == nil {
+						return nil, nil
 					}
+					partialIdx = 1 + len(n.partialKey)
+					handle = child
 				} else {
 					return nil, nil
 				}
@@ -172,6 +249,11 @@ func (t *TrieDB) lookup(fullKey []byte, partialKey []byte, handle NodeHandle) ([
 }
 
 // Internal methods
+func (t *TrieDB) hasUncommittedChanges() bool {
+	_, inMemoryRoot := t.rootHandle.(inMemory)
+	return inMemoryRoot || len(t.deathRow) > 0
+}
+
 func (t *TrieDB) getRootNode() (codec.EncodedNode, error) {
 	encodedNode, err := t.db.Get(t.rootHash[:])
 	if err != nil {
@@ -208,7 +290,7 @@ func (t *TrieDB) getNode(
 		if err != nil {
 			return nil, err
 		}
-		t.recordAccess(encodedNodeAccess{hash: t.rootHash, encodedNode: encodedNode})
+		t.recordAccess(encodedNodeAccess{hash: common.Hash(n), encodedNode: encodedNode})
 
 		reader := bytes.NewReader(encodedNode)
 		return codec.Decode(reader)
@@ -242,6 +324,89 @@ func (t *TrieDB) Delete(key []byte) error {
 	return t.remove(keyNibbles)
 }
 
+// ClearPrefix deletes all the keys with the given prefix from the trie.
+// Note the prefix argument is given in little Endian format.
+func (t *TrieDB) ClearPrefix(prefix []byte) error {
+	for _, key := range t.GetKeysWithPrefix(prefix) {
+		err := t.Delete(key)
+		if err != nil {
+			return fmt.Errorf("deleting key 0x%x: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// ClearPrefixLimit deletes the keys with the given prefix from the trie for up
+// to `limit` keys in lexicographic order. It returns the number of deleted keys
+// and a boolean indicating if all keys with the prefix were deleted.
+// Note the prefix argument is given in little Endian format.
+func (t *TrieDB) ClearPrefixLimit(prefix []byte, limit uint32) (
+	deleted uint32, allDeleted bool, err error) {
+	if limit == 0 {
+		return 0, false, nil
+	}
+
+	keys := t.GetKeysWithPrefix(prefix)
+	for _, key := range keys {
+		if deleted == limit {
+			break
+		}
+
+		err = t.Delete(key)
+		if err != nil {
+			return deleted, false, fmt.Errorf("deleting key 0x%x: %w", key, err)
+		}
+		deleted++
+	}
+
+	return deleted, int(deleted) == len(keys), nil
+}
+
+// GetChangedNodeHashes returns the hashes of the nodes written to and deleted
+// from the db since the trieDB was created, including the ones of its child
+// tries. Pending changes are only taken into account once committed.
+func (t *TrieDB) GetChangedNodeHashes() (inserted, deleted map[common.Hash]struct{}, err error) {
+	inserted = make(map[common.Hash]struct{}, len(t.insertedNodes))
+	deleted = make(map[common.Hash]struct{}, len(t.deletedNodes))
+
+	tries := make([]*TrieDB, 0, len(t.childTries)+1)
+	tries = append(tries, t)
+	for _, child := range t.childTries {
+		tries = append(tries, child)
+	}
+
+	for _, trieDB := range tries {
+		for hash := range trieDB.insertedNodes {
+			inserted[hash] = struct{}{}
+		}
+		for hash := range trieDB.deletedNodes {
+			deleted[hash] = struct{}{}
+		}
+	}
+
+	return inserted, deleted, nil
+}
+
+// HandleTrackedDeltas does nothing since the trieDB tracks the inserted and
+// deleted nodes itself when committing its changes.
+func (*TrieDB) HandleTrackedDeltas(bool, tracking.Getter) {}
+
+func (t *TrieDB) trackInsertedNode(hash common.Hash) {
+	delete(t.deletedNodes, hash)
+	t.insertedNodes[hash] = struct{}{}
+}
+
+func (t *TrieDB) trackDeletedNode(hash common.Hash) {
+	if _, ok := t.insertedNodes[hash]; ok {
+		// the node was written in this trieDB session so it was never part
+		// of a previous state
+		delete(t.insertedNodes, hash)
+		return
+	}
+	t.deletedNodes[hash] = struct{}{}
+}
+
 // insert inserts the node and update the rootHandle
 func (t *TrieDB) insert(keyNibbles, value []byte) error {
 	var oldValue nodeValue
@@ -436,9 +601,17 @@ func (t *TrieDB) fix(branch Branch) (Node, error) {
 
 		switch n := childNode.(type) {
 		case Leaf:
-			return Leaf{combinedKey, n.value}, nil
+			value, err := t.moveValue(n.value, n.partialKey)
+			if err != nil {
+				return nil, err
+			}
+			return Leaf{combinedKey, value}, nil
 		case Branch:
-			return Branch{combinedKey, n.children, n.value}, nil
+			value, err := t.moveValue(n.value, n.partialKey)
+			if err != nil {
+				return nil, err
+			}
+			return Branch{combinedKey, n.children, value}, nil
 		default:
 			panic("unreachable")
 		}
@@ -465,20 +638,6 @@ func (t *TrieDB) removeInspector(stored Node, keyNibbles []byte, oldValue *nodeV
 		// Wrong partial, so we return the node as is
 		return restoreNode{n}, nil
 	case Branch:
-		if len(partial) == 0 {
-			if n.value == nil {
-				// Nothing to delete since the branch doesn't contains a value
-				return restoreNode{n}, nil
-			}
-			// The branch contains the value so we delete it
-			t.replaceOldValue(oldValue, n.value, partial)
-			newNode, err := t.fix(Branch{n.partialKey, n.children, nil})
-			if err != nil {
-				return nil, err
-			}
-			return replaceNode{newNode}, nil
-		}
-
 		common := nibbles.CommonPrefix(n.partialKey, partial)
 		existingLength := len(n.partialKey)
 
@@ -562,7 +721,11 @@ func (t *TrieDB) insertInspector(stored Node, keyNibbles []byte, value []byte, o
 			idx := existingKey[common]
 
 			// Modify the existing leaf partial key and add it as a child
-			newLeaf := Leaf{existingKey[common+1:], n.value}
+			leafValue, err := t.moveValue(n.value, existingKey)
+			if err != nil {
+				return nil, err
+			}
+			newLeaf := Leaf{existingKey[common+1:], leafValue}
 			children[idx] = inMemory(t.storage.alloc(NewStoredNode{node: newLeaf}))
 			branch := Branch{
 				partialKey: partial[:common],
@@ -621,7 +784,11 @@ func (t *TrieDB) insertInspector(stored Node, keyNibbles []byte, value []byte, o
 
 			// So we take this branch and we add it as a child of the new one
 			branchPartial := existingKey[common+1:]
-			lowerBranch := Branch{branchPartial, n.children, n.value}
+			branchValue, err := t.moveValue(n.value, existingKey)
+			if err != nil {
+				return nil, err
+			}
+			lowerBranch := Branch{branchPartial, n.children, branchValue}
 			allocStorage := t.storage.alloc(NewStoredNode{node: lowerBranch})
 
 			children := [codec.ChildrenCapacity]NodeHandle{}
@@ -702,24 +869,49 @@ func (t *TrieDB) replaceOldValue(
 	case valueRef, newValueRef:
 		hash := oldv.getHash()
 		if hash != common.EmptyHash {
-			prefixedKey := append(prefix, oldv.getHash().ToBytes()...)
+			prefixedKey := bytes.Join([][]byte{prefix, hash.ToBytes()}, nil)
 			t.deathRow[string(prefixedKey)] = nil
 		}
 	}
 	*oldValue = storedValue
 }
 
+// moveValue returns the value of a node whose partial key changes from
+// `prefix` to a new one. Since hashed values are stored in the db using the
+// node partial key as prefix, a value already stored in the db is loaded to be
+// stored again under the new partial key on commit.
+func (t *TrieDB) moveValue(value nodeValue, prefix []byte) (nodeValue, error) {
+	v, ok := value.(valueRef)
+	if !ok {
+		return value, nil
+	}
+
+	data, err := inMemoryFetchedValue(v, prefix, t.db)
+	if err != nil {
+		return nil, fmt.Errorf("fetching value: %w", err)
+	}
+
+	prefixedKey := bytes.Join([][]byte{prefix, v[:]}, nil)
+	t.deathRow[string(prefixedKey)] = nil
+	return newValueRef{hash: common.Hash(v), data: data}, nil
+}
+
 // lookup node in DB and add it in storage, return storage handle
 // TODO: implement cache to improve performance
 func (t *TrieDB) lookupNode(hash common.Hash) (storageHandle, error) {
+	if hash == hashedNullNode {
+		// the empty trie root node does not need to be stored in the db
+		return t.storage.alloc(NewStoredNode{Empty{}}), nil
+	}
+
 	encodedNode, err := t.db.Get(hash[:])
 	if err != nil {
 		return -1, ErrIncompleteDB
 	}
 
-	t.recordAccess(encodedNodeAccess{hash: t.rootHash, encodedNode: encodedNode})
+	t.recordAccess(encodedNodeAccess{hash: hash, encodedNode: encodedNode})
 
-	node, err := newNodeFromEncoded(hash, encodedNode, t.storage)
+	node, err := newNodeFromEncoded(hash, encodedNode, &t.storage)
 	if err != nil {
 		return -1, err
 	}
@@ -747,6 +939,10 @@ func (t *TrieDB) commit() error {
 		if err != nil {
 			return err
 		}
+
+		if len(hash) == common.HashLength {
+			t.trackDeletedNode(common.NewHash([]byte(hash)))
+		}
 	}
 
 	// Reset deathRow
@@ -778,7 +974,7 @@ func(node nodeToEncode, partialKey []byte, childIndex *byte) (ChildReference, er
 				switch n := node.(type) {
 				case newNodeToEncode:
 					hash := common.MustBlake2bHash(n.value)
-					prefixedKey := append(n.partialKey, hash.ToBytes()...)
+					prefixedKey := bytes.Join([][]byte{n.partialKey, hash.ToBytes()}, nil)
 					err := dbBatch.Put(prefixedKey, n.value)
 					if err != nil {
 						return nil, err
@@ -809,6 +1005,7 @@ func(node nodeToEncode, partialKey []byte, childIndex *byte) (ChildReference, er
 		if err != nil {
 			return err
 		}
+		t.trackInsertedNode(hash)
 
 		t.rootHash = hash
 		t.rootHandle = persisted(t.rootHash)
@@ -854,14 +1051,14 @@ func (t *TrieDB) commitChild(
 				switch n := node.(type) {
 				case newNodeToEncode:
 					hash := common.MustBlake2bHash(n.value)
-					prefixedKey := append(n.partialKey, hash.ToBytes()...)
+					prefixedKey := bytes.Join([][]byte{n.partialKey, hash.ToBytes()}, nil)
 					err := dbBatch.Put(prefixedKey, n.value)
 					if err != nil {
-						panic("inserting in db")
+						return nil, err
 					}
 
 					if t.cache != nil {
-						t.cache.SetValue(n.partialKey, n.value)
+						t.cache.SetValue(prefixedKey, n.value)
 					}
 
 					prefixKey = prefixKey[:mov]
@@ -891,6 +1088,7 @@ func (t *TrieDB) commitChild(
 				if err != nil {
 					return nil, err
 				}
+				t.trackInsertedNode(hash)
 
 				return HashChildReference(hash), nil
 			} else {
@@ -904,11 +1102,24 @@ func (t *TrieDB) commitChild(
 	}
 }
 
-func (t *TrieDB) Iter() trie.TrieIterator {
+// Iter commits the pending changes of the trie and returns an iterator over its entries.
+func (t *TrieDB) Iter() (trie.TrieIterator, error) {
+	err := t.commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing trie: %w", err)
+	}
+
 	return NewTrieDBIterator(t)
 }
 
-func (t *TrieDB) PrefixedIter(prefix []byte) trie.TrieIterator {
+// PrefixedIter commits the pending changes of the trie and returns an iterator
+// over its entries whose keys are greater than the given key in little Endian format.
+func (t *TrieDB) PrefixedIter(prefix []byte) (trie.TrieIterator, error) {
+	err := t.commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing trie: %w", err)
+	}
+
 	return NewPrefixedTrieDBIterator(t, prefix)
 }
 
@@ -918,4 +1129,4 @@ func (t *TrieDB) recordAccess(access trieAccess) {
 	}
 }
 
-var _ trie.TrieRead = (*TrieDB)(nil)
+var _ trie.Trie = (*TrieDB)(nil)
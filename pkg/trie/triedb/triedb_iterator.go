@@ -5,6 +5,7 @@
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	nibbles "github.com/ChainSafe/gossamer/pkg/trie/codec"
@@ -30,38 +31,47 @@ func (s *iteratorState) fullKeyNibbles(child *int) []byte {
 type TrieDBIterator struct {
 	db        *TrieDB          // trie to iterate over
 	nodeStack []*iteratorState // Pending nodes to visit
+	// cursor is the key in nibbles from which the entries are returned,
+	// it is included if inclusive is set to true
+	cursor    []byte
+	inclusive bool
 }
 
-func NewTrieDBIterator(trie *TrieDB) *TrieDBIterator {
+// NewTrieDBIterator creates an iterator over the entries of the trie. The
+// iterator reads the trie nodes from the database, so the pending changes of the
+// trie have to be committed beforehand.
+func NewTrieDBIterator(trie *TrieDB) (*TrieDBIterator, error) {
+	if trie.hasUncommittedChanges() {
+		return nil, ErrUncommittedChanges
+	}
+
+	iter := &TrieDBIterator{db: trie}
+	if trie.rootHash == hashedNullNode {
+		// nothing to iterate over
+		return iter, nil
+	}
+
 	rootNode, err := trie.getRootNode()
 	if err != nil {
-		panic("trying to create trie iterator with incomplete trie DB")
+		return nil, fmt.Errorf("getting root node: %w", err)
 	}
-	return &TrieDBIterator{
-		db: trie,
-		nodeStack: []*iteratorState{
-			{
-				node: rootNode,
-			},
+	iter.nodeStack = []*iteratorState{
+		{
+			node: rootNode,
 		},
 	}
+	return iter, nil
 }
 
-func NewPrefixedTrieDBIterator(trie *TrieDB, prefix []byte) *TrieDBIterator {
-	nodeAtPrefix, err := trie.getNodeAt(prefix)
+// NewPrefixedTrieDBIterator creates an iterator over the entries of the trie
+// whose keys are greater than the given key in little Endian format.
+func NewPrefixedTrieDBIterator(trie *TrieDB, prefix []byte) (*TrieDBIterator, error) {
+	iter, err := NewTrieDBIterator(trie)
 	if err != nil {
-		panic("trying to create trie iterator with incomplete trie DB")
-	}
-
-	return &TrieDBIterator{
-		db: trie,
-		nodeStack: []*iteratorState{
-			{
-				parentFullKey: prefix[:len(nodeAtPrefix.GetPartialKey())-1],
-				node:          nodeAtPrefix,
-			},
-		},
+		return nil, err
 	}
+	iter.cursor = nibbles.KeyLEToNibbles(prefix)
+	return iter, nil
 }
 
 // nextToVisit sets the next node to visit in the iterator
@@ -88,6 +98,9 @@ func (i *TrieDBIterator) NextEntry() *trie.Entry {
 
 		switch n := currentNode.(type) {
 		case codec.Leaf:
+			if i.beforeCursor(currentState) {
+				continue
+			}
 			key := currentState.fullKeyNibbles(nil)
 			value := i.db.Get(key)
 			return &trie.Entry{Key: key, Value: value}
@@ -96,15 +109,26 @@ func (i *TrieDBIterator) NextEntry() *trie.Entry {
 			// and we want to visit the leftmost child first
 			for idx := len(n.Children) - 1; idx >= 0; idx-- {
 				child := n.Children[idx]
-				if child != nil {
-					childNode, err := i.db.getNode(child)
-					if err != nil {
-						panic(err)
+				if child == nil {
+					continue
+				}
+
+				childKey := currentState.fullKeyNibbles(&idx)
+				if i.cursor != nil {
+					// Skip the children whose keys are all before the cursor
+					cursorPrefix := i.cursor[:min(len(childKey), len(i.cursor))]
+					if bytes.Compare(childKey, cursorPrefix) < 0 {
+						continue
 					}
-					i.nextToVisit(currentState.fullKeyNibbles(&idx), childNode)
 				}
+
+				childNode, err := i.db.getNode(child)
+				if err != nil {
+					panic(err)
+				}
+				i.nextToVisit(childKey, childNode)
 			}
-			if n.GetValue() != nil {
+			if n.GetValue() != nil && !i.beforeCursor(currentState) {
 				key := currentState.fullKeyNibbles(nil)
 				value := i.db.Get(key)
 				return &trie.Entry{Key: key, Value: value}
@@ -115,6 +139,18 @@ func (i *TrieDBIterator) NextEntry() *trie.Entry {
 	return nil
 }
 
+// beforeCursor returns true if the key of the node in the given state should
+// not be returned because of the iterator cursor.
+func (i *TrieDBIterator) beforeCursor(s *iteratorState) bool {
+	if i.cursor == nil {
+		return false
+	}
+
+	fullKey := bytes.Join([][]byte{s.parentFullKey, s.node.GetPartialKey()}, nil)
+	cmp := bytes.Compare(fullKey, i.cursor)
+	return cmp < 0 || (cmp == 0 && !i.inclusive)
+}
+
 // NextKey performs a depth-first search on the trie and returns the next key
 // based on the current state of the iterator.
 func (i *TrieDBIterator) NextKey() []byte {
@@ -38,7 +38,8 @@ func TestIterator(t *testing.T) {
 
 	trieDB := NewTrieDB(root, db)
 	t.Run("iterate_over_all_entries", func(t *testing.T) {
-		iter := NewTrieDBIterator(trieDB)
+		iter, err := NewTrieDBIterator(trieDB)
+		assert.NoError(t, err)
 
 		expected := inMemoryTrie.NextKey([]byte{})
 		i := 0
@@ -52,7 +53,8 @@ func TestIterator(t *testing.T) {
 	})
 
 	t.Run("iterate_from_given_key", func(t *testing.T) {
-		iter := NewTrieDBIterator(trieDB)
+		iter, err := NewTrieDBIterator(trieDB)
+		assert.NoError(t, err)
 
 		iter.Seek([]byte("not"))
 
@@ -61,4 +63,20 @@ func TestIterator(t *testing.T) {
 
 		assert.Equal(t, expected, actual)
 	})
+
+	t.Run("uncommitted_changes", func(t *testing.T) {
+		trieDB := NewTrieDB(root, db)
+		err := trieDB.Put([]byte("new"), []byte{1})
+		assert.NoError(t, err)
+
+		// the iterator does not commit the changes to the database
+		iter, err := NewTrieDBIterator(trieDB)
+		assert.ErrorIs(t, err, ErrUncommittedChanges)
+		assert.Nil(t, iter)
+
+		// the changes are committed before iterating over the trie
+		trieIter, err := trieDB.PrefixedIter([]byte("ne"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("new"), trieIter.NextKey())
+	})
 }
@@ -4,11 +4,16 @@
 package triedb
 
 import (
+	"bytes"
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
 	"github.com/ChainSafe/gossamer/pkg/trie/triedb/codec"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInsertions(t *testing.T) {
@@ -784,3 +789,208 @@ func TestDBCommits(t *testing.T) {
 		assert.Nil(t, v)
 	})
 }
+
+func TestRandomOperations(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []trie.TrieLayout{trie.V0, trie.V1} {
+		version := version
+		t.Run(version.String(), func(t *testing.T) {
+			t.Parallel()
+
+			for seed := int64(0); seed < 20; seed++ {
+				generator := rand.New(rand.NewSource(seed)) //nolint:gosec
+				db := newTestDB(t)
+				entries := make(map[string][]byte)
+				root := trie.EmptyHash
+
+				// Each round reopens the trie at the last committed root
+				for round := 0; round < 4; round++ {
+					trieDB := NewTrieDB(root, db)
+					trieDB.SetVersion(version)
+
+					for op := 0; op < 30; op++ {
+						// Small keys over a small alphabet to exercise node
+						// splits and merges
+						key := make([]byte, generator.Intn(4)+1)
+						for i := range key {
+							key[i] = byte(generator.Intn(4))
+						}
+
+						if generator.Intn(3) == 0 {
+							delete(entries, string(key))
+							require.NoError(t, trieDB.Delete(key))
+						} else {
+							// Values longer than 32 bytes are hashed in trie V1
+							value := make([]byte, generator.Intn(50)+1)
+							generator.Read(value)
+							entries[string(key)] = value
+							require.NoError(t, trieDB.Put(key, value))
+						}
+					}
+
+					for key, value := range entries {
+						require.Equal(t, value, trieDB.Get([]byte(key)), "seed %d round %d", seed, round)
+					}
+
+					expectedTrie := inmemory.NewEmptyTrie()
+					expectedTrie.SetVersion(version)
+					for key, value := range entries {
+						require.NoError(t, expectedTrie.Put([]byte(key), value))
+					}
+
+					root = trieDB.MustHash()
+					require.Equal(t, expectedTrie.MustHash(), root, "seed %d round %d", seed, round)
+				}
+
+				trieDB := NewTrieDB(root, db)
+				require.Equal(t, entries, trieDB.Entries())
+
+				keys := make([]string, 0, len(entries))
+				for key := range entries {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+
+				nextKey := trieDB.NextKey(nil)
+				for _, key := range keys {
+					require.Equal(t, []byte(key), nextKey)
+					nextKey = trieDB.NextKey(nextKey)
+				}
+				require.Nil(t, nextKey)
+			}
+		})
+	}
+}
+
+func TestClearPrefix(t *testing.T) {
+	t.Parallel()
+
+	entries := map[string][]byte{
+		"no":        []byte("noValue"),
+		"noot":      []byte("nootValue"),
+		"not":       []byte("notValue"),
+		"a":         []byte("aValue"),
+		"dimartiro": []byte("dimartiroValue"),
+	}
+
+	newTrie := func() *TrieDB {
+		trieDB := NewEmptyTrieDB(newTestDB(t))
+		for k, v := range entries {
+			require.NoError(t, trieDB.Put([]byte(k), v))
+		}
+		return trieDB
+	}
+
+	t.Run("clear_prefix", func(t *testing.T) {
+		t.Parallel()
+
+		trieDB := newTrie()
+		err := trieDB.ClearPrefix([]byte("no"))
+		require.NoError(t, err)
+
+		expected := map[string][]byte{
+			"a":         []byte("aValue"),
+			"dimartiro": []byte("dimartiroValue"),
+		}
+		assert.Equal(t, expected, trieDB.Entries())
+	})
+
+	t.Run("clear_prefix_limit", func(t *testing.T) {
+		t.Parallel()
+
+		trieDB := newTrie()
+		deleted, allDeleted, err := trieDB.ClearPrefixLimit([]byte("no"), 2)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), deleted)
+		assert.False(t, allDeleted)
+		assert.Equal(t, [][]byte{[]byte("not")}, trieDB.GetKeysWithPrefix([]byte("no")))
+
+		deleted, allDeleted, err = trieDB.ClearPrefixLimit([]byte("no"), 2)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), deleted)
+		assert.True(t, allDeleted)
+		assert.Empty(t, trieDB.GetKeysWithPrefix([]byte("no")))
+	})
+}
+
+func TestChildTries(t *testing.T) {
+	t.Parallel()
+
+	trieDB := NewEmptyTrieDB(newTestDB(t))
+	keyToChild := []byte("child")
+
+	_, err := trieDB.GetChild(keyToChild)
+	require.ErrorIs(t, err, trie.ErrChildTrieDoesNotExist)
+
+	require.NoError(t, trieDB.PutIntoChild(keyToChild, []byte("a"), []byte("aValue")))
+	require.NoError(t, trieDB.PutIntoChild(keyToChild, []byte("b"), []byte("bValue")))
+
+	expectedChild := inmemory.NewEmptyTrie()
+	require.NoError(t, expectedChild.Put([]byte("a"), []byte("aValue")))
+	require.NoError(t, expectedChild.Put([]byte("b"), []byte("bValue")))
+
+	child, err := trieDB.GetChild(keyToChild)
+	require.NoError(t, err)
+	assert.Equal(t, expectedChild.MustHash(), child.MustHash())
+	assert.Len(t, trieDB.GetChildTries(), 1)
+
+	childRoot := trieDB.Get(bytes.Join([][]byte{trie.ChildStorageKeyPrefix, keyToChild}, nil))
+	assert.Equal(t, expectedChild.MustHash().ToBytes(), childRoot)
+
+	value, err := trieDB.GetFromChild(keyToChild, []byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("aValue"), value)
+
+	// The child trie is loaded from db once the main trie is reopened
+	reopened := NewTrieDB(trieDB.MustHash(), trieDB.db)
+	value, err = reopened.GetFromChild(keyToChild, []byte("b"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bValue"), value)
+
+	require.NoError(t, trieDB.ClearFromChild(keyToChild, []byte("a")))
+	value, err = trieDB.GetFromChild(keyToChild, []byte("a"))
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// The child trie is deleted once empty
+	require.NoError(t, trieDB.ClearFromChild(keyToChild, []byte("b")))
+	_, err = trieDB.GetChild(keyToChild)
+	require.ErrorIs(t, err, trie.ErrChildTrieDoesNotExist)
+	assert.Empty(t, trieDB.GetChildTries())
+}
+
+func TestGetChangedNodeHashes(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	trieDB := NewEmptyTrieDB(db)
+	require.NoError(t, trieDB.Put([]byte("branch"), []byte("branchvalue")))
+	require.NoError(t, trieDB.Put([]byte("branchleaf"), make([]byte, 40)))
+	root := trieDB.MustHash()
+
+	inserted, deleted, err := trieDB.GetChangedNodeHashes()
+	require.NoError(t, err)
+	assert.Contains(t, inserted, root)
+	assert.Empty(t, deleted)
+
+	// Nodes inserted and deleted in the same session are not reported
+	require.NoError(t, trieDB.Put([]byte("branch"), []byte("newvalue")))
+	newRoot := trieDB.MustHash()
+
+	inserted, deleted, err = trieDB.GetChangedNodeHashes()
+	require.NoError(t, err)
+	assert.Contains(t, inserted, newRoot)
+	assert.NotContains(t, inserted, root)
+	assert.Empty(t, deleted)
+
+	// Nodes of the previous state are reported as deleted
+	trieDB = NewTrieDB(newRoot, db)
+	require.NoError(t, trieDB.Put([]byte("branch"), []byte("branchvalue")))
+	lastRoot := trieDB.MustHash()
+
+	inserted, deleted, err = trieDB.GetChangedNodeHashes()
+	require.NoError(t, err)
+	assert.Contains(t, inserted, lastRoot)
+	assert.Contains(t, deleted, newRoot)
+}